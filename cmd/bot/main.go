@@ -14,11 +14,13 @@ import (
 
 	_ "net/http/pprof"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/api"
+	"github.com/yandex-development-1-team/go/internal/api/middleware"
 	"github.com/yandex-development-1-team/go/internal/api/server"
 	"github.com/yandex-development-1-team/go/internal/bot"
 	"github.com/yandex-development-1-team/go/internal/config"
@@ -26,6 +28,7 @@ import (
 	botHandlers "github.com/yandex-development-1-team/go/internal/handlers"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository/postgres"
 	"github.com/yandex-development-1-team/go/internal/repository/redis"
 	"github.com/yandex-development-1-team/go/internal/service"
@@ -33,6 +36,8 @@ import (
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 	"github.com/yandex-development-1-team/go/internal/shutdown"
 	minioStorage "github.com/yandex-development-1-team/go/internal/storage/minio"
+	"github.com/yandex-development-1-team/go/internal/tracker"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 	"github.com/yandex-development-1-team/go/internal/worker"
 )
 
@@ -100,20 +105,35 @@ func run() error {
 	boxSolutionRepo := postgres.NewBoxSolutionRepo(dbSqlx)
 	bookRepo := postgres.NewBookingRepository(dbSqlx)
 	sessionRepo := redis.NewSessionRepository(redisClient, redis.WithTTL(cfg.Session.TTL))
-	settingsRepo := postgres.NewSettingsRep(dbSqlx)
+	permissionCache := redis.NewPermissionCache(redisClient, postgres.NewSettingsRep(dbSqlx), postgres.NewStaffRepo(dbSqlx),
+		cfg.PermissionCache.Size, cfg.PermissionCache.LocalTTL, cfg.PermissionCache.TTL)
+	go permissionCache.Listen(ctx)
+	tokenRevocation := redis.NewTokenRevocation(redisClient)
+	messagesCache := redis.NewSettingsMessagesCache(redisClient, postgres.NewSettingsRep(dbSqlx))
+	go messagesCache.Listen(ctx)
+	settingsRepo := postgres.NewSettingsRep(dbSqlx, postgres.WithPermissionCache(permissionCache),
+		postgres.WithMessagesCache(messagesCache))
+	roleRepo := postgres.NewRoleRepo(dbSqlx, postgres.WithRolePermissionCache(permissionCache))
 	specialProjectRepo := postgres.NewSpecialProjectRepository(dbSqlx)
 	refreshTokenRepoRepo := postgres.NewRefreshTokenRepo(dbSqlx)
 	txRepo := postgres.NewTxRepo(dbSqlx)
-	staffRepo := postgres.NewStaffRepo(dbSqlx)
+	staffRepo := postgres.NewStaffRepo(dbSqlx, postgres.WithStaffPermissionCache(permissionCache))
 	analyticsRepo := postgres.NewAnalyticsRepo(dbSqlx)
 	resourcePageRepo := postgres.NewResourcePageRepo(dbSqlx)
 	passwordResetRepo := postgres.NewPasswordResetRepository(dbSqlx)
 	emailService := apiService.NewEmailService(cfg.Email)
 	fileRepo := postgres.NewFileRepository(dbSqlx)
 	applicationRepo := postgres.NewApplicationRepository(dbSqlx)
+	reminderRepo := postgres.NewBookingReminderRepo(dbSqlx)
+	outboxRepo := postgres.NewNotificationOutboxRepo(dbSqlx)
+	webhookRepo := postgres.NewWebhookRepo(dbSqlx)
+	campaignRepo := postgres.NewCampaignRepo(dbSqlx)
+	webhookPublisher := webhook.NewPublisher(webhookRepo)
+	auditLogRepo := postgres.NewAuditLogRepo(dbSqlx)
 
 	settingsService := apiService.NewSettingsService(settingsRepo)
-	bookService := botService.NewBookingService(sessionRepo, bookRepo, boxSolutionRepo)
+	managerNotifier := botService.NewEmailManagerNotifier(staffRepo, emailService)
+	bookService := botService.NewBookingService(sessionRepo, bookRepo, boxSolutionRepo, txRepo, managerNotifier, webhookPublisher)
 	keyboard := botHandlers.NewKeyboardService()
 	bsService := service.NewBoxSolutionsService(boxSolutionRepo)
 	detailService := botService.NewDetailService(boxSolutionRepo)
@@ -123,14 +143,35 @@ func run() error {
 	exampleService := botService.NewExamplesSpService(resourcePageRepo)
 	linksService := botService.NewUsefulLinksService(resourcePageRepo)
 	reqSpService := botService.NewRequestSpService(resourcePageRepo)
-	boxService := apiService.NewAPIBoxService(boxSolutionRepo, fileService, txRepo)
-	specialProjectService := service.NewSpecialProjectService(specialProjectRepo)
+	boxService := apiService.NewAPIBoxService(boxSolutionRepo, fileService, txRepo, webhookPublisher)
+	specialProjectService := service.NewSpecialProjectService(specialProjectRepo, txRepo, webhookPublisher)
 	analyticsService := apiService.NewAnalyticsService(analyticsRepo)
 	resourcePageService := service.NewResourcePageService(resourcePageRepo, fileService, txRepo)
 	userService := apiService.NewUserService(staffRepo)
-	applicationSvc := apiService.NewApplicationsService(applicationRepo, txRepo)
-	bookAPISvc := apiService.NewBookingsService(bookRepo, txRepo)
-	usersAdminService := apiService.NewUsersAdminService(staffRepo, refreshTokenRepoRepo)
+	applicationSvc := apiService.NewApplicationsService(applicationRepo, txRepo, webhookPublisher)
+	spRequestService := botService.NewSpRequestService(sessionRepo, applicationSvc)
+	bookAPISvc := apiService.NewBookingsService(bookRepo, txRepo, messagesCache, outboxRepo, webhookPublisher)
+	usersAdminService := apiService.NewUsersAdminService(staffRepo, refreshTokenRepoRepo, roleRepo, tokenRevocation, emailService,
+		cfg.AuthConfig.InviteTTLHours)
+	permissionService := apiService.NewPermissionService(permissionCache, staffRepo)
+	webhookService := apiService.NewWebhookService(webhookRepo)
+	campaignService := apiService.NewCampaignService(campaignRepo)
+	apiKeyService := apiService.NewAPIKeyService(postgres.NewAPIKeyRepo(dbSqlx))
+	staffTelegramService := apiService.NewStaffTelegramService(postgres.NewStaffTelegramRepo(dbSqlx), permissionService,
+		bookAPISvc, applicationSvc, cfg.AuthConfig.TelegramLinkTTLMinutes)
+	auditService := apiService.NewAuditService(auditLogRepo)
+	roleService := apiService.NewRoleService(roleRepo)
+
+	mfaService, err := apiService.NewMFAService(postgres.NewMFARepo(dbSqlx), txRepo, cfg.AuthConfig.MFAEncryptionKey, cfg.AuthConfig.MFAIssuer)
+	if err != nil {
+		return fmt.Errorf("mfa: %w", err)
+	}
+	loginGuard := apiService.NewLoginGuard(redis.NewLoginThrottle(redisClient), postgres.NewSecurityLogRepo(dbSqlx), apiService.LoginGuardConfig{
+		PerEmail:    apiService.LoginLimit{Attempts: cfg.AuthConfig.Lockout.EmailAttempts, Window: cfg.AuthConfig.Lockout.Window},
+		PerIP:       apiService.LoginLimit{Attempts: cfg.AuthConfig.Lockout.IPAttempts, Window: cfg.AuthConfig.Lockout.Window},
+		LockoutBase: cfg.AuthConfig.Lockout.BaseDuration,
+		LockoutMax:  cfg.AuthConfig.Lockout.MaxDuration,
+	})
 
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", metrics.NewHandler())
@@ -159,8 +200,16 @@ func run() error {
 		}
 	}()
 
+	jwtKeys, err := apiService.NewJWTKeys(cfg.AuthConfig.JWTSecret, cfg.AuthConfig.Signing)
+	if err != nil {
+		return fmt.Errorf("jwt keys: %w", err)
+	}
 	apiAuthService := apiService.NewAuthService(dbSqlx, refreshTokenRepoRepo, passwordResetRepo, staffRepo, emailService, txRepo, cfg.AuthConfig.JWTSecret,
-		cfg.AuthConfig.AccessTokenTTLMinutes, cfg.AuthConfig.RefreshTokenTTLDays)
+		cfg.AuthConfig.AccessTokenTTLMinutes, cfg.AuthConfig.RefreshTokenTTLDays, permissionService, tokenRevocation, mfaService,
+		models.RegistrationPolicy{
+			Mode:           cfg.AuthConfig.Registration.Mode,
+			AllowedDomains: cfg.AuthConfig.Registration.AllowedDomains,
+		}, loginGuard, jwtKeys)
 
 	apiServer := server.New(&cfg, &server.APIServices{
 		BoxService:        boxService,
@@ -171,10 +220,20 @@ func run() error {
 		UserSvc:           userService,
 		FileService:       fileService,
 		ApplicationRepo:   applicationRepo,
-		MiddlewareRepo:    dbSqlx,
+		PermissionCache:   permissionCache,
+		TokenRevocation:   tokenRevocation,
 		ApplicationSvc:    applicationSvc,
 		BookingSvc:        bookAPISvc,
 		UsersAdmin:        usersAdminService,
+		PermissionSvc:     permissionService,
+		WebhookSvc:        webhookService,
+		CampaignSvc:       campaignService,
+		AuditSvc:          auditService,
+		RoleSvc:           roleService,
+		MFASvc:            mfaService,
+		APIKeySvc:         apiKeyService,
+		StaffTelegramSvc:  staffTelegramService,
+		AuditLog:          middleware.NewAuditLog(auditLogRepo),
 	}, apiAuthService)
 
 	if cfg.FileGC.Enabled {
@@ -193,8 +252,51 @@ func run() error {
 		)
 	}
 
+	if cfg.Tracker.Enabled {
+		// The start date is checked when the config is loaded
+		startFrom, _ := cfg.Tracker.StartTime()
+		trackerWorker := worker.NewTrackerSyncWorker(
+			postgres.NewTrackerSyncRepo(dbSqlx, startFrom),
+			tracker.New(cfg.Tracker, nil),
+			bookAPISvc,
+			applicationSvc,
+			cfg.Tracker.Statuses,
+			cfg.Tracker.Interval,
+			cfg.Tracker.BatchSize,
+		)
+
+		go trackerWorker.Start(ctx)
+		logger.Info("tracker sync worker started",
+			zap.String("queue", cfg.Tracker.Queue),
+			zap.Duration("interval", cfg.Tracker.Interval),
+			zap.Int("batch_size", cfg.Tracker.BatchSize),
+			zap.Time("start_from", startFrom),
+		)
+	}
+
+	webhookWorker := worker.NewWebhookDeliveryWorker(
+		webhookRepo,
+		&http.Client{Timeout: cfg.Webhooks.Timeout},
+		cfg.Webhooks.Interval,
+		cfg.Webhooks.BatchSize,
+		cfg.Webhooks.MaxAttempts,
+	)
+	go webhookWorker.Start(ctx)
+	logger.Info("webhook delivery worker started",
+		zap.Duration("interval", cfg.Webhooks.Interval),
+		zap.Int("batch_size", cfg.Webhooks.BatchSize),
+		zap.Int("max_attempts", cfg.Webhooks.MaxAttempts),
+	)
+
 	apiServer.RegisterRoutes(cfg.YandexForms.WebhookToken, cfg.DocsPath)
 
+	// In webhook mode the API server receives the updates, so the route must
+	// exist before the server starts
+	var webhookUpdates tgbotapi.UpdatesChannel
+	if !cfg.APIOnly && cfg.Telegram.Mode == config.TelegramModeWebhook {
+		webhookUpdates = apiServer.RegisterTelegramWebhook(cfg.Telegram.Webhook)
+	}
+
 	var wg sync.WaitGroup
 	go func() {
 		wg.Go(func() {
@@ -223,10 +325,60 @@ func run() error {
 		return fmt.Errorf("rate limiter: %w", err)
 	}
 
-	startHandler := botHandlers.NewStartHandler(tgBot.Api, telegramUserRepo, sessionRepo)
-	statusHandler := botHandlers.NewStatusHandler(tgBot.Api, bookRepo, sessionRepo)
+	if cfg.Reminders.Enabled {
+		reminderWorker := worker.NewBookingReminderWorker(
+			reminderRepo,
+			messagesCache,
+			tgBot,
+			msgRL,
+			cfg.Reminders.Interval,
+			cfg.Reminders.BatchSize,
+		)
+
+		go reminderWorker.Start(ctx)
+		logger.Info("booking reminder worker started",
+			zap.Duration("interval", cfg.Reminders.Interval),
+			zap.Int("batch_size", cfg.Reminders.BatchSize),
+		)
+	}
+
+	outboxWorker := worker.NewNotificationOutboxWorker(
+		outboxRepo,
+		tgBot,
+		msgRL,
+		cfg.Outbox.Interval,
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.MaxAttempts,
+	)
+	go outboxWorker.Start(ctx)
+	logger.Info("notification outbox worker started",
+		zap.Duration("interval", cfg.Outbox.Interval),
+		zap.Int("batch_size", cfg.Outbox.BatchSize),
+	)
+
+	// Campaigns share both limiters with the updates and the notifications,
+	// so a broadcast can't push the bot over the limits of Telegram
+	campaignWorker := worker.NewCampaignDeliveryWorker(
+		campaignRepo,
+		tgBot,
+		apiRL,
+		msgRL,
+		cfg.Campaigns.Interval,
+		cfg.Campaigns.BatchSize,
+		cfg.Campaigns.MaxAttempts,
+	)
+	go campaignWorker.Start(ctx)
+	logger.Info("campaign delivery worker started",
+		zap.Duration("interval", cfg.Campaigns.Interval),
+		zap.Int("batch_size", cfg.Campaigns.BatchSize),
+		zap.Int("max_attempts", cfg.Campaigns.MaxAttempts),
+	)
+
+	startHandler := botHandlers.NewStartHandler(tgBot.Api, telegramUserRepo, sessionRepo, staffTelegramService)
+	statusHandler := botHandlers.NewStatusHandler(tgBot.Api, bookRepo, sessionRepo, keyboard)
 	bsHandler := botHandlers.NewBoxSolutions(tgBot.Api, bsService)
 	bcHandler := botHandlers.NewBookingFormHandler(tgBot.Api, bookService, startHandler, bsHandler, keyboard)
+	myBookingHandler := botHandlers.NewMyBookingHandler(tgBot.Api, bookService, bcHandler, keyboard)
 	infoHandler := botHandlers.NewDetailHandler(detailService, tgBot.Api, startHandler, bsHandler, keyboard)
 
 	aboutHandler := botHandlers.NewAboutHandler(aboutService, tgBot.Api, startHandler, bsHandler, keyboard)
@@ -234,12 +386,18 @@ func run() error {
 	exampleHandler := botHandlers.NewExamplesSpHandler(exampleService, tgBot.Api, startHandler, bsHandler, keyboard)
 	linksHandler := botHandlers.NewUsefulLinksHandler(linksService, tgBot.Api, startHandler, bsHandler, keyboard)
 	reqSpHandler := botHandlers.NewRequestSpHandler(reqSpService, tgBot.Api, startHandler, bsHandler, keyboard)
+	spRequestHandler := botHandlers.NewSpRequestHandler(tgBot.Api, spRequestService, startHandler)
+	staffHandler := botHandlers.NewStaffHandler(tgBot.Api, staffTelegramService)
+	languageHandler := botHandlers.NewLanguageHandler(tgBot.Api, startHandler, telegramUserRepo)
 
 	callbackRouter := botHandlers.NewCallbackRouter(tgBot.Api)
-	msgRouter := botHandlers.NewMessageRouter(tgBot.Api, startHandler, statusHandler, sessionRepo, bcHandler, msgRL)
+	deepLinkHandler := botHandlers.NewDeepLinkHandler(startHandler, callbackRouter, detailService, telegramUserRepo)
+	msgRouter := botHandlers.NewMessageRouter(tgBot.Api, startHandler, statusHandler, sessionRepo, bcHandler, msgRL, staffHandler, deepLinkHandler,
+		spRequestHandler)
 
 	callbackRouter.Register(botHandlers.CallbackBoxSolutions, bsHandler)
 	callbackRouter.Register(botService.CallbackBookingPrefix, bcHandler)
+	callbackRouter.Register(botHandlers.CallbackMyBooking, myBookingHandler)
 	callbackRouter.Register(botHandlers.CallbackInfoPrefix, infoHandler)
 	callbackRouter.Register(botHandlers.BoxSolutionsButtonBackToMainMenu, startHandler)
 
@@ -248,12 +406,26 @@ func run() error {
 	callbackRouter.Register(botHandlers.CallbackProjectExamples, exampleHandler)
 	callbackRouter.Register(botHandlers.CallbackSupport, linksHandler)
 	callbackRouter.Register(botHandlers.CallbackSpecialProject, reqSpHandler)
+	callbackRouter.Register(botService.CallbackSpRequestPrefix, spRequestHandler)
+	callbackRouter.Register(botHandlers.CallbackStaff, staffHandler)
+	callbackRouter.Register(botHandlers.CallbackLanguage, languageHandler)
 
-	handler := botHandlers.NewHandler(tgBot, msgRL, msgRouter, callbackRouter)
+	handler := botHandlers.NewHandler(tgBot, msgRL, msgRouter, callbackRouter, telegramUserRepo, messagesCache)
 
-	logger.Info("bot started", zap.String("env", cfg.Environment))
+	logger.Info("bot started", zap.String("env", cfg.Environment), zap.String("mode", cfg.Telegram.Mode))
 
-	updates := tgBot.GetUpdates(30 * time.Second)
+	updates := webhookUpdates
+	if updates != nil {
+		if err := tgBot.SetWebhook(); err != nil {
+			return fmt.Errorf("telegram webhook: %w", err)
+		}
+	} else {
+		// getUpdates fails while a webhook is set, e.g. after switching modes
+		if err := tgBot.DeleteWebhook(); err != nil {
+			logger.Warn("failed to delete telegram webhook", zap.Error(err))
+		}
+		updates = tgBot.GetUpdates(30 * time.Second)
+	}
 	go func() {
 		for update := range updates {
 			wg.Go(func() {
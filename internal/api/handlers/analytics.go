@@ -29,14 +29,14 @@ func NewAnalyticsHandler(svc *apiService.AnalyticsService) *AnalyticsHandler {
 func (h *AnalyticsHandler) Export(c *gin.Context) {
 	exportType := dto.ExportType(c.Query("type"))
 	switch exportType {
-	case dto.ExportTypeBoxes, dto.ExportTypeUsers:
+	case dto.ExportTypeBoxes, dto.ExportTypeUsers, dto.ExportTypeSources:
 	case "":
 		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest,
-			[]string{"Параметр type обязателен (допустимые значения: boxes, users)"})
+			[]string{"Параметр type обязателен (допустимые значения: boxes, users, sources)"})
 		return
 	default:
 		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest,
-			[]string{"Неверное значение type: допустимые значения — boxes, users"})
+			[]string{"Неверное значение type: допустимые значения — boxes, users, sources"})
 		return
 	}
 
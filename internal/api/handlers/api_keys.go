@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/models"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+// createdAPIKeyID passes the id of a new key to its audit snapshot
+const createdAPIKeyID = "created_api_key_id"
+
+type APIKeyHandler struct {
+	svc *service.APIKeyService
+}
+
+func NewAPIKeyHandler(svc *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	items := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		items = append(items, toAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, dto.APIKeyListResponse{Items: items})
+}
+
+// Create issues a key. The response is the only place the key is shown.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	createdBy := c.GetInt64("user_id")
+	key, plain, err := h.svc.Create(c.Request.Context(), &models.APIKey{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		CreatedBy: &createdBy,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Set(createdAPIKeyID, key.ID)
+	resp := toAPIKeyResponse(key)
+	resp.Key = plain
+	c.JSON(http.StatusCreated, resp)
+}
+
+// Revoke disables the key at once; it stays in the list
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	var id dto.APIKeyID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.Revoke(c.Request.Context(), id.ID); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toAPIKeyResponse(key *models.APIKey) dto.APIKeyResponse {
+	resp := dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	return resp
+}
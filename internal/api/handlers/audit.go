@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/models"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+const defaultAuditLimit = 50
+
+type AuditHandler struct {
+	svc *service.AuditService
+}
+
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+// List returns the audit log, newest first. date_to is inclusive.
+func (h *AuditHandler) List(c *gin.Context) {
+	var query dto.AuditListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	from, err := parseOptionalDate(query.DateFrom)
+	if err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректная дата начала периода"})
+		return
+	}
+	to, err := parseOptionalDate(query.DateTo)
+	if err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректная дата окончания периода"})
+		return
+	}
+	if to != nil {
+		next := to.AddDate(0, 0, 1)
+		to = &next
+	}
+
+	filter := models.AuditFilter{
+		Entity:   query.Entity,
+		EntityID: query.EntityID,
+		ActorID:  query.ActorID,
+		From:     from,
+		To:       to,
+		Limit:    query.Limit,
+		Offset:   query.Offset,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	list, err := h.svc.List(c.Request.Context(), filter)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuditListResponse(list))
+}
+
+func toAuditListResponse(list *models.AuditList) dto.AuditListResponse {
+	items := make([]dto.AuditEntryItem, len(list.Items))
+	for i, e := range list.Items {
+		items[i] = dto.AuditEntryItem{
+			ID:         e.ID,
+			ActorID:    e.ActorID,
+			ActorRole:  e.ActorRole,
+			Entity:     e.Entity,
+			EntityID:   e.EntityID,
+			Action:     e.Action,
+			Method:     e.Method,
+			Path:       e.Path,
+			StatusCode: e.StatusCode,
+			Before:     e.Before,
+			After:      e.After,
+			Diff:       e.Diff,
+			CreatedAt:  e.CreatedAt,
+		}
+	}
+
+	return dto.AuditListResponse{
+		Items: items,
+		Pagination: dto.Pagination{
+			Total:  list.Total,
+			Limit:  list.Limit,
+			Offset: list.Offset,
+		},
+	}
+}
+
+// The AuditSnapshot methods below load the entity changed by the request in
+// the form its GET endpoint returns, for the before/after of the audit log.
+
+func (h *BoxHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	box, err := h.boxService.GetByID(c.Request.Context(), id)
+	if err != nil || box == nil {
+		return nil, err
+	}
+	return toBoxResponse(box), nil
+}
+
+func (h *SpecialProjectHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	project, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toSpecialProjectResponse(project), nil
+}
+
+func (h *BookingHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := h.svc.GetBookingById(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toBookingDetailResponse(booking), nil
+}
+
+func (h *ApplicationHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	app, err := h.svc.GetApplicationByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toAppResponse(app), nil
+}
+
+func (h *ResourcePageHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	page, err := h.service.GetResourcePage(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		return nil, err
+	}
+	return toResourcePageResponse(*page), nil
+}
+
+// The UsersHandler snapshot is also taken after creation, in place of the
+// response that holds the invite token
+func (h *UsersHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditCreatedID(c, createdUserID)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.GetByID(c.Request.Context(), id)
+}
+
+func (h *UsersHandler) AuditPermissionsSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	permissions, effective, err := h.perms.Get(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toUserPermissionsResponse(permissions, effective), nil
+}
+
+// The WebhookHandler snapshot is also taken after creation, in place of the
+// response that holds the signing secret
+func (h *WebhookHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditCreatedID(c, createdWebhookID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookResponse(sub, false), nil
+}
+
+func (h *CampaignHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditParamID(c)
+	if err != nil {
+		return nil, err
+	}
+	campaign, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toCampaignResponse(campaign), nil
+}
+
+func (h *RoleHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	role, err := h.svc.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+func (a SettingsHandler) AuditMessagesSnapshot(c *gin.Context) (any, error) {
+	settings, err := a.service.GetSettings(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	return convertModelsToDTOFromSettingsMessages(settings), nil
+}
+
+// AuditPermissionsSnapshot takes the role from the request body of the update
+func (a SettingsHandler) AuditPermissionsSnapshot(c *gin.Context) (any, error) {
+	var req dto.SettingsPermissions
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	permissions, err := a.service.GetSettingsPermissions(c.Request.Context(), req.Role)
+	if err != nil {
+		return nil, err
+	}
+	return convertServiceToDTOFromSettingsPermissions(permissions), nil
+}
+
+// The APIKeyHandler snapshot is also taken after creation, in place of the response that
+// holds the key: the id then comes from the context
+func (h *APIKeyHandler) AuditSnapshot(c *gin.Context) (any, error) {
+	id, err := auditCreatedID(c, createdAPIKeyID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKeyResponse(key), nil
+}
+
+func auditParamID(c *gin.Context) (int64, error) {
+	return strconv.ParseInt(c.Param("id"), 10, 64)
+}
+
+// auditCreatedID takes the id of the entity created by the handler from the
+// context, or else from the route
+func auditCreatedID(c *gin.Context, key string) (int64, error) {
+	if id := c.GetInt64(key); id != 0 {
+		return id, nil
+	}
+	return auditParamID(c)
+}
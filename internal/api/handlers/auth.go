@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,7 +29,38 @@ func (h *AuthHandler) HandleLogin(c *gin.Context) {
 		return
 	}
 
-	authResult, err := h.svc.Login(c.Request.Context(), req.Login, req.Password)
+	authResult, err := h.svc.Login(c.Request.Context(), req.Login, req.Password, clientInfo(c, req.Device))
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	if authResult.MFAToken != "" {
+		c.JSON(http.StatusOK, dto.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    authResult.MFAToken,
+			ExpiresIn:   int(svcapi.MFAChallengeTTL.Seconds()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LoginResponse{
+		Token:        authResult.Token,
+		RefreshToken: authResult.RefreshToken,
+		User:         toUserResponse(authResult.User),
+		Permissions:  authResult.Permissions,
+	})
+}
+
+// HandleMFAVerify completes a login that asked for the second factor
+func (h *AuthHandler) HandleMFAVerify(c *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	authResult, err := h.svc.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, clientInfo(c, req.Device))
 	if err != nil {
 		apierrors.WriteErrorGin(c, err)
 		return
@@ -38,6 +70,7 @@ func (h *AuthHandler) HandleLogin(c *gin.Context) {
 		Token:        authResult.Token,
 		RefreshToken: authResult.RefreshToken,
 		User:         toUserResponse(authResult.User),
+		Permissions:  authResult.Permissions,
 	})
 }
 
@@ -51,7 +84,7 @@ func (h *AuthHandler) HandleRefresh(c *gin.Context) {
 		return
 	}
 
-	tokenResponse, err := h.svc.Refresh(ctx, req.RefreshToken)
+	tokenResponse, err := h.svc.Refresh(ctx, req.RefreshToken, clientInfo(c, ""))
 	if err != nil {
 		apierrors.WriteErrorMessagesGin(c, http.StatusUnauthorized, []string{"Некорректный или просроченный refresh token"})
 		return
@@ -75,16 +108,79 @@ func (h *AuthHandler) RegisterHandler(c *gin.Context) {
 		LastName:    req.LastName,
 		Email:       req.Email,
 		InviteToken: req.InviteToken,
-	}, req.Password)
+	}, req.Password, clientInfo(c, req.Device))
 	if err != nil {
 		apierrors.WriteErrorGin(c, err)
 		return
 	}
 
+	if authResult.Token == "" {
+		c.JSON(http.StatusCreated, dto.RegisterPendingResponse{
+			Message: "Подтвердите email по ссылке из письма",
+			User:    toUserResponse(authResult.User),
+		})
+		return
+	}
+
 	c.JSON(http.StatusCreated, dto.LoginResponse{
 		Token:        authResult.Token,
 		RefreshToken: authResult.RefreshToken,
 		User:         toUserResponse(authResult.User),
+		Permissions:  authResult.Permissions,
+	})
+}
+
+// HandleVerifyEmail confirms the email of a self-registered staff member
+func (h *AuthHandler) HandleVerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	user, err := h.svc.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+func (h *AuthHandler) HandleResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	if err := h.svc.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Если email ожидает подтверждения, ссылка отправлена повторно"})
+}
+
+// HandleAcceptInvite lets an invited staff member set a password and log in
+func (h *AuthHandler) HandleAcceptInvite(c *gin.Context) {
+	var req dto.AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	authResult, err := h.svc.AcceptInvite(c.Request.Context(), req.Token, req.Password, clientInfo(c, req.Device))
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LoginResponse{
+		Token:        authResult.Token,
+		RefreshToken: authResult.RefreshToken,
+		User:         toUserResponse(authResult.User),
+		Permissions:  authResult.Permissions,
 	})
 }
 
@@ -98,7 +194,7 @@ func (h *AuthHandler) HandleLogout(c *gin.Context) {
 		return
 	}
 
-	_ = h.svc.Logout(ctx, req.RefreshToken)
+	_ = h.svc.Logout(ctx, req.RefreshToken, bearerToken(c))
 
 	c.JSON(http.StatusOK, dto.LogoutResponse{Message: "Logged out successfully"})
 }
@@ -110,7 +206,7 @@ func (h *AuthHandler) HandleForgotPassword(c *gin.Context) {
 		return
 	}
 
-	err := h.svc.ForgotPassword(c.Request.Context(), req.Email)
+	err := h.svc.ForgotPassword(c.Request.Context(), req.Email, clientInfo(c, ""))
 	if err != nil {
 		apierrors.WriteErrorGin(c, err)
 		return
@@ -135,27 +231,117 @@ func (h *AuthHandler) HandleResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Пароль успешно изменен"})
 }
 
+// JWKS publishes the public keys of access tokens for other services
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.svc.Keys().JWKS())
+}
+
+// Sessions lists the devices the user is logged in on
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	sessions, err := h.svc.Sessions(c.Request.Context(), userID)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	var currentID int64
+	if claims, ok := c.Value("claims").(*svcapi.AccessClaims); ok {
+		currentID = claims.SessionID
+	}
+
+	items := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		items = append(items, dto.SessionResponse{
+			ID:         s.ID,
+			Device:     s.Device,
+			IP:         s.IP,
+			UserAgent:  s.UserAgent,
+			Current:    s.ID == currentID,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, dto.SessionListResponse{Items: items})
+}
+
+// RevokeSession logs the user out on one device
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	var id dto.SessionID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.RevokeSession(c.Request.Context(), c.GetInt64("user_id"), id.ID); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ClearLockout lifts the brute-force lockout of an email and/or a client IP
+func (h *AuthHandler) ClearLockout(c *gin.Context) {
+	var req dto.ClearLockoutRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.ClearLockout(c.Request.Context(), c.GetInt64("user_id"), req.Email, req.IP); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bearerToken returns the access token of the request, if any
+func bearerToken(c *gin.Context) string {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+func clientInfo(c *gin.Context, device string) models.ClientInfo {
+	return models.ClientInfo{
+		Device:    device,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
 func toUserResponse(user *models.UserAPI) dto.UserResponse {
 	if user == nil {
 		return dto.UserResponse{}
 	}
 	return dto.UserResponse{
-		ID:           user.ID,
-		TelegramNick: user.TelegramNick,
-		Name:         user.Name,
-		LastName:     user.LastName,
-		SecondName:   user.SecondName,
-		Email:        user.Email,
-		PhoneNumber:  user.PhoneNumber,
-		Role:         user.Role,
-		Status:       user.Status,
-		Department:   user.Department,
-		Position:     user.Position,
-		Supervisor:   user.Supervisor,
-		Address:      user.Address,
-		Image:        user.Image,
-		InviteToken:  user.InviteToken,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:              user.ID,
+		TelegramNick:    user.TelegramNick,
+		Name:            user.Name,
+		LastName:        user.LastName,
+		SecondName:      user.SecondName,
+		Email:           user.Email,
+		PhoneNumber:     user.PhoneNumber,
+		Role:            user.Role,
+		Status:          user.Status,
+		Department:      user.Department,
+		Position:        user.Position,
+		Supervisor:      user.Supervisor,
+		Address:         user.Address,
+		Image:           user.Image,
+		InviteToken:     user.InviteToken,
+		InviteExpiresAt: user.InviteExpiresAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ApprovedAt:      user.ApprovedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}
 }
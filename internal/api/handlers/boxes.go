@@ -241,6 +241,7 @@ func toBoxResponse(box *models.Service) *dto.BoxDetailResponse {
 			Date:      s.Date,
 			StartTime: s.StartTime,
 			EndTime:   s.EndTime,
+			Capacity:  s.Capacity,
 		})
 	}
 
@@ -267,6 +268,7 @@ func toServiceUpdateModel(box *dto.BoxUpdateRequest) *models.BoxUpdate {
 			Date:      s.Date,
 			StartTime: s.StartTime,
 			EndTime:   s.EndTime,
+			Capacity:  s.Capacity,
 		})
 	}
 
@@ -290,6 +292,7 @@ func toServiceCreateModel(box *dto.BoxCreateRequest) *models.BoxCreate {
 			Date:      s.Date,
 			StartTime: s.StartTime,
 			EndTime:   s.EndTime,
+			Capacity:  s.Capacity,
 		})
 	}
 
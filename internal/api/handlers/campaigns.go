@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/models"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+const defaultCampaignRecipientsLimit = 50
+
+type CampaignHandler struct {
+	svc *service.CampaignService
+}
+
+func NewCampaignHandler(svc *service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{svc: svc}
+}
+
+func (h *CampaignHandler) List(c *gin.Context) {
+	campaigns, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	items := make([]dto.CampaignResponse, 0, len(campaigns))
+	for i := range campaigns {
+		items = append(items, toCampaignResponse(&campaigns[i]))
+	}
+
+	c.JSON(http.StatusOK, dto.CampaignListResponse{Items: items})
+}
+
+// GetByID returns the campaign with its delivery progress
+func (h *CampaignHandler) GetByID(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	campaign, err := h.svc.GetByID(c.Request.Context(), id.ID)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCampaignResponse(campaign))
+}
+
+// Create saves the campaign as a draft; it is sent after Start
+func (h *CampaignHandler) Create(c *gin.Context) {
+	var req dto.CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	campaign := toCampaignModel(&req)
+	createdBy := c.GetInt64("user_id")
+	if createdBy != 0 {
+		campaign.CreatedBy = &createdBy
+	}
+
+	created, err := h.svc.Create(c.Request.Context(), campaign)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCampaignResponse(created))
+}
+
+// Update replaces the draft
+func (h *CampaignHandler) Update(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	var req dto.CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	campaign := toCampaignModel(&req)
+	campaign.ID = id.ID
+
+	updated, err := h.svc.Update(c.Request.Context(), campaign)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCampaignResponse(updated))
+}
+
+func (h *CampaignHandler) Delete(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), id.ID); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Audience returns how many bot users the segment reaches
+func (h *CampaignHandler) Audience(c *gin.Context) {
+	var req dto.CampaignSegment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	count, err := h.svc.Audience(c.Request.Context(), toCampaignSegment(req))
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CampaignAudienceResponse{Count: count})
+}
+
+// Start queues the draft for delivery to the users of its segment
+func (h *CampaignHandler) Start(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	campaign, err := h.svc.Start(c.Request.Context(), id.ID)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCampaignResponse(campaign))
+}
+
+// Cancel stops the delivery of a draft or running campaign
+func (h *CampaignHandler) Cancel(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	campaign, err := h.svc.Cancel(c.Request.Context(), id.ID)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCampaignResponse(campaign))
+}
+
+// Recipients returns the delivery status of every recipient
+func (h *CampaignHandler) Recipients(c *gin.Context) {
+	var id dto.CampaignID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	var query dto.CampaignRecipientListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	filter := models.CampaignRecipientFilter{
+		CampaignID: id.ID,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultCampaignRecipientsLimit
+	}
+	if query.Status != nil {
+		filter.Status = *query.Status
+	}
+
+	list, err := h.svc.ListRecipients(c.Request.Context(), filter)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCampaignRecipientListResponse(list))
+}
+
+func toCampaignModel(req *dto.CampaignRequest) *models.Campaign {
+	buttons := make([]models.CampaignButton, len(req.Buttons))
+	for i, b := range req.Buttons {
+		buttons[i] = models.CampaignButton{Text: b.Text, URL: b.URL}
+	}
+
+	return &models.Campaign{
+		Title:    req.Title,
+		Text:     req.Text,
+		ImageURL: req.ImageURL,
+		Buttons:  buttons,
+		Segment:  toCampaignSegment(req.Segment),
+	}
+}
+
+func toCampaignSegment(segment dto.CampaignSegment) models.CampaignSegment {
+	return models.CampaignSegment{
+		Type:       segment.Type,
+		ServiceIDs: segment.ServiceIDs,
+		Grades:     segment.Grades,
+	}
+}
+
+func toCampaignResponse(campaign *models.Campaign) dto.CampaignResponse {
+	buttons := make([]dto.CampaignButton, len(campaign.Buttons))
+	for i, b := range campaign.Buttons {
+		buttons[i] = dto.CampaignButton{Text: b.Text, URL: b.URL}
+	}
+
+	return dto.CampaignResponse{
+		ID:       campaign.ID,
+		Title:    campaign.Title,
+		Text:     campaign.Text,
+		ImageURL: campaign.ImageURL,
+		Buttons:  buttons,
+		Segment: dto.CampaignSegment{
+			Type:       campaign.Segment.Type,
+			ServiceIDs: campaign.Segment.ServiceIDs,
+			Grades:     campaign.Segment.Grades,
+		},
+		Status:    campaign.Status,
+		CreatedBy: campaign.CreatedBy,
+		Progress: dto.CampaignProgress{
+			Total:   campaign.Progress.Total,
+			Pending: campaign.Progress.Pending,
+			Sent:    campaign.Progress.Sent,
+			Blocked: campaign.Progress.Blocked,
+			Failed:  campaign.Progress.Failed,
+		},
+		StartedAt:  campaign.StartedAt,
+		FinishedAt: campaign.FinishedAt,
+		CreatedAt:  campaign.CreatedAt,
+		UpdatedAt:  campaign.UpdatedAt,
+	}
+}
+
+func toCampaignRecipientListResponse(list *models.CampaignRecipientList) dto.CampaignRecipientListResponse {
+	items := make([]dto.CampaignRecipientItem, len(list.Items))
+	for i, r := range list.Items {
+		items[i] = dto.CampaignRecipientItem{
+			TelegramID: r.TelegramID,
+			Status:     r.Status,
+			Attempts:   r.Attempts,
+			LastError:  r.LastError,
+			SentAt:     r.SentAt,
+			UpdatedAt:  r.UpdatedAt,
+		}
+	}
+
+	return dto.CampaignRecipientListResponse{
+		Items: items,
+		Pagination: dto.Pagination{
+			Total:  list.Total,
+			Limit:  list.Limit,
+			Offset: list.Offset,
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	svcapi "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+// MFAHandler lets the user manage their own second factor
+type MFAHandler struct {
+	svc *svcapi.MFAService
+}
+
+func NewMFAHandler(svc *svcapi.MFAService) *MFAHandler {
+	return &MFAHandler{svc: svc}
+}
+
+func (h *MFAHandler) Status(c *gin.Context) {
+	mfa, err := h.svc.State(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFAStatusResponse{
+		Enabled:           mfa.Enabled(),
+		Required:          mfa.Required,
+		RecoveryCodesLeft: mfa.CodesLeft,
+	})
+}
+
+// Enroll issues a new secret for an authenticator app
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	enrollment, err := h.svc.Enroll(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFAEnrollResponse{
+		Secret:     enrollment.Secret,
+		OtpauthURL: enrollment.URL,
+	})
+}
+
+// Confirm enables the enrolled secret and returns the recovery codes
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	codes, err := h.svc.Confirm(c.Request.Context(), c.GetInt64("user_id"), req.Code)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	codes, err := h.svc.RegenerateRecoveryCodes(c.Request.Context(), c.GetInt64("user_id"), req.Code)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	if err := h.svc.Disable(c.Request.Context(), c.GetInt64("user_id"), req.Code); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
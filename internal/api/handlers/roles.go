@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/models"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+type RoleHandler struct {
+	svc *service.RoleService
+}
+
+func NewRoleHandler(svc *service.RoleService) *RoleHandler {
+	return &RoleHandler{svc: svc}
+}
+
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	items := make([]dto.RoleResponse, 0, len(roles))
+	for i := range roles {
+		items = append(items, toRoleResponse(&roles[i]))
+	}
+
+	c.JSON(http.StatusOK, dto.RoleListResponse{Items: items})
+}
+
+func (h *RoleHandler) Get(c *gin.Context) {
+	var name dto.RoleName
+	if err := c.ShouldBindUri(&name); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	role, err := h.svc.Get(c.Request.Context(), name.Name)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoleResponse(role))
+}
+
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req dto.RoleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	role, err := h.svc.Create(c.Request.Context(), &models.Role{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		MFARequired: req.MFARequired,
+		Assignable:  req.Assignable,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toRoleResponse(role))
+}
+
+func (h *RoleHandler) Update(c *gin.Context) {
+	var name dto.RoleName
+	if err := c.ShouldBindUri(&name); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	var req dto.RoleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	role, err := h.svc.Update(c.Request.Context(), name.Name, &models.RoleUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		MFARequired: req.MFARequired,
+		Assignable:  req.Assignable,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoleResponse(role))
+}
+
+func (h *RoleHandler) Delete(c *gin.Context) {
+	var name dto.RoleName
+	if err := c.ShouldBindUri(&name); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), name.Name); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toRoleResponse(role *models.Role) dto.RoleResponse {
+	return dto.RoleResponse{
+		Name:        role.Name,
+		Title:       role.Title,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		MFARequired: role.MFARequired,
+		Assignable:  role.Assignable,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
@@ -1,15 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/apierrors"
 	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
 	service "github.com/yandex-development-1-team/go/internal/service/api"
 )
 
@@ -52,6 +56,10 @@ func (a SettingsHandler) GetPermissions(c *gin.Context) {
 	}
 
 	permissions, err := a.service.GetSettingsPermissions(ctx, role)
+	if errors.Is(err, models.ErrRoleNotFound) {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("failed to get settings permissions from handler", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -81,6 +89,11 @@ func (a SettingsHandler) Put(c *gin.Context) {
 	reqService := convertDTOToModelsFromSettingsMessages(reqDTO)
 
 	err := a.service.PutSettings(ctx, reqService)
+	var templateErr *models.MessageTemplateError
+	if errors.As(err, &templateErr) {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, templateErr.Messages())
+		return
+	}
 	if err != nil {
 		logger.Error("failed to get settings messages from handler", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -94,6 +107,61 @@ func (a SettingsHandler) Put(c *gin.Context) {
 	})
 }
 
+// Preview renders a message template with sample data, the fields of the
+// request override the sample
+func (a SettingsHandler) Preview(c *gin.Context) {
+	var req dto.SettingsMessagePreviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("failed to get message preview from request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": models.ErrValidation,
+		})
+		return
+	}
+
+	lang, ok := i18n.Parse(req.Lang)
+	if !ok {
+		lang = i18n.Default
+	}
+
+	text, err := a.service.PreviewMessage(req.Template, previewData(req, lang))
+	var templateErr *msgtemplate.Error
+	if errors.As(err, &templateErr) {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{templateErr.Reason})
+		return
+	}
+	if err != nil {
+		logger.Error("failed to preview message from handler", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SettingsMessagePreviewResponse{Text: text})
+}
+
+func previewData(req dto.SettingsMessagePreviewRequest, lang i18n.Lang) msgtemplate.Data {
+	data := msgtemplate.Sample(lang)
+	if req.GuestName != "" {
+		data.GuestName = req.GuestName
+	}
+	if req.ServiceName != "" {
+		data.ServiceName = req.ServiceName
+	}
+	if req.Date != "" {
+		data.Date = msgtemplate.FormatDate(req.Date)
+	}
+	if req.Time != "" {
+		data.Time = req.Time
+	}
+	if req.BookingID != 0 {
+		data.BookingID = req.BookingID
+	}
+	return data
+}
+
 func (a SettingsHandler) Post(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -119,6 +187,12 @@ func (a SettingsHandler) Post(c *gin.Context) {
 	reqService := convertDTOToServiceFromSettingsPermissions(reqDTO)
 
 	err = a.service.PostSettings(ctx, reqService)
+	if errors.Is(err, models.ErrRoleNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": models.ErrValidation,
+		})
+		return
+	}
 	if err != nil {
 		logger.Error("failed to update settings permissions from handler", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -171,15 +245,9 @@ func convertDTOToModelsFromSettingsMessages(settingsMes dto.SettingsFormMessages
 }
 
 func validateSettingsPermissionsFromRequest(req dto.SettingsPermissions) error {
-	exist := false
-
-	for _, role := range service.Roles {
-		if role == req.Role {
-			exist = true
-		}
-	}
-
-	if !exist {
+	// The role itself is checked against the roles table on save;
+	// guests have no admin panel permissions
+	if req.Role == "" || req.Role == service.RoleUser {
 		return fmt.Errorf("wrong role")
 	}
 
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+// StaffTelegramHandler lets an admin link staff accounts to Telegram
+type StaffTelegramHandler struct {
+	svc *service.StaffTelegramService
+}
+
+func NewStaffTelegramHandler(svc *service.StaffTelegramService) *StaffTelegramHandler {
+	return &StaffTelegramHandler{svc: svc}
+}
+
+// CreateLinkCode issues a one-time code the staff member sends to the bot.
+// The response is the only place the code is shown.
+func (h *StaffTelegramHandler) CreateLinkCode(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+
+	code, err := h.svc.CreateLinkCode(c.Request.Context(), id)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.TelegramLinkCodeResponse{
+		Code:      code.Code,
+		Command:   "/link " + code.Code,
+		ExpiresAt: code.ExpiresAt,
+	})
+}
+
+// Unlink detaches the Telegram account; the staff member becomes a guest in the bot
+func (h *StaffTelegramHandler) Unlink(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+
+	if err := h.svc.Unlink(c.Request.Context(), id); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/bot"
+	"github.com/yandex-development-1-team/go/internal/logger"
+)
+
+// TelegramWebhookHandler receives the updates Telegram posts in webhook mode
+// and hands them to the bot through the same channel polling fills
+type TelegramWebhookHandler struct {
+	secret  []byte
+	updates chan tgbotapi.Update
+}
+
+func NewTelegramWebhookHandler(secret string, buffer int) *TelegramWebhookHandler {
+	return &TelegramWebhookHandler{
+		secret:  []byte(secret),
+		updates: make(chan tgbotapi.Update, buffer),
+	}
+}
+
+// Updates is the channel the bot reads the received updates from
+func (h *TelegramWebhookHandler) Updates() tgbotapi.UpdatesChannel {
+	return h.updates
+}
+
+// Receive checks the secret token and queues the update. A full queue holds
+// the request, and Telegram retries the update if the request is given up.
+func (h *TelegramWebhookHandler) Receive(c *gin.Context) {
+	token := c.GetHeader(bot.WebhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(token), h.secret) != 1 {
+		logger.Warn("telegram webhook: invalid secret token", zap.String("ip", c.ClientIP()))
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(c.Request.Body).Decode(&update); err != nil {
+		logger.Warn("telegram webhook: bad update", zap.Error(err))
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.updates <- update:
+		c.Status(http.StatusOK)
+	case <-c.Request.Context().Done():
+		logger.Warn("telegram webhook: update queue is full", zap.Int("update_id", update.UpdateID))
+		c.Status(http.StatusServiceUnavailable)
+	}
+}
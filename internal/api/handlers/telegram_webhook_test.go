@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/bot"
+)
+
+func newTelegramWebhookRouter(h *TelegramWebhookHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/telegram/webhook", h.Receive)
+	return r
+}
+
+func postTelegramUpdate(ctx context.Context, r *gin.Engine, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(bot.WebhookSecretHeader, secret)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestTelegramWebhookHandler_Receive(t *testing.T) {
+	h := NewTelegramWebhookHandler("s3cret", 1)
+	r := newTelegramWebhookRouter(h)
+	ctx := context.Background()
+	update := `{"update_id": 7, "message": {"message_id": 1, "text": "/start", "chat": {"id": 42, "type": "private"}}}`
+
+	w := postTelegramUpdate(ctx, r, "", update)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "no secret token")
+
+	w = postTelegramUpdate(ctx, r, "wrong", update)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "wrong secret token")
+
+	w = postTelegramUpdate(ctx, r, "s3cret", "{")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postTelegramUpdate(ctx, r, "s3cret", update)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	got := <-h.Updates()
+	assert.Equal(t, 7, got.UpdateID)
+	assert.Equal(t, "/start", got.Message.Text)
+}
+
+func TestTelegramWebhookHandler_ReceiveQueueFull(t *testing.T) {
+	h := NewTelegramWebhookHandler("s3cret", 1)
+	r := newTelegramWebhookRouter(h)
+
+	w := postTelegramUpdate(context.Background(), r, "s3cret", `{"update_id": 1}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	w = postTelegramUpdate(ctx, r, "s3cret", `{"update_id": 2}`)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Telegram retries the update later")
+}
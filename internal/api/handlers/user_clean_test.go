@@ -26,6 +26,7 @@ import (
 
 	"github.com/yandex-development-1-team/go/internal/config"
 	"github.com/yandex-development-1-team/go/internal/database"
+	"github.com/yandex-development-1-team/go/internal/models"
 	pgrepo "github.com/yandex-development-1-team/go/internal/repository/postgres"
 	service "github.com/yandex-development-1-team/go/internal/service/api"
 )
@@ -79,7 +80,8 @@ func setupServer(t *testing.T, db *sqlx.DB) *httptest.Server {
 		FromEmail:    "test@example.com",
 		BaseURL:      "http://localhost",
 	})
-	svc := service.NewAuthService(db, refreshRepo, passwordResetRepo, userRepo, emailService, txRepo, "test-secret", 15, 30)
+	svc := service.NewAuthService(db, refreshRepo, passwordResetRepo, userRepo, emailService, txRepo, "test-secret", 15, 30, nil, nil, nil,
+		models.RegistrationPolicy{}, nil, nil)
 	handler := NewAuthHandler(svc)
 
 	router := gin.New()
@@ -94,7 +96,7 @@ func seedUser(t *testing.T, db *sqlx.DB, p seedUserParams) {
 	t.Helper()
 	_, err := db.Exec(`
 		INSERT INTO staff (telegram_nick, first_name, last_name, email, role, status, password_hash)
-		VALUES ($1, $2, $3, $4, $5::user_role_type, $6::user_status_type, $7)`,
+		VALUES ($1, $2, $3, $4, $5, $6::user_status_type, $7)`,
 		p.TelegramNick, p.FirstName, p.LastName, p.Email, p.Role, p.Status, p.PassHash,
 	)
 	require.NoError(t, err)
@@ -239,14 +241,27 @@ func TestRegisterHandler(t *testing.T) {
 			},
 			wantStatus: http.StatusCreated,
 			checkBody: func(t *testing.T, body map[string]any) {
-				assert.NotEmpty(t, body["token"])
-				assert.NotEmpty(t, body["refresh_token"])
+				assert.Nil(t, body["token"], "no tokens before the email is verified")
+				assert.NotEmpty(t, body["message"])
 
 				user, ok := body["user"].(map[string]any)
 				require.True(t, ok, "user field missing or wrong type")
 				assert.Equal(t, "newuser@example.com", user["email"])
 				assert.Equal(t, "invited", user["status"])
+				assert.Equal(t, "user", user["role"])
+				assert.Nil(t, user["email_verified_at"])
+			},
+		},
+		{
+			name: "домен не разрешён",
+			body: map[string]string{
+				"first_name": "Outside",
+				"last_name":  "User",
+				"email":      "outside@other.test",
+				"password":   "password123",
 			},
+			wantStatus: http.StatusForbidden,
+			checkBody:  checkServiceErrorBody,
 		},
 		{
 			name: "email уже существует",
@@ -441,7 +456,8 @@ func setupRegisterServer(t *testing.T, db *sqlx.DB) *httptest.Server {
 		FromEmail:    "test@example.com",
 		BaseURL:      "http://localhost",
 	})
-	svc := service.NewAuthService(db, refreshRepo, passwordResetRepo, userRepo, emailService, txRepo, "test-secret", 15, 30)
+	svc := service.NewAuthService(db, refreshRepo, passwordResetRepo, userRepo, emailService, txRepo, "test-secret", 15, 30, nil, nil, nil,
+		models.RegistrationPolicy{Mode: models.RegistrationDomains, AllowedDomains: []string{"example.com"}}, nil, nil)
 	handler := NewAuthHandler(svc)
 
 	router := gin.New()
@@ -456,7 +472,7 @@ func seedStaff(t *testing.T, db *sqlx.DB, email string, passHash string) {
 	t.Helper()
 	_, err := db.Exec(`
 		INSERT INTO staff (first_name, last_name, email, password_hash, role, status)
-		VALUES ($1, $2, $3, $4, $5::user_role_type, $6::user_status_type)`,
+		VALUES ($1, $2, $3, $4, $5, $6::user_status_type)`,
 		"Existing", "User", email, passHash, "manager_1", "active",
 	)
 	require.NoError(t, err)
@@ -13,12 +13,16 @@ import (
 	apiService "github.com/yandex-development-1-team/go/internal/service/api"
 )
 
+// createdUserID passes the id of a new staff member to its audit snapshot
+const createdUserID = "created_user_id"
+
 type UsersHandler struct {
-	svc *apiService.UsersAdminService
+	svc   *apiService.UsersAdminService
+	perms *apiService.PermissionService
 }
 
-func NewUsersHandler(svc *apiService.UsersAdminService) *UsersHandler {
-	return &UsersHandler{svc: svc}
+func NewUsersHandler(svc *apiService.UsersAdminService, perms *apiService.PermissionService) *UsersHandler {
+	return &UsersHandler{svc: svc, perms: perms}
 }
 
 func (h *UsersHandler) Create(c *gin.Context) {
@@ -32,6 +36,7 @@ func (h *UsersHandler) Create(c *gin.Context) {
 		apierrors.WriteErrorGin(c, err)
 		return
 	}
+	c.Set(createdUserID, user.ID)
 	c.JSON(http.StatusCreated, toUserResponse(user))
 }
 
@@ -77,6 +82,104 @@ func (h *UsersHandler) UpdateStatus(c *gin.Context) {
 	})
 }
 
+// ResendInvite sends a new invite link to a staff member who has not accepted yet
+func (h *UsersHandler) ResendInvite(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+	user, err := h.svc.ResendInvite(c.Request.Context(), id)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+func (h *UsersHandler) RevokeInvite(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+	user, err := h.svc.RevokeInvite(c.Request.Context(), id)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+// Approve lets a self-registered staff member be given roles above user
+func (h *UsersHandler) Approve(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+	user, err := h.svc.Approve(c.Request.Context(), id)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+func (h *UsersHandler) GetPermissions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+	permissions, effective, err := h.perms.Get(c.Request.Context(), id)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserPermissionsResponse(permissions, effective))
+}
+
+// UpdatePermissions replaces the grants and denies of the staff member on top of their role
+func (h *UsersHandler) UpdatePermissions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректный id"})
+		return
+	}
+	var req dto.UserPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+	permissions, effective, err := h.perms.Update(c.Request.Context(), id, models.PermissionOverrides{
+		Granted: req.Granted,
+		Denied:  req.Denied,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserPermissionsResponse(permissions, effective))
+}
+
+func toUserPermissionsResponse(permissions *models.StaffPermissions, effective []string) dto.UserPermissionsResponse {
+	resp := dto.UserPermissionsResponse{
+		UserID:    permissions.StaffID,
+		Role:      permissions.Role,
+		Granted:   permissions.Granted,
+		Denied:    permissions.Denied,
+		Effective: effective,
+	}
+	if resp.Granted == nil {
+		resp.Granted = []string{}
+	}
+	if resp.Denied == nil {
+		resp.Denied = []string{}
+	}
+	return resp
+}
+
 type UserHandler struct {
 	svc *apiService.UserService
 }
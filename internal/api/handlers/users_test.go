@@ -4,6 +4,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/yandex-development-1-team/go/internal/models"
 	pgrepo "github.com/yandex-development-1-team/go/internal/repository/postgres"
 	svcapi "github.com/yandex-development-1-team/go/internal/service/api"
 )
@@ -44,8 +46,8 @@ func setupUsersAdminServer(t *testing.T) *httptest.Server {
 
 	staffRepo := pgrepo.NewStaffRepo(db)
 	refreshRepo := pgrepo.NewRefreshTokenRepo(db)
-	svc := svcapi.NewUsersAdminService(staffRepo, refreshRepo)
-	handler := NewUsersHandler(svc)
+	svc := svcapi.NewUsersAdminService(staffRepo, refreshRepo, pgrepo.NewRoleRepo(db), nil, nil, 72)
+	handler := NewUsersHandler(svc, nil)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -89,6 +91,9 @@ func setupUsersAdminServer(t *testing.T) *httptest.Server {
 		users.POST("", handler.Create)
 		users.PUT("/:id", handler.Update)
 		users.PUT("/:id/block", handler.UpdateStatus)
+		users.POST("/:id/invite", handler.ResendInvite)
+		users.DELETE("/:id/invite", handler.RevokeInvite)
+		users.POST("/:id/approve", handler.Approve)
 	}
 
 	server := httptest.NewServer(router)
@@ -357,8 +362,13 @@ func TestUsersAdmin_Block_RefreshTokensRevoked(t *testing.T) {
 	id := seedStaffAdmin(t, "revoke@example.com")
 
 	_, err := db.Exec(`
-    	INSERT INTO refresh_tokens (user_id, token, expires_at)
-    	VALUES ($1, 'test-refresh-token', NOW() + INTERVAL '7 days')`, id)
+    	WITH session AS (
+    		INSERT INTO auth_sessions (user_id, expires_at)
+    		VALUES ($1, NOW() + INTERVAL '7 days')
+    		RETURNING id
+    	)
+    	INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at)
+    	SELECT $1, id, 'test-refresh-token-hash', NOW() + INTERVAL '7 days' FROM session`, id)
 	require.NoError(t, err)
 
 	var count int
@@ -428,3 +438,155 @@ func doRequest(url, method string, body []byte, auth string) (*http.Response, er
 
 	return http.DefaultClient.Do(req)
 }
+
+// createInvitedStaff creates a staff member through the API and returns the response
+func createInvitedStaff(t *testing.T, serverURL, token, email string) map[string]any {
+	t.Helper()
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"first_name": "Пётр",
+		"last_name":  "Петров",
+		"email":      email,
+		"role":       "manager_1",
+	})
+	resp, err := doRequest(serverURL+"/users", http.MethodPost, b, authHeader(token))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func TestUsersAdmin_ResendInvite(t *testing.T) {
+	_, _ = db.Exec(`TRUNCATE TABLE staff CASCADE`)
+	server := setupUsersAdminServer(t)
+	token := generateAdminToken(t)
+
+	created := createInvitedStaff(t, server.URL, token, "resend@example.com")
+	assert.NotEmpty(t, created["invite_expires_at"])
+
+	resp, err := doRequest(fmt.Sprintf("%s/users/%v/invite", server.URL, created["id"]), http.MethodPost, nil, authHeader(token))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.NotEmpty(t, result["invite_token"])
+	assert.NotEqual(t, created["invite_token"], result["invite_token"], "the previous link stops working")
+
+	resp, err = doRequest(server.URL+"/users/99999/invite", http.MethodPost, nil, authHeader(token))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestUsersAdmin_RevokeInvite(t *testing.T) {
+	_, _ = db.Exec(`TRUNCATE TABLE staff CASCADE`)
+	server := setupUsersAdminServer(t)
+	token := generateAdminToken(t)
+
+	created := createInvitedStaff(t, server.URL, token, "revoke-invite@example.com")
+	url := fmt.Sprintf("%s/users/%v/invite", server.URL, created["id"])
+
+	resp, err := doRequest(url, http.MethodDelete, nil, authHeader(token))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Empty(t, result["invite_token"])
+	assert.Nil(t, result["invite_expires_at"])
+
+	resp, err = doRequest(url, http.MethodDelete, nil, authHeader(token))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestUsersAdmin_AcceptInvite(t *testing.T) {
+	_, _ = db.Exec(`TRUNCATE TABLE staff CASCADE`)
+	server := setupUsersAdminServer(t)
+	token := generateAdminToken(t)
+	authSvc := svcapi.NewAuthService(db, pgrepo.NewRefreshTokenRepo(db), nil, pgrepo.NewStaffRepo(db), nil, pgrepo.NewTxRepo(db),
+		"test-secret", 15, 30, nil, nil, nil, models.RegistrationPolicy{}, nil, nil)
+
+	created := createInvitedStaff(t, server.URL, token, "accept@example.com")
+	inviteToken, _ := created["invite_token"].(string)
+
+	result, err := authSvc.AcceptInvite(context.Background(), inviteToken, "password123", models.ClientInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, "active", result.User.Status)
+	assert.Empty(t, result.User.InviteToken)
+	assert.NotEmpty(t, result.Token)
+
+	_, err = authSvc.AcceptInvite(context.Background(), inviteToken, "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrInvalidInvite, "an invite is accepted once")
+
+	expired := createInvitedStaff(t, server.URL, token, "expired@example.com")
+	_, err = db.Exec(`UPDATE staff SET invite_expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, expired["id"])
+	require.NoError(t, err)
+	_, err = authSvc.AcceptInvite(context.Background(), expired["invite_token"].(string), "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrInvalidInvite)
+}
+
+func TestUsersAdmin_RegisterWithInvite(t *testing.T) {
+	_, _ = db.Exec(`TRUNCATE TABLE staff CASCADE`)
+	server := setupUsersAdminServer(t)
+	token := generateAdminToken(t)
+	authSvc := svcapi.NewAuthService(db, pgrepo.NewRefreshTokenRepo(db), nil, pgrepo.NewStaffRepo(db), nil, pgrepo.NewTxRepo(db),
+		"test-secret", 15, 30, nil, nil, nil, models.RegistrationPolicy{Mode: models.RegistrationInviteOnly}, nil, nil)
+
+	created := createInvitedStaff(t, server.URL, token, "register-invite@example.com")
+	inviteToken, _ := created["invite_token"].(string)
+
+	_, err := authSvc.Register(context.Background(), &models.UserAPI{
+		Name: "Чужой", LastName: "Адрес", Email: "someone-else@example.com", InviteToken: inviteToken,
+	}, "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrInvalidInvite, "the invite is bound to its email")
+
+	result, err := authSvc.Register(context.Background(), &models.UserAPI{
+		Name: "Павел", LastName: "Петров", Email: "Register-Invite@example.com", InviteToken: inviteToken,
+	}, "password123", models.ClientInfo{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.Equal(t, "active", result.User.Status)
+	assert.Equal(t, "Павел", result.User.Name)
+	assert.Equal(t, "manager_1", result.User.Role, "the role is the one given by the admin")
+}
+
+func TestUsersAdmin_Approve(t *testing.T) {
+	_, _ = db.Exec(`TRUNCATE TABLE staff CASCADE`)
+	server := setupUsersAdminServer(t)
+	token := generateAdminToken(t)
+
+	var id int64
+	err := db.Get(&id, `
+		INSERT INTO staff (first_name, last_name, email, password_hash, email_verified_at, approved_at)
+		VALUES ('Self', 'Registered', 'approve@example.com', '', NOW(), NULL)
+		RETURNING id`)
+	require.NoError(t, err)
+
+	update, _ := json.Marshal(map[string]any{"role": "manager_1"})
+	resp, err := doRequest(fmt.Sprintf("%s/users/%d", server.URL, id), http.MethodPut, update, authHeader(token))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "a role above user needs approval")
+
+	resp, err = doRequest(fmt.Sprintf("%s/users/%d/approve", server.URL, id), http.MethodPost, nil, authHeader(token))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.NotEmpty(t, result["approved_at"])
+
+	resp, err = doRequest(fmt.Sprintf("%s/users/%d", server.URL, id), http.MethodPut, update, authHeader(token))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
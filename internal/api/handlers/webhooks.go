@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yandex-development-1-team/go/internal/apierrors"
+	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/models"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+const defaultWebhookDeliveriesLimit = 20
+
+// createdWebhookID passes the id of a new subscription to its audit snapshot
+const createdWebhookID = "created_webhook_id"
+
+type WebhookHandler struct {
+	svc *service.WebhookService
+}
+
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	items := make([]dto.WebhookResponse, 0, len(subs))
+	for i := range subs {
+		items = append(items, toWebhookResponse(&subs[i], false))
+	}
+
+	c.JSON(http.StatusOK, dto.WebhookListResponse{Items: items})
+}
+
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	var id dto.WebhookID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	sub, err := h.svc.GetByID(c.Request.Context(), id.ID)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookResponse(sub, false))
+}
+
+// Create subscribes the endpoint. The response is the only place the secret is shown.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req dto.WebhookCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	sub, err := h.svc.Create(c.Request.Context(), &models.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+		IsActive:    isActive,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Set(createdWebhookID, sub.ID)
+	c.JSON(http.StatusCreated, toWebhookResponse(sub, true))
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var id dto.WebhookID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	var req dto.WebhookUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	sub, err := h.svc.Update(c.Request.Context(), id.ID, &models.WebhookSubscriptionUpdate{
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookResponse(sub, false))
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	var id dto.WebhookID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), id.ID); err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Deliveries returns the delivery log of the subscription, newest first
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	var id dto.WebhookID
+	if err := c.ShouldBindUri(&id); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Неверные параметры запроса"})
+		return
+	}
+
+	var query dto.WebhookDeliveryListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierrors.WriteErrorMessagesGin(c, http.StatusBadRequest, []string{"Некорректные данные"})
+		return
+	}
+
+	filter := models.WebhookDeliveryFilter{
+		SubscriptionID: id.ID,
+		Limit:          query.Limit,
+		Offset:         query.Offset,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultWebhookDeliveriesLimit
+	}
+	if query.Status != nil {
+		filter.Status = *query.Status
+	}
+
+	list, err := h.svc.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookDeliveryListResponse(list))
+}
+
+func toWebhookResponse(sub *models.WebhookSubscription, withSecret bool) dto.WebhookResponse {
+	resp := dto.WebhookResponse{
+		ID:          sub.ID,
+		URL:         sub.URL,
+		Events:      sub.Events,
+		Description: sub.Description,
+		IsActive:    sub.IsActive,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+	if resp.Events == nil {
+		resp.Events = []string{}
+	}
+	if withSecret {
+		resp.Secret = sub.Secret
+	}
+	return resp
+}
+
+func toWebhookDeliveryListResponse(list *models.WebhookDeliveryList) dto.WebhookDeliveryListResponse {
+	items := make([]dto.WebhookDeliveryItem, len(list.Items))
+	for i, d := range list.Items {
+		items[i] = dto.WebhookDeliveryItem{
+			ID:             d.ID,
+			Event:          d.Event,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			Payload:        d.Payload,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		}
+		if d.Status == models.WebhookDeliveryPending {
+			next := d.NextAttemptAt
+			items[i].NextAttemptAt = &next
+		}
+	}
+
+	return dto.WebhookDeliveryListResponse{
+		Items: items,
+		Pagination: dto.Pagination{
+			Total:  list.Total,
+			Limit:  list.Limit,
+			Offset: list.Offset,
+		},
+	}
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const auditMaxBodySize = 1 << 20
+
+// auditSecretFields are never stored from a creation response: they are
+// credentials shown to the client once
+var auditSecretFields = []string{"secret", "invite_token", "token", "key", "password"}
+
+// AuditSnapshot returns the current state of the entity the request changes,
+// in the form the API returns it.
+type AuditSnapshot func(c *gin.Context) (any, error)
+
+// AuditLog records successful mutating API calls to the audit log.
+type AuditLog struct {
+	repo repository.AuditLogRepository
+}
+
+func NewAuditLog(repo repository.AuditLogRepository) *AuditLog {
+	return &AuditLog{repo: repo}
+}
+
+// Track records the route call after the handler succeeds. snapshot loads the
+// entity before and after the change; for creation the response body without
+// its secret fields is the new state unless snapshot is given, for deletion
+// there is no state after. The JSON request body is
+// buffered, so snapshot may read it as well as the handler, unless it is
+// larger than auditMaxBodySize.
+func (a *AuditLog) Track(entity, action string, snapshot AuditSnapshot) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := bufferJSONBody(c)
+
+		var before []byte
+		if action != models.AuditActionCreate {
+			before = takeSnapshot(c, snapshot, body)
+		}
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		resetBody(c, body)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest {
+			return
+		}
+
+		var after []byte
+		switch action {
+		case models.AuditActionCreate:
+			if snapshot != nil {
+				after = takeSnapshot(c, snapshot, body)
+			} else {
+				after = redactSecrets(writer.body.Bytes())
+			}
+		case models.AuditActionDelete:
+		default:
+			after = takeSnapshot(c, snapshot, body)
+		}
+
+		entry := &models.AuditEntry{
+			ActorRole:  c.GetString("role"),
+			Entity:     entity,
+			EntityID:   auditEntityID(c, before, after),
+			Action:     action,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			StatusCode: status,
+			Before:     before,
+			After:      after,
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(int64); ok {
+				entry.ActorID = &id
+			}
+		}
+
+		diff, err := auditDiff(before, after)
+		if err != nil {
+			logger.Warn("audit: diff failed", zap.String("entity", entity), zap.Error(err))
+		}
+		entry.Diff = diff
+
+		// The change is already made: record it even if the client went away
+		if err := a.repo.Create(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			logger.Error("audit: record failed",
+				zap.String("entity", entity),
+				zap.String("entity_id", entry.EntityID),
+				zap.String("action", action),
+				zap.Error(err))
+		}
+	}
+}
+
+func takeSnapshot(c *gin.Context, snapshot AuditSnapshot, body []byte) []byte {
+	if snapshot == nil {
+		return nil
+	}
+
+	resetBody(c, body)
+	state, err := snapshot(c)
+	if err != nil || state == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		logger.Warn("audit: marshal snapshot failed", zap.Error(err))
+		return nil
+	}
+	return data
+}
+
+// redactSecrets drops the secret fields from a JSON object; anything else is
+// not stored
+func redactSecrets(body []byte) []byte {
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil
+	}
+	for _, key := range auditSecretFields {
+		delete(fields, key)
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// bufferJSONBody reads a JSON request body so that it can be read again.
+// Other bodies, e.g. file uploads, are left to the handler, as are JSON
+// bodies above auditMaxBodySize: the handler gets them in full and
+// snapshots go without.
+func bufferJSONBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+
+	original := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(original, auditMaxBodySize+1))
+	if err == nil && len(body) > auditMaxBodySize {
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), original), original}
+		return nil
+	}
+
+	_ = original.Close()
+	if err != nil {
+		logger.Warn("audit: read request body failed", zap.Error(err))
+	}
+	if body == nil {
+		body = []byte{}
+	}
+	return body
+}
+
+func resetBody(c *gin.Context, body []byte) {
+	if body != nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+}
+
+// auditEntityID takes the id from the route or, for creation, from the new state
+func auditEntityID(c *gin.Context, before, after []byte) string {
+	for _, param := range []string{"slug", "id", "role", "name"} {
+		if v := c.Param(param); v != "" {
+			return v
+		}
+	}
+
+	for _, state := range [][]byte{after, before} {
+		var fields map[string]any
+		if json.Unmarshal(state, &fields) != nil {
+			continue
+		}
+		for _, key := range []string{"id", "role", "slug", "name"} {
+			switch v := fields[key].(type) {
+			case float64:
+				return strconv.FormatInt(int64(v), 10)
+			case string:
+				if v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+type auditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// auditDiff returns the top-level fields that differ between two JSON objects
+func auditDiff(before, after []byte) ([]byte, error) {
+	if len(before) == 0 && len(after) == 0 {
+		return nil, nil
+	}
+
+	var b, a map[string]any
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil, err
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := make(map[string]auditChange)
+	for key, value := range a {
+		if old, ok := b[key]; !ok || !reflect.DeepEqual(old, value) {
+			diff[key] = auditChange{Before: b[key], After: value}
+		}
+	}
+	for key, value := range b {
+		if _, ok := a[key]; !ok {
+			diff[key] = auditChange{Before: value}
+		}
+	}
+
+	return json.Marshal(diff)
+}
+
+// auditResponseWriter keeps a copy of the response body for creation records
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.body.Len()+len(data) <= auditMaxBodySize {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	if w.body.Len()+len(s) <= auditMaxBodySize {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type fakeAuditRepo struct {
+	repository.AuditLogRepository
+	entries []*models.AuditEntry
+}
+
+func (r *fakeAuditRepo) Create(_ context.Context, entry *models.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type auditBox struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func newAuditRouter(repo *fakeAuditRepo, box *auditBox, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	audit := NewAuditLog(repo)
+
+	snapshot := func(c *gin.Context) (any, error) {
+		copied := *box
+		return copied, nil
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(7))
+		c.Set("role", "admin")
+	})
+	router.PUT("/boxes/:id", audit.Track("box", models.AuditActionUpdate, snapshot), func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		if status < http.StatusBadRequest {
+			box.Name = req.Name
+		}
+		c.JSON(status, box)
+	})
+	router.POST("/boxes", audit.Track("box", models.AuditActionCreate, nil), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, auditBox{ID: 42, Name: "new"})
+	})
+	return router
+}
+
+func TestAuditLog_TrackUpdate(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	box := &auditBox{ID: 1, Name: "old", Status: "active"}
+	router := newAuditRouter(repo, box, http.StatusOK)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/boxes/1", strings.NewReader(`{"name":"renamed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "renamed", box.Name, "handler must still read the body")
+	require.Len(t, repo.entries, 1)
+
+	entry := repo.entries[0]
+	require.NotNil(t, entry.ActorID)
+	assert.Equal(t, int64(7), *entry.ActorID)
+	assert.Equal(t, "admin", entry.ActorRole)
+	assert.Equal(t, "box", entry.Entity)
+	assert.Equal(t, "1", entry.EntityID)
+	assert.Equal(t, models.AuditActionUpdate, entry.Action)
+
+	var diff map[string]auditChange
+	require.NoError(t, json.Unmarshal(entry.Diff, &diff))
+	assert.Equal(t, map[string]auditChange{"name": {Before: "old", After: "renamed"}}, diff)
+}
+
+func TestAuditLog_TrackCreate(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	router := newAuditRouter(repo, &auditBox{}, http.StatusOK)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/boxes", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, repo.entries, 1)
+	assert.Equal(t, "42", repo.entries[0].EntityID)
+	assert.Nil(t, repo.entries[0].Before)
+	assert.JSONEq(t, `{"id":42,"name":"new","status":""}`, string(repo.entries[0].After))
+}
+
+func TestAuditLog_SkipsFailedRequests(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	router := newAuditRouter(repo, &auditBox{ID: 1}, http.StatusConflict)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/boxes/1", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, repo.entries)
+}
+
+func TestAuditLog_TrackCreateDropsSecrets(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	audit := NewAuditLog(repo)
+
+	router := gin.New()
+	router.POST("/webhooks", audit.Track("webhook", models.AuditActionCreate, nil), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": 5, "url": "https://example.com/hook", "secret": "s3cr3t", "invite_token": "t0k3n"})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, repo.entries, 1)
+	assert.Equal(t, "5", repo.entries[0].EntityID)
+	assert.JSONEq(t, `{"id":5,"url":"https://example.com/hook"}`, string(repo.entries[0].After))
+	assert.NotContains(t, string(repo.entries[0].Diff), "s3cr3t")
+}
+
+func TestAuditLog_PassesLargeBodyWhole(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	box := &auditBox{ID: 1}
+	router := newAuditRouter(repo, box, http.StatusOK)
+
+	name := strings.Repeat("x", auditMaxBodySize)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/boxes/1", strings.NewReader(`{"name":"`+name+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, name, box.Name, "the handler reads the body past the audit limit")
+	assert.Len(t, repo.entries, 1)
+}
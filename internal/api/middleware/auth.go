@@ -1,30 +1,40 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/apierrors"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
 	service "github.com/yandex-development-1-team/go/internal/service/api"
 )
 
+// APIKeyAuthenticator resolves the API key an external system presents
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, plain string) (*models.APIKey, error)
+}
+
 type Middleware struct {
-	client *sqlx.DB
+	permissions repository.PermissionCache
+	tokens      repository.TokenRevocation
+	apiKeys     APIKeyAuthenticator
 }
 
-func NewMiddlewareRepository(db *sqlx.DB) *Middleware {
-	return &Middleware{client: db}
+func NewMiddlewareRepository(permissions repository.PermissionCache, tokens repository.TokenRevocation, apiKeys APIKeyAuthenticator) *Middleware {
+	return &Middleware{permissions: permissions, tokens: tokens, apiKeys: apiKeys}
 }
 
-func (m *Middleware) Auth(jwtSecret []byte) gin.HandlerFunc {
+func (m *Middleware) Auth(keys *service.JWTKeys) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -44,16 +54,12 @@ func (m *Middleware) Auth(jwtSecret []byte) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := jwt.ParseWithClaims(
-			tokenString,
-			&service.AccessClaims{},
-			func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return jwtSecret, nil
-			},
-		)
+		if strings.HasPrefix(tokenString, service.APIKeyPrefix) {
+			m.authAPIKey(c, tokenString)
+			return
+		}
+
+		token, err := jwt.ParseWithClaims(tokenString, &service.AccessClaims{}, keys.Keyfunc)
 
 		if err != nil || !token.Valid {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrValidation})
@@ -66,6 +72,21 @@ func (m *Middleware) Auth(jwtSecret []byte) gin.HandlerFunc {
 			return
 		}
 
+		if m.tokens != nil {
+			revoked, err := m.tokens.IsRevoked(c.Request.Context(), claims.UserID, claims.ID, claims.SessionID, claims.Version)
+			if err != nil {
+				// Fail closed: while Redis is down a blocked user or a revoked
+				// token can not be told apart from a valid one
+				logger.Error("failed to check token revocation", zap.Int64("user_id", claims.UserID), zap.Error(err))
+				apierrors.WriteErrorGin(c, models.ErrAuthUnavailable)
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrUnauthorized})
+				return
+			}
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
 		c.Set("claims", claims)
@@ -74,6 +95,73 @@ func (m *Middleware) Auth(jwtSecret []byte) gin.HandlerFunc {
 	}
 }
 
+// authAPIKey lets an external system in with an API key instead of a JWT.
+// The key acts with its own role and has no staff account behind it.
+func (m *Middleware) authAPIKey(c *gin.Context, plain string) {
+	if m.apiKeys == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrUnauthorized})
+		return
+	}
+
+	key, err := m.apiKeys.Authenticate(c.Request.Context(), plain)
+	if err != nil {
+		if errors.Is(err, models.ErrUnauthorized) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrUnauthorized})
+			return
+		}
+		logger.Error("failed to authenticate api key", zap.Error(err))
+		apierrors.WriteErrorGin(c, err)
+		return
+	}
+
+	c.Set("role", models.APIKeyActorRole)
+	c.Set("api_key", key)
+
+	c.Next()
+}
+
+func apiKeyFromContext(c *gin.Context) (*models.APIKey, bool) {
+	key, ok := c.Value("api_key").(*models.APIKey)
+	return key, ok
+}
+
+// RequireStaff keeps API keys out of the routes that manage a staff
+// member's own account
+func RequireStaff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := apiKeyFromContext(c); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireMFA keeps users whose role requires a second factor out of the API
+// until they enroll one; the enrollment routes are registered without it
+func RequireMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := apiKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		claims, ok := c.Value("claims").(*service.AccessClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": models.ErrUnauthorized})
+			return
+		}
+
+		if claims.MFAPending {
+			apierrors.WriteErrorGin(c, models.ErrMFAEnrollmentRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
@@ -94,7 +182,7 @@ func RequireAdmin() gin.HandlerFunc {
 	}
 }
 
-func RequireManagersOrAdmin() gin.HandlerFunc {
+func (m *Middleware) RequireManagersOrAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
@@ -103,13 +191,13 @@ func RequireManagersOrAdmin() gin.HandlerFunc {
 			return
 		}
 
-		if role == service.RoleUser {
+		if _, ok := apiKeyFromContext(c); ok || role == service.RoleUser {
 			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
 			c.Abort()
 			return
 		}
 
-		err := validateRoleFromRequest(role)
+		err := m.validateRoleFromRequest(c.Request.Context(), role)
 		if err != nil {
 			logger.Error("failed to validate role", zap.Error(err))
 			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
@@ -124,8 +212,6 @@ func RequireManagersOrAdmin() gin.HandlerFunc {
 func (m *Middleware) RoleVerification(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
-		var permissionsForRole pq.StringArray
-		const getRolePermissionsQuery = `SELECT permissions FROM role_permissions WHERE role = $1`
 
 		role, exists := c.Get("role")
 		if !exists {
@@ -134,7 +220,18 @@ func (m *Middleware) RoleVerification(permission string) gin.HandlerFunc {
 			return
 		}
 
-		err := validateRoleFromRequest(role)
+		// An API key is granted exactly its scopes
+		if key, ok := apiKeyFromContext(c); ok {
+			if key.HasScope(permission) {
+				c.Next()
+				return
+			}
+			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
+			c.Abort()
+			return
+		}
+
+		err := m.validateRoleFromRequest(c.Request.Context(), role)
 		if err != nil {
 			logger.Error("failed to validate role", zap.Error(err))
 			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
@@ -153,19 +250,27 @@ func (m *Middleware) RoleVerification(permission string) gin.HandlerFunc {
 			return
 		}
 
-		err = m.client.QueryRowContext(ctx, getRolePermissionsQuery, role).Scan(&permissionsForRole)
+		permissionsForRole, err := m.permissions.Permissions(ctx, role.(string))
 		if err != nil {
-			logger.Error("failed to get permissions for role from db", zap.Error(err))
+			logger.Error("failed to get permissions for role", zap.Error(err))
 			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
 			c.Abort()
 			return
 		}
 
-		for _, permissionForRole := range permissionsForRole {
-			if permissionForRole == permission {
-				c.Next()
-				return
-			}
+		userID, _ := c.Get("user_id")
+		staffID, _ := userID.(int64)
+		overrides, err := m.permissions.Overrides(ctx, staffID)
+		if err != nil {
+			logger.Error("failed to get permission overrides", zap.Int64("user_id", staffID), zap.Error(err))
+			c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
+			c.Abort()
+			return
+		}
+
+		if slices.Contains(overrides.Apply(permissionsForRole), permission) {
+			c.Next()
+			return
 		}
 
 		c.JSON(http.StatusForbidden, gin.H{"error": models.ErrForbidden})
@@ -173,17 +278,16 @@ func (m *Middleware) RoleVerification(permission string) gin.HandlerFunc {
 	}
 }
 
-func validateRoleFromRequest(roleReq any) error {
-	exist := false
-
-	for _, role := range service.Roles {
-		if role == roleReq {
-			exist = true
-		}
+// validateRoleFromRequest checks that the role from the token still exists:
+// a deleted role loses its access even before the token expires
+func (m *Middleware) validateRoleFromRequest(ctx context.Context, roleReq any) error {
+	role, ok := roleReq.(string)
+	if !ok || role == "" {
+		return fmt.Errorf("wrong role")
 	}
 
-	if !exist {
-		return fmt.Errorf("wrong role")
+	if _, err := m.permissions.Permissions(ctx, role); err != nil {
+		return fmt.Errorf("role %q: %w", role, err)
 	}
 
 	return nil
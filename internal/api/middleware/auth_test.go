@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
+)
+
+type fakeAPIKeys map[string]*models.APIKey
+
+func (f fakeAPIKeys) Authenticate(_ context.Context, plain string) (*models.APIKey, error) {
+	key, ok := f[plain]
+	if !ok {
+		return nil, models.ErrUnauthorized
+	}
+	return key, nil
+}
+
+type fakePermissionCache struct {
+	repository.PermissionCache
+}
+
+func (fakePermissionCache) Permissions(_ context.Context, _ string) ([]string, error) {
+	return nil, models.ErrRoleNotFound
+}
+
+func newAPIKeyRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	keys, err := service.NewJWTKeys("secret", config.SigningConfig{})
+	require.NoError(t, err)
+	m := NewMiddlewareRepository(fakePermissionCache{}, nil, fakeAPIKeys{
+		"ak_bookings": {ID: 1, Scopes: []string{models.PermBookingsView}},
+	})
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	api := router.Group("/", m.Auth(keys), RequireMFA())
+	api.GET("/bookings", m.RoleVerification(models.PermBookingsView), ok)
+	api.DELETE("/bookings", m.RoleVerification(models.PermBookingsDelete), ok)
+	api.GET("/boxes", m.RequireManagersOrAdmin(), ok)
+	api.GET("/users", RequireAdmin(), ok)
+	api.GET("/sessions", RequireStaff(), ok)
+	return router
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	router := newAPIKeyRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{"scope granted", http.MethodGet, "/bookings", "ak_bookings", http.StatusOK},
+		{"scope missing", http.MethodDelete, "/bookings", "ak_bookings", http.StatusForbidden},
+		{"role routes are closed", http.MethodGet, "/boxes", "ak_bookings", http.StatusForbidden},
+		{"admin routes are closed", http.MethodGet, "/users", "ak_bookings", http.StatusForbidden},
+		{"staff routes are closed", http.MethodGet, "/sessions", "ak_bookings", http.StatusForbidden},
+		{"unknown key", http.MethodGet, "/bookings", "ak_unknown", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+type fakeTokenRevocation struct {
+	repository.TokenRevocation
+	revoked bool
+	err     error
+}
+
+func (f fakeTokenRevocation) IsRevoked(context.Context, int64, string, int64, int64) (bool, error) {
+	return f.revoked, f.err
+}
+
+func TestAuth_TokenRevocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys, err := service.NewJWTKeys("secret", config.SigningConfig{})
+	require.NoError(t, err)
+
+	token, err := keys.Sign(service.AccessClaims{
+		UserID: 1,
+		Role:   service.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		tokens fakeTokenRevocation
+		want   int
+	}{
+		{"valid token", fakeTokenRevocation{}, http.StatusOK},
+		{"revoked token", fakeTokenRevocation{revoked: true}, http.StatusUnauthorized},
+		{"revocation store is down", fakeTokenRevocation{err: errors.New("connection refused")}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddlewareRepository(fakePermissionCache{}, tt.tokens, nil)
+			router := gin.New()
+			router.GET("/me", m.Auth(keys), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
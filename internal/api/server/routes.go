@@ -2,34 +2,51 @@ package server
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/jmoiron/sqlx"
 
 	"github.com/yandex-development-1-team/go/internal/api/handlers"
 	"github.com/yandex-development-1-team/go/internal/api/middleware"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	service "github.com/yandex-development-1-team/go/internal/service/api"
 )
 
-func SetupRoutes(client *sqlx.DB, router *gin.Engine, jwtSecret []byte, authHandler *handlers.AuthHandler, boxHandler *handlers.BoxHandler, specProjHandler *handlers.SpecialProjectHandler, settingsHandler *handlers.SettingsHandler, analyticsHandler *handlers.AnalyticsHandler, recPageHandler *handlers.ResourcePageHandler, userHandler *handlers.UserHandler, fileHandler *handlers.FileHandler, applicationHandler *handlers.ApplicationHandler, usersHandler *handlers.UsersHandler, bookingHandler *handlers.BookingHandler, specPath string) {
-	middlewareRepo := middleware.NewMiddlewareRepository(client)
+func SetupRoutes(permissions repository.PermissionCache, tokens repository.TokenRevocation, apiKeys middleware.APIKeyAuthenticator, router *gin.Engine, jwtKeys *service.JWTKeys, authHandler *handlers.AuthHandler, boxHandler *handlers.BoxHandler, specProjHandler *handlers.SpecialProjectHandler, settingsHandler *handlers.SettingsHandler, analyticsHandler *handlers.AnalyticsHandler, recPageHandler *handlers.ResourcePageHandler, userHandler *handlers.UserHandler, fileHandler *handlers.FileHandler, applicationHandler *handlers.ApplicationHandler, usersHandler *handlers.UsersHandler, bookingHandler *handlers.BookingHandler, webhookHandler *handlers.WebhookHandler, campaignHandler *handlers.CampaignHandler, auditHandler *handlers.AuditHandler, roleHandler *handlers.RoleHandler, mfaHandler *handlers.MFAHandler, apiKeyHandler *handlers.APIKeyHandler, staffTelegramHandler *handlers.StaffTelegramHandler, audit *middleware.AuditLog, specPath string) {
+	middlewareRepo := middleware.NewMiddlewareRepository(permissions, tokens, apiKeys)
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
 	apiV1 := router.Group("/api/v1")
 	{
 		setupAuthRoutes(apiV1, authHandler)
 		setupDocsRoutes(apiV1, specPath)
 
-		protected := apiV1.Group("/")
-		protected.Use(middlewareRepo.Auth(jwtSecret))
+		authenticated := apiV1.Group("/")
+		authenticated.Use(middlewareRepo.Auth(jwtKeys))
 		{
-			setupBoxRoutes(protected, boxHandler, middlewareRepo)
-			setupSpecialProjectRoutes(protected, specProjHandler, middlewareRepo)
-			setupSettingsRoutes(protected, settingsHandler)
+			staff := authenticated.Group("/")
+			staff.Use(middleware.RequireStaff())
+			setupSessionRoutes(staff, authHandler)
+			setupMFARoutes(staff, mfaHandler)
+		}
+
+		protected := authenticated.Group("/")
+		protected.Use(middleware.RequireMFA())
+		{
+			setupBoxRoutes(protected, boxHandler, middlewareRepo, audit)
+			setupSpecialProjectRoutes(protected, specProjHandler, middlewareRepo, audit)
+			setupSettingsRoutes(protected, settingsHandler, middlewareRepo, audit)
+			setupRoleRoutes(protected, roleHandler, audit)
+			setupWebhookRoutes(protected, webhookHandler, audit)
+			setupCampaignRoutes(protected, campaignHandler, middlewareRepo, audit)
+			setupAPIKeyRoutes(protected, apiKeyHandler, audit)
 			setupAnalyticsRoutes(protected, analyticsHandler, middlewareRepo)
 			setupUserRoutes(protected, userHandler)
-			setupResourcesRoutes(protected, recPageHandler)
-			setupFileRoutes(protected, fileHandler)
-			setupUsersAdminRoutes(protected, usersHandler)
-			setupApplicationRoutes(protected, applicationHandler, middlewareRepo)
-			setupBookingRoutes(protected, bookingHandler, middlewareRepo)
-			setupDashboardRoutes(protected, userHandler)
+			setupResourcesRoutes(protected, recPageHandler, middlewareRepo, audit)
+			setupFileRoutes(protected, fileHandler, middlewareRepo)
+			setupUsersAdminRoutes(protected, usersHandler, staffTelegramHandler, audit)
+			setupApplicationRoutes(protected, applicationHandler, middlewareRepo, audit)
+			setupBookingRoutes(protected, bookingHandler, middlewareRepo, audit)
+			setupDashboardRoutes(protected, userHandler, middlewareRepo)
+			setupAuditRoutes(protected, auditHandler)
+			setupLockoutRoutes(protected, authHandler)
 		}
 		public := apiV1.Group("/public")
 		public.GET("/resources/:slug", recPageHandler.GetPublicBySlug)
@@ -41,7 +58,11 @@ func setupAuthRoutes(rg *gin.RouterGroup, h *handlers.AuthHandler) {
 	auth := rg.Group("/auth")
 	{
 		auth.POST("/login", h.HandleLogin)
+		auth.POST("/mfa/verify", h.HandleMFAVerify)
 		auth.POST("/register", h.RegisterHandler)
+		auth.POST("/accept-invite", h.HandleAcceptInvite)
+		auth.POST("/verify-email", h.HandleVerifyEmail)
+		auth.POST("/verify-email/resend", h.HandleResendVerification)
 		auth.POST("/refresh", h.HandleRefresh)
 		auth.POST("/logout", h.HandleLogout)
 		auth.POST("/forgot-password", h.HandleForgotPassword)
@@ -49,14 +70,41 @@ func setupAuthRoutes(rg *gin.RouterGroup, h *handlers.AuthHandler) {
 	}
 }
 
-func setupSpecialProjectRoutes(rg *gin.RouterGroup, h *handlers.SpecialProjectHandler, middlewareRepo *middleware.Middleware) {
+func setupLockoutRoutes(rg *gin.RouterGroup, h *handlers.AuthHandler) {
+	lockouts := rg.Group("/auth/lockouts")
+	lockouts.Use(middleware.RequireAdmin())
+	{
+		lockouts.DELETE("", h.ClearLockout)
+	}
+}
+
+func setupSessionRoutes(rg *gin.RouterGroup, h *handlers.AuthHandler) {
+	sessions := rg.Group("/auth/sessions")
+	{
+		sessions.GET("", h.Sessions)
+		sessions.DELETE("/:id", h.RevokeSession)
+	}
+}
+
+func setupMFARoutes(rg *gin.RouterGroup, h *handlers.MFAHandler) {
+	mfa := rg.Group("/auth/mfa")
+	{
+		mfa.GET("", h.Status)
+		mfa.POST("/enroll", h.Enroll)
+		mfa.POST("/confirm", h.Confirm)
+		mfa.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+		mfa.POST("/disable", h.Disable)
+	}
+}
+
+func setupSpecialProjectRoutes(rg *gin.RouterGroup, h *handlers.SpecialProjectHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	sp := rg.Group("/special-projects")
 	{
 		sp.GET("/", middlewareRepo.RoleVerification(models.PermSpecProjectView), h.ListSpecialProjects)
-		sp.POST("/", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), h.CreateSpecialProject)
+		sp.POST("/", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), audit.Track("special_project", models.AuditActionCreate, nil), h.CreateSpecialProject)
 		sp.GET("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectView), h.GetSpecialProjectByID)
-		sp.PUT("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), h.UpdateSpecialProject)
-		sp.DELETE("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectDelete), h.DeleteSpecialProject)
+		sp.PUT("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), audit.Track("special_project", models.AuditActionUpdate, h.AuditSnapshot), h.UpdateSpecialProject)
+		sp.DELETE("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectDelete), audit.Track("special_project", models.AuditActionDelete, h.AuditSnapshot), h.DeleteSpecialProject)
 	}
 }
 
@@ -67,26 +115,79 @@ func setupAnalyticsRoutes(rg *gin.RouterGroup, h *handlers.AnalyticsHandler, mid
 	}
 }
 
-func setupBoxRoutes(rg *gin.RouterGroup, boxHandler *handlers.BoxHandler, middlewareRepo *middleware.Middleware) {
+func setupBoxRoutes(rg *gin.RouterGroup, boxHandler *handlers.BoxHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	boxes := rg.Group("/boxes")
 	{
-		boxes.GET("/", middleware.RequireManagersOrAdmin(), boxHandler.List)
-		boxes.POST("/", middlewareRepo.RoleVerification(models.PermBoxesCreate), boxHandler.Create)
-		boxes.GET("/:id", middleware.RequireManagersOrAdmin(), boxHandler.GetByID)
-		boxes.PUT("/:id", middlewareRepo.RoleVerification(models.PermBoxesEdit), boxHandler.Update)
-		boxes.DELETE("/:id", middlewareRepo.RoleVerification(models.PermBoxesDelete), boxHandler.Delete)
-		boxes.POST("/:id/image", middlewareRepo.RoleVerification(models.PermBoxesEdit), boxHandler.UploadImage)
-		boxes.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermBoxesEdit), boxHandler.UpdateStatus)
+		boxes.GET("/", middlewareRepo.RequireManagersOrAdmin(), boxHandler.List)
+		boxes.POST("/", middlewareRepo.RoleVerification(models.PermBoxesCreate), audit.Track("box", models.AuditActionCreate, nil), boxHandler.Create)
+		boxes.GET("/:id", middlewareRepo.RequireManagersOrAdmin(), boxHandler.GetByID)
+		boxes.PUT("/:id", middlewareRepo.RoleVerification(models.PermBoxesEdit), audit.Track("box", models.AuditActionUpdate, boxHandler.AuditSnapshot), boxHandler.Update)
+		boxes.DELETE("/:id", middlewareRepo.RoleVerification(models.PermBoxesDelete), audit.Track("box", models.AuditActionDelete, boxHandler.AuditSnapshot), boxHandler.Delete)
+		boxes.POST("/:id/image", middlewareRepo.RoleVerification(models.PermBoxesEdit), audit.Track("box", models.AuditActionUpload, boxHandler.AuditSnapshot), boxHandler.UploadImage)
+		boxes.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermBoxesEdit), audit.Track("box", models.AuditActionUpdateStatus, boxHandler.AuditSnapshot), boxHandler.UpdateStatus)
 	}
 }
 
-func setupSettingsRoutes(rg *gin.RouterGroup, settingsHandler *handlers.SettingsHandler) {
+func setupSettingsRoutes(rg *gin.RouterGroup, settingsHandler *handlers.SettingsHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	settings := rg.Group("/settings")
 	{
-		settings.GET("/messages", middleware.RequireManagersOrAdmin(), settingsHandler.Get)
-		settings.PUT("/messages", middleware.RequireAdmin(), settingsHandler.Put)
-		settings.GET("/permissions/:role", middleware.RequireManagersOrAdmin(), settingsHandler.GetPermissions)
-		settings.POST("/permissions", middleware.RequireAdmin(), settingsHandler.Post)
+		settings.GET("/messages", middlewareRepo.RequireManagersOrAdmin(), settingsHandler.Get)
+		settings.PUT("/messages", middleware.RequireAdmin(), audit.Track("settings_messages", models.AuditActionUpdate, settingsHandler.AuditMessagesSnapshot), settingsHandler.Put)
+		settings.POST("/messages/preview", middleware.RequireAdmin(), settingsHandler.Preview)
+		settings.GET("/permissions/:role", middlewareRepo.RequireManagersOrAdmin(), settingsHandler.GetPermissions)
+		settings.POST("/permissions", middleware.RequireAdmin(), audit.Track("settings_permissions", models.AuditActionUpdate, settingsHandler.AuditPermissionsSnapshot), settingsHandler.Post)
+	}
+}
+
+func setupRoleRoutes(rg *gin.RouterGroup, h *handlers.RoleHandler, audit *middleware.AuditLog) {
+	roles := rg.Group("/settings/roles")
+	roles.Use(middleware.RequireAdmin())
+	{
+		roles.GET("", h.List)
+		roles.POST("", audit.Track("role", models.AuditActionCreate, nil), h.Create)
+		roles.GET("/:name", h.Get)
+		roles.PUT("/:name", audit.Track("role", models.AuditActionUpdate, h.AuditSnapshot), h.Update)
+		roles.DELETE("/:name", audit.Track("role", models.AuditActionDelete, h.AuditSnapshot), h.Delete)
+	}
+}
+
+func setupWebhookRoutes(rg *gin.RouterGroup, h *handlers.WebhookHandler, audit *middleware.AuditLog) {
+	webhooks := rg.Group("/settings/webhooks")
+	webhooks.Use(middleware.RequireAdmin())
+	{
+		webhooks.GET("", h.List)
+		webhooks.POST("", audit.Track("webhook", models.AuditActionCreate, h.AuditSnapshot), h.Create)
+		webhooks.GET("/:id", h.GetByID)
+		webhooks.PUT("/:id", audit.Track("webhook", models.AuditActionUpdate, h.AuditSnapshot), h.Update)
+		webhooks.DELETE("/:id", audit.Track("webhook", models.AuditActionDelete, h.AuditSnapshot), h.Delete)
+		webhooks.GET("/:id/deliveries", h.Deliveries)
+	}
+}
+
+// setupCampaignRoutes serves the broadcasts to the bot users: managers see
+// them and their progress, only the admin writes and sends them
+func setupCampaignRoutes(rg *gin.RouterGroup, h *handlers.CampaignHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
+	campaigns := rg.Group("/campaigns")
+	{
+		campaigns.GET("", middlewareRepo.RequireManagersOrAdmin(), h.List)
+		campaigns.POST("", middleware.RequireAdmin(), audit.Track("campaign", models.AuditActionCreate, nil), h.Create)
+		campaigns.POST("/audience", middlewareRepo.RequireManagersOrAdmin(), h.Audience)
+		campaigns.GET("/:id", middlewareRepo.RequireManagersOrAdmin(), h.GetByID)
+		campaigns.PUT("/:id", middleware.RequireAdmin(), audit.Track("campaign", models.AuditActionUpdate, h.AuditSnapshot), h.Update)
+		campaigns.DELETE("/:id", middleware.RequireAdmin(), audit.Track("campaign", models.AuditActionDelete, h.AuditSnapshot), h.Delete)
+		campaigns.POST("/:id/start", middleware.RequireAdmin(), audit.Track("campaign", models.AuditActionStart, h.AuditSnapshot), h.Start)
+		campaigns.POST("/:id/cancel", middleware.RequireAdmin(), audit.Track("campaign", models.AuditActionCancel, h.AuditSnapshot), h.Cancel)
+		campaigns.GET("/:id/recipients", middlewareRepo.RequireManagersOrAdmin(), h.Recipients)
+	}
+}
+
+func setupAPIKeyRoutes(rg *gin.RouterGroup, h *handlers.APIKeyHandler, audit *middleware.AuditLog) {
+	keys := rg.Group("/settings/api-keys")
+	keys.Use(middleware.RequireAdmin())
+	{
+		keys.GET("", h.List)
+		keys.POST("", audit.Track("api_key", models.AuditActionCreate, h.AuditSnapshot), h.Create)
+		keys.DELETE("/:id", audit.Track("api_key", models.AuditActionRevoke, h.AuditSnapshot), h.Revoke)
 	}
 }
 
@@ -98,61 +199,75 @@ func setupUserRoutes(rg *gin.RouterGroup, h *handlers.UserHandler) {
 	}
 }
 
-func setupResourcesRoutes(rg *gin.RouterGroup, h *handlers.ResourcePageHandler) {
+func setupResourcesRoutes(rg *gin.RouterGroup, h *handlers.ResourcePageHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	resources := rg.Group("/resources")
 	{
-		resources.GET("/", middleware.RequireManagersOrAdmin(), h.GetAll)
-		resources.GET("/:slug", middleware.RequireManagersOrAdmin(), h.GetBySlug)
-		resources.PUT("/:slug", middleware.RequireManagersOrAdmin(), h.Update)
-		resources.PUT("/:slug/file", middleware.RequireManagersOrAdmin(), h.UploadFile)
-		resources.DELETE("/:slug/:id", middleware.RequireManagersOrAdmin(), h.DeleteLink)
-		resources.DELETE("/:slug", middleware.RequireManagersOrAdmin(), h.Delete)
+		resources.GET("/", middlewareRepo.RequireManagersOrAdmin(), h.GetAll)
+		resources.GET("/:slug", middlewareRepo.RequireManagersOrAdmin(), h.GetBySlug)
+		resources.PUT("/:slug", middlewareRepo.RequireManagersOrAdmin(), audit.Track("resource_page", models.AuditActionUpdate, h.AuditSnapshot), h.Update)
+		resources.PUT("/:slug/file", middlewareRepo.RequireManagersOrAdmin(), audit.Track("resource_page", models.AuditActionUpload, h.AuditSnapshot), h.UploadFile)
+		resources.DELETE("/:slug/:id", middlewareRepo.RequireManagersOrAdmin(), audit.Track("resource_page", models.AuditActionUpdate, h.AuditSnapshot), h.DeleteLink)
+		resources.DELETE("/:slug", middlewareRepo.RequireManagersOrAdmin(), audit.Track("resource_page", models.AuditActionDelete, h.AuditSnapshot), h.Delete)
 	}
 }
 
-func setupFileRoutes(rg *gin.RouterGroup, h *handlers.FileHandler) {
+func setupFileRoutes(rg *gin.RouterGroup, h *handlers.FileHandler, middlewareRepo *middleware.Middleware) {
 	files := rg.Group("/files")
 	{
-		files.POST("/upload", middleware.RequireManagersOrAdmin(), h.Upload)
+		files.POST("/upload", middlewareRepo.RequireManagersOrAdmin(), h.Upload)
 	}
 }
 
-func setupApplicationRoutes(rg *gin.RouterGroup, h *handlers.ApplicationHandler, middlewareRepo *middleware.Middleware) {
+func setupApplicationRoutes(rg *gin.RouterGroup, h *handlers.ApplicationHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	applications := rg.Group("/applications")
 	{
 		applications.GET("/", middlewareRepo.RoleVerification(models.PermSpecProjectView), h.ApplicationsList)
-		applications.POST("/", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), h.Create)
+		applications.POST("/", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), audit.Track("application", models.AuditActionCreate, nil), h.Create)
 		applications.GET("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectView), h.GetByID)
-		applications.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), h.UpdateApplicationStatus)
-		applications.DELETE("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectDelete), h.DeleteApplication)
+		applications.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermSpecProjectEdit), audit.Track("application", models.AuditActionUpdateStatus, h.AuditSnapshot), h.UpdateApplicationStatus)
+		applications.DELETE("/:id", middlewareRepo.RoleVerification(models.PermSpecProjectDelete), audit.Track("application", models.AuditActionDelete, h.AuditSnapshot), h.DeleteApplication)
 
 	}
 }
 
-func setupBookingRoutes(rg *gin.RouterGroup, h *handlers.BookingHandler, middlewareRepo *middleware.Middleware) {
+func setupBookingRoutes(rg *gin.RouterGroup, h *handlers.BookingHandler, middlewareRepo *middleware.Middleware, audit *middleware.AuditLog) {
 	bookings := rg.Group("/bookings")
 	{
 		bookings.GET("/", middlewareRepo.RoleVerification(models.PermBookingsView), h.BookingsList)
 		bookings.GET("/:id", middlewareRepo.RoleVerification(models.PermBookingsView), h.BookingsById)
-		bookings.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermBookingsEdit), h.UpdateBookingStatus)
-		bookings.DELETE("/:id", middlewareRepo.RoleVerification(models.PermBookingsDelete), h.DeleteBooking)
+		bookings.PUT("/:id/status", middlewareRepo.RoleVerification(models.PermBookingsEdit), audit.Track("booking", models.AuditActionUpdateStatus, h.AuditSnapshot), h.UpdateBookingStatus)
+		bookings.DELETE("/:id", middlewareRepo.RoleVerification(models.PermBookingsDelete), audit.Track("booking", models.AuditActionDelete, h.AuditSnapshot), h.DeleteBooking)
 	}
 }
 
-func setupUsersAdminRoutes(rg *gin.RouterGroup, h *handlers.UsersHandler) {
+func setupUsersAdminRoutes(rg *gin.RouterGroup, h *handlers.UsersHandler, telegram *handlers.StaffTelegramHandler, audit *middleware.AuditLog) {
 	users := rg.Group("/users")
 	users.Use(middleware.RequireAdmin())
 	{
-		users.POST("/", h.Create)
-		users.PUT("/:id", h.Update)
-		users.PUT("/:id/status", h.UpdateStatus)
+		users.POST("/", audit.Track("user", models.AuditActionCreate, h.AuditSnapshot), h.Create)
+		users.PUT("/:id", audit.Track("user", models.AuditActionUpdate, h.AuditSnapshot), h.Update)
+		users.PUT("/:id/status", audit.Track("user", models.AuditActionUpdateStatus, h.AuditSnapshot), h.UpdateStatus)
+		users.POST("/:id/invite", audit.Track("user", models.AuditActionResendInvite, h.AuditSnapshot), h.ResendInvite)
+		users.DELETE("/:id/invite", audit.Track("user", models.AuditActionRevokeInvite, h.AuditSnapshot), h.RevokeInvite)
+		users.POST("/:id/approve", audit.Track("user", models.AuditActionApprove, h.AuditSnapshot), h.Approve)
+		users.GET("/:id/permissions", h.GetPermissions)
+		users.PUT("/:id/permissions", audit.Track("user_permissions", models.AuditActionUpdate, h.AuditPermissionsSnapshot), h.UpdatePermissions)
+		users.POST("/:id/telegram-link", audit.Track("user", models.AuditActionLinkTelegram, h.AuditSnapshot), telegram.CreateLinkCode)
+		users.DELETE("/:id/telegram-link", audit.Track("user", models.AuditActionUnlinkTelegram, h.AuditSnapshot), telegram.Unlink)
+	}
+}
+
+func setupAuditRoutes(rg *gin.RouterGroup, h *handlers.AuditHandler) {
+	audit := rg.Group("/audit")
+	{
+		audit.GET("", middleware.RequireAdmin(), h.List)
 	}
 }
 
-func setupDashboardRoutes(rg *gin.RouterGroup, h *handlers.UserHandler) {
+func setupDashboardRoutes(rg *gin.RouterGroup, h *handlers.UserHandler, middlewareRepo *middleware.Middleware) {
 	dashboard := rg.Group("/dashboard")
 	{
-		dashboard.GET("", middleware.RequireManagersOrAdmin(), h.GetDashboard)
+		dashboard.GET("", middlewareRepo.RequireManagersOrAdmin(), h.GetDashboard)
 	}
 }
 
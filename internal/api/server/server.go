@@ -7,14 +7,16 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/jmoiron/sqlx"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/api/handlers"
 	"github.com/yandex-development-1-team/go/internal/api/middleware"
 	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/repository"
 	"github.com/yandex-development-1-team/go/internal/service"
 	apiService "github.com/yandex-development-1-team/go/internal/service/api"
@@ -29,12 +31,25 @@ type APIServices struct {
 	UserSvc           *apiService.UserService
 	FileService       *apiService.FileService
 	UsersAdmin        *apiService.UsersAdminService
+	PermissionSvc     *apiService.PermissionService
 	ApplicationRepo   repository.ApplicationRepository
-	MiddlewareRepo    *sqlx.DB
+	PermissionCache   repository.PermissionCache
+	TokenRevocation   repository.TokenRevocation
 	ApplicationSvc    *apiService.ApplicationsService
 	BookingSvc        *apiService.BookingsService
+	WebhookSvc        *apiService.WebhookService
+	CampaignSvc       *apiService.CampaignService
+	AuditSvc          *apiService.AuditService
+	RoleSvc           *apiService.RoleService
+	MFASvc            *apiService.MFAService
+	APIKeySvc         *apiService.APIKeyService
+	StaffTelegramSvc  *apiService.StaffTelegramService
+	AuditLog          *middleware.AuditLog
 }
 
+// telegramUpdatesBuffer matches the buffer of the polling updates channel
+const telegramUpdatesBuffer = 100
+
 type Server struct {
 	router      *gin.Engine
 	services    *APIServices
@@ -50,6 +65,13 @@ func New(cfg *config.Config, services *APIServices, authService *apiService.Auth
 	}
 
 	router := gin.New()
+	// The client IP keys the login limits and the security log: forwarded
+	// headers count only from the configured proxies or platform
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Error("invalid trusted proxies, forwarded headers are ignored", zap.Error(err))
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.TrustedPlatform = cfg.TrustedPlatform
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger())
 	router.Use(middleware.Metrics())
@@ -72,11 +94,27 @@ func (s *Server) RegisterRoutes(yandexFormToken string, specPath string) {
 	recPageHandler := handlers.NewResourcePageHandler(s.services.RecPageSvc)
 	userHandler := handlers.NewUserHandler(s.services.UserSvc)
 	fileHandler := handlers.NewFileHandler(s.services.FileService)
-	usersHandler := handlers.NewUsersHandler(s.services.UsersAdmin)
+	usersHandler := handlers.NewUsersHandler(s.services.UsersAdmin, s.services.PermissionSvc)
 	applicationHandler := handlers.NewApplicationHandler(s.services.ApplicationSvc, yandexFormToken)
 	bookingHamdler := handlers.NewBookingHandler(s.services.BookingSvc)
+	webhookHandler := handlers.NewWebhookHandler(s.services.WebhookSvc)
+	campaignHandler := handlers.NewCampaignHandler(s.services.CampaignSvc)
+	auditHandler := handlers.NewAuditHandler(s.services.AuditSvc)
+	roleHandler := handlers.NewRoleHandler(s.services.RoleSvc)
+	mfaHandler := handlers.NewMFAHandler(s.services.MFASvc)
+	apiKeyHandler := handlers.NewAPIKeyHandler(s.services.APIKeySvc)
+	staffTelegramHandler := handlers.NewStaffTelegramHandler(s.services.StaffTelegramSvc)
+
+	SetupRoutes(s.services.PermissionCache, s.services.TokenRevocation, s.services.APIKeySvc, s.router, s.authService.Keys(), authHandler, boxHandler, specProjHandler, settingsHandler, analyticsHandler, recPageHandler, userHandler, fileHandler, applicationHandler, usersHandler, bookingHamdler, webhookHandler, campaignHandler, auditHandler, roleHandler, mfaHandler, apiKeyHandler, staffTelegramHandler, s.services.AuditLog, specPath)
+}
 
-	SetupRoutes(s.services.MiddlewareRepo, s.router, s.authService.JwtSecret, authHandler, boxHandler, specProjHandler, settingsHandler, analyticsHandler, recPageHandler, userHandler, fileHandler, applicationHandler, usersHandler, bookingHamdler, specPath)
+// RegisterTelegramWebhook serves the bot webhook and returns the channel of
+// received updates. It is registered only in webhook mode, outside the API auth
+// middleware: Telegram proves itself with the secret token instead.
+func (s *Server) RegisterTelegramWebhook(webhook config.TelegramWebhook) tgbotapi.UpdatesChannel {
+	h := handlers.NewTelegramWebhookHandler(webhook.SecretToken, telegramUpdatesBuffer)
+	s.router.POST(webhook.Path, h.Receive)
+	return h.Updates()
 }
 
 func (s *Server) Run(cfg *config.Config) error {
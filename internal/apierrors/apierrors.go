@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -40,6 +41,35 @@ var errMappings = []errMapping{
 	{models.ErrBoxSolutionNotFound, http.StatusNotFound, "Коробочное решение не найдено"},
 	{models.ErrInvalidEmail, http.StatusBadRequest, "Email адрес недействительный"},
 	{models.ErrPhoneNumberAlreadyExist, http.StatusConflict, "Указанный номер телефона уже занят"},
+	{models.ErrWebhookNotFound, http.StatusNotFound, "Подписка на вебхуки не найдена"},
+	{models.ErrInvalidWebhookURL, http.StatusBadRequest, "Адрес вебхука должен быть абсолютным http(s) URL"},
+	{models.ErrUnknownWebhookEvent, http.StatusBadRequest, "Неизвестный тип события"},
+	{models.ErrUnknownPermission, http.StatusBadRequest, "Неизвестное право доступа"},
+	{models.ErrPermissionConflict, http.StatusBadRequest, "Право не может быть одновременно выдано и запрещено"},
+	{models.ErrRoleNotFound, http.StatusNotFound, "Роль не найдена"},
+	{models.ErrRoleAlreadyExist, http.StatusConflict, "Роль с таким именем уже существует"},
+	{models.ErrRoleInUse, http.StatusConflict, "Роль назначена пользователям"},
+	{models.ErrSystemRole, http.StatusConflict, "Системную роль нельзя удалить"},
+	{models.ErrAuthSessionNotFound, http.StatusNotFound, "Сессия не найдена"},
+	{models.ErrMFAInvalidCode, http.StatusUnauthorized, "Неверный код подтверждения"},
+	{models.ErrMFANotEnrolled, http.StatusConflict, "Двухфакторная аутентификация не подключена"},
+	{models.ErrMFAAlreadyEnabled, http.StatusConflict, "Двухфакторная аутентификация уже подключена"},
+	{models.ErrMFARequired, http.StatusConflict, "Роль требует двухфакторную аутентификацию"},
+	{models.ErrMFAEnrollmentRequired, http.StatusForbidden, "Подключите двухфакторную аутентификацию"},
+	{models.ErrInvalidInvite, http.StatusBadRequest, "Приглашение недействительно или истекло"},
+	{models.ErrInviteNotPending, http.StatusConflict, "У сотрудника нет действующего приглашения"},
+	{models.ErrRegistrationDisabled, http.StatusForbidden, "Регистрация закрыта"},
+	{models.ErrEmailDomainNotAllowed, http.StatusForbidden, "Регистрация с этого почтового домена не разрешена"},
+	{models.ErrEmailNotVerified, http.StatusForbidden, "Подтвердите email по ссылке из письма"},
+	{models.ErrInvalidVerification, http.StatusBadRequest, "Ссылка подтверждения недействительна или устарела"},
+	{models.ErrStaffNotApproved, http.StatusConflict, "Сотрудник не одобрен администратором: доступна только роль user"},
+	{models.ErrTooManyAttempts, http.StatusTooManyRequests, "Слишком много попыток. Повторите позже"},
+	{models.ErrAuthUnavailable, http.StatusServiceUnavailable, "Проверка авторизации временно недоступна. Повторите позже"},
+	{models.ErrAPIKeyNotFound, http.StatusNotFound, "API-ключ не найден"},
+	{models.ErrCampaignNotFound, http.StatusNotFound, "Рассылка не найдена"},
+	{models.ErrInvalidCampaign, http.StatusBadRequest, "Некорректная рассылка: проверьте текст, картинку, кнопки и сегмент"},
+	{models.ErrCampaignStatus, http.StatusConflict, "Действие недоступно в текущем статусе рассылки"},
+	{models.ErrCampaignNoRecipients, http.StatusConflict, "В сегменте рассылки нет получателей"},
 }
 
 const defaultMessage = "Произошла ошибка. Попробуйте позже."
@@ -79,6 +109,10 @@ func messageFor(err error) string {
 func WriteError(w http.ResponseWriter, err error) {
 	code := HTTPStatus(err)
 	messages := Messages(err)
+	var lockout *models.LockoutError
+	if errors.As(err, &lockout) {
+		w.Header().Set("Retry-After", strconv.Itoa(lockout.RetryAfterSeconds()))
+	}
 	WriteErrorMessages(w, code, messages)
 }
 
@@ -15,8 +15,15 @@ import (
 	"github.com/yandex-development-1-team/go/internal/logger"
 )
 
+// allowedUpdates are the update types the bot handles, in both modes
+var allowedUpdates = []string{"message", "callback_query", "my_chat_member"}
+
 type TelegramBot struct {
 	Api *tgbotapi.BotAPI
+
+	webhook config.TelegramWebhook
+	// webhookSet is set once SetWebhook succeeds, so Shutdown knows what to undo
+	webhookSet bool
 }
 
 func NewTelegramBot(cfg config.Telegram) (*TelegramBot, error) {
@@ -67,14 +74,15 @@ func NewTelegramBot(cfg config.Telegram) (*TelegramBot, error) {
 	}
 
 	return &TelegramBot{
-		Api: bot,
+		Api:     bot,
+		webhook: cfg.Webhook,
 	}, nil
 }
 
 func (b *TelegramBot) GetUpdates(timeout time.Duration) tgbotapi.UpdatesChannel {
 	updates := b.Api.GetUpdatesChan(tgbotapi.UpdateConfig{
 		Timeout:        int(timeout.Seconds()),
-		AllowedUpdates: []string{"message", "callback_query", "my_chat_member"},
+		AllowedUpdates: allowedUpdates,
 	})
 	return updates
 }
@@ -83,6 +91,11 @@ func (b *TelegramBot) Shutdown(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() {
 		b.Api.StopReceivingUpdates()
+		if b.webhookSet && b.webhook.DeleteOnShutdown {
+			if err := b.DeleteWebhook(); err != nil {
+				logger.Error("failed to delete telegram webhook", zap.Error(err))
+			}
+		}
 		close(done)
 	}()
 	select {
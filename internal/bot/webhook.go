@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+)
+
+// WebhookSecretHeader carries the secret token Telegram signs webhook requests with
+const WebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// SetWebhook points Telegram at the webhook URL of the config. The library's
+// WebhookConfig has no secret_token, so the request is made by hand.
+func (b *TelegramBot) SetWebhook() error {
+	params := tgbotapi.Params{}
+	params["url"] = b.webhook.URL
+	params["secret_token"] = b.webhook.SecretToken
+	params.AddNonZero("max_connections", b.webhook.MaxConnections)
+	if err := params.AddInterface("allowed_updates", allowedUpdates); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+
+	if _, err := b.Api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+	b.webhookSet = true
+	logger.Info("telegram webhook set", zap.String("url", b.webhook.URL))
+	return nil
+}
+
+// DeleteWebhook removes the webhook. Polling mode calls it on start, since
+// getUpdates fails while a webhook is set.
+func (b *TelegramBot) DeleteWebhook() error {
+	if _, err := b.Api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	b.webhookSet = false
+	return nil
+}
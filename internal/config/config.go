@@ -2,36 +2,49 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/yandex-development-1-team/go/internal/models"
 )
 
 type Config struct {
-	TelegramBotToken  string            `mapstructure:"telegram_bot_token"`
-	TelegramBotAPIUrl string            `mapstructure:"telegram_bot_api_url"`
-	Telegram          Telegram          `mapstructure:"telegram"`
-	AuthConfig        AuthConfig        `mapstructure:"auth_config"`
-	DB                DatabaseConfig    `mapstructure:"db"`
-	Port              int               `mapstructure:"port"`
-	Environment       string            `mapstructure:"environment"`
-	PrometheusPort    int               `mapstructure:"prometheus_port"`
-	LogLevel          string            `mapstructure:"log_level"`
-	HostName          string            `mapstructure:"host_name"`
-	Redis             RedisConfig       `mapstructure:"redis"`
-	Session           SessionConfig     `mapstructure:"session"`
-	MsgRPS            float64           `mapstructure:"msg_rps"`
-	ApiRPS            float64           `mapstructure:"api_rps"`
-	CacheSizeRPS      int               `mapstructure:"cache_size_rps"`
-	APIOnly           bool              `mapstructure:"api_only"`
-	CORS              CORSConfig        `mapstructure:"cors"`
-	MigrationsDir     string            `mapstructure:"migrations_dir"`
-	Email             EmailConfig       `mapstructure:"email"`
-	Storage           StorageConfig     `mapstructure:"storage"`
-	FileGC            FileGCConfig      `mapstructure:"file_gc"`
-	YandexForms       YandexFormsConfig `mapstructure:"yandex_forms"`
-	DocsPath          string            `mapstructure:"docs_path"`
+	TelegramBotToken  string                `mapstructure:"telegram_bot_token"`
+	TelegramBotAPIUrl string                `mapstructure:"telegram_bot_api_url"`
+	Telegram          Telegram              `mapstructure:"telegram"`
+	AuthConfig        AuthConfig            `mapstructure:"auth_config"`
+	DB                DatabaseConfig        `mapstructure:"db"`
+	Port              int                   `mapstructure:"port"`
+	Environment       string                `mapstructure:"environment"`
+	PrometheusPort    int                   `mapstructure:"prometheus_port"`
+	LogLevel          string                `mapstructure:"log_level"`
+	HostName          string                `mapstructure:"host_name"`
+	Redis             RedisConfig           `mapstructure:"redis"`
+	Session           SessionConfig         `mapstructure:"session"`
+	MsgRPS            float64               `mapstructure:"msg_rps"`
+	ApiRPS            float64               `mapstructure:"api_rps"`
+	CacheSizeRPS      int                   `mapstructure:"cache_size_rps"`
+	APIOnly           bool                  `mapstructure:"api_only"`
+	CORS              CORSConfig            `mapstructure:"cors"`
+	TrustedProxies    []string              `mapstructure:"trusted_proxies"`
+	TrustedPlatform   string                `mapstructure:"trusted_platform"`
+	MigrationsDir     string                `mapstructure:"migrations_dir"`
+	Email             EmailConfig           `mapstructure:"email"`
+	Storage           StorageConfig         `mapstructure:"storage"`
+	FileGC            FileGCConfig          `mapstructure:"file_gc"`
+	Reminders         RemindersConfig       `mapstructure:"reminders"`
+	Outbox            OutboxConfig          `mapstructure:"outbox"`
+	Campaigns         CampaignsConfig       `mapstructure:"campaigns"`
+	Tracker           TrackerConfig         `mapstructure:"tracker"`
+	Webhooks          WebhooksConfig        `mapstructure:"webhooks"`
+	PermissionCache   PermissionCacheConfig `mapstructure:"permission_cache"`
+	YandexForms       YandexFormsConfig     `mapstructure:"yandex_forms"`
+	DocsPath          string                `mapstructure:"docs_path"`
 }
 
 type StorageConfig struct {
@@ -50,10 +63,78 @@ type FileGCConfig struct {
 	DeleteBatchSize int           `mapstructure:"delete_batch_size"`
 }
 
+type RemindersConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batch_size"`
+}
+
+type OutboxConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+}
+
+// CampaignsConfig is the delivery of the broadcasts; the rate is bounded by
+// api_rps and msg_rps shared with the rest of the bot
+type CampaignsConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+}
+
+type WebhooksConfig struct {
+	Interval    time.Duration `mapstructure:"interval"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+// PermissionCacheConfig configures the role permission cache: an in-process
+// LRU of Size roles in front of Redis. LocalTTL bounds how long a replica may
+// serve stale permissions if it misses an invalidation message.
+type PermissionCacheConfig struct {
+	Size     int           `mapstructure:"size"`
+	LocalTTL time.Duration `mapstructure:"local_ttl"`
+	TTL      time.Duration `mapstructure:"ttl"`
+}
+
+type TrackerConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	BaseURL    string        `mapstructure:"base_url"`
+	Token      string        `mapstructure:"token"`
+	OrgID      string        `mapstructure:"org_id"`
+	CloudOrgID string        `mapstructure:"cloud_org_id"`
+	Queue      string        `mapstructure:"queue"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	Interval   time.Duration `mapstructure:"interval"`
+	BatchSize  int           `mapstructure:"batch_size"`
+	// StartFrom is the date (YYYY-MM-DD) from which created bookings and
+	// applications get Tracker issues; older records are not backfilled
+	StartFrom string `mapstructure:"start_from"`
+	// Statuses maps booking/application statuses to Tracker status keys
+	Statuses map[string]string `mapstructure:"statuses"`
+}
+
+// StartTime returns StartFrom as the start of that day in UTC
+func (c TrackerConfig) StartTime() (time.Time, error) {
+	return time.Parse(time.DateOnly, c.StartFrom)
+}
+
+// Modes of receiving Telegram updates
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+)
+
 type Telegram struct {
 	BotToken string `mapstructure:"bot_token"`
 	ApiUrl   string `mapstructure:"api_url"`
 	Debug    bool   `mapstructure:"debug"`
+	// Mode is "polling" (getUpdates) or "webhook". Only webhook mode allows
+	// more than one bot replica.
+	Mode    string          `mapstructure:"mode"`
+	Webhook TelegramWebhook `mapstructure:"webhook"`
 
 	Proxy struct {
 		Enabled bool   `mapstructure:"enabled"`
@@ -62,6 +143,18 @@ type Telegram struct {
 	} `mapstructure:"proxy"`
 }
 
+// TelegramWebhook configures the webhook mode: Telegram posts updates to URL,
+// which must reach Path of the API server, and signs them with SecretToken.
+type TelegramWebhook struct {
+	URL            string `mapstructure:"url"`
+	Path           string `mapstructure:"path"`
+	SecretToken    string `mapstructure:"secret_token"`
+	MaxConnections int    `mapstructure:"max_connections"`
+	// DeleteOnShutdown removes the webhook when the bot stops. Turn it off when
+	// several replicas share the webhook, so that a rolling restart keeps it.
+	DeleteOnShutdown bool `mapstructure:"delete_on_shutdown"`
+}
+
 type CORSConfig struct {
 	AllowOrigin      string `mapstructure:"allow_origin"`
 	AllowMethods     string `mapstructure:"allow_methods"`
@@ -115,6 +208,52 @@ type AuthConfig struct {
 	JWTSecret             string `mapstructure:"jwt_secret"`
 	AccessTokenTTLMinutes int    `mapstructure:"access_token_ttl_minutes"`
 	RefreshTokenTTLDays   int    `mapstructure:"refresh_token_ttl_days"`
+	// MFAEncryptionKey encrypts the TOTP secrets. It is separate from JWTSecret,
+	// so rotating the signing secret keeps the enrolled secrets readable.
+	MFAEncryptionKey string `mapstructure:"mfa_encryption_key"`
+	MFAIssuer        string `mapstructure:"mfa_issuer"`
+	InviteTTLHours   int    `mapstructure:"invite_ttl_hours"`
+	// TelegramLinkTTLMinutes is how long a code linking staff to Telegram is valid
+	TelegramLinkTTLMinutes int `mapstructure:"telegram_link_ttl_minutes"`
+	// Registration is the policy of POST /auth/register
+	Registration RegistrationConfig `mapstructure:"registration"`
+	// Lockout protects login and password recovery against brute force
+	Lockout LockoutConfig `mapstructure:"lockout"`
+	// Signing selects the keys of access tokens; JWTSecret is used without them
+	Signing SigningConfig `mapstructure:"signing"`
+}
+
+// SigningConfig lists the asymmetric keys of access tokens. Tokens are signed
+// with the ActiveKeyID key, RS256 or EdDSA by the key type, and every listed
+// key verifies them: a key rotated out keeps accepting the tokens it signed.
+type SigningConfig struct {
+	ActiveKeyID string             `mapstructure:"active_kid"`
+	Keys        []SigningKeyConfig `mapstructure:"keys"`
+}
+
+// SigningKeyConfig is a PEM key. A key that only verifies may be given by
+// its public part.
+type SigningKeyConfig struct {
+	ID             string `mapstructure:"kid"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+// LockoutConfig limits failed attempts per email and per client IP within a
+// sliding window. The first lockout lasts BaseDuration, each next one within
+// a day twice as long, up to MaxDuration.
+type LockoutConfig struct {
+	EmailAttempts int           `mapstructure:"email_attempts"`
+	IPAttempts    int           `mapstructure:"ip_attempts"`
+	Window        time.Duration `mapstructure:"window"`
+	BaseDuration  time.Duration `mapstructure:"base_duration"`
+	MaxDuration   time.Duration `mapstructure:"max_duration"`
+}
+
+// RegistrationConfig takes one of the models.Registration* modes
+type RegistrationConfig struct {
+	Mode           string   `mapstructure:"mode"`
+	AllowedDomains []string `mapstructure:"allowed_domains"`
 }
 
 type EmailConfig struct {
@@ -200,6 +339,10 @@ func loadConfig(paths []string) (*Config, error) {
 
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("telegram.api_url", "https://api.telegram.org")
+	v.SetDefault("telegram.mode", TelegramModePolling)
+	v.SetDefault("telegram.webhook.path", "/telegram/webhook")
+	v.SetDefault("telegram.webhook.max_connections", 40)
+	v.SetDefault("telegram.webhook.delete_on_shutdown", true)
 	v.SetDefault("port", 8080)
 	v.SetDefault("environment", "dev")
 	v.SetDefault("prometheus_port", 9090)
@@ -232,6 +375,16 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("session.ttl", "24h")
 
+	v.SetDefault("auth_config.mfa_issuer", "Yandex Staff Bot")
+	v.SetDefault("auth_config.invite_ttl_hours", 72)
+	v.SetDefault("auth_config.telegram_link_ttl_minutes", 15)
+	v.SetDefault("auth_config.registration.mode", models.RegistrationInviteOnly)
+	v.SetDefault("auth_config.lockout.email_attempts", 5)
+	v.SetDefault("auth_config.lockout.ip_attempts", 20)
+	v.SetDefault("auth_config.lockout.window", "15m")
+	v.SetDefault("auth_config.lockout.base_duration", "1m")
+	v.SetDefault("auth_config.lockout.max_duration", "1h")
+
 	v.SetDefault("storage.endpoint", "localhost:9000")
 	v.SetDefault("storage.access_key", "minio")
 	v.SetDefault("storage.secret_key", "minio123")
@@ -243,14 +396,51 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("file_gc.interval", "1h")
 	v.SetDefault("file_gc.orphan_grace_period", "24h")
 	v.SetDefault("file_gc.delete_batch_size", 100)
+
+	v.SetDefault("reminders.enabled", true)
+	v.SetDefault("reminders.interval", "5m")
+	v.SetDefault("reminders.batch_size", 100)
+
+	v.SetDefault("outbox.interval", "5s")
+	v.SetDefault("outbox.batch_size", 50)
+	v.SetDefault("outbox.max_attempts", 8)
+
+	v.SetDefault("campaigns.interval", "5s")
+	v.SetDefault("campaigns.batch_size", 100)
+	v.SetDefault("campaigns.max_attempts", 5)
+
+	v.SetDefault("tracker.enabled", false)
+	v.SetDefault("tracker.base_url", "https://api.tracker.yandex.net")
+	v.SetDefault("tracker.timeout", "10s")
+	v.SetDefault("tracker.interval", "1m")
+	v.SetDefault("tracker.batch_size", 50)
+	v.SetDefault("tracker.statuses", map[string]string{
+		"pending":   "open",
+		"confirmed": "inProgress",
+		"cancelled": "closed",
+	})
+
+	v.SetDefault("webhooks.interval", "5s")
+	v.SetDefault("webhooks.batch_size", 50)
+	v.SetDefault("webhooks.max_attempts", 10)
+	v.SetDefault("webhooks.timeout", "10s")
+
+	v.SetDefault("permission_cache.size", 64)
+	v.SetDefault("permission_cache.local_ttl", "30s")
+	v.SetDefault("permission_cache.ttl", "10m")
 	v.SetDefault("docs_path", "./docs/openapi.json")
 }
 
 func bindEnvs(v *viper.Viper) {
 	// BindEnv returns error only on invalid key; keys are fixed at compile time.
 	_ = v.BindEnv("telegram_bot_token", "BOT_TOKEN")
+	_ = v.BindEnv("telegram.mode", "TELEGRAM_MODE")
+	_ = v.BindEnv("telegram.webhook.url", "TELEGRAM_WEBHOOK_URL")
+	_ = v.BindEnv("telegram.webhook.secret_token", "TELEGRAM_WEBHOOK_SECRET")
 	_ = v.BindEnv("postgres_url", "POSTGRES_URL")
 	_ = v.BindEnv("port", "SERVER_PORT")
+	_ = v.BindEnv("trusted_proxies", "TRUSTED_PROXIES")
+	_ = v.BindEnv("trusted_platform", "TRUSTED_PLATFORM")
 
 	_ = v.BindEnv("db.name", "POSTGRES_NAME")
 	_ = v.BindEnv("db.user", "POSTGRES_USER")
@@ -271,6 +461,11 @@ func bindEnvs(v *viper.Viper) {
 	_ = v.BindEnv("api_only", "API_ONLY")
 
 	_ = v.BindEnv("auth_config.jwt_secret", "JWT_SECRET")
+	_ = v.BindEnv("auth_config.mfa_encryption_key", "MFA_ENCRYPTION_KEY")
+	_ = v.BindEnv("auth_config.registration.mode", "REGISTRATION_MODE")
+	_ = v.BindEnv("auth_config.signing.active_kid", "JWT_ACTIVE_KID")
+	_ = v.BindEnv("auth_config.lockout.email_attempts", "LOCKOUT_EMAIL_ATTEMPTS")
+	_ = v.BindEnv("auth_config.lockout.ip_attempts", "LOCKOUT_IP_ATTEMPTS")
 	_ = v.BindEnv("migrations_dir", "MIGRATIONS_DIR")
 
 	_ = v.BindEnv("storage.endpoint", "MINIO_ENDPOINT")
@@ -284,8 +479,39 @@ func bindEnvs(v *viper.Viper) {
 	_ = v.BindEnv("file_gc.interval", "FILE_GC_INTERVAL")
 	_ = v.BindEnv("file_gc.orphan_grace_period", "FILE_GC_ORPHAN_GRACE_PERIOD")
 	_ = v.BindEnv("file_gc.delete_batch_size", "FILE_GC_DELETE_BATCH_SIZE")
+
+	_ = v.BindEnv("reminders.enabled", "REMINDERS_ENABLED")
+	_ = v.BindEnv("reminders.interval", "REMINDERS_INTERVAL")
+	_ = v.BindEnv("reminders.batch_size", "REMINDERS_BATCH_SIZE")
+
+	_ = v.BindEnv("outbox.interval", "OUTBOX_INTERVAL")
+	_ = v.BindEnv("outbox.batch_size", "OUTBOX_BATCH_SIZE")
+	_ = v.BindEnv("outbox.max_attempts", "OUTBOX_MAX_ATTEMPTS")
+	_ = v.BindEnv("campaigns.interval", "CAMPAIGNS_INTERVAL")
+	_ = v.BindEnv("campaigns.batch_size", "CAMPAIGNS_BATCH_SIZE")
+	_ = v.BindEnv("campaigns.max_attempts", "CAMPAIGNS_MAX_ATTEMPTS")
 	_ = v.BindEnv("yandex_forms.webhook_token", "YANDEX_FORMS_WEBHOOK_TOKEN")
 
+	_ = v.BindEnv("tracker.enabled", "TRACKER_ENABLED")
+	_ = v.BindEnv("tracker.base_url", "TRACKER_BASE_URL")
+	_ = v.BindEnv("tracker.token", "TRACKER_TOKEN")
+	_ = v.BindEnv("tracker.org_id", "TRACKER_ORG_ID")
+	_ = v.BindEnv("tracker.cloud_org_id", "TRACKER_CLOUD_ORG_ID")
+	_ = v.BindEnv("tracker.queue", "TRACKER_QUEUE")
+	_ = v.BindEnv("tracker.timeout", "TRACKER_TIMEOUT")
+	_ = v.BindEnv("tracker.interval", "TRACKER_INTERVAL")
+	_ = v.BindEnv("tracker.batch_size", "TRACKER_BATCH_SIZE")
+	_ = v.BindEnv("tracker.start_from", "TRACKER_START_FROM")
+
+	_ = v.BindEnv("webhooks.interval", "WEBHOOKS_INTERVAL")
+	_ = v.BindEnv("webhooks.batch_size", "WEBHOOKS_BATCH_SIZE")
+	_ = v.BindEnv("webhooks.max_attempts", "WEBHOOKS_MAX_ATTEMPTS")
+	_ = v.BindEnv("webhooks.timeout", "WEBHOOKS_TIMEOUT")
+
+	_ = v.BindEnv("permission_cache.size", "PERMISSION_CACHE_SIZE")
+	_ = v.BindEnv("permission_cache.local_ttl", "PERMISSION_CACHE_LOCAL_TTL")
+	_ = v.BindEnv("permission_cache.ttl", "PERMISSION_CACHE_TTL")
+
 	_ = v.BindEnv("email.smtp_host", "SMTP_HOST")
 	_ = v.BindEnv("email.smtp_port", "SMTP_PORT")
 	_ = v.BindEnv("email.smtp_username", "SMTP_USERNAME")
@@ -299,6 +525,10 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("telegram bot token is empty")
 	}
 
+	if err := validateTelegramMode(config.Telegram); err != nil {
+		return err
+	}
+
 	if config.DB.PostgresURL == "" {
 		return fmt.Errorf("postgres_url is empty")
 	}
@@ -314,5 +544,110 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("yandex_forms.webhook_token is empty")
 	}
 
+	if config.AuthConfig.MFAEncryptionKey == "" {
+		return fmt.Errorf("auth_config.mfa_encryption_key is empty")
+	}
+
+	switch config.AuthConfig.Registration.Mode {
+	case "", models.RegistrationDisabled, models.RegistrationInviteOnly:
+	case models.RegistrationDomains:
+		if len(config.AuthConfig.Registration.AllowedDomains) == 0 {
+			return fmt.Errorf("auth_config.registration.allowed_domains is required when registration mode is %q", models.RegistrationDomains)
+		}
+	default:
+		return fmt.Errorf("auth_config.registration.mode must be one of %q, %q, %q",
+			models.RegistrationDisabled, models.RegistrationInviteOnly, models.RegistrationDomains)
+	}
+
+	lockout := config.AuthConfig.Lockout
+	if lockout.EmailAttempts < 0 || lockout.IPAttempts < 0 || lockout.Window < 0 || lockout.BaseDuration < 0 {
+		return fmt.Errorf("auth_config.lockout values must not be negative")
+	}
+	if lockout.MaxDuration < lockout.BaseDuration {
+		return fmt.Errorf("auth_config.lockout.max_duration must not be less than base_duration")
+	}
+
+	if err := validateSigning(config.AuthConfig.Signing); err != nil {
+		return err
+	}
+
+	for _, proxy := range config.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("trusted_proxies: %q is neither an IP nor a CIDR", proxy)
+			}
+		}
+	}
+
+	if config.Tracker.Enabled {
+		if config.Tracker.Token == "" || config.Tracker.Queue == "" {
+			return fmt.Errorf("tracker.token and tracker.queue are required when tracker is enabled")
+		}
+		if config.Tracker.OrgID == "" && config.Tracker.CloudOrgID == "" {
+			return fmt.Errorf("tracker.org_id or tracker.cloud_org_id is required when tracker is enabled")
+		}
+		if _, err := config.Tracker.StartTime(); err != nil {
+			return fmt.Errorf("tracker.start_from must be a date like 2006-01-02 when tracker is enabled: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateTelegramMode(telegram Telegram) error {
+	switch telegram.Mode {
+	case "", TelegramModePolling:
+		return nil
+	case TelegramModeWebhook:
+	default:
+		return fmt.Errorf("telegram.mode must be %q or %q", TelegramModePolling, TelegramModeWebhook)
+	}
+
+	webhook := telegram.Webhook
+	if webhook.URL == "" {
+		return fmt.Errorf("telegram.webhook.url is required in webhook mode")
+	}
+	if !strings.HasPrefix(webhook.Path, "/") {
+		return fmt.Errorf("telegram.webhook.path must start with /")
+	}
+	// Telegram accepts 1-256 characters A-Z, a-z, 0-9, _ and -
+	if !telegramSecretRe.MatchString(webhook.SecretToken) {
+		return fmt.Errorf("telegram.webhook.secret_token must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+	if webhook.MaxConnections < 0 || webhook.MaxConnections > 100 {
+		return fmt.Errorf("telegram.webhook.max_connections must be from 0 to 100")
+	}
+	return nil
+}
+
+var telegramSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+func validateSigning(signing SigningConfig) error {
+	if len(signing.Keys) == 0 {
+		if signing.ActiveKeyID != "" {
+			return fmt.Errorf("auth_config.signing.keys has no key %q", signing.ActiveKeyID)
+		}
+		return nil
+	}
+
+	seen := make(map[string]bool, len(signing.Keys))
+	for _, key := range signing.Keys {
+		if key.ID == "" {
+			return fmt.Errorf("auth_config.signing.keys: kid is empty")
+		}
+		if seen[key.ID] {
+			return fmt.Errorf("auth_config.signing.keys: kid %q is repeated", key.ID)
+		}
+		seen[key.ID] = true
+		if key.PrivateKeyFile == "" && key.PublicKeyFile == "" {
+			return fmt.Errorf("auth_config.signing.keys: key %q has no key file", key.ID)
+		}
+		if key.ID == signing.ActiveKeyID && key.PrivateKeyFile == "" {
+			return fmt.Errorf("auth_config.signing.keys: active key %q has no private_key_file", key.ID)
+		}
+	}
+	if !seen[signing.ActiveKeyID] {
+		return fmt.Errorf("auth_config.signing.active_kid must be one of auth_config.signing.keys")
+	}
 	return nil
 }
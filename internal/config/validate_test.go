@@ -1,6 +1,11 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
 
 func TestValidateConfig(t *testing.T) {
 	t.Parallel()
@@ -12,12 +17,32 @@ func TestValidateConfig(t *testing.T) {
 			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
 			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
 			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key"},
 		})
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
+	t.Run("mfa encryption key is required", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:     true,
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{JWTSecret: "jwt-secret"},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when the mfa encryption key is empty")
+		}
+
+		cfg.AuthConfig.MFAEncryptionKey = "mfa-key"
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
 	t.Run("not api_only requires bot token", func(t *testing.T) {
 		t.Parallel()
 		err := validateConfig(&Config{
@@ -39,9 +64,161 @@ func TestValidateConfig(t *testing.T) {
 			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
 			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
 			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key"},
 		})
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("enabled tracker requires credentials", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:     true,
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key"},
+			Tracker:     TrackerConfig{Enabled: true, Token: "token", Queue: "BOOK"},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when tracker org id is empty")
+		}
+
+		cfg.Tracker.OrgID = "42"
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when tracker start date is empty")
+		}
+
+		cfg.Tracker.StartFrom = "2026-10-01"
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("registration by domains requires allowed domains", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:     true,
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key", Registration: RegistrationConfig{Mode: models.RegistrationDomains}},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when allowed domains are empty")
+		}
+
+		cfg.AuthConfig.Registration.AllowedDomains = []string{"example.com"}
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.AuthConfig.Registration.Mode = "open"
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error on unknown registration mode")
+		}
+	})
+
+	t.Run("trusted proxies must be IPs or CIDRs", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:        true,
+			DB:             DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:        StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms:    YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:     AuthConfig{MFAEncryptionKey: "mfa-key"},
+			TrustedProxies: []string{"10.0.0.0/8", "127.0.0.1", "::1"},
+		}
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.TrustedProxies = append(cfg.TrustedProxies, "proxy.local")
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error on a host name")
+		}
+	})
+	t.Run("lockout cannot be shorter than its base", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:     true,
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key", Lockout: LockoutConfig{EmailAttempts: 5, BaseDuration: time.Hour, MaxDuration: time.Minute}},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when max_duration is less than base_duration")
+		}
+
+		cfg.AuthConfig.Lockout.MaxDuration = 2 * time.Hour
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.AuthConfig.Lockout.EmailAttempts = -1
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error on negative attempts")
+		}
+	})
+	t.Run("active signing key must be listed with a private key", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			APIOnly:     true,
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig: AuthConfig{MFAEncryptionKey: "mfa-key", Signing: SigningConfig{
+				ActiveKeyID: "new",
+				Keys:        []SigningKeyConfig{{ID: "old", PublicKeyFile: "old.pub"}},
+			}},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when the active key is not listed")
+		}
+
+		cfg.AuthConfig.Signing.Keys = append(cfg.AuthConfig.Signing.Keys, SigningKeyConfig{ID: "new", PublicKeyFile: "new.pub"})
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when the active key has no private key")
+		}
+
+		cfg.AuthConfig.Signing.Keys[1] = SigningKeyConfig{ID: "new", PrivateKeyFile: "new.pem"}
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("webhook mode requires url and secret token", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			Telegram: Telegram{
+				BotToken: "123:abc",
+				Mode:     TelegramModeWebhook,
+				Webhook:  TelegramWebhook{Path: "/telegram/webhook"},
+			},
+			DB:          DatabaseConfig{PostgresURL: "postgres://u:p@h/db?sslmode=disable"},
+			Storage:     StorageConfig{Endpoint: "http://localhost:9000", Bucket: "test-bucket"},
+			YandexForms: YandexFormsConfig{WebhookToken: "test-token"},
+			AuthConfig:  AuthConfig{MFAEncryptionKey: "mfa-key"},
+		}
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error when the webhook url is empty")
+		}
+
+		cfg.Telegram.Webhook.URL = "https://bot.example.com/telegram/webhook"
+		cfg.Telegram.Webhook.SecretToken = "not a valid token!"
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error on a secret token Telegram rejects")
+		}
+
+		cfg.Telegram.Webhook.SecretToken = "s3cret_token-1"
+		if err := validateConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.Telegram.Mode = "push"
+		if err := validateConfig(cfg); err == nil {
+			t.Fatal("expected error on an unknown mode")
+		}
+	})
 }
@@ -7,6 +7,8 @@ type ExportType string
 const (
 	ExportTypeBoxes ExportType = "boxes"
 	ExportTypeUsers ExportType = "users"
+	// ExportTypeSources breaks bookings down by the src_ campaign of the bot deep link
+	ExportTypeSources ExportType = "sources"
 )
 
 type ExportFormat string
@@ -39,4 +41,13 @@ type AnalyticsUserRow struct {
 	Email         string    `db:"email"`
 	TotalBookings int64     `db:"total_bookings"`
 	RegisteredAt  time.Time `db:"registered_at"`
+	Source        string    `db:"source"`
+}
+
+type AnalyticsSourceRow struct {
+	Source            string `db:"source"`
+	Guests            int64  `db:"guests"`
+	TotalBookings     int64  `db:"total_bookings"`
+	ConfirmedBookings int64  `db:"confirmed_bookings"`
+	CancelledBookings int64  `db:"cancelled_bookings"`
 }
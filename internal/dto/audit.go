@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditListRequest struct {
+	Entity   string `form:"entity"`
+	EntityID string `form:"entity_id"`
+	ActorID  int64  `form:"actor_id" binding:"omitempty,min=1"`
+	DateFrom string `form:"date_from"`
+	DateTo   string `form:"date_to"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type AuditEntryItem struct {
+	ID         int64           `json:"id"`
+	ActorID    *int64          `json:"actor_id"`
+	ActorRole  string          `json:"actor_role"`
+	Entity     string          `json:"entity"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	StatusCode int             `json:"status_code"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Diff       json.RawMessage `json:"diff"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Items      []AuditEntryItem `json:"items"`
+	Pagination Pagination       `json:"pagination"`
+}
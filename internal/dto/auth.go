@@ -7,53 +7,61 @@ import (
 type LoginRequest struct {
 	Login    string `json:"login"     binding:"required,email,max=255"`
 	Password string `json:"password"  binding:"required,min=8,max=72"`
+	Device   string `json:"device"    binding:"omitempty,max=255"`
 }
 
 type LoginResponse struct {
 	Token        string       `json:"token"`
 	RefreshToken string       `json:"refresh_token"`
 	User         UserResponse `json:"user"`
+	Permissions  []string     `json:"permissions"`
 }
 
 type UserResponse struct {
-	ID           int64     `json:"id"`
-	TelegramNick string    `json:"telegram_nick"`
-	Name         string    `json:"name"`
-	LastName     string    `json:"last_name"`
-	SecondName   string    `json:"second_name"`
-	Email        string    `json:"email"`
-	PhoneNumber  string    `json:"phone_number"`
-	Role         string    `json:"role"`
-	Status       string    `json:"status"`
-	Department   string    `json:"department"`
-	Position     string    `json:"position"`
-	Supervisor   string    `json:"supervisor"`
-	Address      string    `json:"address"`
-	Image        string    `json:"image"`
-	InviteToken  string    `json:"invite_token"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int64      `json:"id"`
+	TelegramNick    string     `json:"telegram_nick"`
+	Name            string     `json:"name"`
+	LastName        string     `json:"last_name"`
+	SecondName      string     `json:"second_name"`
+	Email           string     `json:"email"`
+	PhoneNumber     string     `json:"phone_number"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	Department      string     `json:"department"`
+	Position        string     `json:"position"`
+	Supervisor      string     `json:"supervisor"`
+	Address         string     `json:"address"`
+	Image           string     `json:"image"`
+	InviteToken     string     `json:"invite_token"`
+	InviteExpiresAt *time.Time `json:"invite_expires_at,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 type UserRow struct {
-	ID           int64     `db:"id"`
-	TelegramNick *string   `db:"telegram_nick"`
-	Name         string    `db:"first_name"`
-	LastName     string    `db:"last_name"`
-	SecondName   string    `db:"second_name"`
-	Email        string    `db:"email"`
-	PhoneNumber  *string   `db:"phone_number"`
-	UserPass     string    `db:"password_hash"`
-	Role         string    `db:"role"`
-	Status       string    `db:"status"`
-	InviteToken  *string   `db:"invite_token"`
-	Department   *string   `db:"department"`
-	Position     *string   `db:"position"`
-	Image        *string   `db:"image"`
-	Supervisor   *string   `db:"supervisor"`
-	Address      *string   `db:"address"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID              int64      `db:"id"`
+	TelegramNick    *string    `db:"telegram_nick"`
+	Name            string     `db:"first_name"`
+	LastName        string     `db:"last_name"`
+	SecondName      string     `db:"second_name"`
+	Email           string     `db:"email"`
+	PhoneNumber     *string    `db:"phone_number"`
+	UserPass        string     `db:"password_hash"`
+	Role            string     `db:"role"`
+	Status          string     `db:"status"`
+	InviteToken     *string    `db:"invite_token"`
+	InviteExpiresAt *time.Time `db:"invite_expires_at"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	ApprovedAt      *time.Time `db:"approved_at"`
+	Department      *string    `db:"department"`
+	Position        *string    `db:"position"`
+	Image           *string    `db:"image"`
+	Supervisor      *string    `db:"supervisor"`
+	Address         *string    `db:"address"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
 }
 
 type RefreshRequest struct {
@@ -71,6 +79,22 @@ type RegisterRequest struct {
 	Email       string `json:"email"              binding:"required,email,max=255"`
 	Password    string `json:"password"           binding:"required,min=8,max=72"`
 	InviteToken string `json:"invite_token"`
+	Device      string `json:"device"             binding:"omitempty,max=255"`
+}
+
+// RegisterPendingResponse is returned by register instead of the tokens when
+// the email has to be verified first
+type RegisterPendingResponse struct {
+	Message string       `json:"message"`
+	User    UserResponse `json:"user"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required,max=1024"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
 }
 
 type LogoutRequest struct {
@@ -89,3 +113,85 @@ type ResetPasswordRequest struct {
 	Token    string `json:"token"    binding:"required,min=100,max=512"`
 	Password string `json:"password" binding:"required,min=8,max=72"`
 }
+
+type AcceptInviteRequest struct {
+	Token    string `json:"token"    binding:"required,len=64,hexadecimal"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Device   string `json:"device"   binding:"omitempty,max=255"`
+}
+
+// ClearLockoutRequest names the email and/or the IP to unlock
+type ClearLockoutRequest struct {
+	Email string `form:"email" binding:"required_without=IP,omitempty,email,max=255"`
+	IP    string `form:"ip"    binding:"required_without=Email,omitempty,ip"`
+}
+
+type SessionID struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// SessionResponse is a device the user is logged in on; Current marks the
+// session of the request
+type SessionResponse struct {
+	ID         int64     `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type SessionListResponse struct {
+	Items []SessionResponse `json:"items"`
+}
+
+// MFAChallengeResponse is returned by login instead of the tokens when the
+// user has to enter the second factor
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required,max=1024"`
+	Code     string `json:"code"      binding:"required,max=32"`
+	Device   string `json:"device"    binding:"omitempty,max=255"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type MFAStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauth_url"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// JWKSResponse is a JSON Web Key Set (RFC 7517) of the access token keys
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
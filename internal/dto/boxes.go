@@ -69,6 +69,7 @@ type BoxAvailableSlot struct {
 	Date      string `json:"date"       binding:"required,datetime=2006-01-02"`
 	StartTime string `json:"time_from"  binding:"required,datetime=15:04"`
 	EndTime   string `json:"time_to"    binding:"required,datetime=15:04"`
+	Capacity  int    `json:"capacity,omitempty" binding:"omitempty,min=1,max=1000"`
 }
 
 type BoxCreateRequest struct {
@@ -113,22 +114,23 @@ type BoxUpdateStatusResult struct {
 }
 
 type BoxRaw struct {
-	ID          int64        `db:"id"`
-	Name        string       `db:"name"`
-	Slug        string       `db:"slug"`
-	Description *string      `db:"description"`
-	Rules       *string      `db:"rules"`
-	Location    *string      `db:"location"`
-	Price       int          `db:"price"`
-	Image       *string      `db:"image"`
-	Status      string       `db:"status"`
-	Organizer   *string      `db:"organizer"`
-	CreatedBy   int64        `db:"created_by"`
-	SlotDate    sql.NullTime `db:"slot_date"`
-	StartTime   sql.NullTime `db:"start_time"`
-	EndTime     sql.NullTime `db:"end_time"`
-	CreatedAt   time.Time    `db:"created_at"`
-	UpdatedAt   time.Time    `db:"updated_at"`
+	ID          int64         `db:"id"`
+	Name        string        `db:"name"`
+	Slug        string        `db:"slug"`
+	Description *string       `db:"description"`
+	Rules       *string       `db:"rules"`
+	Location    *string       `db:"location"`
+	Price       int           `db:"price"`
+	Image       *string       `db:"image"`
+	Status      string        `db:"status"`
+	Organizer   *string       `db:"organizer"`
+	CreatedBy   int64         `db:"created_by"`
+	SlotDate    sql.NullTime  `db:"slot_date"`
+	StartTime   sql.NullTime  `db:"start_time"`
+	EndTime     sql.NullTime  `db:"end_time"`
+	Capacity    sql.NullInt64 `db:"capacity"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
 }
 
 type BoxExportRequest struct {
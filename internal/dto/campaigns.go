@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+type CampaignID struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type CampaignButton struct {
+	Text string `json:"text" binding:"required,max=64"`
+	URL  string `json:"url" binding:"required,max=2048"`
+}
+
+// CampaignSegment is the audience: "all" bot users, the users with past
+// bookings of "service_ids" or the users of "grades"
+type CampaignSegment struct {
+	Type       string  `json:"type" binding:"required,oneof=all service grade"`
+	ServiceIDs []int64 `json:"service_ids,omitempty" binding:"omitempty,dive,min=1"`
+	Grades     []int   `json:"grades,omitempty" binding:"omitempty,dive,min=0"`
+}
+
+// CampaignRequest creates a campaign or replaces a draft
+type CampaignRequest struct {
+	Title    string           `json:"title" binding:"required,max=255"`
+	Text     string           `json:"text" binding:"required"`
+	ImageURL string           `json:"image_url,omitempty" binding:"omitempty,max=2048"`
+	Buttons  []CampaignButton `json:"buttons,omitempty" binding:"omitempty,max=10,dive"`
+	Segment  CampaignSegment  `json:"segment"`
+}
+
+type CampaignProgress struct {
+	Total   int `json:"total"`
+	Pending int `json:"pending"`
+	Sent    int `json:"sent"`
+	Blocked int `json:"blocked"`
+	Failed  int `json:"failed"`
+}
+
+type CampaignResponse struct {
+	ID         int64            `json:"id"`
+	Title      string           `json:"title"`
+	Text       string           `json:"text"`
+	ImageURL   string           `json:"image_url"`
+	Buttons    []CampaignButton `json:"buttons"`
+	Segment    CampaignSegment  `json:"segment"`
+	Status     string           `json:"status"`
+	CreatedBy  *int64           `json:"created_by"`
+	Progress   CampaignProgress `json:"progress"`
+	StartedAt  *time.Time       `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+type CampaignListResponse struct {
+	Items []CampaignResponse `json:"items"`
+}
+
+type CampaignAudienceResponse struct {
+	Count int `json:"count"`
+}
+
+type CampaignRecipientListRequest struct {
+	Status *string `form:"status" binding:"omitempty,oneof=pending sent blocked failed"`
+	Limit  int     `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int     `form:"offset" binding:"omitempty,min=0"`
+}
+
+type CampaignRecipientItem struct {
+	TelegramID int64      `json:"telegram_id"`
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	LastError  *string    `json:"last_error"`
+	SentAt     *time.Time `json:"sent_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type CampaignRecipientListResponse struct {
+	Items      []CampaignRecipientItem `json:"items"`
+	Pagination Pagination              `json:"pagination"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type RoleName struct {
+	Name string `uri:"name" binding:"required,max=64"`
+}
+
+type RoleCreateRequest struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Title       string `json:"title" binding:"required,max=255"`
+	Description string `json:"description,omitempty" binding:"omitempty,max=1000"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	Assignable  bool   `json:"assignable,omitempty"`
+}
+
+type RoleUpdateRequest struct {
+	Title       *string `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000"`
+	MFARequired *bool   `json:"mfa_required,omitempty"`
+	Assignable  *bool   `json:"assignable,omitempty"`
+}
+
+type RoleResponse struct {
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	MFARequired bool      `json:"mfa_required"`
+	Assignable  bool      `json:"assignable"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RoleListResponse struct {
+	Items []RoleResponse `json:"items"`
+}
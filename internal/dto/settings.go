@@ -10,6 +10,22 @@ type SettingsFormMessages struct {
 	SystemErrMessage        string `json:"system_err_message"`
 }
 
+// SettingsMessagePreviewRequest is a message template to render; the data
+// fields left empty are filled with sample values
+type SettingsMessagePreviewRequest struct {
+	Template    string `json:"template"`
+	Lang        string `json:"lang,omitempty"`
+	GuestName   string `json:"guest_name,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Time        string `json:"time,omitempty"`
+	BookingID   int64  `json:"booking_id,omitempty"`
+}
+
+type SettingsMessagePreviewResponse struct {
+	Text string `json:"text"`
+}
+
 type SettingsResponse struct {
 	Notifications Notifications `json:"notifications,omitempty"`
 	Booking       Booking       `json:"booking"`
@@ -6,7 +6,7 @@ type UserCreateRequest struct {
 	FirstName   string  `json:"first_name"          binding:"required,min=1,max=255"`
 	LastName    string  `json:"last_name"            binding:"required,min=1,max=255"`
 	Email       string  `json:"email"                binding:"required,email,max=255"`
-	Role        string  `json:"role"                 binding:"required,oneof=admin manager_1 manager_2 manager_3 user"`
+	Role        string  `json:"role"                 binding:"required,max=64"`
 	Status      string  `json:"status,omitempty"     binding:"omitempty,oneof=active blocked invited"`
 	PhoneNumber *string `json:"phone_number,omitempty" binding:"omitempty,max=50"`
 	Image       *string `json:"image,omitempty"      binding:"omitempty,httpurl,max=500"`
@@ -21,7 +21,7 @@ type UserUpdateRequest struct {
 	FirstName   *string `json:"first_name,omitempty"  binding:"omitempty,min=1,max=255"`
 	LastName    *string `json:"last_name,omitempty"   binding:"omitempty,min=1,max=255"`
 	Email       *string `json:"email,omitempty"       binding:"omitempty,email,max=255"`
-	Role        *string `json:"role,omitempty"        binding:"omitempty,oneof=admin manager_1 manager_2 manager_3 user"`
+	Role        *string `json:"role,omitempty"        binding:"omitempty,max=64"`
 	Status      *string `json:"status,omitempty"      binding:"omitempty,oneof=active blocked invited"`
 	PhoneNumber *string `json:"phone_number,omitempty" binding:"omitempty,max=50"`
 	Image       *string `json:"image,omitempty"       binding:"omitempty,httpurl,max=500"`
@@ -79,6 +79,7 @@ type VisitHistoryItem struct {
 type UserWithDetails struct {
 	ID            int64              `json:"id"`
 	TelegramNick  string             `json:"telegram_nick"`
+	TelegramID    *int64             `json:"telegram_id,omitempty"`
 	FirstName     string             `json:"first_name"`
 	LastName      string             `json:"last_name"`
 	SecondName    string             `json:"second_name"`
@@ -96,6 +97,18 @@ type UserWithDetails struct {
 	VisitHistory  []VisitHistoryItem `json:"visit_history"`
 	FavoriteBoxes []int64            `json:"favorite_boxes"`
 	Image         string             `json:"image"`
+
+	// EmailVerifiedAt and ApprovedAt are nil for a self-registered staff
+	// member who has not verified the email or been approved yet
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+}
+
+// TelegramLinkCodeResponse is shown once: the staff member sends Command to the bot
+type TelegramLinkCodeResponse struct {
+	Code      string    `json:"code"`
+	Command   string    `json:"command"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type DashboardOverview struct {
@@ -122,3 +135,17 @@ type DashboardResponse struct {
 	ManagerStats DashboardManagerStats `json:"manager_stats"`
 	Applications []ApplicationShort    `json:"applications"`
 }
+
+// UserPermissionsRequest replaces the personal overrides of the staff member
+type UserPermissionsRequest struct {
+	Granted []string `json:"granted"`
+	Denied  []string `json:"denied"`
+}
+
+type UserPermissionsResponse struct {
+	UserID    int64    `json:"user_id"`
+	Role      string   `json:"role"`
+	Granted   []string `json:"granted"`
+	Denied    []string `json:"denied"`
+	Effective []string `json:"effective"`
+}
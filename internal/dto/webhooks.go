@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookID struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type WebhookCreateRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"`
+	Secret      string   `json:"secret,omitempty" binding:"omitempty,min=16,max=256"`
+	Description string   `json:"description,omitempty" binding:"omitempty,max=500"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+type WebhookUpdateRequest struct {
+	URL         *string  `json:"url,omitempty" binding:"omitempty,url,max=2048"`
+	Events      []string `json:"events,omitempty" binding:"omitempty,min=1,dive,required"`
+	Description *string  `json:"description,omitempty" binding:"omitempty,max=500"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// WebhookResponse is a subscription; Secret is filled only in the create response
+type WebhookResponse struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"is_active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookListResponse struct {
+	Items []WebhookResponse `json:"items"`
+}
+
+type WebhookDeliveryListRequest struct {
+	Status *string `form:"status" binding:"omitempty,oneof=pending delivered failed"`
+	Limit  int     `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int     `form:"offset" binding:"omitempty,min=0"`
+}
+
+type WebhookDeliveryItem struct {
+	ID             int64           `json:"id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	Payload        json.RawMessage `json:"payload"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Items      []WebhookDeliveryItem `json:"items"`
+	Pagination Pagination            `json:"pagination"`
+}
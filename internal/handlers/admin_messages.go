@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
+)
+
+// MessageSettings serves the bot messages edited in the admin panel
+type MessageSettings interface {
+	GetSettings(ctx context.Context) (models.SettingsFormMessages, error)
+}
+
+type adminMessagesKey struct{}
+
+// withAdminMessages makes the admin messages available to the handlers of
+// the update; without them the handlers use the catalog texts
+func withAdminMessages(ctx context.Context, messages MessageSettings) context.Context {
+	if messages == nil {
+		return ctx
+	}
+
+	settings, err := messages.GetSettings(ctx)
+	if err != nil {
+		logger.Error("failed to get admin messages", zap.Error(err))
+		return ctx
+	}
+	return context.WithValue(ctx, adminMessagesKey{}, settings)
+}
+
+// adminText renders the admin message picked from the settings; ok is false
+// when the message is not set
+func adminText(ctx context.Context, pick func(models.SettingsFormMessages) string, data msgtemplate.Data) (string, bool) {
+	settings, ok := ctx.Value(adminMessagesKey{}).(models.SettingsFormMessages)
+	if !ok || strings.TrimSpace(pick(settings)) == "" {
+		return "", false
+	}
+
+	data.Lang = string(i18n.FromContext(ctx))
+	text, err := msgtemplate.Render(pick(settings), data)
+	if err != nil {
+		logger.Error("failed to render admin message", zap.Error(err))
+		return "", false
+	}
+	return text, true
+}
+
+// welcomeText is the greeting above the main menu
+func welcomeText(ctx context.Context, user *tgbotapi.User) string {
+	data := msgtemplate.Data{}
+	if user != nil {
+		data.GuestName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+
+	if text, ok := adminText(ctx, func(s models.SettingsFormMessages) string { return s.WelcomeMessage }, data); ok {
+		return text
+	}
+	return i18n.T(ctx, i18n.Welcome)
+}
+
+// systemErrorText is the reply to a failure the guest can't fix
+func systemErrorText(ctx context.Context) string {
+	if text, ok := adminText(ctx, func(s models.SettingsFormMessages) string { return s.SystemErrMessage }, msgtemplate.Data{}); ok {
+		return text
+	}
+	return i18n.T(ctx, i18n.ErrorGeneric)
+}
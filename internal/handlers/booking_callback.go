@@ -2,22 +2,24 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
 
 // stepStartBooking handles the step of starting the booking process
 func (h *BookingFormHandler) stepStartBooking(ctx context.Context, query *tgbotapi.CallbackQuery, parts []string) error {
 	if len(parts) < 4 {
-		return h.sendError(query.Message.Chat.ID, "неверный формат запроса")
+		return h.sendError(ctx, query.Message.Chat.ID, i18n.BookingErrRequestFormat)
 	}
 
 	serviceID, err := strconv.ParseInt(parts[1], 10, 64)
@@ -48,11 +50,11 @@ func (h *BookingFormHandler) stepDateSelect(
 	parts []string,
 ) error {
 	if len(parts) != 5 {
-		return h.sendError(query.Message.Chat.ID, "неверный формат выбора даты")
+		return h.sendError(ctx, query.Message.Chat.ID, i18n.BookingErrDateFormat)
 	}
 
 	if parts[1] != "select_date" {
-		return h.sendError(query.Message.Chat.ID, "неверный формат")
+		return h.sendError(ctx, query.Message.Chat.ID, i18n.BookingErrFormat)
 	}
 
 	startTime := strings.ReplaceAll(parts[3], ".", ":")
@@ -102,18 +104,19 @@ func (h *BookingFormHandler) renderDateSelection(
 		logger.Error("failed to get dates from repository",
 			zap.Error(err),
 			zap.Int64("user_id", state.UserID))
-		return h.sendError(chatID, "Ошибка получения слотов дат")
+		return h.sendError(ctx, chatID, i18n.BookingErrSlots)
 	}
 
+	slots = FreeSlots(slots)
+
 	var msg tgbotapi.MessageConfig
 	if len(slots) == 0 {
-		msg = tgbotapi.NewMessage(chatID, "На данный момент нет доступных слотов для бронирования")
-		keyboard := h.keyboard.FormNavigationKeyboard(botService.StepReturnInBoxList)
+		msg = tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingNoSlots))
+		keyboard := h.keyboard.FormNavigationKeyboard(ctx, botService.StepReturnInBoxList)
 		msg.ReplyMarkup = &keyboard
 	} else {
-		keyboard := h.keyboard.DatesKeyboard(slots, page)
-		messageText := "Выберите дату:\n"
-		msg = tgbotapi.NewMessage(chatID, messageText)
+		keyboard := h.keyboard.DatesKeyboard(ctx, slots, page)
+		msg = tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingSelectDate))
 		msg.ReplyMarkup = &keyboard
 	}
 
@@ -143,10 +146,15 @@ func (h *BookingFormHandler) dateSelection(
 	res, err := h.service.ProcessDateSelection(ctx, state, slot)
 	if err != nil {
 		logger.Error("date processing error", zap.Error(err))
-		return h.sendError(chatID, "Не удалось обработать дату")
+		return h.sendError(ctx, chatID, i18n.BookingErrDate)
 	}
 
 	if !res {
+		if state.RescheduleBookingID != 0 {
+			logger.Info("slot not available, returning to date selection")
+			state.Step = botService.StepSelectDate
+			return h.renderDateSelection(ctx, state, chatID, "1")
+		}
 		logger.Info("slot not available, restarting booking")
 		return h.startBooking(ctx, query, state.ServiceID, state.ServiceName, "1")
 	}
@@ -157,6 +165,9 @@ func (h *BookingFormHandler) dateSelection(
 		zap.String("start_time", state.SelectedSlot.StartTime),
 		zap.String("end_time", state.SelectedSlot.EndTime))
 
+	if state.Step == botService.StepConfirmation {
+		return h.renderConfirmation(ctx, chatID, state)
+	}
 	return h.renderNameInput(ctx, query, state)
 }
 
@@ -165,29 +176,21 @@ func (h *BookingFormHandler) stepConfirmation(ctx context.Context, query *tgbota
 	userID := query.From.ID
 	chatID := query.Message.Chat.ID
 
-	bookingID, err := h.service.CreateBooking(ctx, state)
-	if err != nil {
-		logger.Error("booking saving error", zap.Error(err))
-		return h.sendError(chatID, "Не удалось сохранить бронирование")
+	if state.RescheduleBookingID != 0 {
+		return h.stepRescheduleConfirmation(ctx, query, state)
 	}
 
-	var messageText strings.Builder
-	messageText.WriteString("Бронирование успешно создано!\n\n")
-	messageText.WriteString("Номер бронирования: #")
-	if _, err := fmt.Fprintf(&messageText, "%d", bookingID); err != nil {
-		return fmt.Errorf("format booking id: %w", err)
+	bookingID, err := h.service.CreateBooking(ctx, state)
+	if errors.Is(err, models.ErrSlotOccupied) {
+		return h.slotOccupied(ctx, userID, chatID, state)
 	}
-	messageText.WriteString("\nНазвание: ")
-	messageText.WriteString(state.ServiceName)
-	messageText.WriteString("\nДата: ")
-	messageText.WriteString(state.SelectedSlot.Date)
-	messageText.WriteString("\nВремя: ")
-	if _, err := fmt.Fprintf(&messageText, "%s - %s", state.SelectedSlot.StartTime, state.SelectedSlot.EndTime); err != nil {
-		return fmt.Errorf("format booking id: %w", err)
+	if err != nil {
+		logger.Error("booking saving error", zap.Error(err))
+		return h.sendError(ctx, chatID, i18n.BookingErrSave)
 	}
 
-	messageText.WriteString("\nСтатус: Ожидает подтверждения\n\n")
-	successMsg := messageText.String()
+	successMsg := i18n.T(ctx, i18n.BookingCreated, bookingID, state.ServiceName, state.SelectedSlot.Date,
+		state.SelectedSlot.StartTime, state.SelectedSlot.EndTime)
 
 	msg := tgbotapi.NewMessage(chatID, successMsg)
 	msg.ParseMode = "Markdown"
@@ -208,5 +211,83 @@ func (h *BookingFormHandler) stepConfirmation(ctx context.Context, query *tgbota
 		zap.Int64("user_id", userID),
 		zap.Int64("service_id", state.ServiceID))
 
+	h.sendThanks(ctx, chatID, bookingID, state)
+
 	return nil
 }
+
+// sendThanks follows a new booking with the thanks message of the admin panel, if set
+func (h *BookingFormHandler) sendThanks(ctx context.Context, chatID, bookingID int64, state *botService.BookingState) {
+	text, ok := adminText(ctx, func(s models.SettingsFormMessages) string { return s.ThanksMessage }, msgtemplate.Data{
+		GuestName:   state.GuestName,
+		ServiceName: state.ServiceName,
+		Date:        msgtemplate.FormatDate(state.SelectedSlot.Date),
+		Time:        state.SelectedSlot.StartTime,
+		BookingID:   bookingID,
+	})
+	if !ok {
+		return
+	}
+
+	if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		logger.Error("failed to send thanks message", zap.Int64("booking_id", bookingID), zap.Error(err))
+	}
+}
+
+// stepRescheduleConfirmation moves the guest's existing booking to the selected slot
+func (h *BookingFormHandler) stepRescheduleConfirmation(ctx context.Context, query *tgbotapi.CallbackQuery, state *botService.BookingState) error {
+	userID := query.From.ID
+	chatID := query.Message.Chat.ID
+
+	booking, err := h.service.RescheduleBooking(ctx, state)
+	if errors.Is(err, models.ErrSlotOccupied) {
+		return h.slotOccupied(ctx, userID, chatID, state)
+	}
+	if errors.Is(err, botService.ErrBookingClosed) || errors.Is(err, models.ErrBookingNotFound) {
+		if err := h.service.ClearSession(ctx, userID); err != nil {
+			logger.Error("failed to clear session", zap.Error(err))
+		}
+		return h.sendError(ctx, chatID, i18n.BookingErrRescheduleGone)
+	}
+	if err != nil {
+		logger.Error("booking rescheduling error", zap.Error(err))
+		return h.sendError(ctx, chatID, i18n.BookingErrReschedule)
+	}
+
+	if err := h.service.ClearSession(ctx, userID); err != nil {
+		logger.Error("failed to clear session", zap.Error(err))
+	}
+
+	text := i18n.T(ctx, i18n.BookingRescheduled,
+		booking.ID, booking.ServiceName, state.SelectedSlot.Date,
+		state.SelectedSlot.StartTime, state.SelectedSlot.EndTime)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = h.keyboard.MainMenuKeyboard(ctx)
+	if _, err := h.bot.Send(msg); err != nil {
+		return err
+	}
+
+	logger.Info("Booking rescheduled",
+		zap.Int64("booking_id", booking.ID),
+		zap.Int64("user_id", userID),
+		zap.String("date", state.SelectedSlot.Date),
+		zap.String("start_time", state.SelectedSlot.StartTime))
+
+	return nil
+}
+
+// slotOccupied reports that the selected slot has no free places and returns to date selection
+func (h *BookingFormHandler) slotOccupied(ctx context.Context, userID, chatID int64, state *botService.BookingState) error {
+	logger.Info("slot is fully booked, returning to date selection",
+		zap.Int64("user_id", userID),
+		zap.String("date", state.SelectedSlot.Date),
+		zap.String("start_time", state.SelectedSlot.StartTime))
+
+	if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingSlotOccupied))); err != nil {
+		return err
+	}
+	state.SelectedSlot = models.BoxAvailableSlot{}
+	state.Step = botService.StepSelectDate
+	return h.renderDateSelection(ctx, state, chatID, "1")
+}
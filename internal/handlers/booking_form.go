@@ -9,6 +9,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
@@ -81,13 +82,13 @@ func (h *BookingFormHandler) Handle(ctx context.Context, query *tgbotapi.Callbac
 
 	case botService.StepSelectDate:
 		if state == nil {
-			return h.sendError(chatID, "сессия не найдена")
+			return h.sendError(ctx, chatID, i18n.BookingErrSessionNotFound)
 		}
 		return h.stepDateSelect(ctx, query, state, parts)
 
 	case botService.StepConfirmation:
 		if state == nil {
-			return h.sendError(chatID, "сессия не найдена")
+			return h.sendError(ctx, chatID, i18n.BookingErrSessionNotFound)
 		}
 		return h.stepConfirmation(ctx, query, state)
 
@@ -96,7 +97,7 @@ func (h *BookingFormHandler) Handle(ctx context.Context, query *tgbotapi.Callbac
 
 	default:
 		logger.Warn("unknown action", zap.Int("Action", action))
-		return h.sendError(chatID, "неизвестное действие")
+		return h.sendError(ctx, chatID, i18n.BookingErrUnknownAction)
 	}
 }
 
@@ -108,7 +109,7 @@ func (h *BookingFormHandler) HandleTextMessage(ctx context.Context, msg *tgbotap
 
 	state := h.service.GetBookingState(ctx, userID)
 	if state == nil {
-		return h.sendError(chatID, "Ошибка при получении статуса")
+		return h.sendError(ctx, chatID, i18n.BookingErrStatus)
 	}
 
 	if state.OldMessageID != nil {
@@ -132,7 +133,7 @@ func (h *BookingFormHandler) HandleTextMessage(ctx context.Context, msg *tgbotap
 
 	default:
 		logger.Warn("unknown action", zap.Int("Action", state.Step))
-		return h.sendError(chatID, "неизвестное действие")
+		return h.sendError(ctx, chatID, i18n.BookingErrUnknownAction)
 	}
 }
 
@@ -168,20 +169,20 @@ func (h *BookingFormHandler) handleBack(
 	}
 
 	if len(parts) < 3 {
-		return h.sendError(chatID, "Неверный формат кнопки Назад")
+		return h.sendError(ctx, chatID, i18n.BookingErrBackFormat)
 	}
 
 	if state == nil || state.OldMessageID == nil {
-		return h.sendError(chatID, "Сессии пользователя не существует")
+		return h.sendError(ctx, chatID, i18n.BookingErrNoSession)
 	}
 
 	targetStep, err := strconv.Atoi(parts[2])
 	if err != nil {
-		return h.sendError(chatID, "Неверный шаг")
+		return h.sendError(ctx, chatID, i18n.BookingErrStep)
 	}
 
 	if query.Message.MessageID != *state.OldMessageID {
-		return h.sendError(chatID, "Ошибка в сессии")
+		return h.sendError(ctx, chatID, i18n.BookingErrSession)
 	}
 
 	switch targetStep {
@@ -229,13 +230,13 @@ func (h *BookingFormHandler) handleBack(
 		return h.renderPositionInput(ctx, chatID, state)
 
 	default:
-		return h.sendError(chatID, "Нельзя вернуться на этот шаг")
+		return h.sendError(ctx, chatID, i18n.BookingErrStepUnavailable)
 	}
 }
 
 // sendError sends an error message
-func (h *BookingFormHandler) sendError(chatID int64, errorMsg string) error {
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %s", errorMsg))
+func (h *BookingFormHandler) sendError(ctx context.Context, chatID int64, key i18n.Key) error {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ErrorFormat, i18n.T(ctx, key)))
 	if _, err := h.bot.Send(msg); err != nil {
 		return err
 	}
@@ -106,7 +106,7 @@ func TestMain(m *testing.M) {
 	handlerBookingRepo = postgres.NewBookingRepository(handlerTestDB)
 	handlerBoxRepo = postgres.NewBoxSolutionRepo(handlerTestDB)
 
-	handlerService = botService.NewBookingService(handlerSessionRepo, handlerBookingRepo, handlerBoxRepo)
+	handlerService = botService.NewBookingService(handlerSessionRepo, handlerBookingRepo, handlerBoxRepo, postgres.NewTxRepo(handlerTestDB), nil, nil)
 	bsService = service.NewBoxSolutionsService(handlerBoxRepo)
 
 	code := m.Run()
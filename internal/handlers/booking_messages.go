@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/handlers/validation"
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
@@ -16,12 +18,8 @@ import (
 func (h *BookingFormHandler) renderNameInput(ctx context.Context, query *tgbotapi.CallbackQuery, state *botService.BookingState) error {
 	chatID := query.Message.Chat.ID
 
-	var messageText strings.Builder
-	messageText.WriteString("*Введите ФИО*\n\n")
-	messageText.WriteString("Формат: Фамилия Имя Отчество\n")
-
-	keyboard := h.keyboard.FormNavigationKeyboard(botService.StepStartBooking)
-	msg := tgbotapi.NewMessage(chatID, messageText.String())
+	keyboard := h.keyboard.FormNavigationKeyboard(ctx, botService.StepStartBooking)
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingEnterName))
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = &keyboard
 
@@ -48,12 +46,9 @@ func (h *BookingFormHandler) stepNameInput(
 	text := msg.Text
 
 	if err := h.service.ValidateAndSetName(ctx, state, text); err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
-			"*Ошибка валидации ФИО*\n\n%s\n\nВведите ФИО еще раз:",
-			err.Error(),
-		))
+		msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingInvalidName, validationText(ctx, err)))
 		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(botService.StepSelectDate)
+		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(ctx, botService.StepSelectDate)
 		sent, err := h.bot.Send(msg)
 		if err != nil {
 			return err
@@ -76,14 +71,9 @@ func (h *BookingFormHandler) stepNameInput(
 
 // renderOrganizationInput displays the step of entering the organization
 func (h *BookingFormHandler) renderOrganizationInput(ctx context.Context, chatID int64, state *botService.BookingState) error {
-	var messageText strings.Builder
-	messageText.WriteString("Введите организацию\n\n")
-	messageText.WriteString("Можно использовать буквы, цифры, кавычки\n\n")
-	messageText.WriteString("Пример: ООО \"Ромашка\"")
-
-	msg := tgbotapi.NewMessage(chatID, messageText.String())
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingEnterOrg))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(botService.StepEnterName)
+	msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(ctx, botService.StepEnterName)
 
 	sent, err := h.bot.Send(msg)
 	if err != nil {
@@ -108,12 +98,9 @@ func (h *BookingFormHandler) stepOrganizationInput(
 	text := msg.Text
 
 	if err := h.service.ValidateAndSetOrganization(ctx, state, text); err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
-			"*Ошибка валидации организации*\n\n%s\n\nВведите название организации еще раз:",
-			err.Error(),
-		))
+		msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingInvalidOrg, validationText(ctx, err)))
 		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(botService.StepEnterName)
+		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(ctx, botService.StepEnterName)
 
 		sent, err := h.bot.Send(msg)
 		if err != nil {
@@ -137,13 +124,9 @@ func (h *BookingFormHandler) stepOrganizationInput(
 
 // renderPositionInput displays the step of entering the position
 func (h *BookingFormHandler) renderPositionInput(ctx context.Context, chatID int64, state *botService.BookingState) error {
-	var messageText strings.Builder
-	messageText.WriteString("Введите должность\n\n")
-	messageText.WriteString("Пример: Менеджер по продажам")
-
-	msg := tgbotapi.NewMessage(chatID, messageText.String())
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingEnterPosition))
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(botService.StepEnterOrg)
+	msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(ctx, botService.StepEnterOrg)
 
 	sent, err := h.bot.Send(msg)
 	if err != nil {
@@ -168,12 +151,9 @@ func (h *BookingFormHandler) stepPositionInput(
 	text := msg.Text
 
 	if err := h.service.ValidateAndSetPosition(ctx, state, text); err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
-			"*Ошибка валидации должности*\n\n%s\n\nВведите должность еще раз:",
-			err.Error(),
-		))
+		msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.BookingInvalidPosition, validationText(ctx, err)))
 		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(botService.StepEnterOrg)
+		msg.ReplyMarkup = h.keyboard.FormNavigationKeyboard(ctx, botService.StepEnterOrg)
 
 		sent, err := h.bot.Send(msg)
 		if err != nil {
@@ -198,28 +178,22 @@ func (h *BookingFormHandler) stepPositionInput(
 // renderConfirmation displays the booking confirmation step
 func (h *BookingFormHandler) renderConfirmation(ctx context.Context, chatID int64, state *botService.BookingState) error {
 	var messageText strings.Builder
-	messageText.WriteString("Подтверждение бронирования\n\n")
-	if _, err := fmt.Fprintf(&messageText, "Название: %s\n", state.ServiceName); err != nil {
-		return fmt.Errorf("format confirmation title: %w", err)
-	}
-	if _, err := fmt.Fprintf(&messageText, "Дата: %s\n", state.SelectedSlot.Date); err != nil {
-		return fmt.Errorf("format confirmation date: %w", err)
-	}
-	if _, err := fmt.Fprintf(&messageText, "Время: %s - %s\n", state.SelectedSlot.StartTime, state.SelectedSlot.EndTime); err != nil {
-		return fmt.Errorf("format confirmation date: %w", err)
-	}
-	if _, err := fmt.Fprintf(&messageText, "ФИО: %s\n", state.GuestName); err != nil {
-		return fmt.Errorf("format confirmation name: %w", err)
-	}
-	if _, err := fmt.Fprintf(&messageText, "Организация: %s\n", state.GuestOrganization); err != nil {
-		return fmt.Errorf("format confirmation org: %w", err)
-	}
-	if _, err := fmt.Fprintf(&messageText, "Должность: %s\n\n", state.GuestPosition); err != nil {
-		return fmt.Errorf("format confirmation position: %w", err)
-	}
-	messageText.WriteString("Проверьте правильность введенных данных\n")
-
-	keyboard := h.keyboard.ConfirmationKeyboard(botService.StepEnterPosition)
+	backStep := botService.StepEnterPosition
+	if state.RescheduleBookingID != 0 {
+		backStep = botService.StepStartBooking
+		messageText.WriteString(i18n.T(ctx, i18n.BookingRescheduleTitle, state.RescheduleBookingID))
+	} else {
+		messageText.WriteString(i18n.T(ctx, i18n.BookingConfirmTitle))
+	}
+	messageText.WriteString(i18n.T(ctx, i18n.BookingConfirmDetails,
+		state.ServiceName,
+		state.SelectedSlot.Date,
+		state.SelectedSlot.StartTime, state.SelectedSlot.EndTime,
+		state.GuestName,
+		state.GuestOrganization,
+		state.GuestPosition))
+
+	keyboard := h.keyboard.ConfirmationKeyboard(ctx, backStep)
 
 	msg := tgbotapi.NewMessage(chatID, messageText.String())
 	msg.ParseMode = "Markdown"
@@ -237,3 +211,12 @@ func (h *BookingFormHandler) renderConfirmation(ctx context.Context, chatID int6
 	}
 	return nil
 }
+
+// validationText renders a validation error of the form in the guest's language
+func validationText(ctx context.Context, err error) string {
+	var vErr *validation.Error
+	if errors.As(err, &vErr) {
+		return vErr.Text(i18n.FromContext(ctx))
+	}
+	return err.Error()
+}
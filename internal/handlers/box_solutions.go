@@ -10,13 +10,13 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/service"
 )
 
 const (
-	textForBoxSolutions              = "📦 Коробочные решения\n\nВыберите интересующее вас предложение:\n"
 	BoxSolutionsButtonBackToMainMenu = "main_menu"
 	BoxSolutionsPerPage              = 5
 )
@@ -51,14 +51,9 @@ func (h *BoxSolutionsHandler) Handle(ctx context.Context, query *tgbotapi.Callba
 		logger.Error("failed to get inline buttons from service", zap.Int64("chat_id", query.Message.Chat.ID), zap.Error(err))
 	}
 
-	replyMarkup, pageText := getPaginatedBoxSolutionsMenu(boxSolutionsButtons, page)
+	replyMarkup, pageText := getPaginatedBoxSolutionsMenu(ctx, boxSolutionsButtons, page)
 
-	messageText := textForBoxSolutions
-	if pageText != "" {
-		messageText = pageText
-	}
-
-	reply := tgbotapi.NewMessage(query.Message.Chat.ID, messageText)
+	reply := tgbotapi.NewMessage(query.Message.Chat.ID, pageText)
 	reply.ReplyMarkup = replyMarkup
 
 	if _, err := h.bot.Send(reply); err != nil {
@@ -69,15 +64,15 @@ func (h *BoxSolutionsHandler) Handle(ctx context.Context, query *tgbotapi.Callba
 	return nil
 }
 
-func getPaginatedBoxSolutionsMenu(boxSolutionsButtons []models.BoxSolutionsButton, currentPage int) (tgbotapi.InlineKeyboardMarkup, string) {
+func getPaginatedBoxSolutionsMenu(ctx context.Context, boxSolutionsButtons []models.BoxSolutionsButton, currentPage int) (tgbotapi.InlineKeyboardMarkup, string) {
 	totalItems := len(boxSolutionsButtons)
 	totalPages := (totalItems + BoxSolutionsPerPage - 1) / BoxSolutionsPerPage
 
 	if totalItems == 0 {
 		var rows [][]tgbotapi.InlineKeyboardButton
-		btnBack := getBackButton(BoxSolutionsButtonBackToMainMenu)
+		btnBack := getBackButton(ctx, BoxSolutionsButtonBackToMainMenu)
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnBack))
-		return tgbotapi.NewInlineKeyboardMarkup(rows...), "❌ Решения не найдены"
+		return tgbotapi.NewInlineKeyboardMarkup(rows...), i18n.T(ctx, i18n.BoxSolutionsEmpty)
 	}
 
 	if currentPage < 1 {
@@ -107,26 +102,24 @@ func getPaginatedBoxSolutionsMenu(boxSolutionsButtons []models.BoxSolutionsButto
 
 	if currentPage > 1 {
 		prevData := fmt.Sprintf("box_solutions:page:%d", currentPage-1)
-		paginationRow = append(paginationRow, tgbotapi.NewInlineKeyboardButtonData("← Предыдущая", prevData))
+		paginationRow = append(paginationRow, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonPrevPage), prevData))
 	}
 
 	if currentPage < totalPages {
 		nextData := fmt.Sprintf("box_solutions:page:%d", currentPage+1)
-		paginationRow = append(paginationRow, tgbotapi.NewInlineKeyboardButtonData("Следующая →", nextData))
+		paginationRow = append(paginationRow, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonNextPage), nextData))
 	}
 
 	if len(paginationRow) > 0 {
 		rows = append(rows, paginationRow)
 	}
 
-	btnBack := getBackButton(BoxSolutionsButtonBackToMainMenu)
+	btnBack := getBackButton(ctx, BoxSolutionsButtonBackToMainMenu)
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnBack))
 
-	var pageText string
+	pageText := i18n.T(ctx, i18n.BoxSolutionsTitle)
 	if totalPages > 1 {
-		pageText = fmt.Sprintf("%s\n\n📄 Страница %d из %d", textForBoxSolutions, currentPage, totalPages)
-	} else {
-		pageText = textForBoxSolutions
+		pageText = fmt.Sprintf("%s\n\n%s", pageText, i18n.T(ctx, i18n.BoxSolutionsPage, currentPage, totalPages))
 	}
 
 	return tgbotapi.NewInlineKeyboardMarkup(rows...), pageText
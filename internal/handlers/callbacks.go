@@ -39,7 +39,7 @@ func (r *CallbackRouter) Register(name string, handler CallbackHandler) {
 }
 
 // HandleCallback обрабатывает callback запрос
-func HandleCallback(router *CallbackRouter, query *tgbotapi.CallbackQuery) error {
+func HandleCallback(ctx context.Context, router *CallbackRouter, query *tgbotapi.CallbackQuery) error {
 
 	// Инкрементируем CallbacksReceived
 	metrics.IncCallbacksReceived()
@@ -69,7 +69,7 @@ func HandleCallback(router *CallbackRouter, query *tgbotapi.CallbackQuery) error
 		}
 	}()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	handler, err := router.findHandler(query.Data)
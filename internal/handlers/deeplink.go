@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	botService "github.com/yandex-development-1-team/go/internal/service/bot"
+)
+
+// UserSourceRepository stores the campaign a guest came from
+type UserSourceRepository interface {
+	SetSource(ctx context.Context, telegramID int64, source string) error
+}
+
+// ServiceSlugLookup finds the box a deep link points to
+type ServiceSlugLookup interface {
+	GetIDBySlug(ctx context.Context, slug string) (int64, error)
+}
+
+// deepLinkCallbacks are the menu buttons the deep link sections stand for
+var deepLinkCallbacks = map[string]string{
+	botService.LinkSectionBoxes:          CallbackBoxSolutions,
+	botService.LinkSectionSpecialProject: CallbackSpecialProject,
+	botService.LinkSectionGuide:          CallbackVisitGuide,
+	botService.LinkSectionExamples:       CallbackProjectExamples,
+	botService.LinkSectionAbout:          CallbackAboutUs,
+	botService.LinkSectionSupport:        CallbackSupport,
+}
+
+// DeepLinkHandler handles '/start <payload>' from t.me/<bot>?start=<payload>
+// links: it records the src_ campaign of the guest and opens the linked
+// section or box card as if the guest pressed its button in the main menu.
+type DeepLinkHandler struct {
+	sh       *StartHandler
+	router   *CallbackRouter
+	services ServiceSlugLookup
+	sources  UserSourceRepository
+}
+
+func NewDeepLinkHandler(sh *StartHandler, router *CallbackRouter, services ServiceSlugLookup, sources UserSourceRepository) *DeepLinkHandler {
+	return &DeepLinkHandler{
+		sh:       sh,
+		router:   router,
+		services: services,
+		sources:  sources,
+	}
+}
+
+// HandleStart processes the '/start' command with or without a payload
+func (h *DeepLinkHandler) HandleStart(ctx context.Context, msg *tgbotapi.Message) error {
+	link := botService.ParseStartLink(msg.CommandArguments())
+
+	menu, err := h.sh.start(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	if link.Source != "" {
+		if err := h.sources.SetSource(ctx, msg.From.ID, link.Source); err != nil {
+			logger.Error("failed to save user source",
+				zap.Int64("telegram_id", msg.From.ID), zap.String("source", link.Source), zap.Error(err))
+		}
+	}
+	if link.Section == "" {
+		return nil
+	}
+
+	data, err := h.callbackData(ctx, link)
+	if err != nil {
+		// The main menu is already shown, so a stale link just lands there
+		logger.Warn("deep link target not found",
+			zap.String("section", link.Section), zap.String("slug", link.Slug), zap.Error(err))
+		return nil
+	}
+
+	handler, err := h.router.findHandler(data)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("deep link opened",
+		zap.Int64("telegram_id", msg.From.ID), zap.String("callback_data", data), zap.String("source", link.Source))
+	return handler.Handle(ctx, &tgbotapi.CallbackQuery{From: msg.From, Message: menu, Data: data})
+}
+
+// callbackData returns the callback of the button the link stands for
+func (h *DeepLinkHandler) callbackData(ctx context.Context, link botService.StartLink) (string, error) {
+	if link.Section == botService.LinkSectionBoxes && link.Slug != "" {
+		id, err := h.services.GetIDBySlug(ctx, link.Slug)
+		if err != nil {
+			return "", err
+		}
+		// The back button of the card returns to the first page of boxes
+		return fmt.Sprintf("%s:ID:%d:1", CallbackInfoPrefix, id), nil
+	}
+	return deepLinkCallbacks[link.Section], nil
+}
@@ -8,6 +8,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
@@ -42,7 +43,7 @@ func (h *ExamplesSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQue
 
 	res, err := h.service.GetBySlug(ctx)
 	if err != nil {
-		return h.handleError(chatID, userID, err)
+		return h.handleError(ctx, chatID, userID, err)
 	}
 
 	var builder strings.Builder
@@ -51,7 +52,7 @@ func (h *ExamplesSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQue
 	builder.WriteString("\n\n")
 
 	if len(res.Links) > 0 {
-		builder.WriteString("*Ссылки:*\n")
+		fmt.Fprintf(&builder, "*%s:*\n", i18n.T(ctx, i18n.ResourceLinks))
 		for i, link := range res.Links {
 			title := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, link.Title)
 			url := link.URL
@@ -61,7 +62,7 @@ func (h *ExamplesSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQue
 
 	msg := tgbotapi.NewMessage(chatID, builder.String())
 	msg.ParseMode = "Markdown"
-	keyboard := h.keyboard.CreateButton("Назад", "main_menu")
+	keyboard := h.keyboard.CreateButton(i18n.T(ctx, i18n.ButtonBack), "main_menu")
 	msg.ReplyMarkup = keyboard
 
 	if _, err := h.bot.Send(msg); err != nil {
@@ -71,20 +72,20 @@ func (h *ExamplesSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQue
 }
 
 // sendError sends an error message
-func (h *ExamplesSpHandler) sendError(chatID int64, errorMsg string) error {
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %s", errorMsg))
+func (h *ExamplesSpHandler) sendError(ctx context.Context, chatID int64, key i18n.Key) error {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ErrorFormat, i18n.T(ctx, key)))
 	_, err := h.bot.Send(msg)
 	return err
 }
 
 // handleError logs failures and sends a message
-func (h *ExamplesSpHandler) handleError(chatID int64, userID int64, err error) error {
+func (h *ExamplesSpHandler) handleError(ctx context.Context, chatID int64, userID int64, err error) error {
 	logger.Error("failed to get examples_sp",
 		zap.Int64("user_id", userID),
 		zap.Error(err),
 	)
 
-	if sendErr := h.sendError(chatID, "Не удалось загрузить примеры спецпроектов"); sendErr != nil {
+	if sendErr := h.sendError(ctx, chatID, i18n.ResourceErrExamples); sendErr != nil {
 		return sendErr
 	}
 
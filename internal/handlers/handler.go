@@ -6,6 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/metrics"
 )
@@ -23,26 +24,35 @@ type Handler struct {
 	msgRL          MsgRateLimiter
 	msgRouter      *MessageRouter
 	callbackRouter *CallbackRouter
+	languages      LanguageRepository
+	messages       MessageSettings
 }
 
-func NewHandler(bot Bot, msgRL MsgRateLimiter, msgRouter *MessageRouter, callbackRouter *CallbackRouter) *Handler {
+func NewHandler(bot Bot, msgRL MsgRateLimiter, msgRouter *MessageRouter, callbackRouter *CallbackRouter, languages LanguageRepository, messages MessageSettings) *Handler {
 	return &Handler{
 		bot:            bot,
 		msgRL:          msgRL,
 		msgRouter:      msgRouter,
 		callbackRouter: callbackRouter,
+		languages:      languages,
+		messages:       messages,
 	}
 }
 
+// Handle routes the update; the handlers render their texts in the language
+// of the user the update came from, the admin messages override the catalog
 func (h *Handler) Handle(ctx context.Context, update tgbotapi.Update) {
 	activeUsers := getActiveUsersCount(ctx)
 	metrics.SetActiveUsers(activeUsers)
 
+	ctx = i18n.WithLang(ctx, userLanguage(ctx, h.languages, update.SentFrom()))
+	ctx = withAdminMessages(ctx, h.messages)
+
 	if msg := update.Message; msg != nil {
 		h.msgRouter.HandleMessage(ctx, msg)
 	}
 	if callbackQuery := update.CallbackQuery; callbackQuery != nil {
-		if err := HandleCallback(h.callbackRouter, update.CallbackQuery); err != nil {
+		if err := HandleCallback(ctx, h.callbackRouter, update.CallbackQuery); err != nil {
 			logger.Error("callback handling", zap.Error(err))
 		}
 	}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/models"
 )
 
@@ -21,12 +23,11 @@ const (
 
 // используется для определения значения serviceType
 const (
-	bookHandler      = "book"
-	backButtons      = "back"
-	BackButtonsTitle = "⬅️ Назад"
+	bookHandler = "book"
+	backButtons = "back"
 )
 
-// KeyboardService генерирует клавиатуры для разных экранов
+// KeyboardService генерирует клавиатуры для разных экранов на языке гостя из контекста
 type KeyboardService struct{}
 
 func NewKeyboardService() *KeyboardService {
@@ -34,33 +35,33 @@ func NewKeyboardService() *KeyboardService {
 }
 
 // ServiceDetailKeyboard создаёт клавиатуру для детального просмотра услуги
-func (ks *KeyboardService) ServiceDetailKeyboard(serviceID int64, serviceName string, page string) tgbotapi.InlineKeyboardMarkup {
+func (ks *KeyboardService) ServiceDetailKeyboard(ctx context.Context, serviceID int64, serviceName string, page string) tgbotapi.InlineKeyboardMarkup {
 	var buttons [][]tgbotapi.InlineKeyboardButton
 
 	buttons = [][]tgbotapi.InlineKeyboardButton{
 		{
-			tgbotapi.NewInlineKeyboardButtonData("📅 Забронировать", fmt.Sprintf("%s:%d:%s:%s", bookHandler, serviceID, serviceName, page)),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonBook), fmt.Sprintf("%s:%d:%s:%s", bookHandler, serviceID, serviceName, page)),
 		},
 	}
 
-	backButton := tgbotapi.NewInlineKeyboardButtonData(BackButtonsTitle, fmt.Sprintf("info:%s:%s", backButtons, page))
+	backButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonBackArrow), fmt.Sprintf("info:%s:%s", backButtons, page))
 	buttons = append(buttons, []tgbotapi.InlineKeyboardButton{backButton})
 
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
-func getBackButton(alias string) tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData("Назад", alias)
+func getBackButton(ctx context.Context, alias string) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonBack), alias)
 }
 
 // FormNavigationKeyboard creates a keyboard with navigation for the steps of the form
-func (ks *KeyboardService) FormNavigationKeyboard(step int) tgbotapi.InlineKeyboardMarkup {
+func (ks *KeyboardService) FormNavigationKeyboard(ctx context.Context, step int) tgbotapi.InlineKeyboardMarkup {
 	buttons := [][]tgbotapi.InlineKeyboardButton{
 		{
-			getBackButton(fmt.Sprintf("book:back:%d", step)),
+			getBackButton(ctx, fmt.Sprintf("book:back:%d", step)),
 		},
 		{
-			tgbotapi.NewInlineKeyboardButtonData("В главное меню", "book:main_menu"),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonMainMenu), "book:main_menu"),
 		},
 	}
 
@@ -68,32 +69,54 @@ func (ks *KeyboardService) FormNavigationKeyboard(step int) tgbotapi.InlineKeybo
 }
 
 // ConfirmationKeyboard creates a keyboard for the confirmation step
-func (ks *KeyboardService) ConfirmationKeyboard(step int) tgbotapi.InlineKeyboardMarkup {
+func (ks *KeyboardService) ConfirmationKeyboard(ctx context.Context, step int) tgbotapi.InlineKeyboardMarkup {
 	buttons := [][]tgbotapi.InlineKeyboardButton{
 		{
-			tgbotapi.NewInlineKeyboardButtonData("Подтвердить", "book:confirm"),
-			getBackButton("book:back:4"),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonConfirm), "book:confirm"),
+			getBackButton(ctx, fmt.Sprintf("book:back:%d", step)),
 		},
 		{
-			tgbotapi.NewInlineKeyboardButtonData("Отменить", "book:main_menu"),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancel), "book:main_menu"),
 		},
 	}
 
 	return tgbotapi.NewInlineKeyboardMarkup(buttons...)
 }
 
+// BookingActionsKeyboard creates 'Cancel' and 'Reschedule' buttons for the guest's booking
+func (ks *KeyboardService) BookingActionsKeyboard(ctx context.Context, bookingID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancelBooking), fmt.Sprintf("%s:%s:%d", CallbackMyBooking, myBookingCancel, bookingID)),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonReschedule), fmt.Sprintf("%s:%s:%d", CallbackMyBooking, myBookingReschedule, bookingID)),
+		),
+	)
+}
+
+// CancelBookingKeyboard asks the guest to confirm the cancellation
+func (ks *KeyboardService) CancelBookingKeyboard(ctx context.Context, bookingID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancelYes), fmt.Sprintf("%s:%s:%d", CallbackMyBooking, myBookingCancelConfirm, bookingID)),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonNo), fmt.Sprintf("%s:%s:%d", CallbackMyBooking, myBookingKeep, bookingID)),
+		),
+	)
+}
+
 // MainMenuKeyboard creates a 'To Main Menu' button
-func (ks *KeyboardService) MainMenuKeyboard() *tgbotapi.InlineKeyboardMarkup {
+func (ks *KeyboardService) MainMenuKeyboard(ctx context.Context) *tgbotapi.InlineKeyboardMarkup {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("В главное меню", "book:main_menu"),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonMainMenu), "book:main_menu"),
 		),
 	)
 	return &keyboard
 }
 
-// DatesKeyboard creates an inline keyboard with available dates and time slots
-func (ks *KeyboardService) DatesKeyboard(slots []models.BoxAvailableSlot, page string) tgbotapi.InlineKeyboardMarkup {
+// DatesKeyboard creates an inline keyboard with available dates and time slots.
+// Fully booked slots are not shown.
+func (ks *KeyboardService) DatesKeyboard(ctx context.Context, slots []models.BoxAvailableSlot, page string) tgbotapi.InlineKeyboardMarkup {
+	slots = FreeSlots(slots)
 	if len(slots) == 0 {
 		return tgbotapi.InlineKeyboardMarkup{}
 	}
@@ -107,7 +130,7 @@ func (ks *KeyboardService) DatesKeyboard(slots []models.BoxAvailableSlot, page s
 		// Первая кнопка в строке
 		slot1 := (slots)[i]
 		btn1 := tgbotapi.NewInlineKeyboardButtonData(
-			ks.formatSlotButton(slot1),
+			ks.formatSlotButton(ctx, slot1),
 			ks.buildSlotCallback(slot1),
 		)
 		row = append(row, btn1)
@@ -116,7 +139,7 @@ func (ks *KeyboardService) DatesKeyboard(slots []models.BoxAvailableSlot, page s
 		if i+1 < len(slots) {
 			slot2 := (slots)[i+1]
 			btn2 := tgbotapi.NewInlineKeyboardButtonData(
-				ks.formatSlotButton(slot2),
+				ks.formatSlotButton(ctx, slot2),
 				ks.buildSlotCallback(slot2),
 			)
 			row = append(row, btn2)
@@ -127,10 +150,10 @@ func (ks *KeyboardService) DatesKeyboard(slots []models.BoxAvailableSlot, page s
 
 	buttons := [][]tgbotapi.InlineKeyboardButton{
 		{
-			getBackButton(fmt.Sprintf("book:back:page:%s", page)),
+			getBackButton(ctx, fmt.Sprintf("book:back:page:%s", page)),
 		},
 		{
-			tgbotapi.NewInlineKeyboardButtonData("В главное меню", "book:main_menu"),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonMainMenu), "book:main_menu"),
 		},
 	}
 
@@ -139,6 +162,17 @@ func (ks *KeyboardService) DatesKeyboard(slots []models.BoxAvailableSlot, page s
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
+// FreeSlots returns the slots that still accept bookings
+func FreeSlots(slots []models.BoxAvailableSlot) []models.BoxAvailableSlot {
+	free := make([]models.BoxAvailableSlot, 0, len(slots))
+	for _, slot := range slots {
+		if !slot.IsFull() {
+			free = append(free, slot)
+		}
+	}
+	return free
+}
+
 // buildSlotCallback generates callback data for the slot
 func (ks *KeyboardService) buildSlotCallback(slot models.BoxAvailableSlot) string {
 	startTime := strings.ReplaceAll(slot.StartTime, ":", ".")
@@ -147,7 +181,7 @@ func (ks *KeyboardService) buildSlotCallback(slot models.BoxAvailableSlot) strin
 }
 
 // formatSlotButton formats the date to display on the button
-func (ks *KeyboardService) formatSlotButton(slot models.BoxAvailableSlot) string {
+func (ks *KeyboardService) formatSlotButton(ctx context.Context, slot models.BoxAvailableSlot) string {
 	date, err := time.Parse("2006-01-02", slot.Date)
 	if err != nil {
 		return slot.Date
@@ -158,27 +192,20 @@ func (ks *KeyboardService) formatSlotButton(slot models.BoxAvailableSlot) string
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	if date.YearDay() == today.YearDay() && date.Year() == today.Year() {
-		dateStr = "Сегодня"
+		dateStr = i18n.T(ctx, i18n.SlotToday)
 	} else if date.YearDay() == today.AddDate(0, 0, 1).YearDay() && date.Year() == today.Year() {
-		dateStr = "Завтра"
+		dateStr = i18n.T(ctx, i18n.SlotTomorrow)
 	} else {
-		russianMonths := []string{
-			"янв", "фев", "мар", "апр", "май", "июн",
-			"июл", "авг", "сен", "окт", "ноя", "дек",
-		}
-		russianWeekdays := []string{
-			"вс", "пн", "вт", "ср", "чт", "пт", "сб",
-		}
-
-		monthIdx := date.Month() - 1
-		weekdayIdx := date.Weekday()
 		dateStr = fmt.Sprintf("%02d %s (%s)",
 			date.Day(),
-			russianMonths[monthIdx],
-			russianWeekdays[weekdayIdx])
+			i18n.ShortMonth(ctx, date.Month()),
+			i18n.ShortWeekday(ctx, date.Weekday()))
 	}
 
 	timeStr := fmt.Sprintf("%s-%s", slot.StartTime, slot.EndTime)
+	if slot.Capacity > 1 {
+		timeStr += i18n.T(ctx, i18n.SlotPlaces, slot.Capacity-slot.Booked)
+	}
 
 	return fmt.Sprintf("%s\n%s", dateStr, timeStr)
 }
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+// CallbackLanguage is the prefix of the language menu: "lang" opens it and
+// "lang:<code>" switches the language
+const CallbackLanguage = "lang"
+
+// LanguageRepository stores the language a guest chose in the bot
+type LanguageRepository interface {
+	GetLanguage(ctx context.Context, telegramID int64) (string, error)
+	SetLanguage(ctx context.Context, telegramID int64, lang string) error
+}
+
+// LanguageHandler lets the guest switch the language of the bot
+type LanguageHandler struct {
+	bot       BotAPI
+	sh        *StartHandler
+	languages LanguageRepository
+}
+
+func NewLanguageHandler(bot *tgbotapi.BotAPI, sh *StartHandler, languages LanguageRepository) *LanguageHandler {
+	return &LanguageHandler{
+		bot:       bot,
+		sh:        sh,
+		languages: languages,
+	}
+}
+
+// Handle shows the languages or saves the chosen one and shows the main menu in it
+func (h *LanguageHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuery) error {
+	chatID := query.Message.Chat.ID
+
+	parts := strings.Split(query.Data, ":")
+	if len(parts) < 2 {
+		delTgMessage(h.bot, query.Message)
+
+		reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.LanguageChoose))
+		reply.ReplyMarkup = languageKeyboard()
+		_, err := h.bot.Send(reply)
+		return err
+	}
+
+	lang, ok := i18n.Parse(parts[1])
+	if !ok {
+		return fmt.Errorf("unknown language %q", parts[1])
+	}
+
+	if err := h.languages.SetLanguage(ctx, query.From.ID, string(lang)); err != nil {
+		logger.Error("failed to save user language", zap.Int64("telegram_id", query.From.ID), zap.Error(err))
+		_, _ = h.bot.Send(tgbotapi.NewMessage(chatID, systemErrorText(ctx)))
+		return err
+	}
+
+	logger.Info("user language changed", zap.Int64("telegram_id", query.From.ID), zap.String("language", string(lang)))
+	return h.sh.Handle(i18n.WithLang(ctx, lang), query)
+}
+
+func languageKeyboard() tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(i18n.Supported))
+	for _, lang := range i18n.Supported {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.Name(), CallbackLanguage+":"+string(lang)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// userLanguage is the language the guest chose in the bot or, until they do,
+// the one of their Telegram client
+func userLanguage(ctx context.Context, languages LanguageRepository, user *tgbotapi.User) i18n.Lang {
+	if user == nil {
+		return i18n.Default
+	}
+
+	if languages != nil {
+		stored, err := languages.GetLanguage(ctx, user.ID)
+		if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+			logger.Error("failed to get user language", zap.Int64("telegram_id", user.ID), zap.Error(err))
+		}
+		if lang, ok := i18n.Parse(stored); ok {
+			return lang
+		}
+	}
+	return i18n.FromTelegram(user.LanguageCode)
+}
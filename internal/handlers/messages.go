@@ -17,8 +17,11 @@ type MessageRouter struct {
 	bot           *tgbotapi.BotAPI
 	sh            *StartHandler
 	statusHandler *StatusHandler
+	staffHandler  *StaffHandler
+	deepLinks     *DeepLinkHandler
 	session       repository.SessionRepository
 	bookHandler   *BookingFormHandler
+	spRequest     *SpRequestHandler
 	msgRL         MsgRateLimiter
 }
 
@@ -30,13 +33,19 @@ func NewMessageRouter(
 	session repository.SessionRepository,
 	bookHandler *BookingFormHandler,
 	msgRL MsgRateLimiter,
+	staffHandler *StaffHandler,
+	deepLinks *DeepLinkHandler,
+	spRequest *SpRequestHandler,
 ) *MessageRouter {
 	return &MessageRouter{
 		bot:           bot,
 		sh:            sh,
 		statusHandler: statusHandler,
+		staffHandler:  staffHandler,
+		deepLinks:     deepLinks,
 		session:       session,
 		bookHandler:   bookHandler,
+		spRequest:     spRequest,
 		msgRL:         msgRL,
 	}
 }
@@ -54,7 +63,7 @@ func (r *MessageRouter) HandleMessage(ctx context.Context, msg *tgbotapi.Message
 
 	userID := msg.From.ID
 
-	ctxSession, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctxSession, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	state, err := r.session.GetSession(ctxSession, userID)
@@ -63,9 +72,10 @@ func (r *MessageRouter) HandleMessage(ctx context.Context, msg *tgbotapi.Message
 			zap.Error(err),
 			zap.Int64("user_id", userID),
 		)
+		return
 	}
 
-	ctxStep, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctxStep, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	switch state.CurrentState {
@@ -73,6 +83,13 @@ func (r *MessageRouter) HandleMessage(ctx context.Context, msg *tgbotapi.Message
 		if err := r.bookHandler.HandleTextMessage(ctxStep, msg); err != nil {
 			logger.Error("booking text message", zap.Error(err))
 		}
+	case botService.CallbackSpRequestPrefix:
+		if r.spRequest == nil {
+			return
+		}
+		if err := r.spRequest.HandleTextMessage(ctxStep, msg); err != nil {
+			logger.Error("special project request text message", zap.Error(err))
+		}
 	}
 }
 
@@ -80,7 +97,11 @@ func (r *MessageRouter) HandleMessage(ctx context.Context, msg *tgbotapi.Message
 func (r *MessageRouter) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
 	switch msg.Command() {
 	case "start":
-		if err := r.msgRL.Exec(ctx, msg.Chat.ID, func() error { return r.sh.HandleStart(ctx, msg) }); err != nil {
+		handleStart := r.sh.HandleStart
+		if r.deepLinks != nil {
+			handleStart = r.deepLinks.HandleStart
+		}
+		if err := r.msgRL.Exec(ctx, msg.Chat.ID, func() error { return handleStart(ctx, msg) }); err != nil {
 			logger.Error("failed to handle /start", zap.Error(err))
 		}
 
@@ -88,5 +109,13 @@ func (r *MessageRouter) handleCommand(ctx context.Context, msg *tgbotapi.Message
 		if err := r.msgRL.Exec(ctx, msg.Chat.ID, func() error { return r.statusHandler.Handle(ctx, msg) }); err != nil {
 			logger.Error("failed to handle /status", zap.Error(err))
 		}
+
+	case "link":
+		if r.staffHandler == nil {
+			return
+		}
+		if err := r.msgRL.Exec(ctx, msg.Chat.ID, func() error { return r.staffHandler.HandleLink(ctx, msg) }); err != nil {
+			logger.Error("failed to handle /link", zap.Error(err))
+		}
 	}
 }
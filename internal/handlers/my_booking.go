@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	botService "github.com/yandex-development-1-team/go/internal/service/bot"
+)
+
+// CallbackMyBooking is the prefix of the buttons under the guest's bookings in '/status'
+const CallbackMyBooking = "mybook"
+
+// Actions of the guest's booking buttons
+const (
+	myBookingCancel        = "cancel"
+	myBookingCancelConfirm = "cancel_yes"
+	myBookingKeep          = "keep"
+	myBookingReschedule    = "reschedule"
+)
+
+// MyBookingHandler processes the 'Cancel' and 'Reschedule' buttons of the guest's bookings
+type MyBookingHandler struct {
+	bot      BotAPI
+	service  *botService.BookingService
+	form     *BookingFormHandler
+	keyboard *KeyboardService
+}
+
+// NewMyBookingHandler creates a new instance of the 'MyBookingHandler'
+func NewMyBookingHandler(
+	bot *tgbotapi.BotAPI,
+	service *botService.BookingService,
+	form *BookingFormHandler,
+	keyboard *KeyboardService,
+) *MyBookingHandler {
+	return &MyBookingHandler{
+		bot:      bot,
+		service:  service,
+		form:     form,
+		keyboard: keyboard,
+	}
+}
+
+// Handle handles callbacks in the format 'mybook:<action>:<booking_id>'
+func (h *MyBookingHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuery) error {
+	if _, err := h.bot.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+		logger.Error("answer callback query", zap.Error(err), zap.String("callback_id", query.ID))
+		return fmt.Errorf("answer callback query: %w", err)
+	}
+
+	chatID := query.Message.Chat.ID
+	parts := strings.Split(query.Data, ":")
+	if len(parts) != 3 {
+		return h.form.sendError(ctx, chatID, i18n.BookingErrRequestFormat)
+	}
+
+	bookingID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return h.form.sendError(ctx, chatID, i18n.BookingErrNumber)
+	}
+
+	logger.Info("my booking callback received",
+		zap.Int64("user_id", query.From.ID),
+		zap.String("action", parts[1]),
+		zap.Int64("booking_id", bookingID))
+
+	switch parts[1] {
+	case myBookingCancel:
+		return h.editKeyboard(query, h.keyboard.CancelBookingKeyboard(ctx, bookingID))
+	case myBookingKeep:
+		return h.editKeyboard(query, h.keyboard.BookingActionsKeyboard(ctx, bookingID))
+	case myBookingCancelConfirm:
+		return h.cancel(ctx, query, bookingID)
+	case myBookingReschedule:
+		return h.reschedule(ctx, query, bookingID)
+	default:
+		return h.form.sendError(ctx, chatID, i18n.BookingErrUnknownAction)
+	}
+}
+
+// cancel cancels the booking and replaces its card with the result
+func (h *MyBookingHandler) cancel(ctx context.Context, query *tgbotapi.CallbackQuery, bookingID int64) error {
+	chatID := query.Message.Chat.ID
+
+	booking, err := h.service.CancelBooking(ctx, query.From.ID, bookingID)
+	if err != nil {
+		return h.handleServiceError(ctx, query, bookingID, err, i18n.BookingErrCancel)
+	}
+
+	text := fmt.Sprintf("%s\n\n%s",
+		formatBookingCard(ctx, booking.ServiceName, booking.BookingDate, booking.BookingTime, booking.GuestName, booking.Status, booking.ID),
+		i18n.T(ctx, i18n.BookingCancelled))
+	edit := tgbotapi.NewEditMessageText(chatID, query.Message.MessageID, text)
+	if _, err := h.bot.Send(edit); err != nil {
+		return err
+	}
+
+	logger.Info("booking cancelled by guest",
+		zap.Int64("booking_id", bookingID),
+		zap.Int64("user_id", query.From.ID))
+	return nil
+}
+
+// reschedule starts the booking form from the date step for an existing booking
+func (h *MyBookingHandler) reschedule(ctx context.Context, query *tgbotapi.CallbackQuery, bookingID int64) error {
+	state, err := h.service.StartReschedule(ctx, query.From.ID, bookingID)
+	if err != nil {
+		return h.handleServiceError(ctx, query, bookingID, err, i18n.BookingErrStartReschedule)
+	}
+
+	logger.Info("booking rescheduling started",
+		zap.Int64("booking_id", bookingID),
+		zap.Int64("user_id", query.From.ID))
+
+	return h.form.renderDateSelection(ctx, state, query.Message.Chat.ID, "1")
+}
+
+// handleServiceError answers the guest when the booking can not be changed
+func (h *MyBookingHandler) handleServiceError(ctx context.Context, query *tgbotapi.CallbackQuery, bookingID int64, err error, fallback i18n.Key) error {
+	chatID := query.Message.Chat.ID
+
+	if errors.Is(err, botService.ErrBookingClosed) || errors.Is(err, models.ErrBookingNotFound) {
+		edit := tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID, tgbotapi.InlineKeyboardMarkup{
+			InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+		})
+		_, _ = h.bot.Send(edit)
+		return h.form.sendError(ctx, chatID, i18n.BookingErrClosed)
+	}
+
+	logger.Error("my booking action failed",
+		zap.Int64("booking_id", bookingID),
+		zap.Int64("user_id", query.From.ID),
+		zap.Error(err))
+	return h.form.sendError(ctx, chatID, fallback)
+}
+
+// editKeyboard replaces the buttons under the booking card
+func (h *MyBookingHandler) editKeyboard(query *tgbotapi.CallbackQuery, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, keyboard)
+	if _, err := h.bot.Send(edit); err != nil {
+		return err
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
@@ -42,7 +43,7 @@ func (h *RequestSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuer
 
 	res, err := h.service.GetBySlug(ctx)
 	if err != nil {
-		return h.handleError(chatID, userID, err)
+		return h.handleError(ctx, chatID, userID, err)
 	}
 
 	var builder strings.Builder
@@ -51,7 +52,7 @@ func (h *RequestSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuer
 	builder.WriteString("\n\n")
 
 	if len(res.Links) > 0 {
-		builder.WriteString("*Ссылки:*\n")
+		fmt.Fprintf(&builder, "*%s:*\n", i18n.T(ctx, i18n.ResourceLinks))
 		for i, link := range res.Links {
 			title := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, link.Title)
 			url := link.URL
@@ -61,8 +62,14 @@ func (h *RequestSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuer
 
 	msg := tgbotapi.NewMessage(chatID, builder.String())
 	msg.ParseMode = "Markdown"
-	keyboard := h.keyboard.CreateButton("Назад", "main_menu")
-	msg.ReplyMarkup = keyboard
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.SpRequestButton), fmt.Sprintf(spRequestCallbackPattern, spRequestActionStart)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonBack), BoxSolutionsButtonBackToMainMenu),
+		),
+	)
 
 	if _, err := h.bot.Send(msg); err != nil {
 		return err
@@ -71,20 +78,20 @@ func (h *RequestSpHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuer
 }
 
 // sendError sends an error message
-func (h *RequestSpHandler) sendError(chatID int64, errorMsg string) error {
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %s", errorMsg))
+func (h *RequestSpHandler) sendError(ctx context.Context, chatID int64, key i18n.Key) error {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ErrorFormat, i18n.T(ctx, key)))
 	_, err := h.bot.Send(msg)
 	return err
 }
 
 // handleError logs failures and sends a message
-func (h *RequestSpHandler) handleError(chatID int64, userID int64, err error) error {
+func (h *RequestSpHandler) handleError(ctx context.Context, chatID int64, userID int64, err error) error {
 	logger.Error("failed to get req_sp",
 		zap.Int64("user_id", userID),
 		zap.Error(err),
 	)
 
-	if sendErr := h.sendError(chatID, "Не удалось сделать запрос спецпроекта"); sendErr != nil {
+	if sendErr := h.sendError(ctx, chatID, i18n.ResourceErrRequest); sendErr != nil {
 		return sendErr
 	}
 
@@ -13,6 +13,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
@@ -51,7 +52,7 @@ func (h *DetailHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuery)
 	parts := strings.Split(callbackData, ":")
 	back, err := h.checkBack(ctx, parts, tg)
 	if err != nil {
-		if sendErr := h.sendError(chatID, "Ошибка перехода в Главное меню"); sendErr != nil {
+		if sendErr := h.sendError(ctx, chatID, i18n.ServiceErrMainMenu); sendErr != nil {
 			logger.Error("failed_to_send_error_message", zap.Error(sendErr))
 		}
 		return err
@@ -62,7 +63,7 @@ func (h *DetailHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuery)
 
 	serviceID, err := h.service.ParseServiceID(callbackData)
 	if err != nil {
-		if sendErr := h.sendError(chatID, "Неверный формат данных"); sendErr != nil {
+		if sendErr := h.sendError(ctx, chatID, i18n.ServiceErrData); sendErr != nil {
 			logger.Error("failed_to_send_error_message", zap.Error(sendErr))
 		}
 		return err
@@ -72,13 +73,16 @@ func (h *DetailHandler) Handle(ctx context.Context, tg *tgbotapi.CallbackQuery)
 
 	service, err := h.service.GetByID(ctx, serviceID)
 	if err != nil {
-		return h.handleError(chatID, userID, serviceID, err)
+		return h.handleError(ctx, chatID, userID, serviceID, err)
 	}
 
-	serviceName := h.service.GetDisplayName(service)
-	messageText := h.buildServiceMessage(service, serviceName)
+	serviceName := service.Name
+	if serviceName == "" {
+		serviceName = i18n.T(ctx, i18n.ServiceOther)
+	}
+	messageText := h.buildServiceMessage(ctx, service, serviceName)
 
-	keyboard := h.keyboard.ServiceDetailKeyboard(service.ID, serviceName, parts[3])
+	keyboard := h.keyboard.ServiceDetailKeyboard(ctx, service.ID, serviceName, parts[3])
 	if err := h.sendMessage(chatID, service.Image, messageText, keyboard); err != nil {
 		logger.Error("failed_to_send_service_detail",
 			zap.Int64("service_id", serviceID),
@@ -105,8 +109,8 @@ func (h *DetailHandler) checkBack(ctx context.Context, parts []string, query *tg
 }
 
 // sendError sends an error message
-func (h *DetailHandler) sendError(chatID int64, errorMsg string) error {
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %s", errorMsg))
+func (h *DetailHandler) sendError(ctx context.Context, chatID int64, key i18n.Key) error {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ErrorFormat, i18n.T(ctx, key)))
 	_, err := h.bot.Send(msg)
 	return err
 }
@@ -155,21 +159,21 @@ func (h *DetailHandler) sendMessage(chatID int64, link *string, text string, key
 }
 
 // handleError logs service retrieval failures and sends a message
-func (h *DetailHandler) handleError(chatID int64, userID int64, serviceID int64, err error) error {
+func (h *DetailHandler) handleError(ctx context.Context, chatID int64, userID int64, serviceID int64, err error) error {
 	if err != nil {
 		logger.Error("failed_to_get_service",
 			zap.Int64("service_id", serviceID),
 			zap.Int64("user_id", userID),
 			zap.Error(err),
 		)
-		if sendErr := h.sendError(chatID, "Не удалось загрузить информацию об услуге"); sendErr != nil {
+		if sendErr := h.sendError(ctx, chatID, i18n.ServiceErrLoad); sendErr != nil {
 			logger.Error("failed_to_send_error_message", zap.Error(sendErr))
 		}
 	}
 	return err
 }
 
-func formatSchedule(slots []models.BoxAvailableSlot) string {
+func formatSchedule(ctx context.Context, slots []models.BoxAvailableSlot) string {
 	if len(slots) == 0 {
 		return ""
 	}
@@ -182,7 +186,7 @@ func formatSchedule(slots []models.BoxAvailableSlot) string {
 	}
 
 	for date, dateSlots := range slotsByDate {
-		formattedDate := formatDateForDisplay(date)
+		formattedDate := formatDateForDisplay(ctx, date)
 		fmt.Fprintf(&sb, "\n  %s:", formattedDate)
 
 		for _, slot := range dateSlots {
@@ -194,31 +198,20 @@ func formatSchedule(slots []models.BoxAvailableSlot) string {
 }
 
 // formatDateForDisplay formats the date
-func formatDateForDisplay(dateStr string) string {
+func formatDateForDisplay(ctx context.Context, dateStr string) string {
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return dateStr
 	}
 
-	russianMonths := []string{
-		"янв", "фев", "мар", "апр", "май", "июн",
-		"июл", "авг", "сен", "окт", "ноя", "дек",
-	}
-	russianWeekdays := []string{
-		"вс", "пн", "вт", "ср", "чт", "пт", "сб",
-	}
-
-	monthIdx := date.Month() - 1
-	weekdayIdx := date.Weekday()
-
 	return fmt.Sprintf("%d %s (%s)",
 		date.Day(),
-		russianMonths[monthIdx],
-		russianWeekdays[weekdayIdx])
+		i18n.ShortMonth(ctx, date.Month()),
+		i18n.ShortWeekday(ctx, date.Weekday()))
 }
 
 // buildServiceMessage creates a formatted string containing all service information
-func (h *DetailHandler) buildServiceMessage(service *models.Service, serviceName string) string {
+func (h *DetailHandler) buildServiceMessage(ctx context.Context, service *models.Service, serviceName string) string {
 	var builder strings.Builder
 	builder.WriteString(serviceName)
 	builder.WriteString("\n\n")
@@ -227,10 +220,11 @@ func (h *DetailHandler) buildServiceMessage(service *models.Service, serviceName
 		label     string
 		value     string
 		skipEmpty bool
+		multiline bool
 	}{
-		{"Описание", service.Description, true},
-		{"Правила", service.Rules, true},
-		{"Расписание", formatSchedule(service.BoxAvailableSlots), false},
+		{i18n.T(ctx, i18n.ServiceDescription), service.Description, true, false},
+		{i18n.T(ctx, i18n.ServiceRules), service.Rules, true, false},
+		{i18n.T(ctx, i18n.ServiceSchedule), formatSchedule(ctx, service.BoxAvailableSlots), false, true},
 	}
 
 	for i, section := range sections {
@@ -241,7 +235,7 @@ func (h *DetailHandler) buildServiceMessage(service *models.Service, serviceName
 		builder.WriteString(section.label)
 		builder.WriteString(":")
 
-		if section.label == "Расписание" {
+		if section.multiline {
 			builder.WriteString(section.value)
 		} else {
 			builder.WriteString(" ")
@@ -254,6 +248,6 @@ func (h *DetailHandler) buildServiceMessage(service *models.Service, serviceName
 		}
 	}
 
-	builder.WriteString("Доступные действия:")
+	builder.WriteString(i18n.T(ctx, i18n.ServiceActions))
 	return builder.String()
 }
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	botService "github.com/yandex-development-1-team/go/internal/service/bot"
+)
+
+// Actions of the special project request callbacks, "sp_request:<action>"
+const (
+	spRequestActionStart     = "start"
+	spRequestActionUsername  = "username"
+	spRequestActionConfirm   = "confirm"
+	spRequestActionCancel    = "cancel"
+	spRequestCallbackPattern = botService.CallbackSpRequestPrefix + ":%s"
+)
+
+// SpRequestHandler walks the guest through the special project request:
+// name, Telegram contact, description and confirmation
+type SpRequestHandler struct {
+	bot     BotAPI
+	service *botService.SpRequestService
+	sh      *StartHandler
+}
+
+// NewSpRequestHandler creates a new instance of the special project request handler
+func NewSpRequestHandler(bot *tgbotapi.BotAPI, service *botService.SpRequestService, sh *StartHandler) *SpRequestHandler {
+	return &SpRequestHandler{
+		bot:     bot,
+		service: service,
+		sh:      sh,
+	}
+}
+
+// Handle handles the buttons of the request
+func (h *SpRequestHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuery) error {
+	userID := query.From.ID
+	chatID := query.Message.Chat.ID
+
+	_, action, _ := strings.Cut(query.Data, ":")
+	logger.Info("special project request callback", zap.Int64("user_id", userID), zap.String("action", action))
+
+	delTgMessage(h.bot, query.Message)
+
+	switch action {
+	case spRequestActionStart:
+		state, err := h.service.Start(ctx, userID)
+		if err != nil {
+			return h.sendError(ctx, chatID, i18n.SpRequestErrSession)
+		}
+		return h.prompt(ctx, chatID, state, i18n.T(ctx, i18n.SpRequestEnterName), cancelKeyboard(ctx))
+
+	case spRequestActionCancel:
+		if err := h.service.ClearSession(ctx, userID); err != nil {
+			return err
+		}
+		return h.sh.Handle(ctx, query)
+	}
+
+	state := h.service.GetState(ctx, userID)
+	if state == nil {
+		return h.sendError(ctx, chatID, i18n.SpRequestErrSession)
+	}
+
+	switch {
+	case action == spRequestActionUsername && state.Step == botService.SpRequestStepEnterContact && query.From.UserName != "":
+		return h.contactInput(ctx, chatID, state, "@"+query.From.UserName)
+
+	case action == spRequestActionConfirm && state.Step == botService.SpRequestStepConfirmation:
+		return h.submit(ctx, chatID, state)
+
+	default:
+		return h.sendError(ctx, chatID, i18n.BookingErrUnknownAction)
+	}
+}
+
+// HandleTextMessage processes the answers of the request
+func (h *SpRequestHandler) HandleTextMessage(ctx context.Context, msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+
+	state := h.service.GetState(ctx, msg.From.ID)
+	if state == nil {
+		return h.sendError(ctx, chatID, i18n.SpRequestErrSession)
+	}
+
+	if state.OldMessageID != nil {
+		delTgMessage(h.bot, &tgbotapi.Message{MessageID: *state.OldMessageID, Chat: &tgbotapi.Chat{ID: chatID}})
+	}
+
+	switch state.Step {
+	case botService.SpRequestStepEnterName:
+		if err := h.service.ValidateAndSetName(state, msg.Text); err != nil {
+			return h.prompt(ctx, chatID, state, i18n.T(ctx, i18n.SpRequestInvalid, validationText(ctx, err)), cancelKeyboard(ctx))
+		}
+		return h.prompt(ctx, chatID, state, i18n.T(ctx, i18n.SpRequestEnterContact), contactKeyboard(ctx, msg.From))
+
+	case botService.SpRequestStepEnterContact:
+		return h.contactInput(ctx, chatID, state, msg.Text)
+
+	case botService.SpRequestStepEnterDescription:
+		if err := h.service.ValidateAndSetDescription(state, msg.Text); err != nil {
+			return h.prompt(ctx, chatID, state, i18n.T(ctx, i18n.SpRequestInvalid, validationText(ctx, err)), cancelKeyboard(ctx))
+		}
+		text := i18n.T(ctx, i18n.SpRequestConfirm, state.CustomerName, state.ContactInfo, state.Description)
+		return h.prompt(ctx, chatID, state, text, confirmRequestKeyboard(ctx))
+
+	default:
+		// The request waits for a button, e.g. the confirmation
+		return h.sendError(ctx, chatID, i18n.BookingErrStep)
+	}
+}
+
+// contactInput takes the contact typed or picked with the username button
+func (h *SpRequestHandler) contactInput(ctx context.Context, chatID int64, state *botService.SpRequestState, contact string) error {
+	if err := h.service.ValidateAndSetContact(state, contact); err != nil {
+		text := i18n.T(ctx, i18n.SpRequestInvalid, validationText(ctx, err))
+		return h.prompt(ctx, chatID, state, text, cancelKeyboard(ctx))
+	}
+	return h.prompt(ctx, chatID, state, i18n.T(ctx, i18n.SpRequestEnterDescription), cancelKeyboard(ctx))
+}
+
+// submit creates the application and confirms it with its number
+func (h *SpRequestHandler) submit(ctx context.Context, chatID int64, state *botService.SpRequestState) error {
+	id, err := h.service.Submit(ctx, state)
+	if err != nil {
+		logger.Error("failed to create special project application", zap.Int64("user_id", state.UserID), zap.Error(err))
+		return h.sendError(ctx, chatID, i18n.SpRequestErrSave)
+	}
+
+	if err := h.service.ClearSession(ctx, state.UserID); err != nil {
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SpRequestCreated, id))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonMainMenu), BoxSolutionsButtonBackToMainMenu),
+	))
+	_, err = h.bot.Send(msg)
+	return err
+}
+
+// prompt sends the next question and remembers it to be removed after the answer
+func (h *SpRequestHandler) prompt(ctx context.Context, chatID int64, state *botService.SpRequestState, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		return err
+	}
+
+	state.OldMessageID = &sent.MessageID
+	return h.service.SaveSession(ctx, *state)
+}
+
+// sendError sends an error message
+func (h *SpRequestHandler) sendError(ctx context.Context, chatID int64, key i18n.Key) error {
+	msg := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ErrorFormat, i18n.T(ctx, key)))
+	_, err := h.bot.Send(msg)
+	return err
+}
+
+func cancelKeyboard(ctx context.Context) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancel), fmt.Sprintf(spRequestCallbackPattern, spRequestActionCancel)),
+	))
+}
+
+// contactKeyboard offers the Telegram username of the guest, if they have one
+func contactKeyboard(ctx context.Context, user *tgbotapi.User) tgbotapi.InlineKeyboardMarkup {
+	keyboard := cancelKeyboard(ctx)
+	if user == nil || user.UserName == "" {
+		return keyboard
+	}
+
+	row := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.SpRequestUseUsername, user.UserName), fmt.Sprintf(spRequestCallbackPattern, spRequestActionUsername)),
+	)
+	keyboard.InlineKeyboard = append([][]tgbotapi.InlineKeyboardButton{row}, keyboard.InlineKeyboard...)
+	return keyboard
+}
+
+func confirmRequestKeyboard(ctx context.Context) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonConfirm), fmt.Sprintf(spRequestCallbackPattern, spRequestActionConfirm)),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancel), fmt.Sprintf(spRequestCallbackPattern, spRequestActionCancel)),
+	))
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+// StaffDesk is the staff side of the bot, see service.StaffTelegramService
+type StaffDesk interface {
+	Link(ctx context.Context, code string, telegramID int64) (*models.LinkedStaff, error)
+	Staff(ctx context.Context, telegramID int64) (*models.LinkedStaff, error)
+	NewBookings(ctx context.Context, staff *models.LinkedStaff) ([]models.BookingAPI, error)
+	NewApplications(ctx context.Context, staff *models.LinkedStaff) ([]models.Application, error)
+	SetBookingStatus(ctx context.Context, staff *models.LinkedStaff, id int64, status string) (*models.BookingAPI, error)
+	SetApplicationStatus(ctx context.Context, staff *models.LinkedStaff, id int64, status string) (*models.Application, error)
+}
+
+// Callback data of the staff menu: "staff:<action>" for the sections and
+// "staff:<booking|application>:<id>:<status>" for the buttons under an item
+const (
+	CallbackStaff = "staff"
+
+	staffActionMenu         = "menu"
+	staffActionBookings     = "bookings"
+	staffActionApplications = "applications"
+	staffActionBooking      = "booking"
+	staffActionApplication  = "application"
+)
+
+// StaffHandler links staff accounts with /link and serves the staff menu: the
+// new bookings and applications assigned to the staff member with buttons to
+// confirm or cancel them
+type StaffHandler struct {
+	bot  BotAPI
+	desk StaffDesk
+}
+
+func NewStaffHandler(bot *tgbotapi.BotAPI, desk StaffDesk) *StaffHandler {
+	return &StaffHandler{bot: bot, desk: desk}
+}
+
+// HandleLink processes the '/link <code>' command
+func (h *StaffHandler) HandleLink(ctx context.Context, msg *tgbotapi.Message) error {
+	metrics.IncMessagesReceived()
+	chatID := msg.Chat.ID
+
+	if !msg.Chat.IsPrivate() {
+		return h.send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffLinkPrivate)))
+	}
+	code := strings.TrimSpace(msg.CommandArguments())
+	if code == "" {
+		return h.send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffLinkUsage)))
+	}
+
+	staff, err := h.desk.Link(ctx, code, msg.From.ID)
+	if errors.Is(err, models.ErrTelegramLinkInvalid) {
+		logger.Warn("invalid telegram link code", zap.Int64("telegram_id", msg.From.ID))
+		return h.send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffLinkInvalid)))
+	}
+	if err != nil {
+		metrics.IncMessagesErrors()
+		logger.Error("failed to link telegram", zap.Int64("telegram_id", msg.From.ID), zap.Error(err))
+		_ = h.send(tgbotapi.NewMessage(chatID, systemErrorText(ctx)))
+		return err
+	}
+
+	logger.Info("telegram linked to staff", zap.Int64("staff_id", staff.ID), zap.Int64("telegram_id", msg.From.ID))
+	reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffLinked))
+	reply.ReplyMarkup = staffMenuKeyboard(ctx)
+	return h.send(reply)
+}
+
+// Handle processes the callbacks of the staff menu. The staff member is looked
+// up on every callback, so unlinking or blocking takes effect at once.
+func (h *StaffHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuery) error {
+	chatID := query.Message.Chat.ID
+
+	staff, err := h.desk.Staff(ctx, query.From.ID)
+	if errors.Is(err, models.ErrTelegramNotLinked) {
+		return h.send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffNotLinked)))
+	}
+	if err != nil {
+		logger.Error("failed to get linked staff", zap.Int64("telegram_id", query.From.ID), zap.Error(err))
+		_ = h.send(tgbotapi.NewMessage(chatID, systemErrorText(ctx)))
+		return err
+	}
+
+	parts := strings.Split(query.Data, ":")
+	action := ""
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+
+	switch action {
+	case staffActionBookings:
+		err = h.sendBookings(ctx, chatID, staff)
+	case staffActionApplications:
+		err = h.sendApplications(ctx, chatID, staff)
+	case staffActionBooking, staffActionApplication:
+		err = h.setStatus(ctx, query, staff, parts)
+	default:
+		delTgMessage(h.bot, query.Message)
+		reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffMenu, staffName(staff)))
+		reply.ReplyMarkup = staffMenuKeyboard(ctx)
+		err = h.send(reply)
+	}
+
+	if errors.Is(err, models.ErrForbidden) {
+		return h.send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StaffForbidden)))
+	}
+	if err != nil {
+		_ = h.send(tgbotapi.NewMessage(chatID, systemErrorText(ctx)))
+	}
+	return err
+}
+
+func (h *StaffHandler) sendBookings(ctx context.Context, chatID int64, staff *models.LinkedStaff) error {
+	bookings, err := h.desk.NewBookings(ctx, staff)
+	if err != nil {
+		return err
+	}
+	if len(bookings) == 0 {
+		return h.sendWithMenu(ctx, chatID, i18n.T(ctx, i18n.StaffNoBookings))
+	}
+
+	for i := range bookings {
+		reply := tgbotapi.NewMessage(chatID, formatStaffBooking(ctx, &bookings[i]))
+		reply.ReplyMarkup = staffItemKeyboard(ctx, staffActionBooking, bookings[i].ID)
+		if err := h.send(reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *StaffHandler) sendApplications(ctx context.Context, chatID int64, staff *models.LinkedStaff) error {
+	apps, err := h.desk.NewApplications(ctx, staff)
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		return h.sendWithMenu(ctx, chatID, i18n.T(ctx, i18n.StaffNoApplications))
+	}
+
+	for i := range apps {
+		reply := tgbotapi.NewMessage(chatID, formatStaffApplication(ctx, &apps[i]))
+		reply.ReplyMarkup = staffItemKeyboard(ctx, staffActionApplication, apps[i].ID)
+		if err := h.send(reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setStatus handles the confirm and cancel buttons and replaces the item card
+// with its new state
+func (h *StaffHandler) setStatus(ctx context.Context, query *tgbotapi.CallbackQuery, staff *models.LinkedStaff, parts []string) error {
+	if len(parts) != 4 {
+		return fmt.Errorf("invalid staff callback %q", query.Data)
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid staff callback %q: %w", query.Data, err)
+	}
+	status := parts[3]
+
+	var text string
+	if parts[1] == staffActionBooking {
+		booking, err := h.desk.SetBookingStatus(ctx, staff, id, status)
+		if err != nil {
+			return err
+		}
+		text = formatStaffBooking(ctx, booking)
+	} else {
+		app, err := h.desk.SetApplicationStatus(ctx, staff, id, status)
+		if err != nil {
+			return err
+		}
+		text = formatStaffApplication(ctx, app)
+	}
+
+	logger.Info("status set from the bot",
+		zap.String("entity", parts[1]), zap.Int64("id", id), zap.String("status", status),
+		zap.Int64("staff_id", staff.ID))
+	return h.send(tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text))
+}
+
+func (h *StaffHandler) sendWithMenu(ctx context.Context, chatID int64, text string) error {
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ReplyMarkup = staffMenuKeyboard(ctx)
+	return h.send(reply)
+}
+
+func (h *StaffHandler) send(c tgbotapi.Chattable) error {
+	if _, err := h.bot.Send(c); err != nil {
+		metrics.IncMessagesErrors()
+		logger.Error("failed to send staff message", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func staffMenuKeyboard(ctx context.Context) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.StaffNewBookings), CallbackStaff+":"+staffActionBookings),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.StaffNewApplications), CallbackStaff+":"+staffActionApplications),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.StaffGuestMenu), BoxSolutionsButtonBackToMainMenu),
+		),
+	)
+}
+
+func staffItemKeyboard(ctx context.Context, entity string, id int64) tgbotapi.InlineKeyboardMarkup {
+	data := func(status string) string {
+		return fmt.Sprintf("%s:%s:%d:%s", CallbackStaff, entity, id, status)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.StaffConfirm), data(models.BookingStatusConfirmed)),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ButtonCancelBooking), data(models.BookingStatusCancelled)),
+		),
+	)
+}
+
+func formatStaffBooking(ctx context.Context, b *models.BookingAPI) string {
+	return i18n.T(ctx, i18n.StaffBookingCard,
+		b.ID, b.ServiceName, b.GuestName, b.CreatedAt.Format("02.01.2006 15:04"), statusLabel(ctx, b.Status))
+}
+
+func formatStaffApplication(ctx context.Context, a *models.Application) string {
+	return i18n.T(ctx, i18n.StaffApplicationCard,
+		a.ID, a.CustomerName, a.ContactInfo, a.Description, statusLabel(ctx, a.Status))
+}
+
+func staffName(staff *models.LinkedStaff) string {
+	return strings.TrimSpace(staff.FirstName + " " + staff.LastName)
+}
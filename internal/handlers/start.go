@@ -3,12 +3,15 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
 	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 
@@ -19,17 +22,24 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, telegramID int64, userName string, firstName string, lastName string) error
 }
 
+// StaffLookup tells linked staff from guests
+type StaffLookup interface {
+	Staff(ctx context.Context, telegramID int64) (*models.LinkedStaff, error)
+}
+
 type StartHandler struct {
 	bot            BotAPI
 	userRepository UserRepository
 	session        repository.SessionRepository
+	staff          StaffLookup
 }
 
-func NewStartHandler(bot *tgbotapi.BotAPI, userRepository UserRepository, session repository.SessionRepository) *StartHandler {
+func NewStartHandler(bot *tgbotapi.BotAPI, userRepository UserRepository, session repository.SessionRepository, staff StaffLookup) *StartHandler {
 	return &StartHandler{
 		bot:            bot,
 		userRepository: userRepository,
 		session:        session,
+		staff:          staff,
 	}
 }
 
@@ -42,12 +52,14 @@ const (
 	CallbackSupport         = "support"
 )
 
-const (
-	WelcomeText    = "👋 Добро пожаловать в Bot Яндекса!\n\nВыберите интересующую вас опцию:"
-	ErrMessageUser = "Произошла ошибка, попробуйте позже."
-)
-
 func (sh *StartHandler) HandleStart(ctx context.Context, msg *tgbotapi.Message) error {
+	_, err := sh.start(ctx, msg)
+	return err
+}
+
+// start registers the user and sends the main menu; the sent menu is returned
+// for a deep link to open its section over it
+func (sh *StartHandler) start(ctx context.Context, msg *tgbotapi.Message) (*tgbotapi.Message, error) {
 	metrics.IncMessagesReceived()
 
 	startTime := time.Now()
@@ -86,32 +98,33 @@ func (sh *StartHandler) HandleStart(ctx context.Context, msg *tgbotapi.Message)
 				zap.Error(err),
 			)
 
-			errMsg := tgbotapi.NewMessage(chatID, ErrMessageUser)
+			errMsg := tgbotapi.NewMessage(chatID, systemErrorText(ctx))
 			if _, sendErr := sh.bot.Send(errMsg); sendErr != nil {
 				logger.Error("failed to send error message", zap.Error(sendErr))
 				metrics.IncMessagesErrors()
 			}
-			return err
+			return nil, err
 		}
 	}
 
-	reply := tgbotapi.NewMessage(chatID, WelcomeText)
-	reply.ReplyMarkup = mainMenuKeyboard()
+	reply := tgbotapi.NewMessage(chatID, welcomeText(ctx, msg.From))
+	reply.ReplyMarkup = sh.menuKeyboard(ctx, telegramID)
 
-	if _, err := sh.bot.Send(reply); err != nil {
+	sent, err := sh.bot.Send(reply)
+	if err != nil {
 		metrics.IncMessagesErrors()
 		logger.Error("failed to send start message", zap.Int64("chat_id", chatID), zap.Error(err))
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &sent, nil
 }
 
 func (sh *StartHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuery) error {
 	delTgMessage(sh.bot, query.Message)
 
-	reply := tgbotapi.NewMessage(query.Message.Chat.ID, WelcomeText)
-	reply.ReplyMarkup = mainMenuKeyboard()
+	reply := tgbotapi.NewMessage(query.Message.Chat.ID, welcomeText(ctx, query.From))
+	reply.ReplyMarkup = sh.menuKeyboard(ctx, query.From.ID)
 
 	if _, err := sh.bot.Send(reply); err != nil {
 		logger.Error("failed to send start menu", zap.Int64("chat_id", query.Message.Chat.ID), zap.Error(err))
@@ -121,25 +134,48 @@ func (sh *StartHandler) Handle(ctx context.Context, query *tgbotapi.CallbackQuer
 	return nil
 }
 
-func mainMenuKeyboard() tgbotapi.InlineKeyboardMarkup {
+// menuKeyboard is the main menu; linked staff also get the way to the staff menu
+func (sh *StartHandler) menuKeyboard(ctx context.Context, telegramID int64) tgbotapi.InlineKeyboardMarkup {
+	keyboard := mainMenuKeyboard(ctx)
+	if sh.staff == nil {
+		return keyboard
+	}
+
+	if _, err := sh.staff.Staff(ctx, telegramID); err != nil {
+		if !errors.Is(err, models.ErrTelegramNotLinked) {
+			logger.Error("failed to get linked staff", zap.Int64("telegram_id", telegramID), zap.Error(err))
+		}
+		return keyboard
+	}
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuStaff), CallbackStaff+":"+staffActionMenu),
+	))
+	return keyboard
+}
+
+func mainMenuKeyboard(ctx context.Context) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Коробочные решения", CallbackBoxSolutions),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuBoxSolutions), CallbackBoxSolutions),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuVisitGuide), CallbackVisitGuide),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Гайд по посещению", CallbackVisitGuide),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuSpecialProject), CallbackSpecialProject),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Запрос спецпроекта", CallbackSpecialProject),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuProjectExamples), CallbackProjectExamples),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Примеры спецпроектов", CallbackProjectExamples),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuAboutUs), CallbackAboutUs),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("О нас", CallbackAboutUs),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuSupport), CallbackSupport),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Связь с поддержкой", CallbackSupport),
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.MenuLanguage), CallbackLanguage),
 		),
 	)
 }
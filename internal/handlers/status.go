@@ -2,33 +2,40 @@ package handlers
 
 import (
 	"context"
-	"fmt"
-	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 
+	"github.com/yandex-development-1-team/go/internal/i18n"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/metrics"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
 	"github.com/yandex-development-1-team/go/internal/repository/postgres"
+	botService "github.com/yandex-development-1-team/go/internal/service/bot"
 )
 
 // StatusHandler processes a command '/status'
 type StatusHandler struct {
-	bot     BotAPI
-	repo    *postgres.BookingRepo
-	session repository.SessionRepository
+	bot      BotAPI
+	repo     *postgres.BookingRepo
+	session  repository.SessionRepository
+	keyboard *KeyboardService
 }
 
 // NewStatusHandler creates a new instance of the 'StatusHandler'
-func NewStatusHandler(bot *tgbotapi.BotAPI, repo *postgres.BookingRepo, session repository.SessionRepository) *StatusHandler {
+func NewStatusHandler(
+	bot *tgbotapi.BotAPI,
+	repo *postgres.BookingRepo,
+	session repository.SessionRepository,
+	keyboard *KeyboardService,
+) *StatusHandler {
 	return &StatusHandler{
-		bot:     bot,
-		repo:    repo,
-		session: session,
+		bot:      bot,
+		repo:     repo,
+		session:  session,
+		keyboard: keyboard,
 	}
 }
 
@@ -62,7 +69,7 @@ func (sh *StatusHandler) Handle(ctx context.Context, msg *tgbotapi.Message) erro
 			zap.Error(err),
 		)
 
-		errMsg := tgbotapi.NewMessage(chatID, ErrMessageUser)
+		errMsg := tgbotapi.NewMessage(chatID, systemErrorText(ctx))
 		if _, sendErr := sh.bot.Send(errMsg); sendErr != nil {
 			logger.Error("failed to send error message", zap.Error(sendErr))
 			metrics.IncMessagesErrors()
@@ -70,51 +77,86 @@ func (sh *StatusHandler) Handle(ctx context.Context, msg *tgbotapi.Message) erro
 		return err
 	}
 
-	text := sh.formatBookingMessage(bookings)
-
-	reply := tgbotapi.NewMessage(chatID, text)
+	if len(bookings) == 0 {
+		reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.StatusNoBookings))
+		if _, err := sh.bot.Send(reply); err != nil {
+			metrics.IncMessagesErrors()
+			logger.Error("failed to send status message", zap.Int64("chat_id", chatID), zap.Error(err))
+			return err
+		}
+		return nil
+	}
 
-	if _, err := sh.bot.Send(reply); err != nil {
-		metrics.IncMessagesErrors()
-		logger.Error("failed to send start message", zap.Int64("chat_id", chatID), zap.Error(err))
-		return err
+	for _, booking := range bookings {
+		if err := sh.sendBooking(ctx, chatID, booking); err != nil {
+			metrics.IncMessagesErrors()
+			logger.Error("failed to send status message",
+				zap.Int64("chat_id", chatID),
+				zap.Int64("booking_id", booking.ID),
+				zap.Error(err))
+			return err
+		}
 	}
 
 	return nil
 }
 
-// formatBookingMessage formats booking messages
-func (sh *StatusHandler) formatBookingMessage(bookings []models.BookingExt) string {
-	if len(bookings) == 0 {
-		return "У вас пока нет бронирований."
+// sendBooking sends the booking card. Bookings that can still be changed get
+// 'Cancel' and 'Reschedule' buttons.
+func (sh *StatusHandler) sendBooking(ctx context.Context, chatID int64, booking models.BookingExt) error {
+	bookingTimeStr := ""
+	if booking.BookingTime != nil {
+		bookingTimeStr = booking.BookingTime.Format("15:04")
 	}
 
-	var message strings.Builder
+	text := formatBookingCard(ctx,
+		booking.ServiceName,
+		booking.BookingDate.Format("2006-01-02"),
+		bookingTimeStr,
+		booking.GuestName,
+		booking.Status,
+		booking.ID,
+	)
 
-	for i, booking := range bookings {
-		bookingDate := booking.BookingDate.Format("02.01.2006")
+	reply := tgbotapi.NewMessage(chatID, text)
+	if botService.IsBookingChangeable(booking.Status, booking.BookingDate) {
+		reply.ReplyMarkup = sh.keyboard.BookingActionsKeyboard(ctx, booking.ID)
+	}
 
-		var bookingTimeStr string
-		if booking.BookingTime != nil {
-			bookingTimeStr = booking.BookingTime.Format("15:04")
-		} else {
-			bookingTimeStr = "Не указано"
-		}
+	_, err := sh.bot.Send(reply)
+	return err
+}
 
-		fmt.Fprintf(&message, "%d. %s\n"+
-			"   Дата: %s\n"+
-			"   Время: %s\n"+
-			"   Гость: %s\n"+
-			"   Статус: %s\n"+
-			"   ID: %d\n\n",
-			i+1,
-			booking.ServiceName,
-			bookingDate,
-			bookingTimeStr,
-			booking.GuestName,
-			booking.Status,
-			booking.ID)
+// formatBookingCard formats a single booking. The date is expected as 'YYYY-MM-DD'
+// and the time as 'HH:MM'.
+func formatBookingCard(ctx context.Context, serviceName, date, bookingTime, guestName, status string, id int64) string {
+	if d, err := time.Parse("2006-01-02", date); err == nil {
+		date = d.Format("02.01.2006")
+	}
+	if bookingTime == "" {
+		bookingTime = i18n.T(ctx, i18n.StatusNoTime)
 	}
 
-	return message.String()
+	return i18n.T(ctx, i18n.StatusCard,
+		serviceName,
+		date,
+		bookingTime,
+		guestName,
+		statusLabel(ctx, status),
+		id)
+}
+
+// statusLabels are the catalog texts of the booking and application statuses
+var statusLabels = map[string]i18n.Key{
+	models.BookingStatusPending:   i18n.StatusPending,
+	models.BookingStatusConfirmed: i18n.StatusConfirmed,
+	models.BookingStatusCancelled: i18n.StatusCancelled,
+}
+
+// statusLabel returns the status for the guest; an unknown status is shown as is
+func statusLabel(ctx context.Context, status string) string {
+	if key, ok := statusLabels[status]; ok {
+		return i18n.T(ctx, key)
+	}
+	return status
 }
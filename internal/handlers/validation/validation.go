@@ -1,9 +1,10 @@
 package validation
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
 )
 
 // Configuration constants
@@ -14,16 +15,28 @@ const (
 	maxOrgLength      = 255
 	minPositionLength = 2
 	maxPositionLength = 100
+	minDescription    = 10
+	maxDescription    = 2000
 )
 
-// Error represents a validation error with user-friendly message
+var telegramUsername = regexp.MustCompile(`^[a-zA-Z0-9_]{5,32}$`)
+
+// Error represents a validation error with user-friendly message. The message
+// is a catalog text, so the bot shows it in the guest's language.
 type Error struct {
-	Field   string
-	Message string
+	Field string
+	Key   i18n.Key
+	Args  []any
 }
 
+// Error returns the message in the default language
 func (e *Error) Error() string {
-	return e.Message
+	return e.Text(i18n.Default)
+}
+
+// Text returns the message in the language
+func (e *Error) Text(lang i18n.Lang) string {
+	return i18n.Text(lang, e.Key, e.Args...)
 }
 
 // Name checks the name
@@ -31,29 +44,29 @@ func Name(name string) error {
 	name = strings.TrimSpace(name)
 
 	if name == "" {
-		return &Error{Field: "name", Message: "Полное имя не может быть пустым"}
+		return &Error{Field: "name", Key: i18n.ValidationNameEmpty}
 	}
 
 	if len(name) < minNameLength {
-		return &Error{Field: "name", Message: fmt.Sprintf("Полное имя должно содержать как минимум %d символа", minNameLength)}
+		return &Error{Field: "name", Key: i18n.ValidationNameTooShort, Args: []any{minNameLength}}
 	}
 
 	if len(name) > maxNameLength {
-		return &Error{Field: "name", Message: fmt.Sprintf("Полное имя должно содержать не более %d символов", maxNameLength)}
+		return &Error{Field: "name", Key: i18n.ValidationNameTooLong, Args: []any{maxNameLength}}
 	}
 
 	// We allow letters (Russian and English), spaces and hyphens for double surnames
 	match, _ := regexp.MatchString(`^[a-zA-Zа-яА-ЯёЁ\s-]+$`, name)
 	if !match {
-		return &Error{Field: "name", Message: "Полное имя может содержать только буквы, пробелы и дефисы"}
+		return &Error{Field: "name", Key: i18n.ValidationNameChars}
 	}
 
 	if strings.Contains(name, "  ") {
-		return &Error{Field: "name", Message: "Полное имя не должно содержать двойных пробелов"}
+		return &Error{Field: "name", Key: i18n.ValidationNameDoubleSpaces}
 	}
 
 	if len(strings.Fields(name)) < 2 {
-		return &Error{Field: "name", Message: "Введите вашу фамилию и имя"}
+		return &Error{Field: "name", Key: i18n.ValidationNameFull}
 	}
 
 	return nil
@@ -64,21 +77,21 @@ func Organization(org string) error {
 	org = strings.TrimSpace(org)
 
 	if org == "" {
-		return &Error{Field: "organization", Message: "Название организации не может быть пустым"}
+		return &Error{Field: "organization", Key: i18n.ValidationOrgEmpty}
 	}
 
 	if len(org) < minOrgLength {
-		return &Error{Field: "organization", Message: fmt.Sprintf("Название организации должно содержать не менее %d символов", minOrgLength)}
+		return &Error{Field: "organization", Key: i18n.ValidationOrgTooShort, Args: []any{minOrgLength}}
 	}
 
 	if len(org) > maxOrgLength {
-		return &Error{Field: "organization", Message: fmt.Sprintf("Название организации не должно содержать более %d символов", maxOrgLength)}
+		return &Error{Field: "organization", Key: i18n.ValidationOrgTooLong, Args: []any{maxOrgLength}}
 	}
 
 	// We allow letters, numbers, spaces, quotation marks, dots, commas, hyphens, ampersands, numbers and slashes
 	match, _ := regexp.MatchString(`^[a-zA-Zа-яА-ЯёЁ0-9\s"'\.,\-&№/]+$`, org)
 	if !match {
-		return &Error{Field: "organization", Message: "Название организации содержит недопустимые символы"}
+		return &Error{Field: "organization", Key: i18n.ValidationOrgChars}
 	}
 
 	return nil
@@ -89,21 +102,54 @@ func Position(position string) error {
 	position = strings.TrimSpace(position)
 
 	if position == "" {
-		return &Error{Field: "position", Message: "Должность не может быть пустой"}
+		return &Error{Field: "position", Key: i18n.ValidationPositionEmpty}
 	}
 
 	if len(position) < minPositionLength {
-		return &Error{Field: "position", Message: fmt.Sprintf("Сообщение должно содержать как минимум %d символа", minPositionLength)}
+		return &Error{Field: "position", Key: i18n.ValidationPositionTooShort, Args: []any{minPositionLength}}
 	}
 
 	if len(position) > maxPositionLength {
-		return &Error{Field: "position", Message: fmt.Sprintf("Сообщение должно содержать максимум %d символов", maxPositionLength)}
+		return &Error{Field: "position", Key: i18n.ValidationPositionTooLong, Args: []any{maxPositionLength}}
 	}
 
 	// We allow letters, numbers, spaces, hyphens, dots, commas, slashes
 	match, _ := regexp.MatchString(`^[a-zA-Zа-яА-ЯёЁ0-9\s\-.,/]+$`, position)
 	if !match {
-		return &Error{Field: "position", Message: "В сообщении содержатся недопустимые символы"}
+		return &Error{Field: "position", Key: i18n.ValidationPositionChars}
+	}
+
+	return nil
+}
+
+// Contact validates a Telegram username: "@name", "name" or a t.me link
+func Contact(contact string) error {
+	contact = strings.TrimSpace(contact)
+
+	if contact == "" {
+		return &Error{Field: "contact", Key: i18n.ValidationContactEmpty}
+	}
+
+	if _, after, ok := strings.Cut(contact, "t.me/"); ok {
+		contact = after
+	}
+	if !telegramUsername.MatchString(strings.TrimPrefix(contact, "@")) {
+		return &Error{Field: "contact", Key: i18n.ValidationContactInvalid}
+	}
+
+	return nil
+}
+
+// Description validates the free-form description of a request
+func Description(description string) error {
+	length := len([]rune(strings.TrimSpace(description)))
+
+	if length < minDescription {
+		return &Error{Field: "description", Key: i18n.ValidationDescriptionShort, Args: []any{minDescription}}
+	}
+
+	if length > maxDescription {
+		return &Error{Field: "description", Key: i18n.ValidationDescriptionLong, Args: []any{maxDescription}}
 	}
 
 	return nil
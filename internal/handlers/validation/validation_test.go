@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -264,3 +265,51 @@ func TestPosition_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestContact(t *testing.T) {
+	for _, input := range []string{"@ivan_petrov", "ivan_petrov", "https://t.me/ivan_petrov", "  @Ivan2024  "} {
+		assert.NoError(t, Contact(input), input)
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{
+			name:        "Empty contact",
+			input:       " ",
+			expectedErr: "Укажите ник в Telegram",
+		},
+		{
+			name:        "Too short",
+			input:       "@abc",
+			expectedErr: "Ник в Telegram — от 5 до 32 символов",
+		},
+		{
+			name:        "Phone number",
+			input:       "+7 999 123-45-67",
+			expectedErr: "Ник в Telegram — от 5 до 32 символов",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Contact(tt.input)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestDescription(t *testing.T) {
+	assert.NoError(t, Description("Нужна выставка на 300 гостей в мае"))
+
+	err := Description("  Выставка ")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "не менее 10 символов")
+
+	err = Description(strings.Repeat("я", 2001))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "не более 2000 символов")
+}
@@ -0,0 +1,175 @@
+package i18n
+
+var en = map[Key]string{
+	LanguageName: "English",
+
+	Welcome:             "👋 Welcome to the Yandex Bot!\n\nChoose an option:",
+	MenuBoxSolutions:    "Packaged solutions",
+	MenuVisitGuide:      "Visitor guide",
+	MenuSpecialProject:  "Request a special project",
+	MenuProjectExamples: "Special project examples",
+	MenuAboutUs:         "About us",
+	MenuSupport:         "Contact support",
+	MenuStaff:           "Staff mode",
+	MenuLanguage:        "🌐 Language / Язык",
+	LanguageChoose:      "Choose a language:",
+
+	ButtonBack:          "Back",
+	ButtonBackArrow:     "⬅️ Back",
+	ButtonMainMenu:      "Main menu",
+	ButtonBook:          "📅 Book",
+	ButtonConfirm:       "Confirm",
+	ButtonCancel:        "Cancel",
+	ButtonCancelBooking: "❌ Cancel",
+	ButtonReschedule:    "🔄 Reschedule",
+	ButtonCancelYes:     "Yes, cancel",
+	ButtonNo:            "No",
+	ButtonPrevPage:      "← Previous",
+	ButtonNextPage:      "Next →",
+
+	ErrorGeneric: "Something went wrong, please try again later.",
+	ErrorFormat:  "Error: %s",
+
+	BoxSolutionsTitle:   "📦 Packaged solutions\n\nChoose an offer:\n",
+	BoxSolutionsPage:    "📄 Page %d of %d",
+	BoxSolutionsEmpty:   "❌ No solutions found",
+	ServiceOther:        "Other",
+	ServiceDescription:  "Description",
+	ServiceRules:        "Rules",
+	ServiceSchedule:     "Schedule",
+	ServiceActions:      "Available actions:",
+	ServiceErrMainMenu:  "Could not open the main menu",
+	ServiceErrData:      "Invalid data format",
+	ServiceErrLoad:      "Could not load the service",
+	SlotToday:           "Today",
+	SlotTomorrow:        "Tomorrow",
+	SlotPlaces:          " (places: %d)",
+	ResourceLinks:       "Links",
+	ResourceErrAbout:    "Could not load 'About us'",
+	ResourceErrGuide:    "Could not load the guide",
+	ResourceErrExamples: "Could not load the special project examples",
+	ResourceErrRequest:  "Could not request a special project",
+	ResourceErrLinks:    "Could not load the useful links",
+
+	BookingSelectDate:      "Choose a date:\n",
+	BookingNoSlots:         "There are no slots available for booking at the moment",
+	BookingSlotOccupied:    "Sorry, there are no places left at this time. Please choose another date",
+	BookingEnterName:       "*Enter your full name*\n\nFormat: Last name First name\n",
+	BookingInvalidName:     "*Invalid full name*\n\n%s\n\nPlease enter your full name again:",
+	BookingEnterOrg:        "Enter your organization\n\nLetters, digits and quotes are allowed\n\nExample: Acme Inc.",
+	BookingInvalidOrg:      "*Invalid organization*\n\n%s\n\nPlease enter the organization again:",
+	BookingEnterPosition:   "Enter your position\n\nExample: Sales manager",
+	BookingInvalidPosition: "*Invalid position*\n\n%s\n\nPlease enter your position again:",
+	BookingConfirmTitle:    "Booking confirmation\n\n",
+	BookingRescheduleTitle: "Rescheduling booking #%d\n\n",
+	BookingConfirmDetails: "Title: %s\n" +
+		"Date: %s\n" +
+		"Time: %s - %s\n" +
+		"Full name: %s\n" +
+		"Organization: %s\n" +
+		"Position: %s\n\n" +
+		"Please check the details\n",
+	BookingCreated: "Booking created!\n\n" +
+		"Booking number: #%d\n" +
+		"Title: %s\n" +
+		"Date: %s\n" +
+		"Time: %s - %s\n" +
+		"Status: Awaiting confirmation\n\n",
+	BookingRescheduled: "Booking rescheduled!\n\n" +
+		"Booking number: #%d\n" +
+		"Title: %s\n" +
+		"Date: %s\n" +
+		"Time: %s - %s\n" +
+		"Status: Awaiting confirmation",
+	BookingCancelled: "Booking cancelled.",
+
+	BookingErrSessionNotFound: "session not found",
+	BookingErrUnknownAction:   "unknown action",
+	BookingErrStatus:          "Could not get the booking state",
+	BookingErrBackFormat:      "Invalid Back button format",
+	BookingErrNoSession:       "The user session does not exist",
+	BookingErrStep:            "Invalid step",
+	BookingErrSession:         "Session error",
+	BookingErrStepUnavailable: "You can't go back to this step",
+	BookingErrRequestFormat:   "invalid request format",
+	BookingErrDateFormat:      "invalid date selection format",
+	BookingErrFormat:          "invalid format",
+	BookingErrSlots:           "Could not get the available dates",
+	BookingErrDate:            "Could not process the date",
+	BookingErrSave:            "Could not save the booking",
+	BookingErrRescheduleGone:  "this booking can no longer be rescheduled",
+	BookingErrReschedule:      "Could not reschedule the booking",
+	BookingErrNumber:          "invalid booking number",
+	BookingErrCancel:          "Could not cancel the booking",
+	BookingErrStartReschedule: "Could not start rescheduling the booking",
+	BookingErrClosed:          "this booking can no longer be changed",
+
+	SpRequestButton:           "📝 Submit a request",
+	SpRequestEnterName:        "Special project request\n\nHow should we address you? Enter your last and first name",
+	SpRequestEnterContact:     "Enter your Telegram username, a manager will contact you there\n\nExample: @username",
+	SpRequestUseUsername:      "Use @%s",
+	SpRequestEnterDescription: "Describe the project: what you want to do, for which audience, timing and budget",
+	SpRequestInvalid:          "%s\n\nPlease try again:",
+	SpRequestConfirm: "Please check the request\n\n" +
+		"Name: %s\n" +
+		"Contact: %s\n" +
+		"Description: %s",
+	SpRequestCreated:    "Request #%d received! A manager will contact you in Telegram.",
+	SpRequestErrSave:    "Could not submit the request",
+	SpRequestErrSession: "The request was not found, please start again",
+
+	StatusNoBookings: "You have no bookings yet.",
+	StatusCard: "%s\n" +
+		"Date: %s\n" +
+		"Time: %s\n" +
+		"Guest: %s\n" +
+		"Status: %s\n" +
+		"ID: %d",
+	StatusNoTime:    "Not set",
+	StatusPending:   "Awaiting confirmation",
+	StatusConfirmed: "Confirmed",
+	StatusCancelled: "Cancelled",
+
+	StaffMenu:            "👔 Staff mode: %s\n\nChoose a section:",
+	StaffLinkUsage:       "Send the code from the admin panel: /link CODE",
+	StaffLinkPrivate:     "An account can only be linked in a private chat with the bot.",
+	StaffLinked:          "✅ Telegram is linked to the staff account.",
+	StaffLinkInvalid:     "The code is invalid or expired. Ask an administrator for a new one.",
+	StaffNotLinked:       "This Telegram account is not linked to a staff member.",
+	StaffForbidden:       "You don't have permission for this action.",
+	StaffNoBookings:      "No new bookings.",
+	StaffNoApplications:  "No new applications.",
+	StaffNewBookings:     "New bookings",
+	StaffNewApplications: "New applications",
+	StaffGuestMenu:       "Guest menu",
+	StaffConfirm:         "✅ Confirm",
+	StaffBookingCard: "Booking #%d\n" +
+		"Service: %s\n" +
+		"Guest: %s\n" +
+		"Created: %s\n" +
+		"Status: %s",
+	StaffApplicationCard: "Special project application #%d\n" +
+		"Customer: %s\n" +
+		"Contact: %s\n" +
+		"Description: %s\n" +
+		"Status: %s",
+
+	ValidationNameEmpty:        "Full name can't be empty",
+	ValidationNameTooShort:     "Full name must be at least %d characters long",
+	ValidationNameTooLong:      "Full name must be at most %d characters long",
+	ValidationNameChars:        "Full name may only contain letters, spaces and hyphens",
+	ValidationNameDoubleSpaces: "Full name must not contain double spaces",
+	ValidationNameFull:         "Enter your last and first name",
+	ValidationOrgEmpty:         "Organization can't be empty",
+	ValidationOrgTooShort:      "Organization must be at least %d characters long",
+	ValidationOrgTooLong:       "Organization must be at most %d characters long",
+	ValidationOrgChars:         "Organization contains invalid characters",
+	ValidationPositionEmpty:    "Position can't be empty",
+	ValidationPositionTooShort: "Position must be at least %d characters long",
+	ValidationPositionTooLong:  "Position must be at most %d characters long",
+	ValidationPositionChars:    "Position contains invalid characters",
+	ValidationContactEmpty:     "Enter your Telegram username",
+	ValidationContactInvalid:   "A Telegram username has 5 to 32 characters: Latin letters, digits and _",
+	ValidationDescriptionShort: "The description must be at least %d characters long",
+	ValidationDescriptionLong:  "The description must be at most %d characters long",
+}
@@ -0,0 +1,136 @@
+// Package i18n is the message catalog of the bot. Every text the bot sends is
+// looked up by its Key in the bundle of the guest's language; the language
+// travels with the context of the update.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Lang is a language of the catalog
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	// Default is the language of guests whose Telegram client tells nothing
+	// and the fallback for a text missing from a bundle
+	Default = RU
+)
+
+// Supported lists the languages a guest can switch to, in menu order
+var Supported = []Lang{RU, EN}
+
+// Key identifies a text of the catalog
+type Key string
+
+var bundles = map[Lang]map[Key]string{
+	RU: ru,
+	EN: en,
+}
+
+// russianSpeaking are the Telegram languages whose speakers get the Russian
+// bundle rather than English
+var russianSpeaking = map[string]bool{
+	"ru": true,
+	"uk": true,
+	"be": true,
+	"kk": true,
+}
+
+// Parse returns the catalog language for a stored or chosen code
+func Parse(code string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(code)))
+	_, ok := bundles[lang]
+	return lang, ok
+}
+
+// FromTelegram picks the language for the language_code Telegram sends with
+// the user, e.g. "en", "ru" or "pt-br"
+func FromTelegram(code string) Lang {
+	code = strings.ToLower(code)
+	if i := strings.IndexByte(code, '-'); i >= 0 {
+		code = code[:i]
+	}
+	if code == "" || russianSpeaking[code] {
+		return RU
+	}
+	if lang, ok := Parse(code); ok {
+		return lang
+	}
+	return EN
+}
+
+// Name is the name of the language in the language itself
+func (l Lang) Name() string {
+	return Text(l, LanguageName)
+}
+
+// Text renders the text in the language. A text missing from the bundle falls
+// back to the default language and then to the key itself.
+func Text(lang Lang, key Key, args ...any) string {
+	text, ok := bundles[lang][key]
+	if !ok {
+		text, ok = bundles[Default][key]
+	}
+	if !ok {
+		return string(key)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+type langKey struct{}
+
+// WithLang returns the context carrying the language of the guest
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// FromContext returns the language carried by the context or Default
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(langKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
+
+// T renders the text in the language of the context
+func T(ctx context.Context, key Key, args ...any) string {
+	return Text(FromContext(ctx), key, args...)
+}
+
+var (
+	shortMonths = map[Lang][12]string{
+		RU: {"янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"},
+		EN: {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	}
+	shortWeekdays = map[Lang][7]string{
+		RU: {"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		EN: {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	}
+)
+
+// ShortMonth returns the abbreviated month name in the language of the context
+func ShortMonth(ctx context.Context, m time.Month) string {
+	months, ok := shortMonths[FromContext(ctx)]
+	if !ok {
+		months = shortMonths[Default]
+	}
+	return months[m-1]
+}
+
+// ShortWeekday returns the abbreviated weekday name in the language of the context
+func ShortWeekday(ctx context.Context, d time.Weekday) string {
+	weekdays, ok := shortWeekdays[FromContext(ctx)]
+	if !ok {
+		weekdays = shortWeekdays[Default]
+	}
+	return weekdays[d]
+}
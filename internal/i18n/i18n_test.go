@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestBundlesMatch(t *testing.T) {
+	for lang, bundle := range bundles {
+		assert.Len(t, bundle, len(bundles[Default]), "bundle %s has a different set of texts", lang)
+
+		for key, text := range bundles[Default] {
+			translated, ok := bundle[key]
+			if !assert.True(t, ok, "%s misses %s", lang, key) {
+				continue
+			}
+			assert.Equal(t, verbRe.FindAllString(text, -1), verbRe.FindAllString(translated, -1),
+				"%s: %s has different format verbs", lang, key)
+		}
+	}
+}
+
+func TestFromTelegram(t *testing.T) {
+	tests := []struct {
+		code string
+		want Lang
+	}{
+		{"", RU},
+		{"ru", RU},
+		{"uk", RU},
+		{"en", EN},
+		{"en-GB", EN},
+		{"pt-br", EN},
+		{"de", EN},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FromTelegram(tt.code), "code %q", tt.code)
+	}
+}
+
+func TestText(t *testing.T) {
+	ctx := WithLang(context.Background(), EN)
+
+	assert.Equal(t, "Error: boom", T(ctx, ErrorFormat, "boom"))
+	assert.Equal(t, "Ошибка: boom", T(context.Background(), ErrorFormat, "boom"), "russian by default")
+	assert.Equal(t, "Ошибка: boom", Text("de", ErrorFormat, "boom"), "an unknown language falls back to the default")
+	assert.Equal(t, "no.such.key", Text(EN, "no.such.key"))
+
+	assert.Equal(t, "Mar", ShortMonth(ctx, time.March))
+	assert.Equal(t, "вс", ShortWeekday(context.Background(), time.Sunday))
+
+	_, ok := Parse("EN")
+	assert.True(t, ok)
+	_, ok = Parse("de")
+	assert.False(t, ok)
+}
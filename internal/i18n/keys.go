@@ -0,0 +1,154 @@
+package i18n
+
+// Texts of the catalog. A text with format verbs is rendered with arguments,
+// see Text.
+const (
+	LanguageName Key = "language.name"
+
+	// Main menu
+	Welcome             Key = "menu.welcome"
+	MenuBoxSolutions    Key = "menu.box_solutions"
+	MenuVisitGuide      Key = "menu.visit_guide"
+	MenuSpecialProject  Key = "menu.special_project"
+	MenuProjectExamples Key = "menu.project_examples"
+	MenuAboutUs         Key = "menu.about_us"
+	MenuSupport         Key = "menu.support"
+	MenuStaff           Key = "menu.staff"
+	MenuLanguage        Key = "menu.language"
+	LanguageChoose      Key = "language.choose"
+
+	// Buttons
+	ButtonBack          Key = "button.back"
+	ButtonBackArrow     Key = "button.back_arrow"
+	ButtonMainMenu      Key = "button.main_menu"
+	ButtonBook          Key = "button.book"
+	ButtonConfirm       Key = "button.confirm"
+	ButtonCancel        Key = "button.cancel"
+	ButtonCancelBooking Key = "button.cancel_booking"
+	ButtonReschedule    Key = "button.reschedule"
+	ButtonCancelYes     Key = "button.cancel_yes"
+	ButtonNo            Key = "button.no"
+	ButtonPrevPage      Key = "button.prev_page"
+	ButtonNextPage      Key = "button.next_page"
+
+	// Errors
+	ErrorGeneric Key = "error.generic"
+	ErrorFormat  Key = "error.format"
+
+	// Box solutions and service cards
+	BoxSolutionsTitle   Key = "box_solutions.title"
+	BoxSolutionsPage    Key = "box_solutions.page"
+	BoxSolutionsEmpty   Key = "box_solutions.empty"
+	ServiceOther        Key = "service.other"
+	ServiceDescription  Key = "service.description"
+	ServiceRules        Key = "service.rules"
+	ServiceSchedule     Key = "service.schedule"
+	ServiceActions      Key = "service.actions"
+	ServiceErrMainMenu  Key = "service.err_main_menu"
+	ServiceErrData      Key = "service.err_data"
+	ServiceErrLoad      Key = "service.err_load"
+	SlotToday           Key = "slot.today"
+	SlotTomorrow        Key = "slot.tomorrow"
+	SlotPlaces          Key = "slot.places"
+	ResourceLinks       Key = "resource.links"
+	ResourceErrAbout    Key = "resource.err_about"
+	ResourceErrGuide    Key = "resource.err_guide"
+	ResourceErrExamples Key = "resource.err_examples"
+	ResourceErrRequest  Key = "resource.err_request"
+	ResourceErrLinks    Key = "resource.err_links"
+
+	// Booking form
+	BookingSelectDate      Key = "booking.select_date"
+	BookingNoSlots         Key = "booking.no_slots"
+	BookingSlotOccupied    Key = "booking.slot_occupied"
+	BookingEnterName       Key = "booking.enter_name"
+	BookingInvalidName     Key = "booking.invalid_name"
+	BookingEnterOrg        Key = "booking.enter_org"
+	BookingInvalidOrg      Key = "booking.invalid_org"
+	BookingEnterPosition   Key = "booking.enter_position"
+	BookingInvalidPosition Key = "booking.invalid_position"
+	BookingConfirmTitle    Key = "booking.confirm_title"
+	BookingRescheduleTitle Key = "booking.reschedule_title"
+	BookingConfirmDetails  Key = "booking.confirm_details"
+	BookingCreated         Key = "booking.created"
+	BookingRescheduled     Key = "booking.rescheduled"
+	BookingCancelled       Key = "booking.cancelled"
+
+	BookingErrSessionNotFound Key = "booking.err_session_not_found"
+	BookingErrUnknownAction   Key = "booking.err_unknown_action"
+	BookingErrStatus          Key = "booking.err_status"
+	BookingErrBackFormat      Key = "booking.err_back_format"
+	BookingErrNoSession       Key = "booking.err_no_session"
+	BookingErrStep            Key = "booking.err_step"
+	BookingErrSession         Key = "booking.err_session"
+	BookingErrStepUnavailable Key = "booking.err_step_unavailable"
+	BookingErrRequestFormat   Key = "booking.err_request_format"
+	BookingErrDateFormat      Key = "booking.err_date_format"
+	BookingErrFormat          Key = "booking.err_format"
+	BookingErrSlots           Key = "booking.err_slots"
+	BookingErrDate            Key = "booking.err_date"
+	BookingErrSave            Key = "booking.err_save"
+	BookingErrRescheduleGone  Key = "booking.err_reschedule_gone"
+	BookingErrReschedule      Key = "booking.err_reschedule"
+	BookingErrNumber          Key = "booking.err_number"
+	BookingErrCancel          Key = "booking.err_cancel"
+	BookingErrStartReschedule Key = "booking.err_start_reschedule"
+	BookingErrClosed          Key = "booking.err_closed"
+
+	// Special project request form
+	SpRequestButton           Key = "sp_request.button"
+	SpRequestEnterName        Key = "sp_request.enter_name"
+	SpRequestEnterContact     Key = "sp_request.enter_contact"
+	SpRequestUseUsername      Key = "sp_request.use_username"
+	SpRequestEnterDescription Key = "sp_request.enter_description"
+	SpRequestInvalid          Key = "sp_request.invalid"
+	SpRequestConfirm          Key = "sp_request.confirm"
+	SpRequestCreated          Key = "sp_request.created"
+	SpRequestErrSave          Key = "sp_request.err_save"
+	SpRequestErrSession       Key = "sp_request.err_session"
+
+	// '/status'
+	StatusNoBookings Key = "status.no_bookings"
+	StatusCard       Key = "status.card"
+	StatusNoTime     Key = "status.no_time"
+	StatusPending    Key = "status.pending"
+	StatusConfirmed  Key = "status.confirmed"
+	StatusCancelled  Key = "status.cancelled"
+
+	// Staff menu
+	StaffMenu            Key = "staff.menu"
+	StaffLinkUsage       Key = "staff.link_usage"
+	StaffLinkPrivate     Key = "staff.link_private"
+	StaffLinked          Key = "staff.linked"
+	StaffLinkInvalid     Key = "staff.link_invalid"
+	StaffNotLinked       Key = "staff.not_linked"
+	StaffForbidden       Key = "staff.forbidden"
+	StaffNoBookings      Key = "staff.no_bookings"
+	StaffNoApplications  Key = "staff.no_applications"
+	StaffNewBookings     Key = "staff.new_bookings"
+	StaffNewApplications Key = "staff.new_applications"
+	StaffGuestMenu       Key = "staff.guest_menu"
+	StaffConfirm         Key = "staff.confirm"
+	StaffBookingCard     Key = "staff.booking_card"
+	StaffApplicationCard Key = "staff.application_card"
+
+	// Validation of the booking form
+	ValidationNameEmpty        Key = "validation.name_empty"
+	ValidationNameTooShort     Key = "validation.name_too_short"
+	ValidationNameTooLong      Key = "validation.name_too_long"
+	ValidationNameChars        Key = "validation.name_chars"
+	ValidationNameDoubleSpaces Key = "validation.name_double_spaces"
+	ValidationNameFull         Key = "validation.name_full"
+	ValidationOrgEmpty         Key = "validation.org_empty"
+	ValidationOrgTooShort      Key = "validation.org_too_short"
+	ValidationOrgTooLong       Key = "validation.org_too_long"
+	ValidationOrgChars         Key = "validation.org_chars"
+	ValidationPositionEmpty    Key = "validation.position_empty"
+	ValidationPositionTooShort Key = "validation.position_too_short"
+	ValidationPositionTooLong  Key = "validation.position_too_long"
+	ValidationPositionChars    Key = "validation.position_chars"
+	ValidationContactEmpty     Key = "validation.contact_empty"
+	ValidationContactInvalid   Key = "validation.contact_invalid"
+	ValidationDescriptionShort Key = "validation.description_short"
+	ValidationDescriptionLong  Key = "validation.description_long"
+)
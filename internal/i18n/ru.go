@@ -0,0 +1,175 @@
+package i18n
+
+var ru = map[Key]string{
+	LanguageName: "Русский",
+
+	Welcome:             "👋 Добро пожаловать в Bot Яндекса!\n\nВыберите интересующую вас опцию:",
+	MenuBoxSolutions:    "Коробочные решения",
+	MenuVisitGuide:      "Гайд по посещению",
+	MenuSpecialProject:  "Запрос спецпроекта",
+	MenuProjectExamples: "Примеры спецпроектов",
+	MenuAboutUs:         "О нас",
+	MenuSupport:         "Связь с поддержкой",
+	MenuStaff:           "Режим сотрудника",
+	MenuLanguage:        "🌐 Язык / Language",
+	LanguageChoose:      "Выберите язык:",
+
+	ButtonBack:          "Назад",
+	ButtonBackArrow:     "⬅️ Назад",
+	ButtonMainMenu:      "В главное меню",
+	ButtonBook:          "📅 Забронировать",
+	ButtonConfirm:       "Подтвердить",
+	ButtonCancel:        "Отменить",
+	ButtonCancelBooking: "❌ Отменить",
+	ButtonReschedule:    "🔄 Перенести",
+	ButtonCancelYes:     "Да, отменить",
+	ButtonNo:            "Нет",
+	ButtonPrevPage:      "← Предыдущая",
+	ButtonNextPage:      "Следующая →",
+
+	ErrorGeneric: "Произошла ошибка, попробуйте позже.",
+	ErrorFormat:  "Ошибка: %s",
+
+	BoxSolutionsTitle:   "📦 Коробочные решения\n\nВыберите интересующее вас предложение:\n",
+	BoxSolutionsPage:    "📄 Страница %d из %d",
+	BoxSolutionsEmpty:   "❌ Решения не найдены",
+	ServiceOther:        "Прочее",
+	ServiceDescription:  "Описание",
+	ServiceRules:        "Правила",
+	ServiceSchedule:     "Расписание",
+	ServiceActions:      "Доступные действия:",
+	ServiceErrMainMenu:  "Ошибка перехода в Главное меню",
+	ServiceErrData:      "Неверный формат данных",
+	ServiceErrLoad:      "Не удалось загрузить информацию об услуге",
+	SlotToday:           "Сегодня",
+	SlotTomorrow:        "Завтра",
+	SlotPlaces:          " (мест: %d)",
+	ResourceLinks:       "Ссылки",
+	ResourceErrAbout:    "Не удалось загрузить информацию 'О нас'",
+	ResourceErrGuide:    "Не удалось загрузить гайд",
+	ResourceErrExamples: "Не удалось загрузить примеры спецпроектов",
+	ResourceErrRequest:  "Не удалось сделать запрос спецпроекта",
+	ResourceErrLinks:    "Не удалось загрузить полезные ссылки",
+
+	BookingSelectDate:      "Выберите дату:\n",
+	BookingNoSlots:         "На данный момент нет доступных слотов для бронирования",
+	BookingSlotOccupied:    "К сожалению, на это время мест больше нет. Выберите другую дату",
+	BookingEnterName:       "*Введите ФИО*\n\nФормат: Фамилия Имя Отчество\n",
+	BookingInvalidName:     "*Ошибка валидации ФИО*\n\n%s\n\nВведите ФИО еще раз:",
+	BookingEnterOrg:        "Введите организацию\n\nМожно использовать буквы, цифры, кавычки\n\nПример: ООО \"Ромашка\"",
+	BookingInvalidOrg:      "*Ошибка валидации организации*\n\n%s\n\nВведите название организации еще раз:",
+	BookingEnterPosition:   "Введите должность\n\nПример: Менеджер по продажам",
+	BookingInvalidPosition: "*Ошибка валидации должности*\n\n%s\n\nВведите должность еще раз:",
+	BookingConfirmTitle:    "Подтверждение бронирования\n\n",
+	BookingRescheduleTitle: "Перенос бронирования #%d\n\n",
+	BookingConfirmDetails: "Название: %s\n" +
+		"Дата: %s\n" +
+		"Время: %s - %s\n" +
+		"ФИО: %s\n" +
+		"Организация: %s\n" +
+		"Должность: %s\n\n" +
+		"Проверьте правильность введенных данных\n",
+	BookingCreated: "Бронирование успешно создано!\n\n" +
+		"Номер бронирования: #%d\n" +
+		"Название: %s\n" +
+		"Дата: %s\n" +
+		"Время: %s - %s\n" +
+		"Статус: Ожидает подтверждения\n\n",
+	BookingRescheduled: "Бронирование перенесено!\n\n" +
+		"Номер бронирования: #%d\n" +
+		"Название: %s\n" +
+		"Дата: %s\n" +
+		"Время: %s - %s\n" +
+		"Статус: Ожидает подтверждения",
+	BookingCancelled: "Бронирование отменено.",
+
+	BookingErrSessionNotFound: "сессия не найдена",
+	BookingErrUnknownAction:   "неизвестное действие",
+	BookingErrStatus:          "Ошибка при получении статуса",
+	BookingErrBackFormat:      "Неверный формат кнопки Назад",
+	BookingErrNoSession:       "Сессии пользователя не существует",
+	BookingErrStep:            "Неверный шаг",
+	BookingErrSession:         "Ошибка в сессии",
+	BookingErrStepUnavailable: "Нельзя вернуться на этот шаг",
+	BookingErrRequestFormat:   "неверный формат запроса",
+	BookingErrDateFormat:      "неверный формат выбора даты",
+	BookingErrFormat:          "неверный формат",
+	BookingErrSlots:           "Ошибка получения слотов дат",
+	BookingErrDate:            "Не удалось обработать дату",
+	BookingErrSave:            "Не удалось сохранить бронирование",
+	BookingErrRescheduleGone:  "это бронирование уже нельзя перенести",
+	BookingErrReschedule:      "Не удалось перенести бронирование",
+	BookingErrNumber:          "неверный номер бронирования",
+	BookingErrCancel:          "Не удалось отменить бронирование",
+	BookingErrStartReschedule: "Не удалось начать перенос бронирования",
+	BookingErrClosed:          "это бронирование уже нельзя изменить",
+
+	SpRequestButton:           "📝 Оставить заявку",
+	SpRequestEnterName:        "Заявка на спецпроект\n\nКак к вам обращаться? Введите фамилию и имя",
+	SpRequestEnterContact:     "Укажите ваш ник в Telegram, по нему с вами свяжется менеджер\n\nПример: @username",
+	SpRequestUseUsername:      "Использовать @%s",
+	SpRequestEnterDescription: "Опишите задачу: что хотите сделать, для какой аудитории, сроки и бюджет",
+	SpRequestInvalid:          "%s\n\nПопробуйте ещё раз:",
+	SpRequestConfirm: "Проверьте заявку\n\n" +
+		"Имя: %s\n" +
+		"Контакт: %s\n" +
+		"Описание: %s",
+	SpRequestCreated:    "Заявка #%d принята! Менеджер свяжется с вами в Telegram.",
+	SpRequestErrSave:    "Не удалось отправить заявку",
+	SpRequestErrSession: "Заявка не найдена, начните заново",
+
+	StatusNoBookings: "У вас пока нет бронирований.",
+	StatusCard: "%s\n" +
+		"Дата: %s\n" +
+		"Время: %s\n" +
+		"Гость: %s\n" +
+		"Статус: %s\n" +
+		"ID: %d",
+	StatusNoTime:    "Не указано",
+	StatusPending:   "Ожидает подтверждения",
+	StatusConfirmed: "Подтверждено",
+	StatusCancelled: "Отменено",
+
+	StaffMenu:            "👔 Режим сотрудника: %s\n\nВыберите раздел:",
+	StaffLinkUsage:       "Отправьте код из админки: /link КОД",
+	StaffLinkPrivate:     "Привязать аккаунт можно только в личном чате с ботом.",
+	StaffLinked:          "✅ Telegram привязан к аккаунту сотрудника.",
+	StaffLinkInvalid:     "Код недействителен или истёк. Попросите администратора выпустить новый.",
+	StaffNotLinked:       "Этот аккаунт Telegram не привязан к сотруднику.",
+	StaffForbidden:       "Недостаточно прав для этого действия.",
+	StaffNoBookings:      "Новых бронирований нет.",
+	StaffNoApplications:  "Новых заявок нет.",
+	StaffNewBookings:     "Новые бронирования",
+	StaffNewApplications: "Новые заявки",
+	StaffGuestMenu:       "Гостевое меню",
+	StaffConfirm:         "✅ Подтвердить",
+	StaffBookingCard: "Бронирование #%d\n" +
+		"Услуга: %s\n" +
+		"Гость: %s\n" +
+		"Создано: %s\n" +
+		"Статус: %s",
+	StaffApplicationCard: "Заявка на спецпроект #%d\n" +
+		"Заказчик: %s\n" +
+		"Контакт: %s\n" +
+		"Описание: %s\n" +
+		"Статус: %s",
+
+	ValidationNameEmpty:        "Полное имя не может быть пустым",
+	ValidationNameTooShort:     "Полное имя должно содержать как минимум %d символа",
+	ValidationNameTooLong:      "Полное имя должно содержать не более %d символов",
+	ValidationNameChars:        "Полное имя может содержать только буквы, пробелы и дефисы",
+	ValidationNameDoubleSpaces: "Полное имя не должно содержать двойных пробелов",
+	ValidationNameFull:         "Введите вашу фамилию и имя",
+	ValidationOrgEmpty:         "Название организации не может быть пустым",
+	ValidationOrgTooShort:      "Название организации должно содержать не менее %d символов",
+	ValidationOrgTooLong:       "Название организации не должно содержать более %d символов",
+	ValidationOrgChars:         "Название организации содержит недопустимые символы",
+	ValidationPositionEmpty:    "Должность не может быть пустой",
+	ValidationPositionTooShort: "Сообщение должно содержать как минимум %d символа",
+	ValidationPositionTooLong:  "Сообщение должно содержать максимум %d символов",
+	ValidationPositionChars:    "В сообщении содержатся недопустимые символы",
+	ValidationContactEmpty:     "Укажите ник в Telegram",
+	ValidationContactInvalid:   "Ник в Telegram — от 5 до 32 символов: латинские буквы, цифры и _",
+	ValidationDescriptionShort: "Описание должно содержать не менее %d символов",
+	ValidationDescriptionLong:  "Описание должно содержать не более %d символов",
+}
@@ -31,6 +31,11 @@ var (
 	callbacksReceived *prometheus.CounterVec
 	callbacksErrors   *prometheus.CounterVec
 	redisErrors       *prometheus.CounterVec
+	remindersSent     *prometheus.CounterVec
+	remindersFailed   *prometheus.CounterVec
+	trackerOperations *prometheus.CounterVec
+	webhookDeliveries *prometheus.CounterVec
+	permissionCache   *prometheus.CounterVec
 
 	// Histogram metrics
 	messageProcessingDuration  *prometheus.HistogramVec
@@ -47,6 +52,10 @@ var (
 	dbLabelNames    []string
 	redisLabelNames []string
 	apiLabelNames   []string
+	reminderLabels  []string
+	trackerLabels   []string
+	webhookLabels   []string
+	cacheLabels     []string
 
 	initOnce sync.Once
 )
@@ -69,6 +78,10 @@ func initializeMetrics(cfg *config.Config) {
 	dbLabelNames = append(labelNames, "operation")
 	redisLabelNames = append(labelNames, "operation")
 	apiLabelNames = append(labelNames, "method", "endpoint", "status")
+	reminderLabels = append(labelNames, "kind")
+	trackerLabels = append(labelNames, "entity", "operation", "result")
+	webhookLabels = append(labelNames, "event", "result")
+	cacheLabels = append(labelNames, "layer", "result")
 
 	// init Counter metrics
 	messagesReceived = prometheus.NewCounterVec(
@@ -151,6 +164,46 @@ func initializeMetrics(cfg *config.Config) {
 		labelNames,
 	)
 
+	remindersSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PREFIX + "reminders_sent_total",
+			Help: "Total booking reminders sent",
+		},
+		reminderLabels,
+	)
+
+	remindersFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PREFIX + "reminders_failed_total",
+			Help: "Total booking reminders failed to send",
+		},
+		reminderLabels,
+	)
+
+	trackerOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PREFIX + "tracker_operations_total",
+			Help: "Total Yandex Tracker sync operations",
+		},
+		trackerLabels,
+	)
+
+	webhookDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PREFIX + "webhook_deliveries_total",
+			Help: "Total outbound webhook delivery attempts",
+		},
+		webhookLabels,
+	)
+
+	permissionCache = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PREFIX + "permission_cache_lookups_total",
+			Help: "Total role permission cache lookups",
+		},
+		cacheLabels,
+	)
+
 	botRateLimit = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: PREFIX + "rate_limit_hits_total",
 		Help: "Total hits of the bot request limit",
@@ -216,6 +269,11 @@ func initializeMetrics(cfg *config.Config) {
 	registry.MustRegister(activeUsers)
 	registry.MustRegister(redisQueryDuration)
 	registry.MustRegister(redisErrors)
+	registry.MustRegister(remindersSent)
+	registry.MustRegister(remindersFailed)
+	registry.MustRegister(trackerOperations)
+	registry.MustRegister(webhookDeliveries)
+	registry.MustRegister(permissionCache)
 
 	// standart metrics
 	registry.MustRegister(collectors.NewGoCollector())
@@ -276,6 +334,46 @@ func IncBookingsTotal() {
 	bookingsTotal.With(appLabels).Inc()
 }
 
+func IncRemindersSent(kind string) {
+	labels := maps.Clone(appLabels)
+	labels["kind"] = kind
+	remindersSent.With(labels).Inc()
+}
+
+func IncRemindersFailed(kind string) {
+	labels := maps.Clone(appLabels)
+	labels["kind"] = kind
+	remindersFailed.With(labels).Inc()
+}
+
+// IncTrackerOperations counts Tracker sync operations: operation is
+// create/push/pull, result is ok/error.
+func IncTrackerOperations(entity, operation, result string) {
+	labels := maps.Clone(appLabels)
+	labels["entity"] = entity
+	labels["operation"] = operation
+	labels["result"] = result
+	trackerOperations.With(labels).Inc()
+}
+
+// IncWebhookDeliveries counts webhook delivery attempts: result is
+// delivered/retry/failed.
+func IncWebhookDeliveries(event, result string) {
+	labels := maps.Clone(appLabels)
+	labels["event"] = event
+	labels["result"] = result
+	webhookDeliveries.With(labels).Inc()
+}
+
+// IncPermissionCacheLookups counts role permission lookups: layer is
+// local/redis, result is hit/miss.
+func IncPermissionCacheLookups(layer, result string) {
+	labels := maps.Clone(appLabels)
+	labels["layer"] = layer
+	labels["result"] = result
+	permissionCache.With(labels).Inc()
+}
+
 func IncBotRateLimit() {
 	botRateLimit.Inc()
 }
@@ -296,6 +394,13 @@ func ObserveCacheSetDuration(operation string, seconds float64) {
 	redisQueryDuration.With(labels).Observe(seconds)
 }
 
+// ObserveCacheGetDuration records the duration of a Redis read under operation
+func ObserveCacheGetDuration(operation string, seconds float64) {
+	labels := maps.Clone(appLabels)
+	labels["operation"] = operation
+	redisQueryDuration.With(labels).Observe(seconds)
+}
+
 func SetActiveUsers(count int) {
 	activeUsers.With(appLabels).Set(float64(count))
 }
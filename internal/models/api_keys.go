@@ -0,0 +1,34 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKeyActorRole is the role an API key acts with: it is recorded in the
+// audit log and is never granted anything by role, only by the key's scopes
+const APIKeyActorRole = "api_key"
+
+// APIKey lets an external system call the API without a staff account. The
+// key itself is shown once on creation; only its hash is stored.
+type APIKey struct {
+	ID         int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	CreatedBy  *int64
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the key is neither revoked nor expired
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) HasScope(permission string) bool {
+	return slices.Contains(k.Scopes, permission)
+}
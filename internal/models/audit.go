@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionUpdateStatus   = "update_status"
+	AuditActionUpload         = "upload"
+	AuditActionDelete         = "delete"
+	AuditActionResendInvite   = "resend_invite"
+	AuditActionRevokeInvite   = "revoke_invite"
+	AuditActionApprove        = "approve"
+	AuditActionRevoke         = "revoke"
+	AuditActionLinkTelegram   = "issue_telegram_link"
+	AuditActionUnlinkTelegram = "unlink_telegram"
+	AuditActionStart          = "start"
+	AuditActionCancel         = "cancel"
+)
+
+// AuditEntry is one mutating API call: who changed which entity and how.
+// Before, After and Diff hold JSON; Diff maps changed fields to
+// {"before": ..., "after": ...}.
+type AuditEntry struct {
+	ID         int64     `db:"id"`
+	ActorID    *int64    `db:"actor_id"`
+	ActorRole  string    `db:"actor_role"`
+	Entity     string    `db:"entity"`
+	EntityID   string    `db:"entity_id"`
+	Action     string    `db:"action"`
+	Method     string    `db:"method"`
+	Path       string    `db:"path"`
+	StatusCode int       `db:"status_code"`
+	Before     []byte    `db:"before"`
+	After      []byte    `db:"after"`
+	Diff       []byte    `db:"diff"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type AuditFilter struct {
+	Entity   string
+	EntityID string
+	ActorID  int64
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
+type AuditList struct {
+	Items  []AuditEntry
+	Total  int
+	Limit  int
+	Offset int
+}
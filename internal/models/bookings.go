@@ -42,3 +42,9 @@ type BookingList struct {
 	Limit  int
 	Offset int
 }
+
+const (
+	BookingStatusPending   = "pending"
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCancelled = "cancelled"
+)
@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+const (
+	CampaignStatusDraft     = "draft"
+	CampaignStatusRunning   = "running"
+	CampaignStatusDone      = "done"
+	CampaignStatusCancelled = "cancelled"
+)
+
+const (
+	CampaignRecipientPending = "pending"
+	CampaignRecipientSent    = "sent"
+	CampaignRecipientBlocked = "blocked"
+	CampaignRecipientFailed  = "failed"
+)
+
+// Segment types of a campaign audience
+const (
+	CampaignSegmentAll     = "all"
+	CampaignSegmentService = "service"
+	CampaignSegmentGrade   = "grade"
+)
+
+// Campaign is a message broadcast to the bot users of a segment. Only a
+// draft can be changed; the recipients are resolved when it is started.
+type Campaign struct {
+	ID         int64
+	Title      string
+	Text       string
+	ImageURL   string
+	Buttons    []CampaignButton
+	Segment    CampaignSegment
+	Status     string
+	CreatedBy  *int64
+	Progress   CampaignProgress
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// CampaignButton is an inline button opening the URL
+type CampaignButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// CampaignSegment selects the audience: every bot user, the users with past
+// bookings of the services or the users of the grades.
+type CampaignSegment struct {
+	Type       string  `json:"type"`
+	ServiceIDs []int64 `json:"service_ids,omitempty"`
+	Grades     []int   `json:"grades,omitempty"`
+}
+
+// CampaignProgress counts the recipients by delivery status
+type CampaignProgress struct {
+	Total   int `db:"total"`
+	Pending int `db:"pending"`
+	Sent    int `db:"sent"`
+	Blocked int `db:"blocked"`
+	Failed  int `db:"failed"`
+}
+
+// CampaignRecipient is the delivery of a campaign to one bot user
+type CampaignRecipient struct {
+	ID         int64      `db:"id"`
+	CampaignID int64      `db:"campaign_id"`
+	TelegramID int64      `db:"telegram_id"`
+	Status     string     `db:"status"`
+	Attempts   int        `db:"attempts"`
+	LastError  *string    `db:"last_error"`
+	SentAt     *time.Time `db:"sent_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
+
+// CampaignDelivery is a claimed recipient with the message it gets
+type CampaignDelivery struct {
+	ID         int64
+	CampaignID int64
+	TelegramID int64
+	Attempts   int
+	Text       string
+	ImageURL   string
+	Buttons    []CampaignButton
+}
+
+type CampaignRecipientFilter struct {
+	CampaignID int64
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+type CampaignRecipientList struct {
+	Items  []CampaignRecipient
+	Total  int
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// StaffMFA is the second factor state of a staff member. Secret is the TOTP
+// secret encrypted for storage; Required comes from the role of the staff member.
+type StaffMFA struct {
+	StaffID   int64      `db:"id"`
+	Email     string     `db:"email"`
+	Secret    *string    `db:"mfa_secret"`
+	EnabledAt *time.Time `db:"mfa_enabled_at"`
+	LastStep  *int64     `db:"mfa_last_step"`
+	Required  bool       `db:"mfa_required"`
+	CodesLeft int        `db:"recovery_codes_left"`
+}
+
+// Enabled reports whether login asks for the second factor
+func (m *StaffMFA) Enabled() bool {
+	return m.EnabledAt != nil && m.Secret != nil
+}
+
+// Pending reports whether the role requires the second factor the staff
+// member has not enrolled yet
+func (m *StaffMFA) Pending() bool {
+	return m.Required && !m.Enabled()
+}
+
+// MFAEnrollment is a new TOTP secret to be added to an authenticator app
+type MFAEnrollment struct {
+	Secret string
+	URL    string
+}
@@ -70,6 +70,38 @@ var (
 	ErrInvalidFileType         = errors.New("wrong format file")
 	ErrInvalidEmail            = errors.New("invalid email address")
 	ErrPhoneNumberAlreadyExist = errors.New("phone number already exist")
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL       = errors.New("invalid webhook url")
+	ErrUnknownWebhookEvent     = errors.New("unknown webhook event")
+	ErrUnknownPermission       = errors.New("unknown permission")
+	ErrPermissionConflict      = errors.New("permission is both granted and denied")
+	ErrRoleNotFound            = errors.New("role not found")
+	ErrRoleAlreadyExist        = errors.New("role already exist")
+	ErrRoleInUse               = errors.New("role is assigned to users")
+	ErrSystemRole              = errors.New("system role cannot be deleted")
+	ErrAuthSessionNotFound     = errors.New("auth session not found")
+	ErrRefreshTokenReused      = errors.New("refresh token reused")
+	ErrMFAInvalidCode          = errors.New("invalid mfa code")
+	ErrMFANotEnrolled          = errors.New("mfa is not enrolled")
+	ErrMFAAlreadyEnabled       = errors.New("mfa is already enabled")
+	ErrMFARequired             = errors.New("mfa is required by role")
+	ErrMFAEnrollmentRequired   = errors.New("mfa enrollment required")
+	ErrInvalidInvite           = errors.New("invite is invalid or expired")
+	ErrInviteNotPending        = errors.New("staff member has no pending invite")
+	ErrRegistrationDisabled    = errors.New("registration is disabled")
+	ErrEmailDomainNotAllowed   = errors.New("email domain is not allowed")
+	ErrEmailNotVerified        = errors.New("email is not verified")
+	ErrInvalidVerification     = errors.New("email verification link is invalid or expired")
+	ErrStaffNotApproved        = errors.New("staff member is not approved")
+	ErrTooManyAttempts         = errors.New("too many attempts")
+	ErrAuthUnavailable         = errors.New("token revocation check is unavailable")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrTelegramLinkInvalid     = errors.New("telegram link code is invalid or expired")
+	ErrTelegramNotLinked       = errors.New("telegram account is not linked to staff")
+	ErrCampaignNotFound        = errors.New("campaign not found")
+	ErrInvalidCampaign         = errors.New("invalid campaign")
+	ErrCampaignStatus          = errors.New("action is not allowed in the campaign status")
+	ErrCampaignNoRecipients    = errors.New("campaign audience is empty")
 )
 
 var AllowedSlugs = map[string]struct{}{
@@ -85,13 +117,36 @@ type ApplicationURI struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+// RefreshToken is one token of a session. Only the hash of the token is
+// stored; a rotated token is kept to detect its reuse.
 type RefreshToken struct {
-	ID        int64     `db:"id"`
-	UserID    int64     `db:"user_id"`
-	Role      string    `db:"role"`
-	Token     string    `db:"token"`
-	ExpiresAt time.Time `db:"expires_at"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	SessionID int64      `db:"session_id"`
+	Role      string     `db:"role"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RotatedAt *time.Time `db:"rotated_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// AuthSession is a login on one device: a family of rotated refresh tokens
+type AuthSession struct {
+	ID         int64     `db:"id"`
+	UserID     int64     `db:"user_id"`
+	Device     string    `db:"device"`
+	IP         string    `db:"ip"`
+	UserAgent  string    `db:"user_agent"`
+	ExpiresAt  time.Time `db:"expires_at"`
+	LastUsedAt time.Time `db:"last_used_at"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ClientInfo describes the device a request came from
+type ClientInfo struct {
+	Device    string
+	IP        string
+	UserAgent string
 }
 
 type PasswordResetToken struct {
@@ -132,8 +187,16 @@ type UserAPI struct {
 	Supervisor   string
 	Address      string
 	InviteToken  string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// InviteExpiresAt is set while an invite sent to the staff member is pending
+	InviteExpiresAt *time.Time
+	// EmailVerifiedAt is nil until a self-registered staff member follows the
+	// verification link; such a member cannot log in
+	EmailVerifiedAt *time.Time
+	// ApprovedAt is nil until an admin approves a self-registered staff
+	// member; until then the role stays user
+	ApprovedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // UserWithAuth holds user and password hash for auth flow.
@@ -147,6 +210,9 @@ type AuthResult struct {
 	User         *UserAPI `json:"user"`
 	Token        string   `json:"token"`
 	RefreshToken string   `json:"refresh_token"`
+	Permissions  []string `json:"permissions"`
+	// MFAToken is set instead of the tokens when login needs the second factor
+	MFAToken string `json:"mfa_token,omitempty"`
 }
 
 type BookingExt struct {
@@ -194,6 +260,8 @@ type StaffAdminCreate struct {
 	Address     *string
 	InviteToken string
 	Image       *string
+	// InviteExpiresAt is nil when no invite is sent
+	InviteExpiresAt *time.Time
 }
 
 type StaffAdminUpdate struct {
@@ -0,0 +1,9 @@
+package models
+
+// OutboxMessage is a Telegram message waiting in the notification outbox.
+type OutboxMessage struct {
+	ID       int64  `db:"id"`
+	ChatID   int64  `db:"chat_id"`
+	Text     string `db:"text"`
+	Attempts int    `db:"attempts"`
+}
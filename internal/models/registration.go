@@ -0,0 +1,37 @@
+package models
+
+import "strings"
+
+// Registration modes of POST /auth/register
+const (
+	// RegistrationDisabled rejects every self-registration
+	RegistrationDisabled = "disabled"
+	// RegistrationInviteOnly lets only staff invited by an admin register
+	RegistrationInviteOnly = "invite_only"
+	// RegistrationDomains lets anyone with an email in the allowed domains
+	// register; such accounts verify the email and wait for approval
+	RegistrationDomains = "domains"
+)
+
+// RegistrationPolicy decides who may register themselves. An unknown mode
+// disables registration.
+type RegistrationPolicy struct {
+	Mode           string
+	AllowedDomains []string
+}
+
+// AllowsEmail reports whether the domain of the email is allowed. Subdomains
+// have to be listed separately.
+func (p RegistrationPolicy) AllowsEmail(email string) bool {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range p.AllowedDomains {
+		if strings.EqualFold(domain, strings.TrimPrefix(strings.TrimSpace(allowed), "@")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ReminderKind string
+
+const (
+	ReminderKindWeek    ReminderKind = "week"
+	ReminderKind24Hours ReminderKind = "24h"
+)
+
+// DueReminder is a confirmed booking that should receive a reminder of the given kind.
+type DueReminder struct {
+	BookingID   int64        `db:"booking_id"`
+	ChatID      int64        `db:"chat_id"`
+	ServiceName string       `db:"service_name"`
+	GuestName   string       `db:"guest_name"`
+	Language    string       `db:"language"`
+	BookingDate time.Time    `db:"booking_date"`
+	BookingTime *time.Time   `db:"booking_time"`
+	Kind        ReminderKind `db:"-"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Role is a staff role defined by the admin. System roles carry built-in
+// access rules and cannot be deleted.
+type Role struct {
+	Name        string `db:"name"`
+	Title       string `db:"title"`
+	Description string `db:"description"`
+	IsSystem    bool   `db:"is_system"`
+	MFARequired bool   `db:"mfa_required"`
+	// Assignable staff get new bookings and applications automatically
+	Assignable bool      `db:"assignable"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// RoleUpdate holds the fields to change; nil fields are kept.
+type RoleUpdate struct {
+	Title       *string
+	Description *string
+	MFARequired *bool
+	Assignable  *bool
+}
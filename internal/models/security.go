@@ -0,0 +1,51 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Events recorded in the security log
+const (
+	SecurityEventLockout        = "lockout"
+	SecurityEventLockoutCleared = "lockout_cleared"
+)
+
+// Actions protected against brute force
+const (
+	GuardedActionLogin          = "login"
+	GuardedActionForgotPassword = "forgot_password"
+	GuardedActionMFA            = "mfa"
+)
+
+// SecurityEvent is an entry of the security log. Email and IP are the subject
+// that was locked out or cleared; ActorID is the admin who cleared it.
+type SecurityEvent struct {
+	ID          int64      `db:"id"`
+	Event       string     `db:"event"`
+	Action      string     `db:"action"`
+	Email       string     `db:"email"`
+	IP          string     `db:"ip"`
+	ActorID     *int64     `db:"actor_id"`
+	LockedUntil *time.Time `db:"locked_until"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// LockoutError is returned while the attempts of an email or an IP are locked
+// out; it matches ErrTooManyAttempts
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// RetryAfterSeconds is the value of the Retry-After header, at least one second
+func (e *LockoutError) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
@@ -59,6 +59,15 @@ type BoxAvailableSlot struct {
 	Date      string
 	StartTime string
 	EndTime   string
+	// Capacity — сколько бронирований принимает слот (0 — не задано).
+	Capacity int
+	// Booked — сколько активных бронирований уже есть на слот.
+	Booked int
+}
+
+// IsFull сообщает, что на слот больше нельзя записаться.
+func (s BoxAvailableSlot) IsFull() bool {
+	return s.Capacity > 0 && s.Booked >= s.Capacity
 }
 
 // BoxSolutionsButton — кнопка меню «Коробочные решения».
@@ -72,6 +81,7 @@ type BoxNewSlots struct {
 	Date      []time.Time
 	StartTime []time.Time
 	EndTime   []time.Time
+	Capacity  []int
 }
 
 type BoxUpdateStatusResult struct {
@@ -2,6 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/lib/pq"
 )
@@ -23,6 +26,33 @@ type SettingsPermissions struct {
 	Permissions pq.StringArray `db:"permissions"`
 }
 
+// PermissionOverrides are the staff-level changes to the role permissions.
+// A denial wins over both the role matrix and a grant.
+type PermissionOverrides struct {
+	Granted pq.StringArray `db:"granted_permissions"`
+	Denied  pq.StringArray `db:"denied_permissions"`
+}
+
+// Apply returns the role permissions with the overrides applied
+func (o PermissionOverrides) Apply(rolePermissions []string) []string {
+	result := make([]string, 0, len(rolePermissions)+len(o.Granted))
+	for _, list := range [][]string{rolePermissions, o.Granted} {
+		for _, permission := range list {
+			if !slices.Contains(o.Denied, permission) && !slices.Contains(result, permission) {
+				result = append(result, permission)
+			}
+		}
+	}
+	return result
+}
+
+// StaffPermissions are the permission overrides of a staff member
+type StaffPermissions struct {
+	StaffID int64  `db:"id"`
+	Role    string `db:"role"`
+	PermissionOverrides
+}
+
 type SettingsFormMessages struct {
 	WelcomeMessage          string `db:"welcome_message"`
 	RecordConfirmation      string `db:"record_confirmation"`
@@ -32,3 +62,39 @@ type SettingsFormMessages struct {
 	ThanksMessage           string `db:"thanks_message"`
 	SystemErrMessage        string `db:"system_err_message"`
 }
+
+// Messages returns the message templates by their settings key
+func (s SettingsFormMessages) Messages() map[string]string {
+	return map[string]string{
+		"welcome_message":             s.WelcomeMessage,
+		"record_confirmation":         s.RecordConfirmation,
+		"event_reminder_for_week":     s.EventReminderForWeek,
+		"event_reminder_for_24_hours": s.EventReminderFor24Hours,
+		"cancellation_message":        s.CancellationMessage,
+		"thanks_message":              s.ThanksMessage,
+		"system_err_message":          s.SystemErrMessage,
+	}
+}
+
+// MessageTemplateError lists the invalid message templates by their settings
+// key; it matches ErrValidation
+type MessageTemplateError struct {
+	Fields map[string]string
+}
+
+func (e *MessageTemplateError) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+func (e *MessageTemplateError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Messages are the errors as "key: reason", sorted by key
+func (e *MessageTemplateError) Messages() []string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, key := range slices.Sorted(maps.Keys(e.Fields)) {
+		messages = append(messages, key+": "+e.Fields[key])
+	}
+	return messages
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// LinkedStaff is an active staff member whose Telegram account is linked to
+// the bot. Permissions are the effective ones, resolved when the staff member
+// is looked up.
+type LinkedStaff struct {
+	ID          int64
+	TelegramID  int64
+	FirstName   string
+	LastName    string
+	Role        string
+	Permissions []string
+}
+
+// Can reports whether the staff member has the permission
+func (s *LinkedStaff) Can(permission string) bool {
+	return slices.Contains(s.Permissions, permission)
+}
+
+// TelegramLinkCode is the one-time code a staff member sends to the bot to
+// link their Telegram account. The code is shown once; only its hash is stored.
+type TelegramLinkCode struct {
+	Code      string
+	ExpiresAt time.Time
+}
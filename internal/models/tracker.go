@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TrackerEntity is the kind of record mirrored to Yandex Tracker.
+type TrackerEntity string
+
+const (
+	TrackerEntityBooking     TrackerEntity = "booking"
+	TrackerEntityApplication TrackerEntity = "application"
+)
+
+// TrackerItem is a booking or an application with the data needed for its Tracker issue.
+// TrackerStatus is the last status both sides agreed on; it differs from Status
+// when the record was changed locally after the last sync.
+type TrackerItem struct {
+	Entity            TrackerEntity `db:"-"`
+	ID                int64         `db:"id"`
+	Status            string        `db:"status"`
+	TicketKey         string        `db:"tracker_ticket_id"`
+	TrackerStatus     string        `db:"tracker_status"`
+	GuestName         string        `db:"guest_name"`
+	GuestContact      string        `db:"guest_contact"`
+	GuestOrganization string        `db:"guest_organization"`
+	GuestPosition     string        `db:"guest_position"`
+	ServiceName       string        `db:"service_name"`
+	BookingDate       *time.Time    `db:"booking_date"`
+	BookingTime       *time.Time    `db:"booking_time"`
+	Description       string        `db:"description"`
+	ManagerName       string        `db:"manager_name"`
+	ManagerEmail      string        `db:"manager_email"`
+	CreatedAt         time.Time     `db:"created_at"`
+}
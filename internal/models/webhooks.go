@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription is an external endpoint subscribed to domain events.
+// Secret signs the deliveries and is shown to the admin only on creation.
+type WebhookSubscription struct {
+	ID          int64
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WebhookSubscriptionUpdate holds the fields to change; nil fields are kept.
+type WebhookSubscriptionUpdate struct {
+	URL         *string
+	Events      []string
+	Description *string
+	IsActive    *bool
+}
+
+// WebhookDelivery is one event sent to one subscription.
+type WebhookDelivery struct {
+	ID             int64      `db:"id"`
+	SubscriptionID int64      `db:"subscription_id"`
+	Event          string     `db:"event"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	ResponseStatus *int       `db:"response_status"`
+	LastError      *string    `db:"last_error"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// WebhookDeliveryTask is a claimed delivery with the endpoint it goes to.
+type WebhookDeliveryTask struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	Status         string
+	Limit          int
+	Offset         int
+}
+
+type WebhookDeliveryList struct {
+	Items  []WebhookDelivery
+	Total  int
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,136 @@
+// Package msgtemplate renders the bot messages edited in the admin panel.
+//
+// A message is a text/template over Data, e.g.
+// "{{.GuestName}}, ждём вас {{.Date}} в {{.Time}}". Unknown placeholders and
+// syntax errors are reported by Validate before the message is saved.
+package msgtemplate
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+// Details is the booking line the notifications had before they became
+// templates; WithDetails appends it to a message without placeholders.
+const Details = "{{if .ServiceName}}{{.ServiceName}}, {{end}}{{.Date}}{{if .Time}} {{.Time}}{{end}}"
+
+const (
+	dateLayout     = "02.01.2006"
+	dateLayoutISO  = "2006-01-02"
+	timeLayout     = "15:04"
+	maxRenderedLen = 4096 // Telegram message limit
+)
+
+// Data is what a message can refer to. Fields unrelated to the message,
+// e.g. the booking of the welcome message, are empty.
+type Data struct {
+	GuestName   string
+	ServiceName string
+	Date        string
+	Time        string
+	BookingID   int64
+	// Lang is the language of the guest, "ru" or "en"; empty when unknown,
+	// e.g. in the status notifications sent from the admin panel
+	Lang string
+}
+
+// Sample is the data Validate checks the messages against and the preview
+// starts from
+func Sample(lang i18n.Lang) Data {
+	return Data{
+		GuestName:   "Иванов Иван",
+		ServiceName: "Экскурсия по офису",
+		Date:        time.Now().AddDate(0, 0, 7).Format(dateLayout),
+		Time:        "14:30",
+		BookingID:   42,
+		Lang:        string(lang),
+	}
+}
+
+// Validate reports the syntax errors and unknown placeholders of the message.
+// It is rendered in every language, so the branches on .Lang are checked too.
+func Validate(text string) error {
+	for _, lang := range i18n.Supported {
+		if _, err := Render(text, Sample(lang)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Error is a mistake in the message, not in the data; it matches
+// models.ErrValidation
+type Error struct {
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return e.Reason
+}
+
+func (e *Error) Is(target error) bool {
+	return target == models.ErrValidation
+}
+
+// Render executes the message against the data
+func Render(text string, data Data) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", &Error{Reason: err.Error()}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", &Error{Reason: err.Error()}
+	}
+	if len([]rune(buf.String())) > maxRenderedLen {
+		return "", &Error{Reason: fmt.Sprintf("the message is longer than %d characters", maxRenderedLen)}
+	}
+
+	return buf.String(), nil
+}
+
+// WithDetails appends the booking details to a message without placeholders,
+// so the texts saved before the templates keep their layout
+func WithDetails(text string) string {
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil || tmpl.Tree == nil || hasActions(tmpl.Tree.Root) {
+		return text
+	}
+	return text + "\n\n" + Details
+}
+
+func hasActions(root *parse.ListNode) bool {
+	if root == nil {
+		return false
+	}
+	for _, node := range root.Nodes {
+		if node.Type() != parse.NodeText {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatDate formats a booking date for the guest; dates of other formats
+// are returned as they are
+func FormatDate(date string) string {
+	if parsed, err := time.Parse(dateLayoutISO, date); err == nil {
+		return parsed.Format(dateLayout)
+	}
+	return date
+}
+
+// FormatTime formats a booking time for the guest
+func FormatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(timeLayout)
+}
@@ -0,0 +1,64 @@
+package msgtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+func TestRender(t *testing.T) {
+	data := Data{GuestName: "Анна", ServiceName: "Бокс А", Date: "10.05.2026", Time: "14:30", Lang: "en"}
+
+	text, err := Render(`{{if eq .Lang "en"}}Hi{{else}}Привет{{end}}, {{.GuestName}}! {{.ServiceName}}, {{.Date}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi, Анна! Бокс А, 10.05.2026", text)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"plain text", "Спасибо!", false},
+		{"empty", "", false},
+		{"known placeholders", "{{.GuestName}}: {{.ServiceName}} {{.Date}} {{.Time}} #{{.BookingID}}", false},
+		{"unknown placeholder", "{{.Phone}}", true},
+		{"unknown placeholder in a language branch", `{{if eq .Lang "en"}}{{.Phone}}{{end}}`, true},
+		{"syntax error", "{{.GuestName", true},
+		{"unknown function", "{{upper .GuestName}}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.text)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrValidation)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithDetails(t *testing.T) {
+	data := Data{ServiceName: "Бокс А", Date: "10.05.2026", Time: "14:30"}
+
+	text, err := Render(WithDetails("Завтра"), data)
+	require.NoError(t, err)
+	assert.Equal(t, "Завтра\n\nБокс А, 10.05.2026 14:30", text)
+
+	text, err = Render(WithDetails("Завтра"), Data{Date: "10.05.2026"})
+	require.NoError(t, err)
+	assert.Equal(t, "Завтра\n\n10.05.2026", text, "no service and no time")
+
+	assert.Equal(t, "Ждём вас {{.Date}}", WithDetails("Ждём вас {{.Date}}"), "a template places the details itself")
+}
+
+func TestFormatDate(t *testing.T) {
+	assert.Equal(t, "10.05.2026", FormatDate("2026-05-10"))
+	assert.Equal(t, "завтра", FormatDate("завтра"))
+}
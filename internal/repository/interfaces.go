@@ -22,6 +22,14 @@ type StaffRepository interface {
 	UpdateStaff(ctx context.Context, id int64, req *models.StaffAdminUpdate) (*models.UserAPI, error)
 	UpdatePassword(ctx context.Context, staffId int64, passHash string) error
 	UpdateStaffStatus(ctx context.Context, id int64, status string) (*models.UserAPI, error)
+	RenewInvite(ctx context.Context, id int64, token string, expiresAt time.Time) (*models.UserAPI, error)
+	RevokeInvite(ctx context.Context, id int64) (*models.UserAPI, error)
+	AcceptInvite(ctx context.Context, token, passHash string) (*models.UserAPI, error)
+	RegisterInvited(ctx context.Context, token string, userReq *models.UserAPI, passHash string) (*models.UserAPI, error)
+	VerifyEmail(ctx context.Context, id int64, email string) (*models.UserAPI, error)
+	Approve(ctx context.Context, id int64) (*models.UserAPI, error)
+	GetPermissions(ctx context.Context, id int64) (*models.StaffPermissions, error)
+	UpdatePermissionOverrides(ctx context.Context, id int64, overrides models.PermissionOverrides) (*models.StaffPermissions, error)
 }
 
 type TelegramUserRepository interface {
@@ -37,8 +45,69 @@ type BookingRepository interface {
 	GetBookingsByUserID(ctx context.Context, userID int64) ([]models.Booking, error)
 	UpdateBookingStatus(ctx context.Context, bookingID int64, status string) error
 	GetBookingById(ctx context.Context, id int64) (*models.BookingAPI, error)
+	LockBooking(ctx context.Context, id int64) (*models.BookingAPI, error)
 	GetBookingsList(ctx context.Context, filter *models.ApplicationFilter) (*models.BookingList, error)
 	DeleteBooking(ctx context.Context, id int64) error
+	RescheduleBooking(ctx context.Context, id int64, date time.Time, bookingTime *time.Time) error
+	ReserveBookingSlot(ctx context.Context, id int64) error
+}
+
+type BookingReminderRepository interface {
+	GetDueReminders(ctx context.Context, kind models.ReminderKind, from, to time.Duration, limit int) ([]models.DueReminder, error)
+	ClaimReminder(ctx context.Context, bookingID int64, kind models.ReminderKind) (bool, error)
+	ReleaseReminder(ctx context.Context, bookingID int64, kind models.ReminderKind) error
+}
+
+type NotificationOutboxRepository interface {
+	Enqueue(ctx context.Context, chatID int64, text string) error
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, lastErr string, retryAt *time.Time) error
+}
+
+type TrackerSyncRepository interface {
+	GetWithoutTicket(ctx context.Context, entity models.TrackerEntity, limit int) ([]models.TrackerItem, error)
+	GetForSync(ctx context.Context, entity models.TrackerEntity, limit int) ([]models.TrackerItem, error)
+	SetTicket(ctx context.Context, entity models.TrackerEntity, id int64, key, trackerStatus string) error
+	MarkSynced(ctx context.Context, entity models.TrackerEntity, id int64, status string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id int64, upd *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	EnqueueEvent(ctx context.Context, event string, payload []byte) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDeliveryTask, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	MarkDeliveryFailed(ctx context.Context, id int64, responseStatus int, lastErr string, retryAt *time.Time) error
+	ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) (*models.WebhookDeliveryList, error)
+}
+
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error)
+	Get(ctx context.Context, id int64) (*models.Campaign, error)
+	List(ctx context.Context) ([]models.Campaign, error)
+	Update(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error)
+	Delete(ctx context.Context, id int64) error
+	CountAudience(ctx context.Context, segment models.CampaignSegment) (int, error)
+	Start(ctx context.Context, id int64) (int, error)
+	Cancel(ctx context.Context, id int64) error
+	ListRecipients(ctx context.Context, filter models.CampaignRecipientFilter) (*models.CampaignRecipientList, error)
+}
+
+// CampaignDeliveryRepository is the queue of campaign messages the bot process delivers
+type CampaignDeliveryRepository interface {
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.CampaignDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, status, lastErr string) error
+	MarkRetry(ctx context.Context, id int64, lastErr string, retryAt time.Time) error
+	CompleteFinished(ctx context.Context) (int, error)
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) (*models.AuditList, error)
 }
 
 type ApplicationRepository interface {
@@ -79,14 +148,94 @@ type SettingsRepository interface {
 	PostSettings(ctx context.Context, newSettings models.SettingsPermissions) error
 }
 
+// SettingsMessagesCache serves the bot message templates. Invalidate drops
+// them on every replica after they change.
+type SettingsMessagesCache interface {
+	GetSettings(ctx context.Context) (models.SettingsFormMessages, error)
+	Invalidate(ctx context.Context) error
+}
+
+// PermissionCache serves role permissions and staff overrides for access
+// checks. Invalidate and InvalidateOverrides drop an entry on every replica
+// after it changes.
+type RoleRepository interface {
+	List(ctx context.Context) ([]models.Role, error)
+	Get(ctx context.Context, name string) (*models.Role, error)
+	Create(ctx context.Context, role *models.Role) (*models.Role, error)
+	Update(ctx context.Context, name string, upd *models.RoleUpdate) (*models.Role, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type PermissionCache interface {
+	Permissions(ctx context.Context, role string) ([]string, error)
+	Overrides(ctx context.Context, staffID int64) (models.PermissionOverrides, error)
+	Invalidate(ctx context.Context, role string) error
+	InvalidateOverrides(ctx context.Context, staffID int64) error
+}
+
+type TokenRevocation interface {
+	Version(ctx context.Context, userID int64) (int64, error)
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeAll(ctx context.Context, userID int64) error
+	Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	RevokeSession(ctx context.Context, sessionID int64, ttl time.Duration) error
+	IsRevoked(ctx context.Context, userID int64, jti string, sessionID, version int64) (bool, error)
+}
+
+// LoginThrottle counts failed attempts and locks out emails and IPs
+type LoginThrottle interface {
+	AddAttempt(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, base, limit, memory time.Duration) (time.Duration, error)
+	LockedFor(ctx context.Context, keys ...string) (time.Duration, error)
+	ResetAttempts(ctx context.Context, key string) error
+	Unlock(ctx context.Context, key string) error
+}
+
+type SecurityLogRepository interface {
+	Create(ctx context.Context, event *models.SecurityEvent) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	GetByID(ctx context.Context, id int64) (*models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int64) error
+	Touch(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// StaffTelegramRepository links staff accounts to Telegram with one-time codes
+type StaffTelegramRepository interface {
+	SetLinkCode(ctx context.Context, staffID int64, codeHash string, expiresAt time.Time) error
+	Link(ctx context.Context, codeHash string, telegramID int64) (*models.LinkedStaff, error)
+	Unlink(ctx context.Context, staffID int64) error
+	GetByTelegramID(ctx context.Context, telegramID int64) (*models.LinkedStaff, error)
+}
+
 type RefreshTokenRepository interface {
+	CreateSession(ctx context.Context, session *models.AuthSession) (*models.AuthSession, error)
+	TouchSession(ctx context.Context, sessionID int64, client models.ClientInfo, expiresAt time.Time) error
+	ListSessions(ctx context.Context, userID int64) ([]models.AuthSession, error)
+	DeleteSession(ctx context.Context, sessionID int64) error
+	DeleteUserSession(ctx context.Context, userID, sessionID int64) error
+	DeleteSessionByToken(ctx context.Context, tokenHash string) error
 	Create(ctx context.Context, rt *models.RefreshToken) error
-	CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) error
-	GetForUpdate(ctx context.Context, token string) (*models.RefreshToken, error)
-	DeleteByToken(ctx context.Context, token string) error
+	GetForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkRotated(ctx context.Context, id int64) error
 	DeleteByStaffID(ctx context.Context, id int64) error
 }
 
+// MFARepository stores the TOTP secrets and recovery codes of staff
+type MFARepository interface {
+	Get(ctx context.Context, staffID int64) (*models.StaffMFA, error)
+	SetSecret(ctx context.Context, staffID int64, secret string) error
+	Enable(ctx context.Context, staffID int64) error
+	Disable(ctx context.Context, staffID int64) error
+	UseStep(ctx context.Context, staffID, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, staffID int64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, staffID int64, codeHash string) error
+}
+
 type PasswordResetRepository interface {
 	CreateToken(ctx context.Context, userID int64, token string, expiresAt time.Time) error
 	GetToken(ctx context.Context, token string) (*models.PasswordResetToken, error)
@@ -44,15 +44,31 @@ const (
 			u.last_name,
 			u.email,
 			COUNT(b.id)     AS total_bookings,
-			u.created_at    AS registered_at
+			u.created_at    AS registered_at,
+			COALESCE(u.source, '') AS source
 		FROM users u
 		LEFT JOIN bookings b
 			ON  b.user_id = u.id
 			AND ($1::date IS NULL OR b.booking_date >= $1::date)
 			AND ($2::date IS NULL OR b.booking_date <= $2::date)
-		GROUP BY u.id, u.first_name, u.last_name, u.email, u.created_at
+		GROUP BY u.id, u.first_name, u.last_name, u.email, u.created_at, u.source
 		ORDER BY u.created_at DESC
 		LIMIT $3`
+
+	getSourcesAnalyticsQuery = `
+		SELECT
+			COALESCE(b.source, '')     AS source,
+			COUNT(DISTINCT b.user_id)  AS guests,
+			COUNT(b.id)                AS total_bookings,
+			COUNT(b.id) FILTER (WHERE b.status = 'confirmed')  AS confirmed_bookings,
+			COUNT(b.id) FILTER (WHERE b.status = 'cancelled')  AS cancelled_bookings
+		FROM bookings b
+		WHERE b.deleted_at IS NULL
+			AND ($1::date IS NULL OR b.booking_date >= $1::date)
+			AND ($2::date IS NULL OR b.booking_date <= $2::date)
+		GROUP BY COALESCE(b.source, '')
+		ORDER BY total_bookings DESC, source
+		LIMIT $3`
 )
 
 type AnalyticsRepo struct {
@@ -80,3 +96,12 @@ func (r *AnalyticsRepo) GetUsersAnalytics(ctx context.Context, dateFrom, dateTo
 		return rows, err
 	})
 }
+
+func (r *AnalyticsRepo) GetSourcesAnalytics(ctx context.Context, dateFrom, dateTo *time.Time) ([]dto.AnalyticsSourceRow, error) {
+	const operation = "get_sources_analytics"
+	var rows []dto.AnalyticsSourceRow
+	return repository.WithDBMetricsValue(operation, func() ([]dto.AnalyticsSourceRow, error) {
+		err := r.db.SelectContext(ctx, &rows, getSourcesAnalyticsQuery, dateFrom, dateTo, analyticsExportLimit)
+		return rows, err
+	})
+}
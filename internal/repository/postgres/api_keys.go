@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	apiKeyColumns = `id, name, prefix, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at`
+
+	createAPIKeyQuery = `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+
+	getAPIKeyQuery = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE id = $1`
+
+	getAPIKeyByHashQuery = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1`
+
+	listAPIKeysQuery = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		ORDER BY id`
+
+	revokeAPIKeyQuery = `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1`
+
+	touchAPIKeyQuery = `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+)
+
+type apiKeyRow struct {
+	ID         int64          `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedBy  *int64         `db:"created_by"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (r apiKeyRow) toModel() *models.APIKey {
+	return &models.APIKey{
+		ID:         r.ID,
+		Name:       r.Name,
+		Prefix:     r.Prefix,
+		KeyHash:    r.KeyHash,
+		Scopes:     []string(r.Scopes),
+		CreatedBy:  r.CreatedBy,
+		ExpiresAt:  r.ExpiresAt,
+		LastUsedAt: r.LastUsedAt,
+		RevokedAt:  r.RevokedAt,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// APIKeyRepo stores the API keys of external systems.
+type APIKeyRepo struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyRepo creates a new APIKeyRepo.
+func NewAPIKeyRepo(db *sqlx.DB) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+func (r *APIKeyRepo) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	const operation = "create_api_key"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.APIKey, error) {
+		var row apiKeyRow
+		err := r.db.GetContext(ctx, &row, createAPIKeyQuery,
+			key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.CreatedBy, key.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("create api key: %w", err)
+		}
+		return row.toModel(), nil
+	})
+}
+
+func (r *APIKeyRepo) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	const operation = "get_api_key"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.APIKey, error) {
+		return r.get(ctx, getAPIKeyQuery, id)
+	})
+}
+
+func (r *APIKeyRepo) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	const operation = "get_api_key_by_hash"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.APIKey, error) {
+		return r.get(ctx, getAPIKeyByHashQuery, hash)
+	})
+}
+
+func (r *APIKeyRepo) get(ctx context.Context, query string, arg any) (*models.APIKey, error) {
+	var row apiKeyRow
+	if err := r.db.GetContext(ctx, &row, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *APIKeyRepo) List(ctx context.Context) ([]models.APIKey, error) {
+	const operation = "list_api_keys"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.APIKey, error) {
+		var rows []apiKeyRow
+		if err := r.db.SelectContext(ctx, &rows, listAPIKeysQuery); err != nil {
+			return nil, fmt.Errorf("list api keys: %w", err)
+		}
+
+		keys := make([]models.APIKey, 0, len(rows))
+		for _, row := range rows {
+			keys = append(keys, *row.toModel())
+		}
+		return keys, nil
+	})
+}
+
+// Revoke disables the key; revoking it again keeps the first revocation time
+func (r *APIKeyRepo) Revoke(ctx context.Context, id int64) error {
+	const operation = "revoke_api_key"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, revokeAPIKeyQuery, id)
+		if err != nil {
+			return fmt.Errorf("revoke api key: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("revoke api key: %w", err)
+		}
+		if n == 0 {
+			return models.ErrAPIKeyNotFound
+		}
+		return nil
+	})
+}
+
+func (r *APIKeyRepo) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	const operation = "touch_api_key"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.db.ExecContext(ctx, touchAPIKeyQuery, id, usedAt); err != nil {
+			return fmt.Errorf("touch api key: %w", err)
+		}
+		return nil
+	})
+}
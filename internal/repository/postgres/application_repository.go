@@ -20,21 +20,22 @@ VALUES (
     (
         SELECT s.id
         FROM staff s
+        JOIN roles r ON r.name = s.role AND r.assignable
         LEFT JOIN applications a ON a.manager_id = s.id
             AND a.status != 'cancelled'
         LEFT JOIN bookings b ON b.manager_id = s.id
             AND b.status != 'cancelled'
-        WHERE s.role IN ('manager_1', 'manager_2', 'manager_3', 'admin')
         GROUP BY s.id
         ORDER BY COUNT(a.id) + COUNT(b.id) ASC
         LIMIT 1
     ),
-    $1, $2, $3, $4
+    $1, $2, $3, NULLIF($4, '')
 )
+RETURNING id
 	`
 
 const getApplicationQuery = `
-	SELECT a.id, a.status, a.form_answer_id, a.customer_name, a.contact_info,
+	SELECT a.id, a.status, COALESCE(a.form_answer_id, '') AS form_answer_id, a.customer_name, a.contact_info,
 		a.description, a.created_at, a.updated_at, 
 		COALESCE(a.manager_id, 0) AS manager_id,
 		COALESCE(s.first_name || ' ' || s.last_name, '') AS manager_name
@@ -67,27 +68,13 @@ func NewApplicationRepository(db *sqlx.DB) *ApplicationRepo {
 }
 
 func (r *ApplicationRepo) CreateApplication(ctx context.Context, req *models.Application) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	_, err = tx.ExecContext(ctx, createApplicationQuery,
+	err := r.getDB(ctx).QueryRowxContext(ctx, createApplicationQuery,
 		req.CustomerName,
 		req.ContactInfo,
 		req.Description,
-		req.FormAnswerId)
+		req.FormAnswerId).Scan(&req.ID)
 	if err != nil {
-		return fmt.Errorf("create application tx: %w", err)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit application tx: %w", err)
+		return fmt.Errorf("create application: %w", err)
 	}
 
 	return nil
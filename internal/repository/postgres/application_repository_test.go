@@ -430,6 +430,32 @@ func TestCreateApplication_DuplicateFormAnswerID(t *testing.T) {
 	}
 }
 
+func TestCreateApplication_WithoutFormAnswerID(t *testing.T) {
+	clearApplications(t)
+
+	repo := NewApplicationRepository(db)
+
+	// Applications from the bot have no form answer: they must not collide
+	for i := 0; i < 2; i++ {
+		app := &models.Application{
+			CustomerName: "Клиент Бота",
+			ContactInfo:  "@bot_client",
+			Description:  "описание",
+		}
+		if err := repo.CreateApplication(context.Background(), app); err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+
+		got, err := repo.GetApplicationByID(context.Background(), app.ID)
+		if err != nil {
+			t.Fatalf("get %d: %v", i, err)
+		}
+		if got.FormAnswerId != "" {
+			t.Errorf("expected empty form answer id, got %q", got.FormAnswerId)
+		}
+	}
+}
+
 func TestCreateApplication_NoManagers(t *testing.T) {
 	clearApplications(t)
 	clearStaff(t)
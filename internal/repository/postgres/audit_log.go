@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	createAuditEntryQuery = `
+		INSERT INTO audit_log (actor_id, actor_role, entity, entity_id, action, method, path,
+		                       status_code, before, after, diff)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::jsonb, $10::jsonb, $11::jsonb)`
+
+	listAuditBaseQuery = `
+		SELECT id, actor_id, actor_role, entity, entity_id, action, method, path, status_code,
+		       before, after, diff, created_at,
+		       COUNT(*) OVER() AS total
+		FROM audit_log`
+)
+
+type auditRow struct {
+	models.AuditEntry
+	Total int `db:"total"`
+}
+
+// AuditLogRepo stores the audit log of the admin API.
+type AuditLogRepo struct {
+	db *sqlx.DB
+}
+
+// NewAuditLogRepo creates a new AuditLogRepo.
+func NewAuditLogRepo(db *sqlx.DB) *AuditLogRepo {
+	return &AuditLogRepo{db: db}
+}
+
+func (r *AuditLogRepo) Create(ctx context.Context, entry *models.AuditEntry) error {
+	const operation = "create_audit_entry"
+
+	return repository.WithDBMetrics(operation, func() error {
+		_, err := r.db.ExecContext(ctx, createAuditEntryQuery,
+			entry.ActorID,
+			entry.ActorRole,
+			entry.Entity,
+			entry.EntityID,
+			entry.Action,
+			entry.Method,
+			entry.Path,
+			entry.StatusCode,
+			nullJSON(entry.Before),
+			nullJSON(entry.After),
+			nullJSON(entry.Diff),
+		)
+		if err != nil {
+			return fmt.Errorf("create audit entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// List returns the entries matching the filter, newest first. To is exclusive.
+func (r *AuditLogRepo) List(ctx context.Context, filter models.AuditFilter) (*models.AuditList, error) {
+	const operation = "list_audit_entries"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.AuditList, error) {
+		conditions := make([]string, 0, 5)
+		args := make([]any, 0, 7)
+		i := 1
+
+		if filter.Entity != "" {
+			conditions = append(conditions, fmt.Sprintf("entity = $%d", i))
+			args = append(args, filter.Entity)
+			i++
+		}
+		if filter.EntityID != "" {
+			conditions = append(conditions, fmt.Sprintf("entity_id = $%d", i))
+			args = append(args, filter.EntityID)
+			i++
+		}
+		if filter.ActorID != 0 {
+			conditions = append(conditions, fmt.Sprintf("actor_id = $%d", i))
+			args = append(args, filter.ActorID)
+			i++
+		}
+		if filter.From != nil {
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", i))
+			args = append(args, *filter.From)
+			i++
+		}
+		if filter.To != nil {
+			conditions = append(conditions, fmt.Sprintf("created_at < $%d", i))
+			args = append(args, *filter.To)
+			i++
+		}
+
+		where := ""
+		if len(conditions) > 0 {
+			where = " WHERE " + strings.Join(conditions, " AND ")
+		}
+
+		args = append(args, filter.Limit, filter.Offset)
+		query := listAuditBaseQuery + where +
+			fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", i, i+1)
+
+		var rows []auditRow
+		if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+			return nil, fmt.Errorf("list audit entries: %w", err)
+		}
+
+		list := &models.AuditList{
+			Items:  make([]models.AuditEntry, 0, len(rows)),
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		}
+		for _, row := range rows {
+			list.Items = append(list.Items, row.AuditEntry)
+			list.Total = row.Total
+		}
+		return list, nil
+	})
+}
+
+// nullJSON passes an empty document as SQL NULL
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
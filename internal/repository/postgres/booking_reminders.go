@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	getDueRemindersQuery = `
+		SELECT b.id AS booking_id, b.user_id AS chat_id,
+		       COALESCE(sv.name, '') AS service_name,
+		       b.guest_name, COALESCE(u.language, '') AS language,
+		       b.booking_date, b.booking_time
+		FROM bookings b
+		LEFT JOIN services sv ON sv.id = b.service_id
+		LEFT JOIN users u ON u.telegram_id = b.user_id
+		WHERE b.status = 'confirmed'
+		  AND b.deleted_at IS NULL
+		  AND b.booking_date + COALESCE(b.booking_time, TIME '00:00') > LOCALTIMESTAMP + make_interval(secs => $2)
+		  AND b.booking_date + COALESCE(b.booking_time, TIME '00:00') <= LOCALTIMESTAMP + make_interval(secs => $3)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM booking_reminders r
+		      WHERE r.booking_id = b.id AND r.kind = $1
+		  )
+		ORDER BY b.booking_date ASC, b.booking_time ASC
+		LIMIT $4`
+
+	claimReminderQuery = `
+		INSERT INTO booking_reminders (booking_id, kind)
+		VALUES ($1, $2)
+		ON CONFLICT (booking_id, kind) DO NOTHING`
+
+	releaseReminderQuery = `
+		DELETE FROM booking_reminders
+		WHERE booking_id = $1 AND kind = $2`
+)
+
+// BookingReminderRepo tracks reminders sent to guests about their bookings.
+type BookingReminderRepo struct {
+	db *sqlx.DB
+}
+
+// NewBookingReminderRepo creates a new BookingReminderRepo.
+func NewBookingReminderRepo(db *sqlx.DB) *BookingReminderRepo {
+	return &BookingReminderRepo{db: db}
+}
+
+// GetDueReminders returns confirmed bookings starting in (from, to] from now
+// that have not received a reminder of the given kind yet.
+func (r *BookingReminderRepo) GetDueReminders(ctx context.Context, kind models.ReminderKind, from, to time.Duration, limit int) ([]models.DueReminder, error) {
+	const operation = "get_due_reminders"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.DueReminder, error) {
+		var reminders []models.DueReminder
+		err := r.db.SelectContext(ctx, &reminders, getDueRemindersQuery, string(kind), from.Seconds(), to.Seconds(), limit)
+		if err != nil {
+			return nil, fmt.Errorf("get due reminders: %w", err)
+		}
+		for i := range reminders {
+			reminders[i].Kind = kind
+		}
+		return reminders, nil
+	})
+}
+
+// ClaimReminder records the reminder as sent. It returns false when the
+// reminder has already been recorded, e.g. by a previous run.
+func (r *BookingReminderRepo) ClaimReminder(ctx context.Context, bookingID int64, kind models.ReminderKind) (bool, error) {
+	const operation = "claim_reminder"
+
+	return repository.WithDBMetricsValue(operation, func() (bool, error) {
+		result, err := r.db.ExecContext(ctx, claimReminderQuery, bookingID, string(kind))
+		if err != nil {
+			return false, fmt.Errorf("claim reminder: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("rows affected: %w", err)
+		}
+		return rows > 0, nil
+	})
+}
+
+// ReleaseReminder removes the record so the reminder is retried on the next run.
+func (r *BookingReminderRepo) ReleaseReminder(ctx context.Context, bookingID int64, kind models.ReminderKind) error {
+	const operation = "release_reminder"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.db.ExecContext(ctx, releaseReminderQuery, bookingID, string(kind)); err != nil {
+			return fmt.Errorf("release reminder: %w", err)
+		}
+		return nil
+	})
+}
@@ -21,17 +21,18 @@ const (
 	INSERT INTO bookings (
     user_id, service_id, booking_date, booking_time, 
     guest_name, guest_organization, guest_position, 
-    visit_type, tracker_ticket_id, manager_id
+    visit_type, tracker_ticket_id, source, manager_id
 	) 
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+    (SELECT source FROM users WHERE telegram_id = $1),
     (
         SELECT s.id
         FROM staff s
+        JOIN roles r ON r.name = s.role AND r.assignable
         LEFT JOIN applications a ON a.manager_id = s.id
             AND a.status != 'cancelled'
         LEFT JOIN bookings b ON b.manager_id = s.id
             AND b.status != 'cancelled'
-        WHERE s.role IN ('manager_1', 'manager_2', 'manager_3', 'admin')
         GROUP BY s.id
         ORDER BY COUNT(a.id) + COUNT(b.id) ASC
         LIMIT 1
@@ -39,6 +40,25 @@ const (
 	)
 	RETURNING id`
 
+	lockSlotQuery = `
+		SELECT capacity
+		FROM service_available_slots
+		WHERE service_id = $1
+		  AND slot_date = $2
+		  AND start_time IS NOT DISTINCT FROM $3::time
+		ORDER BY capacity DESC
+		LIMIT 1
+		FOR UPDATE`
+
+	countSlotBookingsQuery = `
+		SELECT COUNT(*)
+		FROM bookings
+		WHERE service_id = $1
+		  AND booking_date = $2
+		  AND booking_time IS NOT DISTINCT FROM $3::time
+		  AND status != 'cancelled'
+		  AND deleted_at IS NULL`
+
 	getAvailableSlotsQuery = `
 		SELECT booking_time 
 		FROM bookings 
@@ -86,6 +106,8 @@ const (
 		LEFT JOIN services sv ON sv.id = b.service_id
 		WHERE b.id = $1 AND b.deleted_at IS NULL
 	`
+	lockBookingQuery = getBookingById + ` FOR UPDATE OF b`
+
 	listBookingsBaseQuery = `
 		SELECT 
 				b.id, b.status, b.guest_name, b.created_at,
@@ -99,6 +121,22 @@ const (
     LEFT JOIN users u ON b.user_id = u.telegram_id
 		`
 
+	lockBookingForRescheduleQuery = `
+		SELECT service_id, booking_date, booking_time
+		FROM bookings
+		WHERE id = $1 AND status != 'cancelled' AND deleted_at IS NULL
+		FOR UPDATE`
+
+	getBookingSlotQuery = `
+		SELECT service_id, booking_date, booking_time
+		FROM bookings
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	rescheduleBookingQuery = `
+	UPDATE bookings
+	SET booking_date = $1, booking_time = $2, status = 'pending', updated_at = NOW()
+	WHERE id = $3`
+
 	deleteBookings = `
 	UPDATE bookings 
 	SET deleted_at = NOW(), updated_at = NOW() 
@@ -113,6 +151,8 @@ func NewBookingRepository(db *sqlx.DB) *BookingRepo {
 	return &BookingRepo{db: db}
 }
 
+// CreateBooking reserves the slot and saves the booking. It joins the
+// transaction of ctx, if any.
 func (r *BookingRepo) CreateBooking(ctx context.Context, b *models.Booking) (int64, error) {
 	const operation = "create_booking"
 
@@ -122,29 +162,18 @@ func (r *BookingRepo) CreateBooking(ctx context.Context, b *models.Booking) (int
 			return 0, err
 		}
 
+		if tx, ok := ctxutil.TxFromContext(ctx); ok {
+			return createBooking(ctx, tx, b)
+		}
+
 		tx, err := r.db.BeginTxx(ctx, nil)
 		if err != nil {
 			return 0, err
 		}
 		defer func() { _ = tx.Rollback() }()
 
-		var id int64
-		err = tx.QueryRowContext(ctx, createBookingAtomicQuery,
-			b.UserID,
-			b.ServiceID,
-			b.BookingDate,
-			b.BookingTime,
-			b.GuestName,
-			b.GuestOrganization,
-			b.GuestPosition,
-			b.VisitType,
-			b.TrackerTicketID,
-		).Scan(&id)
-
+		id, err := createBooking(ctx, tx, b)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return 0, models.ErrSlotOccupied
-			}
 			return 0, err
 		}
 
@@ -156,6 +185,65 @@ func (r *BookingRepo) CreateBooking(ctx context.Context, b *models.Booking) (int
 	})
 }
 
+func createBooking(ctx context.Context, tx *sqlx.Tx, b *models.Booking) (int64, error) {
+	if err := reserveSlot(ctx, tx, b); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := tx.QueryRowContext(ctx, createBookingAtomicQuery,
+		b.UserID,
+		b.ServiceID,
+		b.BookingDate,
+		b.BookingTime,
+		b.GuestName,
+		b.GuestOrganization,
+		b.GuestPosition,
+		b.VisitType,
+		b.TrackerTicketID,
+	).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrSlotOccupied
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// reserveSlot locks the service slot row until the transaction ends and checks
+// that it still has free places. Concurrent bookings of the same slot wait on
+// the lock, so the capacity can not be exceeded. Bookings for a date without
+// configured slots are not limited.
+func reserveSlot(ctx context.Context, tx sqlx.QueryerContext, b *models.Booking) error {
+	var startTime any
+	if b.BookingTime != nil {
+		startTime = b.BookingTime.Format("15:04:05")
+	}
+	date := b.BookingDate.Format("2006-01-02")
+
+	var capacity int
+	err := tx.QueryRowxContext(ctx, lockSlotQuery, b.ServiceID, date, startTime).Scan(&capacity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	var booked int
+	if err := tx.QueryRowxContext(ctx, countSlotBookingsQuery, b.ServiceID, date, startTime).Scan(&booked); err != nil {
+		return err
+	}
+	if booked >= capacity {
+		return models.ErrSlotOccupied
+	}
+
+	return nil
+}
+
 func (r *BookingRepo) GetAvailableSlots(ctx context.Context, serviceID int, date time.Time) ([]time.Time, error) {
 	const operation = "get_available_slots"
 	var slots []time.Time
@@ -211,6 +299,21 @@ func (r *BookingRepo) GetBookingById(ctx context.Context, id int64) (*models.Boo
 	return toBookingDomainModel(&booking), nil
 }
 
+// LockBooking reads the booking and locks its row until the transaction of
+// ctx ends, so that concurrent status changes see each other's result
+func (r *BookingRepo) LockBooking(ctx context.Context, id int64) (*models.BookingAPI, error) {
+	var booking dto.BookingAPIRaw
+	err := sqlx.GetContext(ctx, r.getDB(ctx), &booking, lockBookingQuery, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrBookingNotFound
+		}
+		return nil, err
+	}
+
+	return toBookingDomainModel(&booking), nil
+}
+
 func (r *BookingRepo) GetBookingsList(ctx context.Context, filter *models.ApplicationFilter) (*models.BookingList, error) {
 	conditions := make([]string, 0, 4)
 	args := make([]any, 0, 5)
@@ -292,6 +395,90 @@ func (r *BookingRepo) UpdateBookingStatus(ctx context.Context, id int64, status
 	return nil
 }
 
+// RescheduleBooking moves an active booking to another date and time of the
+// same service. The booking keeps its guest data and manager, goes back to
+// pending and frees its previous slot; the new slot is reserved under the same
+// lock as CreateBooking uses. It joins the transaction of ctx, if any.
+func (r *BookingRepo) RescheduleBooking(ctx context.Context, id int64, date time.Time, bookingTime *time.Time) error {
+	const operation = "reschedule_booking"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if tx, ok := ctxutil.TxFromContext(ctx); ok {
+			return rescheduleBooking(ctx, tx, id, date, bookingTime)
+		}
+
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := rescheduleBooking(ctx, tx, id, date, bookingTime); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func rescheduleBooking(ctx context.Context, tx *sqlx.Tx, id int64, date time.Time, bookingTime *time.Time) error {
+	var current struct {
+		ServiceID   int16      `db:"service_id"`
+		BookingDate time.Time  `db:"booking_date"`
+		BookingTime *time.Time `db:"booking_time"`
+	}
+	if err := tx.GetContext(ctx, &current, lockBookingForRescheduleQuery, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrBookingNotFound
+		}
+		return err
+	}
+
+	if sameSlot(current.BookingDate, current.BookingTime, date, bookingTime) {
+		return nil
+	}
+
+	b := &models.Booking{ServiceID: current.ServiceID, BookingDate: date, BookingTime: bookingTime}
+	if err := reserveSlot(ctx, tx, b); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, rescheduleBookingQuery, date, bookingTime, id); err != nil {
+		return fmt.Errorf("reschedule booking: %w", err)
+	}
+	return nil
+}
+
+// ReserveBookingSlot checks that the slot of a cancelled booking still has a
+// free place before the booking becomes active again. Within the transaction
+// of ctx the slot stays locked until the status is changed.
+func (r *BookingRepo) ReserveBookingSlot(ctx context.Context, id int64) error {
+	var current struct {
+		ServiceID   int16      `db:"service_id"`
+		BookingDate time.Time  `db:"booking_date"`
+		BookingTime *time.Time `db:"booking_time"`
+	}
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &current, getBookingSlotQuery, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrBookingNotFound
+		}
+		return err
+	}
+
+	b := &models.Booking{ServiceID: current.ServiceID, BookingDate: current.BookingDate, BookingTime: current.BookingTime}
+	return reserveSlot(ctx, r.getDB(ctx), b)
+}
+
+// sameSlot reports whether two booking dates and start times point to the same slot
+func sameSlot(d1 time.Time, t1 *time.Time, d2 time.Time, t2 *time.Time) bool {
+	if d1.Format("2006-01-02") != d2.Format("2006-01-02") {
+		return false
+	}
+	if t1 == nil || t2 == nil {
+		return t1 == nil && t2 == nil
+	}
+	return t1.Format("15:04") == t2.Format("15:04")
+}
+
 func (r *BookingRepo) DeleteBooking(ctx context.Context, id int64) error {
 	result, err := r.db.ExecContext(ctx, deleteBookings, id)
 	if err != nil {
@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 
 	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/ctxutil"
 	"github.com/yandex-development-1-team/go/internal/database"
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/metrics"
@@ -276,6 +279,33 @@ func TestCreateBooking(t *testing.T) {
 	}
 }
 
+func TestCreateBooking_AssignsStaffOfAssignableRole(t *testing.T) {
+	cleanBookingsTables(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`
+			INSERT INTO roles (name, title, assignable) VALUES ('front_desk', 'Ресепшен', TRUE)
+			ON CONFLICT (name) DO UPDATE SET assignable = TRUE`)
+	require.NoError(t, err)
+
+	seedUser(t, 222, "guest")
+	seedStaff(t, 1, "viewer@test.local", "user")
+	seedStaff(t, 2, "desk@test.local", "front_desk")
+
+	id, err := repo.CreateBooking(ctx, &models.Booking{
+		UserID:      222,
+		ServiceID:   1,
+		BookingDate: time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour),
+		BookingTime: mustParseTime("15:04:05", "12:00:00"),
+		GuestName:   "Guest",
+	})
+	require.NoError(t, err)
+
+	booking, err := repo.GetBookingById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), booking.ManagerID, "only staff of assignable roles get bookings")
+}
+
 func TestGetAvailableSlots(t *testing.T) {
 	_, _ = db.Exec("DELETE FROM bookings")
 
@@ -380,6 +410,47 @@ func TestGetBookingByID_SoftDeleted(t *testing.T) {
 	assert.ErrorIs(t, err, models.ErrBookingNotFound)
 }
 
+func TestLockBooking_HoldsRowUntilCommit(t *testing.T) {
+	cleanBookingsTables(t)
+
+	telegramID := int64(1003)
+	seedUser(t, telegramID, "lockuser")
+	seedService(t, 12, "Lock Service")
+
+	now := time.Now()
+	bookingID := seedBooking(t, &models.BookingAPI{
+		UserID:      telegramID,
+		ServiceID:   12,
+		BookingDate: now.Format("2006-01-02"),
+		BookingTime: "11:00",
+		GuestName:   "Locked",
+		Status:      "pending",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+
+	ctx := context.Background()
+	tx, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	got, err := repo.LockBooking(ctxutil.WithTx(ctx, tx), bookingID)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", got.Status)
+
+	// Вторая транзакция не может взять ту же строку, пока первая не завершена
+	other, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = other.Rollback() }()
+	_, err = other.ExecContext(ctx, "SET LOCAL lock_timeout = '100ms'")
+	require.NoError(t, err)
+	_, err = repo.LockBooking(ctxutil.WithTx(ctx, other), bookingID)
+	assert.Error(t, err)
+
+	_, err = repo.LockBooking(ctx, 999999)
+	assert.ErrorIs(t, err, models.ErrBookingNotFound)
+}
+
 func TestGetBookingsList_Empty(t *testing.T) {
 	cleanBookingsTables(t)
 	ctx := context.Background()
@@ -804,3 +875,236 @@ func TestUpdateBookingStatus_SoftDeleted_ReturnsNotFound(t *testing.T) {
 	err = repo.UpdateBookingStatus(ctx, bookingID, "confirmed")
 	assert.ErrorIs(t, err, models.ErrBookingNotFound)
 }
+
+// seedSlot создаёт слот услуги с заданной вместимостью
+func seedSlot(t *testing.T, serviceID int64, date time.Time, start, end string, capacity int) {
+	_, err := db.Exec(`
+			INSERT INTO service_available_slots (service_id, slot_date, start_time, end_time, capacity)
+			VALUES ($1, $2, $3::time, $4::time, $5)
+	`, serviceID, date.Format("2006-01-02"), start, end, capacity)
+	require.NoError(t, err)
+}
+
+func TestCreateBooking_ConcurrentRespectsCapacity(t *testing.T) {
+	cleanBookingsTables(t)
+	_, err := db.Exec("DELETE FROM service_available_slots")
+	require.NoError(t, err)
+
+	const (
+		serviceID = 50
+		capacity  = 3
+		attempts  = 12
+	)
+	targetDate := time.Now().AddDate(0, 0, 5).Truncate(24 * time.Hour)
+	seedSlot(t, serviceID, targetDate, "10:00", "11:00", capacity)
+
+	for i := 0; i < attempts; i++ {
+		seedUser(t, int64(5000+i), fmt.Sprintf("race_%d", i))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		created  int
+		occupied int
+		start    = make(chan struct{})
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_, err := repo.CreateBooking(ctx, &models.Booking{
+				UserID:      int64(5000 + i),
+				ServiceID:   serviceID,
+				BookingDate: targetDate,
+				BookingTime: mustParseTime("15:04", "10:00"),
+				GuestName:   "Racer",
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, models.ErrSlotOccupied):
+				occupied++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, capacity, created)
+	assert.Equal(t, attempts-capacity, occupied)
+
+	var count int
+	err = db.Get(&count, `SELECT COUNT(*) FROM bookings WHERE service_id = $1 AND booking_date = $2`, serviceID, targetDate)
+	require.NoError(t, err)
+	assert.Equal(t, capacity, count)
+}
+
+func TestCreateBooking_CancelledBookingFreesSlot(t *testing.T) {
+	cleanBookingsTables(t)
+	_, err := db.Exec("DELETE FROM service_available_slots")
+	require.NoError(t, err)
+
+	const serviceID = 50
+	targetDate := time.Now().AddDate(0, 0, 6).Truncate(24 * time.Hour)
+	seedSlot(t, serviceID, targetDate, "12:00", "13:00", 1)
+	seedUser(t, 6001, "first")
+	seedUser(t, 6002, "second")
+
+	ctx := context.Background()
+	newBooking := func(userID int64) *models.Booking {
+		return &models.Booking{
+			UserID:      userID,
+			ServiceID:   serviceID,
+			BookingDate: targetDate,
+			BookingTime: mustParseTime("15:04", "12:00"),
+			GuestName:   "Guest",
+		}
+	}
+
+	firstID, err := repo.CreateBooking(ctx, newBooking(6001))
+	require.NoError(t, err)
+
+	_, err = repo.CreateBooking(ctx, newBooking(6002))
+	assert.ErrorIs(t, err, models.ErrSlotOccupied)
+
+	require.NoError(t, repo.UpdateBookingStatus(ctx, firstID, models.BookingStatusCancelled))
+
+	_, err = repo.CreateBooking(ctx, newBooking(6002))
+	assert.NoError(t, err)
+
+	slots, err := boxRepo.GetAvailableSlotsByServiceID(ctx, serviceID)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, 1, slots[0].Booked)
+	assert.True(t, slots[0].IsFull())
+}
+
+func TestReserveBookingSlot_CancelledBookingComesBackOnlyToFreeSlot(t *testing.T) {
+	cleanBookingsTables(t)
+	_, err := db.Exec("DELETE FROM service_available_slots")
+	require.NoError(t, err)
+
+	const serviceID = 50
+	targetDate := time.Now().AddDate(0, 0, 8).Truncate(24 * time.Hour)
+	seedSlot(t, serviceID, targetDate, "12:00", "13:00", 1)
+	seedUser(t, 8001, "cancelled")
+	seedUser(t, 8002, "taker")
+
+	ctx := context.Background()
+	newBooking := func(userID int64) *models.Booking {
+		return &models.Booking{
+			UserID:      userID,
+			ServiceID:   serviceID,
+			BookingDate: targetDate,
+			BookingTime: mustParseTime("15:04", "12:00"),
+			GuestName:   "Guest",
+		}
+	}
+
+	cancelledID, err := repo.CreateBooking(ctx, newBooking(8001))
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateBookingStatus(ctx, cancelledID, models.BookingStatusCancelled))
+
+	assert.NoError(t, repo.ReserveBookingSlot(ctx, cancelledID), "the slot is free again")
+
+	_, err = repo.CreateBooking(ctx, newBooking(8002))
+	require.NoError(t, err)
+
+	err = repo.ReserveBookingSlot(ctx, cancelledID)
+	assert.ErrorIs(t, err, models.ErrSlotOccupied)
+
+	err = repo.ReserveBookingSlot(ctx, 999999)
+	assert.ErrorIs(t, err, models.ErrBookingNotFound)
+}
+
+func TestRescheduleBooking_MovesBookingAndFreesOldSlot(t *testing.T) {
+	cleanBookingsTables(t)
+	_, err := db.Exec("DELETE FROM service_available_slots")
+	require.NoError(t, err)
+
+	const serviceID = 50
+	targetDate := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	seedSlot(t, serviceID, targetDate, "10:00", "11:00", 1)
+	seedSlot(t, serviceID, targetDate, "12:00", "13:00", 1)
+	seedUser(t, 7001, "mover")
+	seedUser(t, 7002, "other")
+
+	ctx := context.Background()
+	bookingID, err := repo.CreateBooking(ctx, &models.Booking{
+		UserID:            7001,
+		ServiceID:         serviceID,
+		BookingDate:       targetDate,
+		BookingTime:       mustParseTime("15:04", "10:00"),
+		GuestName:         "Guest",
+		GuestOrganization: "Org",
+		GuestPosition:     "Position",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateBookingStatus(ctx, bookingID, models.BookingStatusConfirmed))
+
+	err = repo.RescheduleBooking(ctx, bookingID, targetDate, mustParseTime("15:04", "12:00"))
+	require.NoError(t, err)
+
+	booking, err := repo.GetBookingById(ctx, bookingID)
+	require.NoError(t, err)
+	assert.Equal(t, "12:00", booking.BookingTime)
+	assert.Equal(t, models.BookingStatusPending, booking.Status)
+	assert.Equal(t, "Org", booking.GuestOrganization)
+
+	_, err = repo.CreateBooking(ctx, &models.Booking{
+		UserID:      7002,
+		ServiceID:   serviceID,
+		BookingDate: targetDate,
+		BookingTime: mustParseTime("15:04", "10:00"),
+		GuestName:   "Other",
+	})
+	assert.NoError(t, err, "old slot must be released")
+
+	err = repo.RescheduleBooking(ctx, bookingID, targetDate, mustParseTime("15:04", "10:00"))
+	assert.ErrorIs(t, err, models.ErrSlotOccupied)
+
+	require.NoError(t, repo.UpdateBookingStatus(ctx, bookingID, models.BookingStatusCancelled))
+	err = repo.RescheduleBooking(ctx, bookingID, targetDate, mustParseTime("15:04", "12:00"))
+	assert.ErrorIs(t, err, models.ErrBookingNotFound)
+}
+
+func TestCreateBooking_TakesUserSource(t *testing.T) {
+	cleanBookingsTables(t)
+	seedUser(t, 7001, "from_campaign")
+	seedUser(t, 7002, "direct")
+
+	ctx := context.Background()
+	require.NoError(t, repoUser.SetSource(ctx, 7001, "vk"))
+	assert.ErrorIs(t, repoUser.SetSource(ctx, 7999, "vk"), models.ErrUserNotFound)
+
+	sourceOf := func(userID int64) *string {
+		id, err := repo.CreateBooking(ctx, &models.Booking{
+			UserID:      userID,
+			ServiceID:   1,
+			BookingDate: time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour),
+			BookingTime: mustParseTime("15:04", "10:00"),
+			GuestName:   "Guest",
+		})
+		require.NoError(t, err)
+
+		var source *string
+		require.NoError(t, db.Get(&source, "SELECT source FROM bookings WHERE id = $1", id))
+		return source
+	}
+
+	source := sourceOf(7001)
+	require.NotNil(t, source)
+	assert.Equal(t, "vk", *source)
+	assert.Nil(t, sourceOf(7002))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/ctxutil"
@@ -39,12 +40,18 @@ const getServiceByIDQuery = `
 	SELECT
 		s.id, s.name, s.slug, s.description, s.rules, s.location, s.price, s.image,
 		s.status, s.organizer, s.created_at, s.updated_at,
-		a.slot_date, a.start_time, a.end_time
+		a.slot_date, a.start_time, a.end_time, a.capacity
 	FROM services s
 	LEFT JOIN service_available_slots a ON s.id = a.service_id
 	WHERE s.id = $1 AND s.deleted_at IS NULL
 	ORDER BY a.slot_date, a.start_time`
 
+const getActiveServiceIDBySlugQuery = `
+	SELECT id FROM services
+	WHERE slug = $1 AND status = 'active' AND deleted_at IS NULL
+	ORDER BY id
+	LIMIT 1`
+
 const updateServiceByIDQuery = `
 WITH 
 old_image AS (
@@ -96,8 +103,8 @@ RETURNING id, created_at, updated_at`
 
 const createAvailableSlotQuery = `
 	INSERT INTO service_available_slots (
-		service_id, slot_date, start_time, end_time
-	) VALUES ($1, $2, $3::time, $4::time)`
+		service_id, slot_date, start_time, end_time, capacity
+	) VALUES ($1, $2, $3::time, $4::time, $5)`
 
 const deleteSlotsQuery = `
 	DELETE FROM service_available_slots
@@ -105,9 +112,20 @@ const deleteSlotsQuery = `
 
 const createSlotsQuery = `
 	INSERT INTO service_available_slots
-		(service_id, slot_date, start_time, end_time)
-		SELECT $1, unnest($2::date[]), unnest($3::time[]),
-		unnest($4::time[])`
+		(service_id, slot_date, start_time, end_time, capacity)
+		SELECT $1, d, st, et, COALESCE(NULLIF(c, 0), 1)
+		FROM unnest($2::date[], $3::time[], $4::time[], $5::int[]) AS t(d, st, et, c)`
+
+// slotBookedCountSQL counts active bookings that occupy the slot aliased as a.
+const slotBookedCountSQL = `(
+		SELECT COUNT(*)
+		FROM bookings b
+		WHERE b.service_id = a.service_id
+			AND b.booking_date = a.slot_date
+			AND b.booking_time IS NOT DISTINCT FROM a.start_time
+			AND b.status != 'cancelled'
+			AND b.deleted_at IS NULL
+	)`
 
 const updateStatusQuery = `
 	UPDATE services 
@@ -246,7 +264,7 @@ func (r *BoxSolutionRepo) List(ctx context.Context, query models.BoxList) (*mode
 	}
 
 	slotQuery := `
-		SELECT service_id, slot_date, start_time, end_time
+		SELECT service_id, slot_date, start_time, end_time, capacity
 		FROM service_available_slots
 		WHERE service_id = ANY($1)
 		ORDER BY service_id, slot_date, start_time
@@ -257,6 +275,7 @@ func (r *BoxSolutionRepo) List(ctx context.Context, query models.BoxList) (*mode
 		SlotDate  sql.NullTime `db:"slot_date"`
 		StartTime sql.NullTime `db:"start_time"`
 		EndTime   sql.NullTime `db:"end_time"`
+		Capacity  int          `db:"capacity"`
 	}
 
 	err = r.db.SelectContext(ctx, &slots, slotQuery, serviceIDs)
@@ -271,6 +290,7 @@ func (r *BoxSolutionRepo) List(ctx context.Context, query models.BoxList) (*mode
 				Date:      slot.SlotDate.Time.Format("2006-01-02"),
 				StartTime: slot.StartTime.Time.Format("15:04"),
 				EndTime:   slot.EndTime.Time.Format("15:04"),
+				Capacity:  slot.Capacity,
 			})
 		}
 	}
@@ -428,6 +448,7 @@ func (r *BoxSolutionRepo) GetServiceByID(ctx context.Context, serviceID int64) (
 				Date:      row.SlotDate.Time.Format("2006-01-02"),
 				StartTime: row.StartTime.Time.Format("15:04"),
 				EndTime:   row.EndTime.Time.Format("15:04"),
+				Capacity:  int(row.Capacity.Int64),
 			})
 		}
 	}
@@ -435,6 +456,18 @@ func (r *BoxSolutionRepo) GetServiceByID(ctx context.Context, serviceID int64) (
 	return svc, nil
 }
 
+// GetActiveServiceIDBySlug finds an active service by its slug
+func (r *BoxSolutionRepo) GetActiveServiceIDBySlug(ctx context.Context, slug string) (int64, error) {
+	var id int64
+	if err := sqlx.GetContext(ctx, r.getDB(ctx), &id, getActiveServiceIDBySlugQuery, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrBoxSolutionNotFound
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
 // GetAvailableSlotsByServiceID gets all available slots for the service
 func (r *BoxSolutionRepo) GetAvailableSlotsByServiceID(ctx context.Context, serviceID int64) ([]models.BoxAvailableSlot, error) {
 	if serviceID <= 0 {
@@ -445,13 +478,16 @@ func (r *BoxSolutionRepo) GetAvailableSlotsByServiceID(ctx context.Context, serv
 		SlotDate  sql.NullTime `db:"slot_date"`
 		StartTime sql.NullTime `db:"start_time"`
 		EndTime   sql.NullTime `db:"end_time"`
+		Capacity  int          `db:"capacity"`
+		Booked    int          `db:"booked"`
 	}
 
 	query := `
-		SELECT slot_date, start_time, end_time
-		FROM service_available_slots
-		WHERE service_id = $1
-		ORDER BY slot_date, start_time
+		SELECT a.slot_date, a.start_time, a.end_time, a.capacity,
+			` + slotBookedCountSQL + ` AS booked
+		FROM service_available_slots a
+		WHERE a.service_id = $1
+		ORDER BY a.slot_date, a.start_time
 	`
 
 	var dbSlots []dbSlot
@@ -467,7 +503,9 @@ func (r *BoxSolutionRepo) GetAvailableSlotsByServiceID(ctx context.Context, serv
 	for _, slot := range dbSlots {
 		if slot.SlotDate.Valid {
 			availableSlot := models.BoxAvailableSlot{
-				Date: slot.SlotDate.Time.UTC().Format("2006-01-02"),
+				Date:     slot.SlotDate.Time.UTC().Format("2006-01-02"),
+				Capacity: slot.Capacity,
+				Booked:   slot.Booked,
 			}
 			if slot.StartTime.Valid {
 				availableSlot.StartTime = slot.StartTime.Time.UTC().Format("15:04")
@@ -482,7 +520,7 @@ func (r *BoxSolutionRepo) GetAvailableSlotsByServiceID(ctx context.Context, serv
 	return availableSlots, nil
 }
 
-// CheckSlotAvailability checks the availability of a specific slot
+// CheckSlotAvailability checks that the slot exists and still has free places
 func (r *BoxSolutionRepo) CheckSlotAvailability(ctx context.Context, serviceID int64, slot models.BoxAvailableSlot) (bool, error) {
 	if serviceID <= 0 {
 		return false, errors.New("invalid service ID")
@@ -500,12 +538,13 @@ func (r *BoxSolutionRepo) CheckSlotAvailability(ctx context.Context, serviceID i
 	var exists bool
 	query := `
 		SELECT EXISTS(
-			SELECT 1 
-			FROM service_available_slots 
-			WHERE service_id = $1 
-				AND slot_date = $2 
-				AND start_time = $3::time
-				AND end_time = $4::time
+			SELECT 1
+			FROM service_available_slots a
+			WHERE a.service_id = $1
+				AND a.slot_date = $2
+				AND a.start_time = $3::time
+				AND a.end_time = $4::time
+				AND a.capacity > ` + slotBookedCountSQL + `
 		)`
 
 	err := r.db.QueryRowContext(ctx, query, serviceID, slot.Date, slot.StartTime, slot.EndTime).Scan(&exists)
@@ -629,11 +668,17 @@ func (r *BoxSolutionRepo) CreateBox(ctx context.Context, box *models.BoxCreate)
 				endTime = slot.EndTime
 			}
 
+			capacity := slot.Capacity
+			if capacity <= 0 {
+				capacity = 1
+			}
+
 			_, err = tx.ExecContext(ctx, createAvailableSlotQuery,
 				service.ID,
 				parsedDate,
 				startTime,
 				endTime,
+				capacity,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create available slot: %w", err)
@@ -748,7 +793,7 @@ func (r *BoxSolutionRepo) DeleteServiceSlots(ctx context.Context, id int64) erro
 }
 
 func (r *BoxSolutionRepo) UpdateServiceSlots(ctx context.Context, id int64, slots *models.BoxNewSlots) error {
-	_, err := r.getDB(ctx).ExecContext(ctx, createSlotsQuery, id, slots.Date, slots.StartTime, slots.EndTime)
+	_, err := r.getDB(ctx).ExecContext(ctx, createSlotsQuery, id, slots.Date, slots.StartTime, slots.EndTime, pq.Array(slots.Capacity))
 	if err != nil {
 		return err
 	}
@@ -778,7 +823,7 @@ func (r *BoxSolutionRepo) SoftDeleteService(ctx context.Context, serviceID int64
 
 func (r *BoxSolutionRepo) UpdateServiceStatus(ctx context.Context, serviceID int64, status models.ServiceStatus) (*models.BoxUpdateStatusResult, error) {
 	var result dto.BoxUpdateStatusResult
-	err := r.db.GetContext(ctx, &result, updateStatusQuery, serviceID, status)
+	err := sqlx.GetContext(ctx, r.getDB(ctx), &result, updateStatusQuery, serviceID, status)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrBoxSolutionNotFound // изменил 30,03,2026
 	}
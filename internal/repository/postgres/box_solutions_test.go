@@ -1159,6 +1159,15 @@ func TestGetServiceByID(t *testing.T) {
 		assert.ErrorIs(t, err, models.ErrBoxSolutionNotFound)
 	})
 
+	t.Run("by slug", func(t *testing.T) {
+		id, err := boxRepo.GetActiveServiceIDBySlug(context.Background(), "test-box")
+		require.NoError(t, err)
+		assert.Equal(t, serviceID, id)
+
+		_, err = boxRepo.GetActiveServiceIDBySlug(context.Background(), "no-such-box")
+		assert.ErrorIs(t, err, models.ErrBoxSolutionNotFound)
+	})
+
 	t.Run("service without slots", func(t *testing.T) {
 		// Вставляем сервис без слотов
 		var noSlotServiceID int64
@@ -0,0 +1,519 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	campaignColumns = `c.id, c.title, c.text, c.image_url, c.buttons, c.segment, c.status::text AS status,
+		c.created_by, c.started_at, c.finished_at, c.created_at, c.updated_at`
+
+	// A draft has no recipients yet, so the queries changing it return zero progress
+	campaignReturning = `
+		RETURNING ` + campaignColumns + `,
+		          0 AS total, 0 AS pending, 0 AS sent, 0 AS blocked, 0 AS failed`
+
+	campaignSelect = `
+		SELECT ` + campaignColumns + `,
+		       COUNT(r.id) AS total,
+		       COUNT(r.id) FILTER (WHERE r.status = 'pending') AS pending,
+		       COUNT(r.id) FILTER (WHERE r.status = 'sent') AS sent,
+		       COUNT(r.id) FILTER (WHERE r.status = 'blocked') AS blocked,
+		       COUNT(r.id) FILTER (WHERE r.status = 'failed') AS failed
+		FROM campaigns c
+		LEFT JOIN campaign_recipients r ON r.campaign_id = c.id`
+
+	createCampaignQuery = `
+		INSERT INTO campaigns AS c (title, text, image_url, buttons, segment, created_by)
+		VALUES ($1, $2, $3, $4::jsonb, $5::jsonb, $6)` + campaignReturning
+
+	getCampaignQuery = campaignSelect + `
+		WHERE c.id = $1
+		GROUP BY c.id`
+
+	listCampaignsQuery = campaignSelect + `
+		GROUP BY c.id
+		ORDER BY c.id DESC`
+
+	updateCampaignQuery = `
+		UPDATE campaigns AS c
+		SET title = $2, text = $3, image_url = $4, buttons = $5::jsonb, segment = $6::jsonb, updated_at = NOW()
+		WHERE c.id = $1 AND c.status = 'draft'` + campaignReturning
+
+	deleteCampaignQuery = `DELETE FROM campaigns WHERE id = $1 AND status <> 'running'`
+
+	// campaignAudienceCondition selects the users of the segment: $1 is the
+	// segment type, $2 the grades and $3 the services. Past bookings are the
+	// visits before today that were not cancelled.
+	campaignAudienceCondition = `
+		($1::text = 'all'
+		 OR ($1::text = 'grade' AND u.grade = ANY($2::int[]))
+		 OR ($1::text = 'service' AND EXISTS (
+		     SELECT 1 FROM bookings b
+		     WHERE b.user_id = u.telegram_id
+		       AND b.service_id = ANY($3::bigint[])
+		       AND b.status <> 'cancelled'
+		       AND b.deleted_at IS NULL
+		       AND b.booking_date < CURRENT_DATE)))`
+
+	countCampaignAudienceQuery = `
+		SELECT COUNT(*) FROM users u
+		WHERE ` + campaignAudienceCondition
+
+	startCampaignQuery = `
+		UPDATE campaigns
+		SET status = 'running', started_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'draft'
+		RETURNING segment`
+
+	insertCampaignRecipientsQuery = `
+		INSERT INTO campaign_recipients (campaign_id, telegram_id)
+		SELECT $4::bigint, u.telegram_id FROM users u
+		WHERE ` + campaignAudienceCondition + `
+		ON CONFLICT (campaign_id, telegram_id) DO NOTHING`
+
+	cancelCampaignQuery = `
+		UPDATE campaigns
+		SET status = 'cancelled', finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status IN ('draft', 'running')`
+
+	listCampaignRecipientsQuery = `
+		SELECT id, campaign_id, telegram_id, status::text AS status, attempts,
+		       last_error, sent_at, updated_at,
+		       COUNT(*) OVER() AS total
+		FROM campaign_recipients
+		WHERE campaign_id = $1 AND ($2 = '' OR status::text = $2)
+		ORDER BY id
+		LIMIT $3 OFFSET $4`
+
+	claimCampaignDeliveriesQuery = `
+		WITH claimed AS (
+		    UPDATE campaign_recipients
+		    SET attempts = attempts + 1,
+		        next_attempt_at = NOW() + make_interval(secs => $2),
+		        updated_at = NOW()
+		    WHERE id IN (
+		        SELECT r.id FROM campaign_recipients r
+		        JOIN campaigns c ON c.id = r.campaign_id
+		        WHERE r.status = 'pending' AND r.next_attempt_at <= NOW() AND c.status = 'running'
+		        ORDER BY r.id
+		        LIMIT $1
+		        FOR UPDATE OF r SKIP LOCKED
+		    )
+		    RETURNING id, campaign_id, telegram_id, attempts
+		)
+		SELECT cl.id, cl.campaign_id, cl.telegram_id, cl.attempts, c.text, c.image_url, c.buttons
+		FROM claimed cl
+		JOIN campaigns c ON c.id = cl.campaign_id
+		ORDER BY cl.id`
+
+	markCampaignSentQuery = `
+		UPDATE campaign_recipients
+		SET status = 'sent', last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+
+	markCampaignUndeliveredQuery = `
+		UPDATE campaign_recipients
+		SET status = $2::campaign_recipient_status, last_error = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	markCampaignRetryQuery = `
+		UPDATE campaign_recipients
+		SET last_error = $2, next_attempt_at = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	completeCampaignsQuery = `
+		UPDATE campaigns c
+		SET status = 'done', finished_at = NOW(), updated_at = NOW()
+		WHERE c.status = 'running'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM campaign_recipients r
+		      WHERE r.campaign_id = c.id AND r.status = 'pending'
+		  )`
+)
+
+type campaignRow struct {
+	ID         int64      `db:"id"`
+	Title      string     `db:"title"`
+	Text       string     `db:"text"`
+	ImageURL   string     `db:"image_url"`
+	Buttons    []byte     `db:"buttons"`
+	Segment    []byte     `db:"segment"`
+	Status     string     `db:"status"`
+	CreatedBy  *int64     `db:"created_by"`
+	StartedAt  *time.Time `db:"started_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	models.CampaignProgress
+}
+
+func (r campaignRow) toModel() (*models.Campaign, error) {
+	campaign := &models.Campaign{
+		ID:         r.ID,
+		Title:      r.Title,
+		Text:       r.Text,
+		ImageURL:   r.ImageURL,
+		Status:     r.Status,
+		CreatedBy:  r.CreatedBy,
+		Progress:   r.CampaignProgress,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+	if err := json.Unmarshal(r.Buttons, &campaign.Buttons); err != nil {
+		return nil, fmt.Errorf("unmarshal campaign buttons: %w", err)
+	}
+	if err := json.Unmarshal(r.Segment, &campaign.Segment); err != nil {
+		return nil, fmt.Errorf("unmarshal campaign segment: %w", err)
+	}
+	return campaign, nil
+}
+
+type campaignDeliveryRow struct {
+	ID         int64  `db:"id"`
+	CampaignID int64  `db:"campaign_id"`
+	TelegramID int64  `db:"telegram_id"`
+	Attempts   int    `db:"attempts"`
+	Text       string `db:"text"`
+	ImageURL   string `db:"image_url"`
+	Buttons    []byte `db:"buttons"`
+}
+
+type campaignRecipientRow struct {
+	models.CampaignRecipient
+	Total int `db:"total"`
+}
+
+// CampaignRepo stores the campaigns and the queue of their messages.
+type CampaignRepo struct {
+	db *sqlx.DB
+}
+
+// NewCampaignRepo creates a new CampaignRepo.
+func NewCampaignRepo(db *sqlx.DB) *CampaignRepo {
+	return &CampaignRepo{db: db}
+}
+
+func (r *CampaignRepo) Create(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	const operation = "create_campaign"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Campaign, error) {
+		buttons, segment, err := marshalCampaign(campaign)
+		if err != nil {
+			return nil, err
+		}
+
+		var row campaignRow
+		err = r.db.GetContext(ctx, &row, createCampaignQuery,
+			campaign.Title, campaign.Text, campaign.ImageURL, buttons, segment, campaign.CreatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("create campaign: %w", err)
+		}
+		return row.toModel()
+	})
+}
+
+// Get returns the campaign with its delivery progress
+func (r *CampaignRepo) Get(ctx context.Context, id int64) (*models.Campaign, error) {
+	const operation = "get_campaign"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Campaign, error) {
+		var row campaignRow
+		if err := r.db.GetContext(ctx, &row, getCampaignQuery, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrCampaignNotFound
+			}
+			return nil, fmt.Errorf("get campaign: %w", err)
+		}
+		return row.toModel()
+	})
+}
+
+// List returns the campaigns with their delivery progress, newest first
+func (r *CampaignRepo) List(ctx context.Context) ([]models.Campaign, error) {
+	const operation = "list_campaigns"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.Campaign, error) {
+		var rows []campaignRow
+		if err := r.db.SelectContext(ctx, &rows, listCampaignsQuery); err != nil {
+			return nil, fmt.Errorf("list campaigns: %w", err)
+		}
+
+		campaigns := make([]models.Campaign, 0, len(rows))
+		for _, row := range rows {
+			campaign, err := row.toModel()
+			if err != nil {
+				return nil, err
+			}
+			campaigns = append(campaigns, *campaign)
+		}
+		return campaigns, nil
+	})
+}
+
+// Update replaces the content and the segment of a draft. It returns
+// ErrCampaignStatus when the campaign is not a draft anymore.
+func (r *CampaignRepo) Update(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	const operation = "update_campaign"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Campaign, error) {
+		buttons, segment, err := marshalCampaign(campaign)
+		if err != nil {
+			return nil, err
+		}
+
+		var row campaignRow
+		err = r.db.GetContext(ctx, &row, updateCampaignQuery,
+			campaign.ID, campaign.Title, campaign.Text, campaign.ImageURL, buttons, segment)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrCampaignStatus
+			}
+			return nil, fmt.Errorf("update campaign: %w", err)
+		}
+		return row.toModel()
+	})
+}
+
+// Delete deletes the campaign together with its recipients. A running
+// campaign must be cancelled first.
+func (r *CampaignRepo) Delete(ctx context.Context, id int64) error {
+	const operation = "delete_campaign"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, deleteCampaignQuery, id)
+		if err != nil {
+			return fmt.Errorf("delete campaign: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete campaign: %w", err)
+		}
+		if n == 0 {
+			return models.ErrCampaignStatus
+		}
+		return nil
+	})
+}
+
+// CountAudience returns the number of bot users in the segment
+func (r *CampaignRepo) CountAudience(ctx context.Context, segment models.CampaignSegment) (int, error) {
+	const operation = "count_campaign_audience"
+
+	return repository.WithDBMetricsValue(operation, func() (int, error) {
+		var count int
+		err := r.db.GetContext(ctx, &count, countCampaignAudienceQuery, audienceArgs(segment)...)
+		if err != nil {
+			return 0, fmt.Errorf("count campaign audience: %w", err)
+		}
+		return count, nil
+	})
+}
+
+// Start runs the draft: the users of its segment become the recipients the
+// delivery worker sends the campaign to. It returns the number of recipients.
+func (r *CampaignRepo) Start(ctx context.Context, id int64) (int, error) {
+	const operation = "start_campaign"
+
+	return repository.WithDBMetricsValue(operation, func() (int, error) {
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("start campaign: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		var rawSegment []byte
+		if err := tx.GetContext(ctx, &rawSegment, startCampaignQuery, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, models.ErrCampaignStatus
+			}
+			return 0, fmt.Errorf("start campaign: %w", err)
+		}
+
+		var segment models.CampaignSegment
+		if err := json.Unmarshal(rawSegment, &segment); err != nil {
+			return 0, fmt.Errorf("unmarshal campaign segment: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, insertCampaignRecipientsQuery, append(audienceArgs(segment), id)...)
+		if err != nil {
+			return 0, fmt.Errorf("insert campaign recipients: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("insert campaign recipients: %w", err)
+		}
+		if n == 0 {
+			return 0, models.ErrCampaignNoRecipients
+		}
+
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("start campaign: %w", err)
+		}
+		return int(n), nil
+	})
+}
+
+// Cancel stops a draft or running campaign; the recipients not reached yet
+// stay pending.
+func (r *CampaignRepo) Cancel(ctx context.Context, id int64) error {
+	const operation = "cancel_campaign"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, cancelCampaignQuery, id)
+		if err != nil {
+			return fmt.Errorf("cancel campaign: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("cancel campaign: %w", err)
+		}
+		if n == 0 {
+			return models.ErrCampaignStatus
+		}
+		return nil
+	})
+}
+
+// ListRecipients returns the recipients of a campaign with their delivery status.
+func (r *CampaignRepo) ListRecipients(ctx context.Context, filter models.CampaignRecipientFilter) (*models.CampaignRecipientList, error) {
+	const operation = "list_campaign_recipients"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.CampaignRecipientList, error) {
+		var rows []campaignRecipientRow
+		err := r.db.SelectContext(ctx, &rows, listCampaignRecipientsQuery,
+			filter.CampaignID, filter.Status, filter.Limit, filter.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("list campaign recipients: %w", err)
+		}
+
+		list := &models.CampaignRecipientList{
+			Items:  make([]models.CampaignRecipient, 0, len(rows)),
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		}
+		for _, row := range rows {
+			list.Items = append(list.Items, row.CampaignRecipient)
+			list.Total = row.Total
+		}
+		return list, nil
+	})
+}
+
+// ClaimDeliveries locks up to limit due recipients of running campaigns for
+// lease so that concurrent workers do not pick them up.
+func (r *CampaignRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.CampaignDelivery, error) {
+	const operation = "claim_campaign_deliveries"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.CampaignDelivery, error) {
+		var rows []campaignDeliveryRow
+		if err := r.db.SelectContext(ctx, &rows, claimCampaignDeliveriesQuery, limit, lease.Seconds()); err != nil {
+			return nil, fmt.Errorf("claim campaign deliveries: %w", err)
+		}
+
+		deliveries := make([]models.CampaignDelivery, 0, len(rows))
+		for _, row := range rows {
+			delivery := models.CampaignDelivery{
+				ID:         row.ID,
+				CampaignID: row.CampaignID,
+				TelegramID: row.TelegramID,
+				Attempts:   row.Attempts,
+				Text:       row.Text,
+				ImageURL:   row.ImageURL,
+			}
+			if err := json.Unmarshal(row.Buttons, &delivery.Buttons); err != nil {
+				return nil, fmt.Errorf("unmarshal campaign buttons: %w", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return deliveries, nil
+	})
+}
+
+// MarkDelivered records the final status of the recipient: sent, blocked or failed
+func (r *CampaignRepo) MarkDelivered(ctx context.Context, id int64, status, lastErr string) error {
+	const operation = "mark_campaign_delivered"
+
+	return repository.WithDBMetrics(operation, func() error {
+		var err error
+		if status == models.CampaignRecipientSent {
+			_, err = r.db.ExecContext(ctx, markCampaignSentQuery, id)
+		} else {
+			_, err = r.db.ExecContext(ctx, markCampaignUndeliveredQuery, id, status, lastErr)
+		}
+		if err != nil {
+			return fmt.Errorf("mark campaign delivered: %w", err)
+		}
+		return nil
+	})
+}
+
+// MarkRetry records a transient error; the recipient is retried at retryAt
+func (r *CampaignRepo) MarkRetry(ctx context.Context, id int64, lastErr string, retryAt time.Time) error {
+	const operation = "mark_campaign_retry"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.db.ExecContext(ctx, markCampaignRetryQuery, id, lastErr, retryAt); err != nil {
+			return fmt.Errorf("mark campaign retry: %w", err)
+		}
+		return nil
+	})
+}
+
+// CompleteFinished marks the running campaigns without pending recipients as
+// done and returns their number.
+func (r *CampaignRepo) CompleteFinished(ctx context.Context) (int, error) {
+	const operation = "complete_campaigns"
+
+	return repository.WithDBMetricsValue(operation, func() (int, error) {
+		res, err := r.db.ExecContext(ctx, completeCampaignsQuery)
+		if err != nil {
+			return 0, fmt.Errorf("complete campaigns: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("complete campaigns: %w", err)
+		}
+		return int(n), nil
+	})
+}
+
+// marshalCampaign encodes the JSONB columns; they are passed as strings,
+// since lib/pq sends []byte as bytea
+func marshalCampaign(campaign *models.Campaign) (buttons, segment string, err error) {
+	list := campaign.Buttons
+	if list == nil {
+		list = []models.CampaignButton{}
+	}
+	rawButtons, err := json.Marshal(list)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal campaign buttons: %w", err)
+	}
+	rawSegment, err := json.Marshal(campaign.Segment)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal campaign segment: %w", err)
+	}
+	return string(rawButtons), string(rawSegment), nil
+}
+
+// audienceArgs are the parameters of campaignAudienceCondition
+func audienceArgs(segment models.CampaignSegment) []any {
+	grades := make([]int64, len(segment.Grades))
+	for i, grade := range segment.Grades {
+		grades[i] = int64(grade)
+	}
+	return []any{segment.Type, pq.Array(grades), pq.Array(segment.ServiceIDs)}
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/yandex-development-1-team/go/internal/ctxutil"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	getStaffMFAQuery = `
+		SELECT s.id, s.email, s.mfa_secret, s.mfa_enabled_at, s.mfa_last_step,
+		       COALESCE(r.mfa_required, FALSE) AS mfa_required,
+		       (SELECT COUNT(*) FROM staff_recovery_codes c
+		        WHERE c.staff_id = s.id AND c.used_at IS NULL) AS recovery_codes_left
+		FROM staff s
+		LEFT JOIN roles r ON r.name = s.role
+		WHERE s.id = $1`
+
+	// A new secret replaces an unconfirmed one; an enabled secret is kept
+	setStaffMFASecretQuery = `
+		UPDATE staff
+		SET mfa_secret = $2, mfa_last_step = NULL, updated_at = NOW()
+		WHERE id = $1 AND mfa_enabled_at IS NULL`
+
+	enableStaffMFAQuery = `
+		UPDATE staff
+		SET mfa_enabled_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND mfa_secret IS NOT NULL AND mfa_enabled_at IS NULL`
+
+	disableStaffMFAQuery = `
+		UPDATE staff
+		SET mfa_secret = NULL, mfa_enabled_at = NULL, mfa_last_step = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	// The step only moves forward, so an accepted code cannot be replayed
+	useStaffMFAStepQuery = `
+		UPDATE staff
+		SET mfa_last_step = $2
+		WHERE id = $1 AND (mfa_last_step IS NULL OR mfa_last_step < $2)`
+
+	deleteRecoveryCodesQuery = `DELETE FROM staff_recovery_codes WHERE staff_id = $1`
+
+	insertRecoveryCodesQuery = `
+		INSERT INTO staff_recovery_codes (staff_id, code_hash)
+		SELECT $1, UNNEST($2::text[])`
+
+	useRecoveryCodeQuery = `
+		UPDATE staff_recovery_codes
+		SET used_at = NOW()
+		WHERE staff_id = $1 AND code_hash = $2 AND used_at IS NULL`
+)
+
+// MFARepo stores the second factor of staff: the encrypted TOTP secret in
+// staff and the hashes of recovery codes in staff_recovery_codes.
+type MFARepo struct {
+	db *sqlx.DB
+}
+
+// NewMFARepo creates a new MFARepo.
+func NewMFARepo(db *sqlx.DB) *MFARepo {
+	return &MFARepo{db: db}
+}
+
+func (r *MFARepo) Get(ctx context.Context, staffID int64) (*models.StaffMFA, error) {
+	const operation = "get_staff_mfa"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.StaffMFA, error) {
+		var mfa models.StaffMFA
+		if err := sqlx.GetContext(ctx, r.getDB(ctx), &mfa, getStaffMFAQuery, staffID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrUserNotFound
+			}
+			return nil, fmt.Errorf("get staff mfa: %w", err)
+		}
+		return &mfa, nil
+	})
+}
+
+// SetSecret stores a new secret awaiting confirmation
+func (r *MFARepo) SetSecret(ctx context.Context, staffID int64, secret string) error {
+	const operation = "set_staff_mfa_secret"
+
+	return repository.WithDBMetrics(operation, func() error {
+		return r.execOne(ctx, setStaffMFASecretQuery, models.ErrMFAAlreadyEnabled, staffID, secret)
+	})
+}
+
+// Enable turns on the confirmed secret
+func (r *MFARepo) Enable(ctx context.Context, staffID int64) error {
+	const operation = "enable_staff_mfa"
+
+	return repository.WithDBMetrics(operation, func() error {
+		return r.execOne(ctx, enableStaffMFAQuery, models.ErrMFANotEnrolled, staffID)
+	})
+}
+
+// Disable removes the secret together with the recovery codes
+func (r *MFARepo) Disable(ctx context.Context, staffID int64) error {
+	const operation = "disable_staff_mfa"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.getDB(ctx).ExecContext(ctx, deleteRecoveryCodesQuery, staffID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+		return r.execOne(ctx, disableStaffMFAQuery, models.ErrUserNotFound, staffID)
+	})
+}
+
+// UseStep records the time step of an accepted TOTP code. A step not after
+// the last accepted one is rejected.
+func (r *MFARepo) UseStep(ctx context.Context, staffID, step int64) error {
+	const operation = "use_staff_mfa_step"
+
+	return repository.WithDBMetrics(operation, func() error {
+		return r.execOne(ctx, useStaffMFAStepQuery, models.ErrMFAInvalidCode, staffID, step)
+	})
+}
+
+// ReplaceRecoveryCodes drops the previous codes of the staff member. Call it
+// within a transaction.
+func (r *MFARepo) ReplaceRecoveryCodes(ctx context.Context, staffID int64, codeHashes []string) error {
+	const operation = "replace_recovery_codes"
+
+	return repository.WithDBMetrics(operation, func() error {
+		db := r.getDB(ctx)
+		if _, err := db.ExecContext(ctx, deleteRecoveryCodesQuery, staffID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, insertRecoveryCodesQuery, staffID, pq.Array(codeHashes)); err != nil {
+			return fmt.Errorf("insert recovery codes: %w", err)
+		}
+		return nil
+	})
+}
+
+// UseRecoveryCode spends an unused recovery code
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, staffID int64, codeHash string) error {
+	const operation = "use_recovery_code"
+
+	return repository.WithDBMetrics(operation, func() error {
+		return r.execOne(ctx, useRecoveryCodeQuery, models.ErrMFAInvalidCode, staffID, codeHash)
+	})
+}
+
+// execOne runs a statement that must change exactly one row, notFound otherwise
+func (r *MFARepo) execOne(ctx context.Context, query string, notFound error, args ...any) error {
+	res, err := r.getDB(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func (r *MFARepo) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctxutil.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/ctxutil"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	enqueueOutboxQuery = `
+		INSERT INTO notification_outbox (chat_id, text)
+		VALUES ($1, $2)`
+
+	claimOutboxQuery = `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2),
+		    updated_at = NOW()
+		WHERE id IN (
+		    SELECT id FROM notification_outbox
+		    WHERE status = 'pending' AND next_attempt_at <= NOW()
+		    ORDER BY id
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, chat_id, text, attempts`
+
+	markOutboxSentQuery = `
+		UPDATE notification_outbox
+		SET status = 'sent', sent_at = NOW(), last_error = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	markOutboxRetryQuery = `
+		UPDATE notification_outbox
+		SET last_error = $2, next_attempt_at = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	markOutboxFailedQuery = `
+		UPDATE notification_outbox
+		SET status = 'failed', last_error = $2, updated_at = NOW()
+		WHERE id = $1`
+)
+
+// NotificationOutboxRepo stores Telegram messages until the bot process delivers them.
+type NotificationOutboxRepo struct {
+	db *sqlx.DB
+}
+
+// NewNotificationOutboxRepo creates a new NotificationOutboxRepo.
+func NewNotificationOutboxRepo(db *sqlx.DB) *NotificationOutboxRepo {
+	return &NotificationOutboxRepo{db: db}
+}
+
+// Enqueue adds a message to the outbox. It joins the transaction stored in ctx, if any.
+func (r *NotificationOutboxRepo) Enqueue(ctx context.Context, chatID int64, text string) error {
+	const operation = "enqueue_outbox"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.getDB(ctx).ExecContext(ctx, enqueueOutboxQuery, chatID, text); err != nil {
+			return fmt.Errorf("enqueue outbox: %w", err)
+		}
+		return nil
+	})
+}
+
+// ClaimPending locks up to limit due messages for lease so that concurrent
+// workers do not pick them up.
+func (r *NotificationOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	const operation = "claim_outbox"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.OutboxMessage, error) {
+		var messages []models.OutboxMessage
+		if err := r.db.SelectContext(ctx, &messages, claimOutboxQuery, limit, lease.Seconds()); err != nil {
+			return nil, fmt.Errorf("claim outbox: %w", err)
+		}
+		return messages, nil
+	})
+}
+
+func (r *NotificationOutboxRepo) MarkSent(ctx context.Context, id int64) error {
+	const operation = "mark_outbox_sent"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.db.ExecContext(ctx, markOutboxSentQuery, id); err != nil {
+			return fmt.Errorf("mark outbox sent: %w", err)
+		}
+		return nil
+	})
+}
+
+// MarkFailed records a delivery error. With a nil retryAt the message is
+// given up on, otherwise it is retried at retryAt.
+func (r *NotificationOutboxRepo) MarkFailed(ctx context.Context, id int64, lastErr string, retryAt *time.Time) error {
+	const operation = "mark_outbox_failed"
+
+	return repository.WithDBMetrics(operation, func() error {
+		var err error
+		if retryAt == nil {
+			_, err = r.db.ExecContext(ctx, markOutboxFailedQuery, id, lastErr)
+		} else {
+			_, err = r.db.ExecContext(ctx, markOutboxRetryQuery, id, lastErr, *retryAt)
+		}
+		if err != nil {
+			return fmt.Errorf("mark outbox failed: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *NotificationOutboxRepo) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctxutil.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
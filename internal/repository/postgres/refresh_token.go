@@ -15,23 +15,59 @@ import (
 )
 
 const (
+	authSessionColumns = `id, user_id, device, ip, user_agent, expires_at, last_used_at, created_at`
+
+	// Expired sessions of the user are cleaned up on the next login
+	insertAuthSessionQuery = `
+		WITH expired AS (
+			DELETE FROM auth_sessions
+			WHERE user_id = $1 AND expires_at <= NOW()
+		)
+		INSERT INTO auth_sessions (user_id, device, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + authSessionColumns
+
+	touchAuthSessionQuery = `
+		UPDATE auth_sessions
+		SET ip = $2, user_agent = $3, expires_at = $4, last_used_at = NOW()
+		WHERE id = $1`
+
+	listAuthSessionsQuery = `
+		SELECT ` + authSessionColumns + `
+		FROM auth_sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY last_used_at DESC, id DESC`
+
+	deleteAuthSessionQuery = `
+	DELETE FROM auth_sessions
+	WHERE id = $1`
+
+	deleteUserAuthSessionQuery = `
+	DELETE FROM auth_sessions
+	WHERE id = $1 AND user_id = $2`
+
+	deleteAuthSessionByTokenQuery = `
+	DELETE FROM auth_sessions
+	WHERE id = (SELECT session_id FROM refresh_tokens WHERE token_hash = $1)`
+
 	insertRefreshTokenQuery = `
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)`
+		INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)`
 
 	getRefreshTokenForUpdateQuery = `
-	SELECT rt.id, rt.user_id, rt.expires_at, rt.created_at, s.role
+	SELECT rt.id, rt.user_id, rt.session_id, rt.token_hash, rt.expires_at, rt.rotated_at, rt.created_at, s.role
 	FROM refresh_tokens rt
 	JOIN staff s ON s.id = rt.user_id
-	WHERE rt.token = $1
+	WHERE rt.token_hash = $1
 	FOR UPDATE OF rt`
 
-	deleteRefreshTokenByToken = `
-	DELETE FROM refresh_tokens
-	WHERE token = $1`
+	markRefreshTokenRotatedQuery = `
+	UPDATE refresh_tokens
+	SET rotated_at = NOW()
+	WHERE id = $1`
 
-	deleteRefreshTokenByStaffID = `
-	DELETE FROM refresh_tokens
+	deleteAuthSessionsByStaffID = `
+	DELETE FROM auth_sessions
 	WHERE user_id = $1`
 )
 
@@ -44,6 +80,8 @@ var (
 	ErrRTDatabase           = errors.New("database error")
 )
 
+// RefreshTokenRepo stores login sessions and their refresh tokens.
+// Deleting a session deletes its tokens.
 type RefreshTokenRepo struct {
 	db *sqlx.DB
 }
@@ -52,27 +90,84 @@ func NewRefreshTokenRepo(db *sqlx.DB) *RefreshTokenRepo {
 	return &RefreshTokenRepo{db: db}
 }
 
-func (r *RefreshTokenRepo) Create(ctx context.Context, rt *models.RefreshToken) error {
-	const op = "create_refresh_token"
-	_, err := r.getDB(ctx).ExecContext(ctx, insertRefreshTokenQuery, rt.UserID, rt.Token, rt.ExpiresAt)
+func (r *RefreshTokenRepo) CreateSession(ctx context.Context, session *models.AuthSession) (*models.AuthSession, error) {
+	const op = "create_auth_session"
+	var created models.AuthSession
+	err := sqlx.GetContext(ctx, r.getDB(ctx), &created, insertAuthSessionQuery,
+		session.UserID, session.Device, session.IP, session.UserAgent, session.ExpiresAt)
+	if err != nil {
+		return nil, r.checkError(op, err)
+	}
+	return &created, nil
+}
+
+// TouchSession records the use of the session and extends it
+func (r *RefreshTokenRepo) TouchSession(ctx context.Context, sessionID int64, client models.ClientInfo, expiresAt time.Time) error {
+	const op = "touch_auth_session"
+	_, err := r.getDB(ctx).ExecContext(ctx, touchAuthSessionQuery, sessionID, client.IP, client.UserAgent, expiresAt)
 	if err != nil {
 		return r.checkError(op, err)
 	}
 	return nil
 }
 
-// CreateRefreshToken is a convenience method for the auth service interface.
-func (r *RefreshTokenRepo) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) error {
-	return r.Create(ctx, &models.RefreshToken{
-		UserID:    userID,
-		Token:     token,
-		ExpiresAt: expiresAt,
-	})
+// ListSessions returns the active sessions of the user, most recently used first
+func (r *RefreshTokenRepo) ListSessions(ctx context.Context, userID int64) ([]models.AuthSession, error) {
+	const op = "list_auth_sessions"
+	var sessions []models.AuthSession
+	if err := sqlx.SelectContext(ctx, r.getDB(ctx), &sessions, listAuthSessionsQuery, userID); err != nil {
+		return nil, r.checkError(op, err)
+	}
+	return sessions, nil
+}
+
+func (r *RefreshTokenRepo) DeleteSession(ctx context.Context, sessionID int64) error {
+	_, err := r.getDB(ctx).ExecContext(ctx, deleteAuthSessionQuery, sessionID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUserSession deletes the session only if it belongs to the user
+func (r *RefreshTokenRepo) DeleteUserSession(ctx context.Context, userID, sessionID int64) error {
+	res, err := r.getDB(ctx).ExecContext(ctx, deleteUserAuthSessionQuery, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrAuthSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSessionByToken deletes the session the token belongs to
+func (r *RefreshTokenRepo) DeleteSessionByToken(ctx context.Context, tokenHash string) error {
+	_, err := r.getDB(ctx).ExecContext(ctx, deleteAuthSessionByTokenQuery, tokenHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepo) Create(ctx context.Context, rt *models.RefreshToken) error {
+	const op = "create_refresh_token"
+	_, err := r.getDB(ctx).ExecContext(ctx, insertRefreshTokenQuery, rt.UserID, rt.SessionID, rt.TokenHash, rt.ExpiresAt)
+	if err != nil {
+		return r.checkError(op, err)
+	}
+	return nil
 }
 
-func (r *RefreshTokenRepo) GetForUpdate(ctx context.Context, token string) (*models.RefreshToken, error) {
+// GetForUpdate locks the token by its hash. A rotated token is returned as
+// well: the caller decides what its reuse means.
+func (r *RefreshTokenRepo) GetForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	var rt models.RefreshToken
-	err := sqlx.GetContext(ctx, r.getDB(ctx), &rt, getRefreshTokenForUpdateQuery, token)
+	err := sqlx.GetContext(ctx, r.getDB(ctx), &rt, getRefreshTokenForUpdateQuery, tokenHash)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrRefreshTokenNotFound
 	}
@@ -86,16 +181,18 @@ func (r *RefreshTokenRepo) GetForUpdate(ctx context.Context, token string) (*mod
 	return &rt, nil
 }
 
-func (r *RefreshTokenRepo) DeleteByToken(ctx context.Context, token string) error {
-	_, err := r.getDB(ctx).ExecContext(ctx, deleteRefreshTokenByToken, token)
+func (r *RefreshTokenRepo) MarkRotated(ctx context.Context, id int64) error {
+	const op = "rotate_refresh_token"
+	_, err := r.getDB(ctx).ExecContext(ctx, markRefreshTokenRotatedQuery, id)
 	if err != nil {
-		return err
+		return r.checkError(op, err)
 	}
 	return nil
 }
 
+// DeleteByStaffID ends all sessions of the staff member
 func (r *RefreshTokenRepo) DeleteByStaffID(ctx context.Context, id int64) error {
-	_, err := r.db.ExecContext(ctx, deleteRefreshTokenByStaffID, id)
+	_, err := r.db.ExecContext(ctx, deleteAuthSessionsByStaffID, id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	roleColumns = `name, title, description, is_system, mfa_required, assignable, created_at, updated_at`
+
+	listRolesQuery = `
+		SELECT ` + roleColumns + `
+		FROM roles
+		ORDER BY is_system DESC, name`
+
+	getRoleQuery = `
+		SELECT ` + roleColumns + `
+		FROM roles
+		WHERE name = $1`
+
+	createRoleQuery = `
+		INSERT INTO roles (name, title, description, mfa_required, assignable)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + roleColumns
+
+	updateRoleQuery = `
+		UPDATE roles
+		SET title = COALESCE($2, title),
+		    description = COALESCE($3, description),
+		    mfa_required = COALESCE($4, mfa_required),
+		    assignable = COALESCE($5, assignable),
+		    updated_at = NOW()
+		WHERE name = $1
+		RETURNING ` + roleColumns
+
+	deleteRoleQuery = `DELETE FROM roles WHERE name = $1 AND NOT is_system`
+)
+
+// RoleRepo stores the staff roles. Permissions of a role live in role_permissions
+// and are deleted together with it.
+type RoleRepo struct {
+	db          *sqlx.DB
+	permissions repository.PermissionCache
+}
+
+type RoleOption func(*RoleRepo)
+
+// WithRolePermissionCache makes Delete invalidate the cached permissions of the role
+func WithRolePermissionCache(cache repository.PermissionCache) RoleOption {
+	return func(r *RoleRepo) {
+		r.permissions = cache
+	}
+}
+
+// NewRoleRepo creates a new RoleRepo.
+func NewRoleRepo(db *sqlx.DB, opts ...RoleOption) *RoleRepo {
+	r := &RoleRepo{db: db}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+func (r *RoleRepo) List(ctx context.Context) ([]models.Role, error) {
+	const operation = "list_roles"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.Role, error) {
+		var roles []models.Role
+		if err := r.db.SelectContext(ctx, &roles, listRolesQuery); err != nil {
+			return nil, fmt.Errorf("list roles: %w", err)
+		}
+		return roles, nil
+	})
+}
+
+func (r *RoleRepo) Get(ctx context.Context, name string) (*models.Role, error) {
+	const operation = "get_role"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Role, error) {
+		var role models.Role
+		if err := r.db.GetContext(ctx, &role, getRoleQuery, name); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrRoleNotFound
+			}
+			return nil, fmt.Errorf("get role: %w", err)
+		}
+		return &role, nil
+	})
+}
+
+func (r *RoleRepo) Create(ctx context.Context, role *models.Role) (*models.Role, error) {
+	const operation = "create_role"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Role, error) {
+		var created models.Role
+		err := r.db.GetContext(ctx, &created, createRoleQuery, role.Name, role.Title, role.Description, role.MFARequired, role.Assignable)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return nil, models.ErrRoleAlreadyExist
+			}
+			return nil, fmt.Errorf("create role: %w", err)
+		}
+		return &created, nil
+	})
+}
+
+// Update changes the non-nil fields of upd. The name of a role is its key and is not changed.
+func (r *RoleRepo) Update(ctx context.Context, name string, upd *models.RoleUpdate) (*models.Role, error) {
+	const operation = "update_role"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.Role, error) {
+		var role models.Role
+		if err := r.db.GetContext(ctx, &role, updateRoleQuery, name, upd.Title, upd.Description, upd.MFARequired, upd.Assignable); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrRoleNotFound
+			}
+			return nil, fmt.Errorf("update role: %w", err)
+		}
+		return &role, nil
+	})
+}
+
+// Delete deletes a custom role. A role still assigned to staff or users is kept.
+func (r *RoleRepo) Delete(ctx context.Context, name string) error {
+	const operation = "delete_role"
+
+	err := repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, deleteRoleQuery, name)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return models.ErrRoleInUse
+			}
+			return fmt.Errorf("delete role: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete role: %w", err)
+		}
+		if n == 0 {
+			return models.ErrRoleNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.permissions != nil {
+		// The role is gone; a stale cache expires on its own
+		if err := r.permissions.Invalidate(ctx, name); err != nil {
+			logger.Error("failed to invalidate cached permissions", zap.String("role", name), zap.Error(err))
+		}
+	}
+
+	return nil
+}
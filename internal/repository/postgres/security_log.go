@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const createSecurityEventQuery = `
+	INSERT INTO security_log (event, action, email, ip, actor_id, locked_until)
+	VALUES ($1, $2, $3, $4, $5, $6)`
+
+// SecurityLogRepo stores lockouts of login and password recovery.
+type SecurityLogRepo struct {
+	db *sqlx.DB
+}
+
+// NewSecurityLogRepo creates a new SecurityLogRepo.
+func NewSecurityLogRepo(db *sqlx.DB) *SecurityLogRepo {
+	return &SecurityLogRepo{db: db}
+}
+
+func (r *SecurityLogRepo) Create(ctx context.Context, event *models.SecurityEvent) error {
+	const operation = "create_security_event"
+
+	return repository.WithDBMetrics(operation, func() error {
+		_, err := r.db.ExecContext(ctx, createSecurityEventQuery,
+			event.Event,
+			event.Action,
+			event.Email,
+			event.IP,
+			event.ActorID,
+			event.LockedUntil,
+		)
+		if err != nil {
+			return fmt.Errorf("create security event: %w", err)
+		}
+		return nil
+	})
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -11,14 +12,37 @@ import (
 
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
 )
 
 type SettingsRep struct {
-	client *sqlx.DB
+	client      *sqlx.DB
+	permissions repository.PermissionCache
+	messages    repository.SettingsMessagesCache
 }
 
-func NewSettingsRep(client *sqlx.DB) *SettingsRep {
-	return &SettingsRep{client: client}
+type SettingsOption func(*SettingsRep)
+
+// WithPermissionCache makes PostSettings invalidate the cached permissions of the role
+func WithPermissionCache(cache repository.PermissionCache) SettingsOption {
+	return func(r *SettingsRep) {
+		r.permissions = cache
+	}
+}
+
+// WithMessagesCache makes PutSettings invalidate the cached message templates
+func WithMessagesCache(cache repository.SettingsMessagesCache) SettingsOption {
+	return func(r *SettingsRep) {
+		r.messages = cache
+	}
+}
+
+func NewSettingsRep(client *sqlx.DB, opts ...SettingsOption) *SettingsRep {
+	r := &SettingsRep{client: client}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
 func (r *SettingsRep) GetSettings(ctx context.Context) (models.SettingsFormMessages, error) {
@@ -53,17 +77,18 @@ func (r *SettingsRep) GetSettings(ctx context.Context) (models.SettingsFormMessa
 func (r *SettingsRep) GetSettingsPermissions(ctx context.Context, role string) (models.SettingsPermissions, error) {
 	var permissions models.SettingsPermissions
 
+	// A role without a row in role_permissions has no permissions yet
 	query := `
-		SELECT role, permissions 
-		FROM role_permissions 
-		WHERE role = $1;
+		SELECT r.name AS role, COALESCE(rp.permissions, '{}') AS permissions
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE r.name = $1;
 	`
 
 	err := r.client.GetContext(ctx, &permissions, query, role)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Error("failed to get settings permissions from db", zap.Error(err))
-			return models.SettingsPermissions{}, fmt.Errorf("settings permissions not found in database")
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SettingsPermissions{}, models.ErrRoleNotFound
 		}
 
 		logger.Error("failed to get settings permissions from db", zap.Error(err))
@@ -96,6 +121,13 @@ func (r *SettingsRep) PutSettings(ctx context.Context, newSettings models.Settin
 		return err
 	}
 
+	if r.messages != nil {
+		// The messages are saved; a stale cache expires on its own
+		if err := r.messages.Invalidate(ctx); err != nil {
+			logger.Error("failed to invalidate cached settings messages", zap.Error(err))
+		}
+	}
+
 	return nil
 }
 
@@ -108,8 +140,22 @@ func (r *SettingsRep) PostSettings(ctx context.Context, newSettings models.Setti
     `
 
 	_, err := r.client.ExecContext(ctx, query, newSettings.Role, pq.Array(newSettings.Permissions))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return models.ErrRoleNotFound
+		}
+		return err
+	}
 
-	return err
+	if r.permissions != nil {
+		// The permissions are saved; a stale cache expires on its own
+		if err := r.permissions.Invalidate(ctx, newSettings.Role); err != nil {
+			logger.Error("failed to invalidate cached permissions", zap.String("role", newSettings.Role), zap.Error(err))
+		}
+	}
+
+	return nil
 }
 
 //const getSettingsQuery = `SELECT key, value, category FROM settings`
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/yandex-development-1-team/go/internal/ctxutil"
 	"github.com/yandex-development-1-team/go/internal/models"
 )
 
@@ -92,7 +93,7 @@ func NewSpecialProjectRepository(db *sqlx.DB) *specialProjectRepo {
 func (r *specialProjectRepo) Create(ctx context.Context, proj *models.SpecialProject) (*models.SpecialProjectDB, error) {
 	var result models.SpecialProjectDB
 
-	err := r.db.QueryRowContext(ctx, createSpecProjectQuery,
+	err := r.getDB(ctx).QueryRowxContext(ctx, createSpecProjectQuery,
 		proj.Title,
 		proj.Description,
 		proj.Image,
@@ -190,7 +191,7 @@ func (r *specialProjectRepo) List(ctx context.Context, statusFilter string, sear
 func (r *specialProjectRepo) Update(ctx context.Context, id int64, specialProject *models.SpecialProjectUpdate) (*models.SpecialProjectDB, error) {
 	var updatedSpecialProject models.SpecialProjectDB
 
-	err := r.db.QueryRowContext(ctx, updateSpecProjectQuery,
+	err := r.getDB(ctx).QueryRowxContext(ctx, updateSpecProjectQuery,
 		specialProject.Title,
 		specialProject.Description,
 		specialProject.Image,
@@ -226,3 +227,10 @@ func (r specialProjectRepo) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+func (r *specialProjectRepo) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctxutil.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
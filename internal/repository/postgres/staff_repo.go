@@ -6,27 +6,34 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
+	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/ctxutil"
 	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
 )
 
 const getUserByEmailQuery = `
     SELECT id, telegram_nick, first_name, last_name, second_name, email,
                phone_number, password_hash, role, status, invite_token, department,
-               position, supervisor, address, image, created_at, updated_at
+               position, supervisor, address, image, email_verified_at, approved_at,
+               created_at, updated_at
     FROM staff
     WHERE email = $1`
 
+// A self-registered staff member starts with the user role, unverified and
+// not approved
 const createUserQuery = `
-    INSERT INTO staff(first_name, last_name, email, invite_token, password_hash)
-    VALUES ($1, $2, $3, $4, $5)
-    RETURNING id, telegram_nick, first_name, email,
-              role, status,
+    INSERT INTO staff(first_name, last_name, email, password_hash, email_verified_at, approved_at)
+    VALUES ($1, $2, $3, $4, NULL, NULL)
+    RETURNING id, telegram_nick, first_name, last_name, email,
+              role, status, email_verified_at, approved_at,
               created_at, updated_at
 `
 const getDashboardOverview = `
@@ -93,12 +100,12 @@ const insertStaffAdminQuery = `
     INSERT INTO staff (
         first_name, last_name, second_name, email,
         phone_number, password_hash, role, status,
-        department, position, image, invite_token, supervisor, address)
-    VALUES ($1, $2, $3, $4, $5, '', $6::user_role_type, $7::user_status_type,
-        $8, $9, $10, $11, $12, $13)
+        department, position, image, invite_token, supervisor, address, invite_expires_at)
+    VALUES ($1, $2, $3, $4, $5, '', $6, $7::user_status_type,
+        $8, $9, $10, $11, $12, $13, $14)
     RETURNING id, telegram_nick, first_name, last_name, second_name, email,
-        phone_number, role, status, invite_token, department, position,
-        supervisor, address, image, created_at, updated_at`
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
 
 const updateStaffQuery = `
     UPDATE staff SET
@@ -106,7 +113,7 @@ const updateStaffQuery = `
         last_name    = COALESCE($3, last_name),
         second_name  = COALESCE($4, second_name),
         email        = COALESCE($5, email),
-        role         = COALESCE($6::user_role_type, role),
+        role         = COALESCE($6, role),
         status       = COALESCE($7::user_status_type, status),
         phone_number = COALESCE($8, phone_number),
         department   = COALESCE($9, department),
@@ -114,26 +121,105 @@ const updateStaffQuery = `
         supervisor   = COALESCE($11, supervisor),
         address      = COALESCE($12, address),
         image        = COALESCE($13, image)
-    WHERE id = $1
+    WHERE id = $1 AND ($6::varchar IS NULL OR $6::varchar = 'user' OR approved_at IS NOT NULL)
     RETURNING id, telegram_nick, first_name, last_name, second_name, email,
-        phone_number, role, status, invite_token, department, position,
-        supervisor, address, image, created_at, updated_at`
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
 
 const updateStaffStatusQuery = `
     UPDATE staff SET status = $2::user_status_type
     WHERE id = $1
     RETURNING id, telegram_nick, first_name, last_name, second_name, email,
-        phone_number, role, status, invite_token, department, position,
-        supervisor, address, image, created_at, updated_at`
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+const renewInviteQuery = `
+    UPDATE staff SET invite_token = $2, invite_expires_at = $3, updated_at = NOW()
+    WHERE id = $1 AND status = 'invited'
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+const revokeInviteQuery = `
+    UPDATE staff SET invite_token = NULL, invite_expires_at = NULL, updated_at = NOW()
+    WHERE id = $1 AND status = 'invited' AND invite_expires_at IS NOT NULL
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+// Registering with an invite accepts it for the invited email only
+const registerInvitedQuery = `
+    UPDATE staff SET first_name = $3, last_name = $4, password_hash = $5, status = 'active',
+        invite_token = NULL, invite_expires_at = NULL, updated_at = NOW()
+    WHERE invite_token = $1 AND LOWER(email) = LOWER($2)
+        AND status = 'invited' AND invite_expires_at > NOW()
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+// Verifying activates a member still waiting for it; a repeated click is a no-op
+const verifyEmailQuery = `
+    UPDATE staff SET
+        status = CASE WHEN email_verified_at IS NULL AND status = 'invited'
+                      THEN 'active'::user_status_type ELSE status END,
+        email_verified_at = COALESCE(email_verified_at, NOW()),
+        updated_at = NOW()
+    WHERE id = $1 AND email = $2
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+const approveStaffQuery = `
+    UPDATE staff SET approved_at = COALESCE(approved_at, NOW()), updated_at = NOW()
+    WHERE id = $1
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+// The token is single use: accepting the invite clears it
+const acceptInviteQuery = `
+    UPDATE staff SET password_hash = $2, status = 'active',
+        invite_token = NULL, invite_expires_at = NULL, updated_at = NOW()
+    WHERE invite_token = $1 AND status = 'invited' AND invite_expires_at > NOW()
+    RETURNING id, telegram_nick, first_name, last_name, second_name, email,
+        phone_number, role, status, invite_token, invite_expires_at, department, position,
+        supervisor, address, image, email_verified_at, approved_at, created_at, updated_at`
+
+const staffExistsQuery = `SELECT EXISTS(SELECT 1 FROM staff WHERE id = $1)`
+
+const getStaffPermissionsQuery = `
+    SELECT id, role, granted_permissions, denied_permissions
+    FROM staff
+    WHERE id = $1`
+
+const updateStaffPermissionsQuery = `
+    UPDATE staff SET granted_permissions = $2, denied_permissions = $3, updated_at = NOW()
+    WHERE id = $1
+    RETURNING id, role, granted_permissions, denied_permissions`
 
 type StaffRepo struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	permissions repository.PermissionCache
+}
+
+type StaffOption func(*StaffRepo)
+
+// WithStaffPermissionCache makes UpdatePermissionOverrides invalidate the
+// cached overrides of the staff member
+func WithStaffPermissionCache(cache repository.PermissionCache) StaffOption {
+	return func(u *StaffRepo) {
+		u.permissions = cache
+	}
 }
 
-func NewStaffRepo(db *sqlx.DB) *StaffRepo {
-	return &StaffRepo{
+func NewStaffRepo(db *sqlx.DB, opts ...StaffOption) *StaffRepo {
+	u := &StaffRepo{
 		db: db,
 	}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
 }
 
 func (u *StaffRepo) CreateStaff(ctx context.Context, userReq *models.UserAPI, hashPassword string) (*models.UserAPI, error) {
@@ -142,7 +228,6 @@ func (u *StaffRepo) CreateStaff(ctx context.Context, userReq *models.UserAPI, ha
 		userReq.Name,
 		userReq.LastName,
 		userReq.Email,
-		userReq.InviteToken,
 		hashPassword)
 	if err != nil {
 		var pqErr *pq.Error
@@ -173,23 +258,26 @@ func (u *StaffRepo) GetUserByEmail(ctx context.Context, email string) (*models.U
 
 func toUser(user *dto.UserRow) *models.UserAPI {
 	return &models.UserAPI{
-		ID:           user.ID,
-		TelegramNick: derefString(user.TelegramNick),
-		Name:         user.Name,
-		LastName:     user.LastName,
-		SecondName:   user.SecondName,
-		Email:        user.Email,
-		PhoneNumber:  derefString(user.PhoneNumber),
-		Role:         user.Role,
-		Status:       user.Status,
-		Department:   derefString(user.Department),
-		Position:     derefString(user.Position),
-		Supervisor:   derefString(user.Supervisor),
-		Address:      derefString(user.Address),
-		Image:        derefString(user.Image),
-		InviteToken:  derefString(user.InviteToken),
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:              user.ID,
+		TelegramNick:    derefString(user.TelegramNick),
+		Name:            user.Name,
+		LastName:        user.LastName,
+		SecondName:      user.SecondName,
+		Email:           user.Email,
+		PhoneNumber:     derefString(user.PhoneNumber),
+		Role:            user.Role,
+		Status:          user.Status,
+		Department:      derefString(user.Department),
+		Position:        derefString(user.Position),
+		Supervisor:      derefString(user.Supervisor),
+		Address:         derefString(user.Address),
+		Image:           derefString(user.Image),
+		InviteToken:     derefString(user.InviteToken),
+		InviteExpiresAt: user.InviteExpiresAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ApprovedAt:      user.ApprovedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}
 }
 
@@ -288,29 +376,32 @@ func (u *StaffRepo) List(ctx context.Context, role, status, search string, limit
 
 func (u *StaffRepo) GetByID(ctx context.Context, id int64) (*dto.UserWithDetails, error) {
 	userQuery := `
-		SELECT id, telegram_nick, first_name, last_name, second_name, email,
+		SELECT id, telegram_nick, telegram_id, first_name, last_name, second_name, email,
 		       phone_number, role, status, department, position, address,
-           			supervisor, image, created_at, updated_at
+           			supervisor, image, email_verified_at, approved_at, created_at, updated_at
 		FROM staff
 		WHERE id = $1`
 
 	type userRow struct {
-		ID           int64        `db:"id"`
-		TelegramNick *string      `db:"telegram_nick"`
-		FirstName    string       `db:"first_name"`
-		LastName     string       `db:"last_name"`
-		SecondName   string       `db:"second_name"`
-		Email        string       `db:"email"`
-		PhoneNumber  *string      `db:"phone_number"`
-		Role         string       `db:"role"`
-		Status       string       `db:"status"`
-		Department   *string      `db:"department"`
-		Position     *string      `db:"position"`
-		Supervisor   *string      `db:"supervisor"`
-		Address      *string      `db:"address"`
-		Image        *string      `db:"image"`
-		CreatedAt    sql.NullTime `db:"created_at"`
-		UpdatedAt    sql.NullTime `db:"updated_at"`
+		ID              int64        `db:"id"`
+		TelegramNick    *string      `db:"telegram_nick"`
+		TelegramID      *int64       `db:"telegram_id"`
+		FirstName       string       `db:"first_name"`
+		LastName        string       `db:"last_name"`
+		SecondName      string       `db:"second_name"`
+		Email           string       `db:"email"`
+		PhoneNumber     *string      `db:"phone_number"`
+		Role            string       `db:"role"`
+		Status          string       `db:"status"`
+		Department      *string      `db:"department"`
+		Position        *string      `db:"position"`
+		Supervisor      *string      `db:"supervisor"`
+		Address         *string      `db:"address"`
+		Image           *string      `db:"image"`
+		EmailVerifiedAt *time.Time   `db:"email_verified_at"`
+		ApprovedAt      *time.Time   `db:"approved_at"`
+		CreatedAt       sql.NullTime `db:"created_at"`
+		UpdatedAt       sql.NullTime `db:"updated_at"`
 	}
 
 	var user userRow
@@ -399,25 +490,28 @@ func (u *StaffRepo) GetByID(ctx context.Context, id int64) (*dto.UserWithDetails
 	}
 
 	return &dto.UserWithDetails{
-		ID:            user.ID,
-		TelegramNick:  derefString(user.TelegramNick),
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		SecondName:    user.SecondName,
-		Email:         user.Email,
-		PhoneNumber:   derefString(user.PhoneNumber),
-		Role:          user.Role,
-		Status:        user.Status,
-		Department:    derefString(user.Department),
-		Position:      derefString(user.Position),
-		Supervisor:    derefString(user.Supervisor),
-		Address:       derefString(user.Address),
-		Image:         derefString(user.Image),
-		CreatedAt:     user.CreatedAt.Time,
-		UpdatedAt:     user.UpdatedAt.Time,
-		Bookings:      bookings,
-		VisitHistory:  visitHistory,
-		FavoriteBoxes: favoriteBoxes,
+		ID:              user.ID,
+		TelegramNick:    derefString(user.TelegramNick),
+		TelegramID:      user.TelegramID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		SecondName:      user.SecondName,
+		Email:           user.Email,
+		PhoneNumber:     derefString(user.PhoneNumber),
+		Role:            user.Role,
+		Status:          user.Status,
+		Department:      derefString(user.Department),
+		Position:        derefString(user.Position),
+		Supervisor:      derefString(user.Supervisor),
+		Address:         derefString(user.Address),
+		Image:           derefString(user.Image),
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ApprovedAt:      user.ApprovedAt,
+		CreatedAt:       user.CreatedAt.Time,
+		UpdatedAt:       user.UpdatedAt.Time,
+		Bookings:        bookings,
+		VisitHistory:    visitHistory,
+		FavoriteBoxes:   favoriteBoxes,
 	}, nil
 }
 
@@ -438,6 +532,7 @@ func (u *StaffRepo) CreateStaffByAdmin(ctx context.Context, req *models.StaffAdm
 		req.InviteToken,
 		req.Supervisor,
 		req.Address,
+		req.InviteExpiresAt,
 	)
 	if err != nil {
 		var pqErr *pq.Error
@@ -473,7 +568,7 @@ func (u *StaffRepo) UpdateStaff(ctx context.Context, id int64, req *models.Staff
 		req.Image,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, models.ErrUserNotFound
+		return nil, u.notUpdated(ctx, id)
 	}
 	if err != nil {
 		return nil, err
@@ -481,6 +576,18 @@ func (u *StaffRepo) UpdateStaff(ctx context.Context, id int64, req *models.Staff
 	return toUser(&row), nil
 }
 
+// notUpdated tells a missing staff member from one not approved for the role
+func (u *StaffRepo) notUpdated(ctx context.Context, id int64) error {
+	var exists bool
+	if err := sqlx.GetContext(ctx, u.getDB(ctx), &exists, staffExistsQuery, id); err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrUserNotFound
+	}
+	return models.ErrStaffNotApproved
+}
+
 func (u *StaffRepo) UpdateStaffStatus(ctx context.Context, id int64, status string) (*models.UserAPI, error) {
 	var row dto.UserRow
 	err := u.db.GetContext(ctx, &row, updateStaffStatusQuery, id, status)
@@ -537,6 +644,140 @@ func (u *StaffRepo) UpdatePassword(ctx context.Context, staffId int64, passHash
 	return nil
 }
 
+// RenewInvite replaces the invite token of a staff member who has not accepted yet
+func (u *StaffRepo) RenewInvite(ctx context.Context, id int64, token string, expiresAt time.Time) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, renewInviteQuery, id, token, expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, u.inviteNotPending(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// RevokeInvite invalidates the pending invite; the staff member stays invited
+func (u *StaffRepo) RevokeInvite(ctx context.Context, id int64) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, revokeInviteQuery, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, u.inviteNotPending(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// AcceptInvite sets the password of the invitee and activates them
+func (u *StaffRepo) AcceptInvite(ctx context.Context, token, passHash string) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, acceptInviteQuery, token, passHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrInvalidInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// RegisterInvited accepts the invite sent to the email, taking the name given
+// on registration
+func (u *StaffRepo) RegisterInvited(ctx context.Context, token string, userReq *models.UserAPI, passHash string) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, registerInvitedQuery,
+		token, userReq.Email, userReq.Name, userReq.LastName, passHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrInvalidInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// VerifyEmail marks the email as confirmed. The email must still be the one
+// the verification link was sent to.
+func (u *StaffRepo) VerifyEmail(ctx context.Context, id int64, email string) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, verifyEmailQuery, id, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrInvalidVerification
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// Approve lets the staff member be given roles above user
+func (u *StaffRepo) Approve(ctx context.Context, id int64) (*models.UserAPI, error) {
+	var row dto.UserRow
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &row, approveStaffQuery, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUser(&row), nil
+}
+
+// inviteNotPending tells a missing staff member from one without a pending invite
+func (u *StaffRepo) inviteNotPending(ctx context.Context, id int64) error {
+	var exists bool
+	if err := sqlx.GetContext(ctx, u.getDB(ctx), &exists, staffExistsQuery, id); err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrUserNotFound
+	}
+	return models.ErrInviteNotPending
+}
+
+func (u *StaffRepo) GetPermissions(ctx context.Context, id int64) (*models.StaffPermissions, error) {
+	var permissions models.StaffPermissions
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &permissions, getStaffPermissionsQuery, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &permissions, nil
+}
+
+func (u *StaffRepo) GetPermissionOverrides(ctx context.Context, id int64) (models.PermissionOverrides, error) {
+	permissions, err := u.GetPermissions(ctx, id)
+	if err != nil {
+		return models.PermissionOverrides{}, err
+	}
+	return permissions.PermissionOverrides, nil
+}
+
+func (u *StaffRepo) UpdatePermissionOverrides(ctx context.Context, id int64, overrides models.PermissionOverrides) (*models.StaffPermissions, error) {
+	var permissions models.StaffPermissions
+	err := sqlx.GetContext(ctx, u.getDB(ctx), &permissions, updateStaffPermissionsQuery,
+		id, pq.Array(overrides.Granted), pq.Array(overrides.Denied))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if u.permissions != nil {
+		// The overrides are saved; a stale cache expires on its own
+		if err := u.permissions.InvalidateOverrides(ctx, id); err != nil {
+			logger.Error("failed to invalidate cached permission overrides", zap.Int64("staff_id", id), zap.Error(err))
+		}
+	}
+
+	return &permissions, nil
+}
+
 func (u *StaffRepo) getDB(ctx context.Context) sqlx.ExtContext {
 	if tx, ok := ctxutil.TxFromContext(ctx); ok {
 		return tx
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	linkedStaffColumns = `id, telegram_id, first_name, last_name, role`
+
+	setTelegramLinkCodeQuery = `
+		UPDATE staff
+		SET telegram_link_code_hash = $2, telegram_link_expires_at = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	releaseTelegramIDQuery = `UPDATE staff SET telegram_id = NULL WHERE telegram_id = $1`
+
+	linkTelegramQuery = `
+		UPDATE staff
+		SET telegram_id = $2, telegram_link_code_hash = NULL, telegram_link_expires_at = NULL, updated_at = NOW()
+		WHERE telegram_link_code_hash = $1 AND telegram_link_expires_at > NOW() AND status = 'active'
+		RETURNING ` + linkedStaffColumns
+
+	unlinkTelegramQuery = `
+		UPDATE staff
+		SET telegram_id = NULL, telegram_link_code_hash = NULL, telegram_link_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	getLinkedStaffQuery = `
+		SELECT ` + linkedStaffColumns + `
+		FROM staff
+		WHERE telegram_id = $1 AND status = 'active'`
+)
+
+type linkedStaffRow struct {
+	ID         int64  `db:"id"`
+	TelegramID int64  `db:"telegram_id"`
+	FirstName  string `db:"first_name"`
+	LastName   string `db:"last_name"`
+	Role       string `db:"role"`
+}
+
+func (r linkedStaffRow) toModel() *models.LinkedStaff {
+	return &models.LinkedStaff{
+		ID:         r.ID,
+		TelegramID: r.TelegramID,
+		FirstName:  r.FirstName,
+		LastName:   r.LastName,
+		Role:       r.Role,
+	}
+}
+
+// StaffTelegramRepo links staff accounts to their Telegram accounts.
+type StaffTelegramRepo struct {
+	db *sqlx.DB
+}
+
+// NewStaffTelegramRepo creates a new StaffTelegramRepo.
+func NewStaffTelegramRepo(db *sqlx.DB) *StaffTelegramRepo {
+	return &StaffTelegramRepo{db: db}
+}
+
+// SetLinkCode replaces the pending link code of the staff member
+func (r *StaffTelegramRepo) SetLinkCode(ctx context.Context, staffID int64, codeHash string, expiresAt time.Time) error {
+	const operation = "set_telegram_link_code"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, setTelegramLinkCodeQuery, staffID, codeHash, expiresAt)
+		if err != nil {
+			return fmt.Errorf("set telegram link code: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("set telegram link code: %w", err)
+		}
+		if n == 0 {
+			return models.ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// Link uses up the code and links the Telegram account to its staff member.
+// An account linked to another staff member before is moved over.
+func (r *StaffTelegramRepo) Link(ctx context.Context, codeHash string, telegramID int64) (*models.LinkedStaff, error) {
+	const operation = "link_telegram"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.LinkedStaff, error) {
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := tx.ExecContext(ctx, releaseTelegramIDQuery, telegramID); err != nil {
+			return nil, fmt.Errorf("release telegram id: %w", err)
+		}
+
+		var row linkedStaffRow
+		if err := tx.GetContext(ctx, &row, linkTelegramQuery, codeHash, telegramID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrTelegramLinkInvalid
+			}
+			return nil, fmt.Errorf("link telegram: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return row.toModel(), nil
+	})
+}
+
+// Unlink forgets the Telegram account and any pending code of the staff member
+func (r *StaffTelegramRepo) Unlink(ctx context.Context, staffID int64) error {
+	const operation = "unlink_telegram"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, unlinkTelegramQuery, staffID)
+		if err != nil {
+			return fmt.Errorf("unlink telegram: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("unlink telegram: %w", err)
+		}
+		if n == 0 {
+			return models.ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// GetByTelegramID returns the active staff member the account is linked to
+func (r *StaffTelegramRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*models.LinkedStaff, error) {
+	const operation = "get_linked_staff"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.LinkedStaff, error) {
+		var row linkedStaffRow
+		if err := r.db.GetContext(ctx, &row, getLinkedStaffQuery, telegramID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrTelegramNotLinked
+			}
+			return nil, fmt.Errorf("get linked staff: %w", err)
+		}
+		return row.toModel(), nil
+	})
+}
@@ -27,6 +27,15 @@ const (
 
 	isAdminQuery = `
 		SELECT is_admin FROM users WHERE telegram_id = $1`
+
+	setUserSourceQuery = `
+		UPDATE users SET source = $1, updated_at = NOW() WHERE telegram_id = $2`
+
+	getUserLanguageQuery = `
+		SELECT COALESCE(language, '') FROM users WHERE telegram_id = $1`
+
+	setUserLanguageQuery = `
+		UPDATE users SET language = $1, updated_at = NOW() WHERE telegram_id = $2`
 )
 
 type TelegramUserRepo struct {
@@ -109,3 +118,60 @@ func (u *TelegramUserRepo) IsAdmin(ctx context.Context, telegramID int64) (bool,
 		return isAdmin, nil
 	})
 }
+
+// SetSource remembers the campaign the guest came from; their next bookings
+// are attributed to it
+func (u *TelegramUserRepo) SetSource(ctx context.Context, telegramID int64, source string) error {
+	const operation = "set_user_source"
+	return repository.WithDBMetrics(operation, func() error {
+		result, err := u.db.ExecContext(ctx, setUserSourceQuery, source, telegramID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return models.ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// GetLanguage returns the language the guest chose in the bot, or an empty
+// string if they have not chosen one
+func (u *TelegramUserRepo) GetLanguage(ctx context.Context, telegramID int64) (string, error) {
+	const operation = "get_user_language"
+	var language string
+
+	return repository.WithDBMetricsValue(operation, func() (string, error) {
+		err := u.db.QueryRowContext(ctx, getUserLanguageQuery, telegramID).Scan(&language)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrUserNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return language, nil
+	})
+}
+
+// SetLanguage remembers the language the guest chose in the bot
+func (u *TelegramUserRepo) SetLanguage(ctx context.Context, telegramID int64, language string) error {
+	const operation = "set_user_language"
+	return repository.WithDBMetrics(operation, func() error {
+		result, err := u.db.ExecContext(ctx, setUserLanguageQuery, language, telegramID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return models.ErrUserNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	trackerBookingSelect = `
+		SELECT b.id, b.status::text AS status,
+		       COALESCE(b.tracker_ticket_id, '') AS tracker_ticket_id,
+		       COALESCE(b.tracker_status, '') AS tracker_status,
+		       b.guest_name,
+		       COALESCE(u.username, '') AS guest_contact,
+		       COALESCE(b.guest_organization, '') AS guest_organization,
+		       COALESCE(b.guest_position, '') AS guest_position,
+		       COALESCE(sv.name, '') AS service_name,
+		       b.booking_date, b.booking_time,
+		       '' AS description,
+		       COALESCE(s.first_name || ' ' || s.last_name, '') AS manager_name,
+		       COALESCE(s.email, '') AS manager_email,
+		       b.created_at
+		FROM bookings b
+		LEFT JOIN services sv ON sv.id = b.service_id
+		LEFT JOIN staff s ON s.id = b.manager_id
+		LEFT JOIN users u ON u.telegram_id = b.user_id`
+
+	trackerApplicationSelect = `
+		SELECT a.id, a.status::text AS status,
+		       COALESCE(a.tracker_ticket_id, '') AS tracker_ticket_id,
+		       COALESCE(a.tracker_status, '') AS tracker_status,
+		       a.customer_name AS guest_name,
+		       a.contact_info AS guest_contact,
+		       '' AS guest_organization,
+		       '' AS guest_position,
+		       '' AS service_name,
+		       NULL::date AS booking_date, NULL::time AS booking_time,
+		       a.description,
+		       COALESCE(s.first_name || ' ' || s.last_name, '') AS manager_name,
+		       COALESCE(s.email, '') AS manager_email,
+		       a.created_at
+		FROM applications a
+		LEFT JOIN staff s ON s.id = a.manager_id`
+
+	getBookingsWithoutTicketQuery = trackerBookingSelect + `
+		WHERE b.deleted_at IS NULL AND COALESCE(b.tracker_ticket_id, '') = ''
+		  AND b.status != 'cancelled' AND b.created_at >= $2
+		ORDER BY b.created_at ASC
+		LIMIT $1`
+
+	getApplicationsWithoutTicketQuery = trackerApplicationSelect + `
+		WHERE a.deleted_at IS NULL AND COALESCE(a.tracker_ticket_id, '') = ''
+		  AND a.status != 'cancelled' AND a.created_at >= $2
+		ORDER BY a.created_at ASC
+		LIMIT $1`
+
+	getBookingsForTrackerSyncQuery = trackerBookingSelect + `
+		WHERE b.deleted_at IS NULL
+		  AND COALESCE(b.tracker_ticket_id, '') != ''
+		  AND (b.status != 'cancelled' OR b.status::text IS DISTINCT FROM b.tracker_status)
+		ORDER BY b.tracker_synced_at ASC NULLS FIRST
+		LIMIT $1`
+
+	getApplicationsForTrackerSyncQuery = trackerApplicationSelect + `
+		WHERE a.deleted_at IS NULL
+		  AND COALESCE(a.tracker_ticket_id, '') != ''
+		  AND (a.status != 'cancelled' OR a.status::text IS DISTINCT FROM a.tracker_status)
+		ORDER BY a.tracker_synced_at ASC NULLS FIRST
+		LIMIT $1`
+
+	setBookingTicketQuery = `
+		UPDATE bookings
+		SET tracker_ticket_id = $1, tracker_status = NULLIF($2, ''), tracker_synced_at = NOW()
+		WHERE id = $3 AND COALESCE(tracker_ticket_id, '') = ''`
+
+	setApplicationTicketQuery = `
+		UPDATE applications
+		SET tracker_ticket_id = $1, tracker_status = NULLIF($2, ''), tracker_synced_at = NOW()
+		WHERE id = $3 AND COALESCE(tracker_ticket_id, '') = ''`
+
+	markBookingTrackerSyncedQuery = `
+		UPDATE bookings
+		SET tracker_status = NULLIF($1, ''), tracker_synced_at = NOW()
+		WHERE id = $2`
+
+	markApplicationTrackerSyncedQuery = `
+		UPDATE applications
+		SET tracker_status = NULLIF($1, ''), tracker_synced_at = NOW()
+		WHERE id = $2`
+)
+
+type trackerQueries struct {
+	withoutTicket string
+	forSync       string
+	setTicket     string
+	markSynced    string
+}
+
+var trackerQueriesByEntity = map[models.TrackerEntity]trackerQueries{
+	models.TrackerEntityBooking: {
+		withoutTicket: getBookingsWithoutTicketQuery,
+		forSync:       getBookingsForTrackerSyncQuery,
+		setTicket:     setBookingTicketQuery,
+		markSynced:    markBookingTrackerSyncedQuery,
+	},
+	models.TrackerEntityApplication: {
+		withoutTicket: getApplicationsWithoutTicketQuery,
+		forSync:       getApplicationsForTrackerSyncQuery,
+		setTicket:     setApplicationTicketQuery,
+		markSynced:    markApplicationTrackerSyncedQuery,
+	},
+}
+
+// TrackerSyncRepo keeps bookings and applications in sync with their Tracker issues.
+type TrackerSyncRepo struct {
+	db        *sqlx.DB
+	startFrom time.Time
+}
+
+// NewTrackerSyncRepo creates a new TrackerSyncRepo. Records created before
+// startFrom never get an issue, so enabling the integration does not flood
+// the queue with the whole history.
+func NewTrackerSyncRepo(db *sqlx.DB, startFrom time.Time) *TrackerSyncRepo {
+	return &TrackerSyncRepo{db: db, startFrom: startFrom}
+}
+
+// GetWithoutTicket returns the oldest active records created since the start
+// time that have no Tracker issue yet.
+func (r *TrackerSyncRepo) GetWithoutTicket(ctx context.Context, entity models.TrackerEntity, limit int) ([]models.TrackerItem, error) {
+	const operation = "tracker_get_without_ticket"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.TrackerItem, error) {
+		q, err := trackerQueriesFor(entity)
+		if err != nil {
+			return nil, err
+		}
+		return r.selectItems(ctx, entity, q.withoutTicket, limit, r.startFrom)
+	})
+}
+
+// GetForSync returns records with an issue whose status has to be compared with
+// Tracker: active ones and the ones changed locally since the last sync. The
+// least recently synced go first.
+func (r *TrackerSyncRepo) GetForSync(ctx context.Context, entity models.TrackerEntity, limit int) ([]models.TrackerItem, error) {
+	const operation = "tracker_get_for_sync"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.TrackerItem, error) {
+		q, err := trackerQueriesFor(entity)
+		if err != nil {
+			return nil, err
+		}
+		return r.selectItems(ctx, entity, q.forSync, limit)
+	})
+}
+
+// SetTicket stores the issue key unless the record already has one.
+func (r *TrackerSyncRepo) SetTicket(ctx context.Context, entity models.TrackerEntity, id int64, key, trackerStatus string) error {
+	const operation = "tracker_set_ticket"
+
+	return repository.WithDBMetrics(operation, func() error {
+		q, err := trackerQueriesFor(entity)
+		if err != nil {
+			return err
+		}
+		if _, err := r.db.ExecContext(ctx, q.setTicket, key, trackerStatus, id); err != nil {
+			return fmt.Errorf("set %s tracker ticket: %w", entity, err)
+		}
+		return nil
+	})
+}
+
+// MarkSynced records the status both sides agree on.
+func (r *TrackerSyncRepo) MarkSynced(ctx context.Context, entity models.TrackerEntity, id int64, status string) error {
+	const operation = "tracker_mark_synced"
+
+	return repository.WithDBMetrics(operation, func() error {
+		q, err := trackerQueriesFor(entity)
+		if err != nil {
+			return err
+		}
+		if _, err := r.db.ExecContext(ctx, q.markSynced, status, id); err != nil {
+			return fmt.Errorf("mark %s tracker synced: %w", entity, err)
+		}
+		return nil
+	})
+}
+
+func (r *TrackerSyncRepo) selectItems(ctx context.Context, entity models.TrackerEntity, query string, args ...any) ([]models.TrackerItem, error) {
+	var items []models.TrackerItem
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, fmt.Errorf("select %s tracker items: %w", entity, err)
+	}
+	for i := range items {
+		items[i].Entity = entity
+	}
+	return items, nil
+}
+
+func trackerQueriesFor(entity models.TrackerEntity) (trackerQueries, error) {
+	q, ok := trackerQueriesByEntity[entity]
+	if !ok {
+		return trackerQueries{}, fmt.Errorf("unknown tracker entity %q", entity)
+	}
+	return q, nil
+}
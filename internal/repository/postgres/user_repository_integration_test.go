@@ -255,3 +255,24 @@ func TestIsAdmin(t *testing.T) {
 		})
 	}
 }
+
+func TestUserLanguage(t *testing.T) {
+	ctx := context.Background()
+	_, _ = db.Exec(`
+    INSERT INTO users (telegram_id, username)
+    VALUES (888888, 'test_language')
+    ON CONFLICT (telegram_id) DO UPDATE SET language = NULL`)
+
+	language, err := repoUser.GetLanguage(ctx, 888888)
+	require.NoError(t, err)
+	assert.Empty(t, language, "not chosen yet")
+
+	require.NoError(t, repoUser.SetLanguage(ctx, 888888, "en"))
+	language, err = repoUser.GetLanguage(ctx, 888888)
+	require.NoError(t, err)
+	assert.Equal(t, "en", language)
+
+	_, err = repoUser.GetLanguage(ctx, 222222)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+	assert.ErrorIs(t, repoUser.SetLanguage(ctx, 222222, "en"), models.ErrUserNotFound)
+}
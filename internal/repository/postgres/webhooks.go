@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/yandex-development-1-team/go/internal/ctxutil"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	webhookSubscriptionColumns = `id, url, secret, events, description, is_active, created_at, updated_at`
+
+	createWebhookSubscriptionQuery = `
+		INSERT INTO webhook_subscriptions (url, secret, events, description, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + webhookSubscriptionColumns
+
+	getWebhookSubscriptionQuery = `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE id = $1`
+
+	listWebhookSubscriptionsQuery = `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		ORDER BY id`
+
+	updateWebhookSubscriptionQuery = `
+		UPDATE webhook_subscriptions
+		SET url = COALESCE($2, url),
+		    events = COALESCE($3::text[], events),
+		    description = COALESCE($4, description),
+		    is_active = COALESCE($5, is_active),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + webhookSubscriptionColumns
+
+	deleteWebhookSubscriptionQuery = `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	enqueueWebhookEventQuery = `
+		INSERT INTO webhook_deliveries (subscription_id, event, payload)
+		SELECT id, $1::text, $2::jsonb
+		FROM webhook_subscriptions
+		WHERE is_active AND $1::text = ANY(events)`
+
+	claimWebhookDeliveriesQuery = `
+		WITH claimed AS (
+		    UPDATE webhook_deliveries
+		    SET attempts = attempts + 1,
+		        next_attempt_at = NOW() + make_interval(secs => $2),
+		        updated_at = NOW()
+		    WHERE id IN (
+		        SELECT id FROM webhook_deliveries
+		        WHERE status = 'pending' AND next_attempt_at <= NOW()
+		        ORDER BY id
+		        LIMIT $1
+		        FOR UPDATE SKIP LOCKED
+		    )
+		    RETURNING id, subscription_id, event, payload, status::text AS status, attempts,
+		              response_status, last_error, next_attempt_at, delivered_at, created_at
+		)
+		SELECT c.*, s.url, s.secret
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		ORDER BY c.id`
+
+	markWebhookDeliveredQuery = `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', response_status = $2, last_error = NULL,
+		    delivered_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+
+	markWebhookRetryQuery = `
+		UPDATE webhook_deliveries
+		SET response_status = NULLIF($2, 0), last_error = $3, next_attempt_at = $4, updated_at = NOW()
+		WHERE id = $1`
+
+	markWebhookFailedQuery = `
+		UPDATE webhook_deliveries
+		SET status = 'failed', response_status = NULLIF($2, 0), last_error = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	listWebhookDeliveriesQuery = `
+		SELECT id, subscription_id, event, payload, status::text AS status, attempts,
+		       response_status, last_error, next_attempt_at, delivered_at, created_at,
+		       COUNT(*) OVER() AS total
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status::text = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+)
+
+type webhookSubscriptionRow struct {
+	ID          int64          `db:"id"`
+	URL         string         `db:"url"`
+	Secret      string         `db:"secret"`
+	Events      pq.StringArray `db:"events"`
+	Description string         `db:"description"`
+	IsActive    bool           `db:"is_active"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func (r webhookSubscriptionRow) toModel() *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:          r.ID,
+		URL:         r.URL,
+		Secret:      r.Secret,
+		Events:      []string(r.Events),
+		Description: r.Description,
+		IsActive:    r.IsActive,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+type webhookDeliveryRow struct {
+	models.WebhookDelivery
+	Total int `db:"total"`
+}
+
+// WebhookRepo stores webhook subscriptions and the queue of their deliveries.
+type WebhookRepo struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepo creates a new WebhookRepo.
+func NewWebhookRepo(db *sqlx.DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	const operation = "create_webhook_subscription"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.WebhookSubscription, error) {
+		var row webhookSubscriptionRow
+		err := r.db.GetContext(ctx, &row, createWebhookSubscriptionQuery,
+			sub.URL, sub.Secret, pq.Array(sub.Events), sub.Description, sub.IsActive)
+		if err != nil {
+			return nil, fmt.Errorf("create webhook subscription: %w", err)
+		}
+		return row.toModel(), nil
+	})
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	const operation = "get_webhook_subscription"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.WebhookSubscription, error) {
+		var row webhookSubscriptionRow
+		if err := r.db.GetContext(ctx, &row, getWebhookSubscriptionQuery, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrWebhookNotFound
+			}
+			return nil, fmt.Errorf("get webhook subscription: %w", err)
+		}
+		return row.toModel(), nil
+	})
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	const operation = "list_webhook_subscriptions"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.WebhookSubscription, error) {
+		var rows []webhookSubscriptionRow
+		if err := r.db.SelectContext(ctx, &rows, listWebhookSubscriptionsQuery); err != nil {
+			return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+		}
+
+		subs := make([]models.WebhookSubscription, 0, len(rows))
+		for _, row := range rows {
+			subs = append(subs, *row.toModel())
+		}
+		return subs, nil
+	})
+}
+
+// UpdateSubscription changes the non-nil fields of upd.
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, id int64, upd *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	const operation = "update_webhook_subscription"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.WebhookSubscription, error) {
+		var row webhookSubscriptionRow
+		err := r.db.GetContext(ctx, &row, updateWebhookSubscriptionQuery,
+			id, upd.URL, pq.Array(upd.Events), upd.Description, upd.IsActive)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, models.ErrWebhookNotFound
+			}
+			return nil, fmt.Errorf("update webhook subscription: %w", err)
+		}
+		return row.toModel(), nil
+	})
+}
+
+// DeleteSubscription deletes the subscription together with its delivery log.
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	const operation = "delete_webhook_subscription"
+
+	return repository.WithDBMetrics(operation, func() error {
+		res, err := r.db.ExecContext(ctx, deleteWebhookSubscriptionQuery, id)
+		if err != nil {
+			return fmt.Errorf("delete webhook subscription: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("delete webhook subscription: %w", err)
+		}
+		if n == 0 {
+			return models.ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+// EnqueueEvent queues a delivery of the event for every active subscription
+// to it. It joins the transaction stored in ctx, if any.
+func (r *WebhookRepo) EnqueueEvent(ctx context.Context, event string, payload []byte) error {
+	const operation = "enqueue_webhook_event"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.getDB(ctx).ExecContext(ctx, enqueueWebhookEventQuery, event, string(payload)); err != nil {
+			return fmt.Errorf("enqueue webhook event: %w", err)
+		}
+		return nil
+	})
+}
+
+// ClaimDeliveries locks up to limit due deliveries for lease so that
+// concurrent workers do not pick them up.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDeliveryTask, error) {
+	const operation = "claim_webhook_deliveries"
+
+	return repository.WithDBMetricsValue(operation, func() ([]models.WebhookDeliveryTask, error) {
+		var tasks []models.WebhookDeliveryTask
+		if err := r.db.SelectContext(ctx, &tasks, claimWebhookDeliveriesQuery, limit, lease.Seconds()); err != nil {
+			return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+		}
+		return tasks, nil
+	})
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	const operation = "mark_webhook_delivered"
+
+	return repository.WithDBMetrics(operation, func() error {
+		if _, err := r.db.ExecContext(ctx, markWebhookDeliveredQuery, id, responseStatus); err != nil {
+			return fmt.Errorf("mark webhook delivered: %w", err)
+		}
+		return nil
+	})
+}
+
+// MarkDeliveryFailed records a delivery error; responseStatus is 0 when the
+// endpoint did not answer. With a nil retryAt the delivery is given up on,
+// otherwise it is retried at retryAt.
+func (r *WebhookRepo) MarkDeliveryFailed(ctx context.Context, id int64, responseStatus int, lastErr string, retryAt *time.Time) error {
+	const operation = "mark_webhook_failed"
+
+	return repository.WithDBMetrics(operation, func() error {
+		var err error
+		if retryAt == nil {
+			_, err = r.db.ExecContext(ctx, markWebhookFailedQuery, id, responseStatus, lastErr)
+		} else {
+			_, err = r.db.ExecContext(ctx, markWebhookRetryQuery, id, responseStatus, lastErr, *retryAt)
+		}
+		if err != nil {
+			return fmt.Errorf("mark webhook failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) (*models.WebhookDeliveryList, error) {
+	const operation = "list_webhook_deliveries"
+
+	return repository.WithDBMetricsValue(operation, func() (*models.WebhookDeliveryList, error) {
+		var rows []webhookDeliveryRow
+		err := r.db.SelectContext(ctx, &rows, listWebhookDeliveriesQuery,
+			filter.SubscriptionID, filter.Status, filter.Limit, filter.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("list webhook deliveries: %w", err)
+		}
+
+		list := &models.WebhookDeliveryList{
+			Items:  make([]models.WebhookDelivery, 0, len(rows)),
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		}
+		for _, row := range rows {
+			list.Items = append(list.Items, row.WebhookDelivery)
+			list.Total = row.Total
+		}
+		return list, nil
+	})
+}
+
+func (r *WebhookRepo) getDB(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctxutil.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
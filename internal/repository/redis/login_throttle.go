@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yandex-development-1-team/go/internal/metrics"
+)
+
+const (
+	attemptsKeyPrefix     = "auth:attempts:"
+	lockoutKeyPrefix      = "auth:lockout:"
+	lockoutLevelKeyPrefix = "auth:lockout_level:"
+)
+
+// LoginThrottle counts failed attempts per key in a sliding window and locks
+// the key out. Attempts are members of a sorted set scored by time, so the
+// window slides with every attempt; each lockout within the level memory
+// doubles the next one.
+type LoginThrottle struct {
+	client *redis.Client
+}
+
+func NewLoginThrottle(client *redis.Client) *LoginThrottle {
+	return &LoginThrottle{client: client}
+}
+
+// AddAttempt records an attempt and returns the number of attempts within the window
+func (t *LoginThrottle) AddAttempt(ctx context.Context, key string, window time.Duration) (int64, error) {
+	now := time.Now()
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+
+	pipe := t.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, attemptsKeyPrefix+key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+	pipe.ZAdd(ctx, attemptsKeyPrefix+key, redis.Z{Score: float64(now.UnixNano()), Member: member})
+	count := pipe.ZCard(ctx, attemptsKeyPrefix+key)
+	pipe.PExpire(ctx, attemptsKeyPrefix+key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.IncCacheErrors("add_login_attempt")
+		return 0, fmt.Errorf("add login attempt: %w", err)
+	}
+	return count.Val(), nil
+}
+
+// Lock locks the key out for base doubled with every lockout remembered
+// within memory, up to limit, and starts a new window of attempts
+func (t *LoginThrottle) Lock(ctx context.Context, key string, base, limit, memory time.Duration) (time.Duration, error) {
+	pipe := t.client.TxPipeline()
+	level := pipe.Incr(ctx, lockoutLevelKeyPrefix+key)
+	pipe.PExpire(ctx, lockoutLevelKeyPrefix+key, memory)
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.IncCacheErrors("lock_login")
+		return 0, fmt.Errorf("bump lockout level: %w", err)
+	}
+
+	duration := lockoutDuration(base, limit, level.Val())
+	pipe = t.client.TxPipeline()
+	pipe.Set(ctx, lockoutKeyPrefix+key, level.Val(), duration)
+	pipe.Del(ctx, attemptsKeyPrefix+key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.IncCacheErrors("lock_login")
+		return 0, fmt.Errorf("lock login: %w", err)
+	}
+	return duration, nil
+}
+
+// LockedFor returns how long the longest lockout among the keys lasts; zero
+// if none is locked out
+func (t *LoginThrottle) LockedFor(ctx context.Context, keys ...string) (time.Duration, error) {
+	pipe := t.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, 0, len(keys))
+	for _, key := range keys {
+		ttls = append(ttls, pipe.PTTL(ctx, lockoutKeyPrefix+key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.IncCacheErrors("check_login_lockout")
+		return 0, fmt.Errorf("check login lockout: %w", err)
+	}
+
+	var locked time.Duration
+	for _, ttl := range ttls {
+		// Missing keys report a negative TTL
+		locked = max(locked, ttl.Val())
+	}
+	return locked, nil
+}
+
+// ResetAttempts forgets the attempts of the key; its lockout level stays
+func (t *LoginThrottle) ResetAttempts(ctx context.Context, key string) error {
+	if err := t.client.Del(ctx, attemptsKeyPrefix+key).Err(); err != nil {
+		metrics.IncCacheErrors("reset_login_attempts")
+		return fmt.Errorf("reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Unlock lifts the lockout of the key and forgets its attempts and level
+func (t *LoginThrottle) Unlock(ctx context.Context, key string) error {
+	err := t.client.Del(ctx, attemptsKeyPrefix+key, lockoutKeyPrefix+key, lockoutLevelKeyPrefix+key).Err()
+	if err != nil {
+		metrics.IncCacheErrors("unlock_login")
+		return fmt.Errorf("unlock login: %w", err)
+	}
+	return nil
+}
+
+// lockoutDuration doubles base for every level above the first, up to limit
+func lockoutDuration(base, limit time.Duration, level int64) time.Duration {
+	duration := base
+	for i := int64(1); i < level && duration < limit; i++ {
+		duration *= 2
+	}
+	return min(duration, limit)
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginThrottle_SlidingWindowAndLockout(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+	throttle := NewLoginThrottle(client)
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := throttle.AddAttempt(ctx, "login:email:a@test.local", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, count)
+	}
+
+	locked, err := throttle.LockedFor(ctx, "login:email:a@test.local", "login:ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+
+	duration, err := throttle.Lock(ctx, "login:email:a@test.local", time.Minute, 10*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, duration)
+
+	locked, err = throttle.LockedFor(ctx, "login:email:a@test.local", "login:ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, locked > 0 && locked <= time.Minute)
+
+	count, err := throttle.AddAttempt(ctx, "login:email:a@test.local", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "a lockout starts a new window")
+
+	duration, err = throttle.Lock(ctx, "login:email:a@test.local", time.Minute, 10*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, duration, "the next lockout is doubled")
+
+	require.NoError(t, throttle.Unlock(ctx, "login:email:a@test.local"))
+	locked, err = throttle.LockedFor(ctx, "login:email:a@test.local")
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+
+	duration, err = throttle.Lock(ctx, "login:email:a@test.local", time.Minute, 10*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, duration, "unlock forgets the level")
+}
+
+func TestLockoutDuration(t *testing.T) {
+	assert.Equal(t, time.Minute, lockoutDuration(time.Minute, time.Hour, 1))
+	assert.Equal(t, 8*time.Minute, lockoutDuration(time.Minute, time.Hour, 4))
+	assert.Equal(t, time.Hour, lockoutDuration(time.Minute, time.Hour, 100))
+}
@@ -0,0 +1,272 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+const (
+	permissionRoleKeyPrefix     = "permissions:role:"
+	permissionStaffKeyPrefix    = "permissions:staff:"
+	permissionVersionKeyPrefix  = "permissions:version:"
+	permissionInvalidateChannel = "permissions:invalidate"
+
+	defaultPermissionCacheSize = 64
+	defaultPermissionLocalTTL  = 30 * time.Second
+	defaultPermissionTTL       = 10 * time.Minute
+)
+
+// RolePermissionLoader reads role permissions from the source of truth
+type RolePermissionLoader interface {
+	GetSettingsPermissions(ctx context.Context, role string) (models.SettingsPermissions, error)
+}
+
+// OverridesLoader reads staff permission overrides from the source of truth
+type OverridesLoader interface {
+	GetPermissionOverrides(ctx context.Context, staffID int64) (models.PermissionOverrides, error)
+}
+
+// PermissionCache keeps role permissions and staff overrides in in-process
+// LRUs in front of Redis. Invalidation is broadcast over Redis pub/sub so that
+// every replica drops its local copy; the local TTL bounds staleness if a
+// message is missed. Redis failures fall back to the loaders.
+//
+// A value loaded before an invalidation must not be cached after it. In Redis
+// every entry is stored under the version of its key, which invalidation
+// increments, so a late write lands under a version nobody reads. Locally a
+// value is kept only if no invalidation arrived while it was loaded.
+type PermissionCache struct {
+	client     *redis.Client
+	roles      RolePermissionLoader
+	overrides  OverridesLoader
+	localRole  *expirable.LRU[string, []string]
+	localUser  *expirable.LRU[int64, models.PermissionOverrides]
+	generation atomic.Uint64
+	ttl        time.Duration
+}
+
+// NewPermissionCache creates a new PermissionCache. size limits each local
+// LRU; non-positive settings fall back to the defaults.
+func NewPermissionCache(
+	client *redis.Client,
+	roles RolePermissionLoader,
+	overrides OverridesLoader,
+	size int,
+	localTTL, ttl time.Duration,
+) *PermissionCache {
+	if size <= 0 {
+		size = defaultPermissionCacheSize
+	}
+	if localTTL <= 0 {
+		localTTL = defaultPermissionLocalTTL
+	}
+	if ttl <= 0 {
+		ttl = defaultPermissionTTL
+	}
+
+	return &PermissionCache{
+		client:    client,
+		roles:     roles,
+		overrides: overrides,
+		localRole: expirable.NewLRU[string, []string](size, nil, localTTL),
+		localUser: expirable.NewLRU[int64, models.PermissionOverrides](size, nil, localTTL),
+		ttl:       ttl,
+	}
+}
+
+// Permissions returns the permissions of the role
+func (c *PermissionCache) Permissions(ctx context.Context, role string) ([]string, error) {
+	return cached(ctx, c, c.localRole, role, permissionRoleKeyPrefix+role, func() ([]string, error) {
+		loaded, err := c.roles.GetSettingsPermissions(ctx, role)
+		return loaded.Permissions, err
+	})
+}
+
+// Overrides returns the permission overrides of the staff member
+func (c *PermissionCache) Overrides(ctx context.Context, staffID int64) (models.PermissionOverrides, error) {
+	return cached(ctx, c, c.localUser, staffID, staffPermissionKey(staffID), func() (models.PermissionOverrides, error) {
+		return c.overrides.GetPermissionOverrides(ctx, staffID)
+	})
+}
+
+// Invalidate moves the role to a new version in Redis and tells every
+// replica, including this one, to drop its local copy.
+func (c *PermissionCache) Invalidate(ctx context.Context, role string) error {
+	c.generation.Add(1)
+	c.localRole.Remove(role)
+	return c.invalidate(ctx, permissionRoleKeyPrefix+role)
+}
+
+// InvalidateOverrides drops the staff overrides the same way as Invalidate
+func (c *PermissionCache) InvalidateOverrides(ctx context.Context, staffID int64) error {
+	c.generation.Add(1)
+	c.localUser.Remove(staffID)
+	return c.invalidate(ctx, staffPermissionKey(staffID))
+}
+
+// Listen applies invalidations published by other replicas until the context
+// is cancelled.
+func (c *PermissionCache) Listen(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, permissionInvalidateChannel)
+	defer func() { _ = pubsub.Close() }()
+
+	// Messages published while the subscription was down are lost: start clean
+	c.generation.Add(1)
+	c.localRole.Purge()
+	c.localUser.Purge()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("permission cache listener stopped")
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			c.dropLocal(msg.Payload)
+		}
+	}
+}
+
+// dropLocal removes the local copy of the entry stored under the Redis key
+func (c *PermissionCache) dropLocal(key string) {
+	c.generation.Add(1)
+	if role, ok := strings.CutPrefix(key, permissionRoleKeyPrefix); ok {
+		c.localRole.Remove(role)
+	} else if id, ok := strings.CutPrefix(key, permissionStaffKeyPrefix); ok {
+		staffID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return
+		}
+		c.localUser.Remove(staffID)
+	}
+	logger.Debug("permission cache invalidated", zap.String("key", key))
+}
+
+func (c *PermissionCache) invalidate(ctx context.Context, key string) error {
+	if err := c.client.Incr(ctx, permissionVersionKeyPrefix+key).Err(); err != nil {
+		metrics.IncCacheErrors("invalidate_permissions")
+		return fmt.Errorf("bump cached permissions version: %w", err)
+	}
+	if err := c.client.Publish(ctx, permissionInvalidateChannel, key).Err(); err != nil {
+		metrics.IncCacheErrors("invalidate_permissions")
+		return fmt.Errorf("publish permissions invalidation: %w", err)
+	}
+	return nil
+}
+
+// cached looks the value up in the local LRU, then in Redis, then loads it
+// and fills both layers
+func cached[K comparable, V any](
+	ctx context.Context,
+	c *PermissionCache,
+	local *expirable.LRU[K, V],
+	key K,
+	redisKey string,
+	load func() (V, error),
+) (V, error) {
+	if value, ok := local.Get(key); ok {
+		metrics.IncPermissionCacheLookups("local", "hit")
+		return value, nil
+	}
+	metrics.IncPermissionCacheLookups("local", "miss")
+
+	generation := c.generation.Load()
+	addLocal := func(value V) {
+		if c.generation.Load() == generation {
+			local.Add(key, value)
+		}
+	}
+
+	var value V
+	versionedKey, err := c.versionedKey(ctx, redisKey)
+	if err == nil {
+		err = c.getRedis(ctx, versionedKey, &value)
+	}
+	switch {
+	case err == nil:
+		metrics.IncPermissionCacheLookups("redis", "hit")
+		addLocal(value)
+		return value, nil
+	case errors.Is(err, redis.Nil):
+		metrics.IncPermissionCacheLookups("redis", "miss")
+	default:
+		metrics.IncCacheErrors("get_permissions")
+		logger.Warn("permission cache: redis get failed", zap.String("key", redisKey), zap.Error(err))
+	}
+
+	value, err = load()
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	if versionedKey != "" {
+		c.setRedis(ctx, versionedKey, value)
+	}
+	addLocal(value)
+
+	return value, nil
+}
+
+// versionedKey returns the Redis key of the current version of the entry
+func (c *PermissionCache) versionedKey(ctx context.Context, key string) (string, error) {
+	start := time.Now()
+	version, err := c.client.Get(ctx, permissionVersionKeyPrefix+key).Result()
+	metrics.ObserveCacheGetDuration("get_permissions_version", time.Since(start).Seconds())
+	if errors.Is(err, redis.Nil) {
+		version = "0"
+	} else if err != nil {
+		return "", err
+	}
+	return key + ":v" + version, nil
+}
+
+func (c *PermissionCache) getRedis(ctx context.Context, key string, dest any) error {
+	start := time.Now()
+	data, err := c.client.Get(ctx, key).Bytes()
+	metrics.ObserveCacheGetDuration("get_permissions", time.Since(start).Seconds())
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("unmarshal cached permissions: %w", err)
+	}
+	return nil
+}
+
+func (c *PermissionCache) setRedis(ctx context.Context, key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	err = c.client.Set(ctx, key, data, c.ttl).Err()
+	metrics.ObserveCacheSetDuration("set_permissions", time.Since(start).Seconds())
+	if err != nil {
+		metrics.IncCacheErrors("set_permissions")
+		logger.Warn("permission cache: redis set failed", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func staffPermissionKey(staffID int64) string {
+	return permissionStaffKeyPrefix + strconv.FormatInt(staffID, 10)
+}
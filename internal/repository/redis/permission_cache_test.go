@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakePermissionLoader struct {
+	mu          sync.Mutex
+	permissions map[string][]string
+	overrides   map[int64]models.PermissionOverrides
+	calls       int
+	// afterLoad runs once the permissions are read, before they are returned
+	afterLoad func()
+}
+
+func newFakePermissionLoader() *fakePermissionLoader {
+	return &fakePermissionLoader{
+		permissions: map[string][]string{},
+		overrides:   map[int64]models.PermissionOverrides{},
+	}
+}
+
+func (l *fakePermissionLoader) GetPermissionOverrides(_ context.Context, staffID int64) (models.PermissionOverrides, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	return l.overrides[staffID], nil
+}
+
+func (l *fakePermissionLoader) GetSettingsPermissions(_ context.Context, role string) (models.SettingsPermissions, error) {
+	l.mu.Lock()
+	l.calls++
+	permissions, afterLoad := l.permissions[role], l.afterLoad
+	l.afterLoad = nil
+	l.mu.Unlock()
+
+	if afterLoad != nil {
+		afterLoad()
+	}
+	return models.SettingsPermissions{Role: role, Permissions: permissions}, nil
+}
+
+func (l *fakePermissionLoader) set(role string, permissions ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.permissions[role] = permissions
+}
+
+func (l *fakePermissionLoader) callCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls
+}
+
+func TestPermissionCache_CachesAndInvalidates(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+
+	loader := newFakePermissionLoader()
+	loader.set("manager_1", "boxes_view")
+
+	cache := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+
+	permissions, err := cache.Permissions(ctx, "manager_1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"boxes_view"}, permissions)
+
+	_, err = cache.Permissions(ctx, "manager_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loader.callCount(), "second lookup must be served from the cache")
+
+	// Another replica reads through Redis without hitting the loader
+	replica := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+	_, err = replica.Permissions(ctx, "manager_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loader.callCount())
+
+	loader.set("manager_1", "boxes_view", "boxes_edit")
+	require.NoError(t, cache.Invalidate(ctx, "manager_1"))
+
+	permissions, err = cache.Permissions(ctx, "manager_1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"boxes_view", "boxes_edit"}, permissions)
+	assert.Equal(t, 2, loader.callCount())
+}
+
+func TestPermissionCache_LoadRacingInvalidateIsNotCached(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+
+	loader := newFakePermissionLoader()
+	loader.set("manager_3", "boxes_view")
+
+	cache := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+	replica := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+
+	// The permissions change and are invalidated while the old ones are loaded
+	loader.afterLoad = func() {
+		loader.set("manager_3", "boxes_view", "boxes_edit")
+		require.NoError(t, cache.Invalidate(ctx, "manager_3"))
+	}
+	permissions, err := cache.Permissions(ctx, "manager_3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"boxes_view"}, permissions)
+
+	for _, c := range []*PermissionCache{cache, replica} {
+		permissions, err = c.Permissions(ctx, "manager_3")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"boxes_view", "boxes_edit"}, permissions, "the stale load must not be cached")
+	}
+}
+
+func TestPermissionCache_ListenDropsLocalCopy(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	loader := newFakePermissionLoader()
+	loader.set("manager_2", "bookings_view")
+
+	writer := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+	replica := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+	go replica.Listen(ctx)
+
+	_, err := replica.Permissions(ctx, "manager_2")
+	require.NoError(t, err)
+
+	loader.set("manager_2", "bookings_view", "bookings_edit")
+
+	// The subscription is asynchronous: keep publishing until the replica sees it
+	require.Eventually(t, func() bool {
+		require.NoError(t, writer.Invalidate(ctx, "manager_2"))
+		permissions, err := replica.Permissions(ctx, "manager_2")
+		require.NoError(t, err)
+		return len(permissions) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestPermissionCache_Overrides(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+
+	loader := newFakePermissionLoader()
+	loader.overrides[5] = models.PermissionOverrides{Granted: []string{"analytics:view"}}
+
+	cache := NewPermissionCache(client, loader, loader, 8, time.Minute, time.Minute)
+
+	overrides, err := cache.Overrides(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"analytics:view"}, []string(overrides.Granted))
+
+	_, err = cache.Overrides(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loader.callCount())
+
+	loader.mu.Lock()
+	loader.overrides[5] = models.PermissionOverrides{Denied: []string{"bookings:view"}}
+	loader.mu.Unlock()
+	require.NoError(t, cache.InvalidateOverrides(ctx, 5))
+
+	overrides, err = cache.Overrides(ctx, 5)
+	require.NoError(t, err)
+	assert.Empty(t, overrides.Granted)
+	assert.Equal(t, []string{"bookings:view"}, []string(overrides.Denied))
+}
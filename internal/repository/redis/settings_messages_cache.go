@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+const (
+	settingsMessagesKey        = "settings:messages"
+	settingsMessagesInvalidate = "settings:messages:invalidate"
+	settingsMessagesVersionKey = "settings:messages:version"
+
+	settingsMessagesLocalTTL = 30 * time.Second
+	settingsMessagesTTL      = 10 * time.Minute
+)
+
+// SettingsMessagesLoader reads the message templates from the source of truth
+type SettingsMessagesLoader interface {
+	GetSettings(ctx context.Context) (models.SettingsFormMessages, error)
+}
+
+// SettingsMessagesCache keeps the bot message templates in process in front
+// of Redis, the same way as PermissionCache: every update, reminder and
+// notification reads them, while they change only from the admin panel.
+// Templates loaded before an invalidation are not cached after it: see
+// PermissionCache for the versioned keys and the local generation.
+type SettingsMessagesCache struct {
+	client     *redis.Client
+	loader     SettingsMessagesLoader
+	local      *expirable.LRU[string, models.SettingsFormMessages]
+	generation atomic.Uint64
+}
+
+// NewSettingsMessagesCache creates a new SettingsMessagesCache
+func NewSettingsMessagesCache(client *redis.Client, loader SettingsMessagesLoader) *SettingsMessagesCache {
+	return &SettingsMessagesCache{
+		client: client,
+		loader: loader,
+		local:  expirable.NewLRU[string, models.SettingsFormMessages](1, nil, settingsMessagesLocalTTL),
+	}
+}
+
+// GetSettings returns the message templates
+func (c *SettingsMessagesCache) GetSettings(ctx context.Context) (models.SettingsFormMessages, error) {
+	if settings, ok := c.local.Get(settingsMessagesKey); ok {
+		return settings, nil
+	}
+
+	generation := c.generation.Load()
+	addLocal := func(settings models.SettingsFormMessages) {
+		if c.generation.Load() == generation {
+			c.local.Add(settingsMessagesKey, settings)
+		}
+	}
+
+	var settings models.SettingsFormMessages
+	key, err := c.versionedKey(ctx)
+	if err == nil {
+		err = c.getRedis(ctx, key, &settings)
+	}
+	switch {
+	case err == nil:
+		addLocal(settings)
+		return settings, nil
+	case errors.Is(err, redis.Nil):
+	default:
+		metrics.IncCacheErrors("get_settings_messages")
+		logger.Warn("settings messages cache: redis get failed", zap.Error(err))
+	}
+
+	settings, err = c.loader.GetSettings(ctx)
+	if err != nil {
+		return models.SettingsFormMessages{}, err
+	}
+
+	if key != "" {
+		c.setRedis(ctx, key, settings)
+	}
+	addLocal(settings)
+
+	return settings, nil
+}
+
+// Invalidate moves the templates to a new version in Redis and tells every
+// replica, including this one, to drop its local copy.
+func (c *SettingsMessagesCache) Invalidate(ctx context.Context) error {
+	c.purgeLocal()
+
+	if err := c.client.Incr(ctx, settingsMessagesVersionKey).Err(); err != nil {
+		metrics.IncCacheErrors("invalidate_settings_messages")
+		return fmt.Errorf("bump cached settings messages version: %w", err)
+	}
+	if err := c.client.Publish(ctx, settingsMessagesInvalidate, settingsMessagesKey).Err(); err != nil {
+		metrics.IncCacheErrors("invalidate_settings_messages")
+		return fmt.Errorf("publish settings messages invalidation: %w", err)
+	}
+	return nil
+}
+
+// Listen applies invalidations published by other replicas until the context
+// is cancelled.
+func (c *SettingsMessagesCache) Listen(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, settingsMessagesInvalidate)
+	defer func() { _ = pubsub.Close() }()
+
+	// Messages published while the subscription was down are lost: start clean
+	c.purgeLocal()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("settings messages cache listener stopped")
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			c.purgeLocal()
+			logger.Debug("settings messages cache invalidated")
+		}
+	}
+}
+
+func (c *SettingsMessagesCache) purgeLocal() {
+	c.generation.Add(1)
+	c.local.Purge()
+}
+
+// versionedKey returns the Redis key of the current version of the templates
+func (c *SettingsMessagesCache) versionedKey(ctx context.Context) (string, error) {
+	start := time.Now()
+	version, err := c.client.Get(ctx, settingsMessagesVersionKey).Result()
+	metrics.ObserveCacheGetDuration("get_settings_messages_version", time.Since(start).Seconds())
+	if errors.Is(err, redis.Nil) {
+		version = "0"
+	} else if err != nil {
+		return "", err
+	}
+	return settingsMessagesKey + ":v" + version, nil
+}
+
+func (c *SettingsMessagesCache) getRedis(ctx context.Context, key string, dest *models.SettingsFormMessages) error {
+	start := time.Now()
+	data, err := c.client.Get(ctx, key).Bytes()
+	metrics.ObserveCacheGetDuration("get_settings_messages", time.Since(start).Seconds())
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("unmarshal cached settings messages: %w", err)
+	}
+	return nil
+}
+
+func (c *SettingsMessagesCache) setRedis(ctx context.Context, key string, settings models.SettingsFormMessages) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	err = c.client.Set(ctx, key, data, settingsMessagesTTL).Err()
+	metrics.ObserveCacheSetDuration("set_settings_messages", time.Since(start).Seconds())
+	if err != nil {
+		metrics.IncCacheErrors("set_settings_messages")
+		logger.Warn("settings messages cache: redis set failed", zap.Error(err))
+	}
+}
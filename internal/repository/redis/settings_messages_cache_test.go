@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeMessagesLoader struct {
+	mu       sync.Mutex
+	settings models.SettingsFormMessages
+	calls    int
+}
+
+func (l *fakeMessagesLoader) GetSettings(context.Context) (models.SettingsFormMessages, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	return l.settings, nil
+}
+
+func (l *fakeMessagesLoader) setWelcome(text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.settings.WelcomeMessage = text
+}
+
+func (l *fakeMessagesLoader) callCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls
+}
+
+func TestSettingsMessagesCache_CachesAndInvalidates(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+
+	loader := &fakeMessagesLoader{}
+	loader.setWelcome("Привет, {{.GuestName}}")
+
+	cache := NewSettingsMessagesCache(client, loader)
+
+	settings, err := cache.GetSettings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Привет, {{.GuestName}}", settings.WelcomeMessage)
+
+	_, err = cache.GetSettings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loader.callCount(), "second lookup must be served from the cache")
+
+	// Another replica reads through Redis without hitting the loader
+	_, err = NewSettingsMessagesCache(client, loader).GetSettings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loader.callCount())
+
+	loader.setWelcome("Здравствуйте")
+	require.NoError(t, cache.Invalidate(ctx))
+
+	settings, err = cache.GetSettings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Здравствуйте", settings.WelcomeMessage)
+	assert.Equal(t, 2, loader.callCount())
+}
+
+func TestSettingsMessagesCache_ListenDropsLocalCopy(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	loader := &fakeMessagesLoader{}
+	loader.setWelcome("Привет")
+
+	writer := NewSettingsMessagesCache(client, loader)
+	replica := NewSettingsMessagesCache(client, loader)
+	go replica.Listen(ctx)
+
+	_, err := replica.GetSettings(ctx)
+	require.NoError(t, err)
+
+	loader.setWelcome("Здравствуйте")
+
+	// The subscription is asynchronous: keep publishing until the replica sees it
+	require.Eventually(t, func() bool {
+		require.NoError(t, writer.Invalidate(ctx))
+		settings, err := replica.GetSettings(ctx)
+		require.NoError(t, err)
+		return settings.WelcomeMessage == "Здравствуйте"
+	}, 5*time.Second, 50*time.Millisecond)
+}
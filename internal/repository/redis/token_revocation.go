@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yandex-development-1-team/go/internal/metrics"
+)
+
+const (
+	tokenVersionKeyPrefix = "auth:token_version:"
+	revokedTokenKeyPrefix = "auth:revoked_jti:"
+	usedTokenKeyPrefix    = "auth:used_jti:"
+	revokedSessionPrefix  = "auth:revoked_sid:"
+)
+
+// TokenRevocation invalidates access tokens before they expire. A single
+// token is denied by its jti until its expiry, the tokens of one device by
+// their session id; all tokens of a user are invalidated at once by bumping
+// the user's token version, which every access token carries.
+type TokenRevocation struct {
+	client *redis.Client
+}
+
+func NewTokenRevocation(client *redis.Client) *TokenRevocation {
+	return &TokenRevocation{client: client}
+}
+
+// Version returns the current token version of the user; zero if never bumped
+func (r *TokenRevocation) Version(ctx context.Context, userID int64) (int64, error) {
+	version, err := r.client.Get(ctx, tokenVersionKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		metrics.IncCacheErrors("get_token_version")
+		return 0, fmt.Errorf("get token version: %w", err)
+	}
+	return version, nil
+}
+
+// Revoke denies the token until it expires on its own
+func (r *TokenRevocation) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	if err := r.client.Set(ctx, revokedTokenKeyPrefix+jti, 1, ttl).Err(); err != nil {
+		metrics.IncCacheErrors("revoke_token")
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
+}
+
+// Consume marks a one-time token as used until it expires. It reports false
+// if the token was used before.
+func (r *TokenRevocation) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return false, nil
+	}
+	ok, err := r.client.SetNX(ctx, usedTokenKeyPrefix+jti, 1, ttl).Result()
+	if err != nil {
+		metrics.IncCacheErrors("consume_token")
+		return false, fmt.Errorf("consume token: %w", err)
+	}
+	return ok, nil
+}
+
+// RevokeSession denies every access token issued within the session. ttl is
+// the access token lifetime: older tokens have expired on their own.
+func (r *TokenRevocation) RevokeSession(ctx context.Context, sessionID int64, ttl time.Duration) error {
+	if sessionID == 0 || ttl <= 0 {
+		return nil
+	}
+	if err := r.client.Set(ctx, revokedSessionKey(sessionID), 1, ttl).Err(); err != nil {
+		metrics.IncCacheErrors("revoke_session")
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAll invalidates every access token issued to the user so far
+func (r *TokenRevocation) RevokeAll(ctx context.Context, userID int64) error {
+	if err := r.client.Incr(ctx, tokenVersionKey(userID)).Err(); err != nil {
+		metrics.IncCacheErrors("bump_token_version")
+		return fmt.Errorf("bump token version: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether the token or its session was denied, or the
+// token was issued before the user's last RevokeAll
+func (r *TokenRevocation) IsRevoked(ctx context.Context, userID int64, jti string, sessionID, version int64) (bool, error) {
+	pipe := r.client.Pipeline()
+	current := pipe.Get(ctx, tokenVersionKey(userID))
+	denied := pipe.Exists(ctx, revokedTokenKeyPrefix+jti, revokedSessionKey(sessionID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		metrics.IncCacheErrors("check_token_revocation")
+		return false, fmt.Errorf("check token revocation: %w", err)
+	}
+
+	if denied.Val() > 0 {
+		return true, nil
+	}
+
+	currentVersion, err := current.Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("parse token version: %w", err)
+	}
+	// A lost counter (e.g. flushed Redis) must not lock everyone out: only
+	// tokens older than the current version are revoked
+	return version < currentVersion, nil
+}
+
+func tokenVersionKey(userID int64) string {
+	return tokenVersionKeyPrefix + strconv.FormatInt(userID, 10)
+}
+
+func revokedSessionKey(sessionID int64) string {
+	return revokedSessionPrefix + strconv.FormatInt(sessionID, 10)
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRevocation_RevokeAll(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+	tokens := NewTokenRevocation(client)
+
+	version, err := tokens.Version(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+
+	revoked, err := tokens.IsRevoked(ctx, 7, "jti-1", 0, version)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, tokens.RevokeAll(ctx, 7))
+
+	revoked, err = tokens.IsRevoked(ctx, 7, "jti-1", 0, version)
+	require.NoError(t, err)
+	assert.True(t, revoked, "token issued before RevokeAll")
+
+	version, err = tokens.Version(ctx, 7)
+	require.NoError(t, err)
+	revoked, err = tokens.IsRevoked(ctx, 7, "jti-2", 0, version)
+	require.NoError(t, err)
+	assert.False(t, revoked, "token issued after RevokeAll")
+
+	revoked, err = tokens.IsRevoked(ctx, 8, "jti-3", 0, 0)
+	require.NoError(t, err)
+	assert.False(t, revoked, "other users are not affected")
+}
+
+func TestTokenRevocation_Revoke(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+	tokens := NewTokenRevocation(client)
+
+	require.NoError(t, tokens.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)))
+	require.NoError(t, tokens.Revoke(ctx, "jti-expired", time.Now().Add(-time.Minute)))
+
+	revoked, err := tokens.IsRevoked(ctx, 7, "jti-1", 0, 0)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = tokens.IsRevoked(ctx, 7, "jti-expired", 0, 0)
+	require.NoError(t, err)
+	assert.False(t, revoked, "an expired token is not stored")
+
+	ttl, err := client.TTL(ctx, revokedTokenKeyPrefix+"jti-1").Result()
+	require.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute, "denial lasts until the token expires")
+}
+
+func TestTokenRevocation_Consume(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+	tokens := NewTokenRevocation(client)
+
+	fresh, err := tokens.Consume(ctx, "challenge-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = tokens.Consume(ctx, "challenge-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, fresh, "a token is used once")
+
+	fresh, err = tokens.Consume(ctx, "challenge-2", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, fresh)
+}
+
+func TestTokenRevocation_RevokeSession(t *testing.T) {
+	_, client := newTestRepo(t)
+	ctx := context.Background()
+	tokens := NewTokenRevocation(client)
+
+	require.NoError(t, tokens.RevokeSession(ctx, 3, time.Minute))
+
+	revoked, err := tokens.IsRevoked(ctx, 7, "jti-1", 3, 0)
+	require.NoError(t, err)
+	assert.True(t, revoked, "every token of the session is denied")
+
+	revoked, err = tokens.IsRevoked(ctx, 7, "jti-2", 4, 0)
+	require.NoError(t, err)
+	assert.False(t, revoked, "other sessions are not affected")
+}
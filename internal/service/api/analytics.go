@@ -16,6 +16,7 @@ import (
 type AnalyticsQuerier interface {
 	GetBoxesAnalytics(ctx context.Context, dateFrom, dateTo *time.Time) ([]dto.AnalyticsBoxRow, error)
 	GetUsersAnalytics(ctx context.Context, dateFrom, dateTo *time.Time) ([]dto.AnalyticsUserRow, error)
+	GetSourcesAnalytics(ctx context.Context, dateFrom, dateTo *time.Time) ([]dto.AnalyticsSourceRow, error)
 }
 
 // ExportResult carries the generated file and its HTTP response metadata.
@@ -47,6 +48,12 @@ func (s *AnalyticsService) Export(ctx context.Context, req dto.AnalyticsExportRe
 			return ExportResult{}, err
 		}
 		return buildUsersFile(rows, req.Format)
+	case dto.ExportTypeSources:
+		rows, err := s.repo.GetSourcesAnalytics(ctx, req.DateFrom, req.DateTo)
+		if err != nil {
+			return ExportResult{}, err
+		}
+		return buildSourcesFile(rows, req.Format)
 	default:
 		return ExportResult{}, fmt.Errorf("unsupported export type: %s", req.Type)
 	}
@@ -59,9 +66,17 @@ var boxesHeaders = []string{
 
 var usersHeaders = []string{
 	"ID пользователя", "Имя", "Фамилия", "Email",
-	"Всего бронирований", "Дата регистрации",
+	"Всего бронирований", "Дата регистрации", "Источник",
+}
+
+var sourcesHeaders = []string{
+	"Источник", "Гостей", "Всего бронирований",
+	"Подтверждённых", "Отменённых",
 }
 
+// noSourceLabel stands for the bookings made without a src_ campaign
+const noSourceLabel = "без метки"
+
 func buildBoxesFile(rows []dto.AnalyticsBoxRow, format dto.ExportFormat) (ExportResult, error) {
 	if format == dto.ExportFormatCSV {
 		return csvResult("analytics_boxes.csv", boxesHeaders, func(w *csv.Writer) error {
@@ -104,6 +119,7 @@ func buildUsersFile(rows []dto.AnalyticsUserRow, format dto.ExportFormat) (Expor
 					r.Email,
 					strconv.FormatInt(r.TotalBookings, 10),
 					r.RegisteredAt.Format("2006-01-02"),
+					r.Source,
 				}); err != nil {
 					return err
 				}
@@ -120,10 +136,47 @@ func buildUsersFile(rows []dto.AnalyticsUserRow, format dto.ExportFormat) (Expor
 			_ = f.SetCellStr(sheet, excelCell(4, row), r.Email)
 			_ = f.SetCellInt(sheet, excelCell(5, row), r.TotalBookings)
 			_ = f.SetCellStr(sheet, excelCell(6, row), r.RegisteredAt.Format("2006-01-02"))
+			_ = f.SetCellStr(sheet, excelCell(7, row), r.Source)
 		}
 	})
 }
 
+func buildSourcesFile(rows []dto.AnalyticsSourceRow, format dto.ExportFormat) (ExportResult, error) {
+	if format == dto.ExportFormatCSV {
+		return csvResult("analytics_sources.csv", sourcesHeaders, func(w *csv.Writer) error {
+			for _, r := range rows {
+				if err := w.Write([]string{
+					sourceLabel(r.Source),
+					strconv.FormatInt(r.Guests, 10),
+					strconv.FormatInt(r.TotalBookings, 10),
+					strconv.FormatInt(r.ConfirmedBookings, 10),
+					strconv.FormatInt(r.CancelledBookings, 10),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return xlsxResult("Источники", "analytics_sources.xlsx", sourcesHeaders, func(f *excelize.File, sheet string) {
+		for i, r := range rows {
+			row := i + 2
+			_ = f.SetCellStr(sheet, excelCell(1, row), sourceLabel(r.Source))
+			_ = f.SetCellInt(sheet, excelCell(2, row), r.Guests)
+			_ = f.SetCellInt(sheet, excelCell(3, row), r.TotalBookings)
+			_ = f.SetCellInt(sheet, excelCell(4, row), r.ConfirmedBookings)
+			_ = f.SetCellInt(sheet, excelCell(5, row), r.CancelledBookings)
+		}
+	})
+}
+
+func sourceLabel(source string) string {
+	if source == "" {
+		return noSourceLabel
+	}
+	return source
+}
+
 func csvResult(filename string, headers []string, fill func(*csv.Writer) error) (ExportResult, error) {
 	data, err := buildCSV(headers, fill)
 	if err != nil {
@@ -16,9 +16,10 @@ import (
 )
 
 type mockAnalyticsQuerier struct {
-	boxes []dto.AnalyticsBoxRow
-	users []dto.AnalyticsUserRow
-	err   error
+	boxes   []dto.AnalyticsBoxRow
+	users   []dto.AnalyticsUserRow
+	sources []dto.AnalyticsSourceRow
+	err     error
 }
 
 func (m *mockAnalyticsQuerier) GetBoxesAnalytics(_ context.Context, _, _ *time.Time) ([]dto.AnalyticsBoxRow, error) {
@@ -29,6 +30,10 @@ func (m *mockAnalyticsQuerier) GetUsersAnalytics(_ context.Context, _, _ *time.T
 	return m.users, m.err
 }
 
+func (m *mockAnalyticsQuerier) GetSourcesAnalytics(_ context.Context, _, _ *time.Time) ([]dto.AnalyticsSourceRow, error) {
+	return m.sources, m.err
+}
+
 var (
 	sampleBoxes = []dto.AnalyticsBoxRow{
 		{ServiceID: 1, ServiceName: "Бокс А", TotalBookings: 10, ConfirmedBookings: 8, CancelledBookings: 2, CancellationRate: 20.00},
@@ -116,6 +121,27 @@ func TestAnalyticsService_Export_UsersCSV(t *testing.T) {
 	assert.Equal(t, "2026-01-10", records[1][5])
 }
 
+func TestAnalyticsService_Export_SourcesCSV(t *testing.T) {
+	svc := NewAnalyticsService(&mockAnalyticsQuerier{sources: []dto.AnalyticsSourceRow{
+		{Source: "vk", Guests: 4, TotalBookings: 6, ConfirmedBookings: 5, CancelledBookings: 1},
+		{Source: "", Guests: 2, TotalBookings: 2},
+	}})
+
+	result, err := svc.Export(context.Background(), dto.AnalyticsExportRequest{
+		Type:   dto.ExportTypeSources,
+		Format: dto.ExportFormatCSV,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "analytics_sources.csv", result.Filename)
+
+	records := parseCSV(t, result.Data)
+	require.Len(t, records, 3)
+	assert.Equal(t, sourcesHeaders, records[0])
+	assert.Equal(t, []string{"vk", "4", "6", "5", "1"}, records[1])
+	assert.Equal(t, noSourceLabel, records[2][0], "bookings without a campaign")
+}
+
 func TestAnalyticsService_Export_RepoErrorPropagated(t *testing.T) {
 	repoErr := errors.New("db unavailable")
 	svc := NewAnalyticsService(&mockAnalyticsQuerier{err: repoErr})
@@ -11,6 +11,7 @@ import (
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 )
 
 // APIBoxService implements HTTP API logic for boxed solutions.
@@ -18,16 +19,20 @@ type APIBoxService struct {
 	lister      repository.BoxSolutionRepository
 	fileService *FileService
 	txRepo      repository.TxRepository
+	webhooks    webhook.Publisher
 }
 
 // NewAPIBoxService creates a new instance of the box service.
+// webhooks may be nil, then no box events are published.
 func NewAPIBoxService(lister repository.BoxSolutionRepository,
 	fileService *FileService,
-	txRepo repository.TxRepository) *APIBoxService {
+	txRepo repository.TxRepository,
+	webhooks webhook.Publisher) *APIBoxService {
 	return &APIBoxService{
 		lister:      lister,
 		fileService: fileService,
 		txRepo:      txRepo,
+		webhooks:    webhooks,
 	}
 }
 
@@ -101,7 +106,14 @@ func (s *APIBoxService) Update(ctx context.Context, id int64, req *models.BoxUpd
 		}
 
 		svc, err = s.lister.GetServiceByID(txCtx, id)
-		return err
+		if err != nil {
+			return err
+		}
+
+		if s.webhooks != nil {
+			return s.webhooks.Publish(txCtx, webhook.EventBoxUpdated, webhook.NewBox(svc))
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -117,8 +129,23 @@ func (s *APIBoxService) Delete(ctx context.Context, id int64) error {
 
 // UpdateStatus updates the status of the box
 func (s *APIBoxService) UpdateStatus(ctx context.Context, id int64, status models.ServiceStatus) (*models.BoxUpdateStatusResult, error) {
+	var res *models.BoxUpdateStatusResult
+	err := s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		res, err = s.lister.UpdateServiceStatus(txCtx, id, status)
+		if err != nil {
+			return err
+		}
+		if s.webhooks == nil {
+			return nil
+		}
 
-	res, err := s.lister.UpdateServiceStatus(ctx, id, status)
+		svc, err := s.lister.GetServiceByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		return s.webhooks.Publish(txCtx, webhook.EventBoxUpdated, webhook.NewBox(svc))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +189,7 @@ func parseBoxSlots(slots []models.BoxAvailableSlot) (models.BoxNewSlots, error)
 			Date:      make([]time.Time, len(slots)),
 			StartTime: make([]time.Time, len(slots)),
 			EndTime:   make([]time.Time, len(slots)),
+			Capacity:  make([]int, len(slots)),
 		}
 		for i, t := range slots {
 			date, err := time.Parse("2006-01-02", t.Date)
@@ -179,6 +207,7 @@ func parseBoxSlots(slots []models.BoxAvailableSlot) (models.BoxNewSlots, error)
 			boxNewSlots.Date[i] = date
 			boxNewSlots.StartTime[i] = startTime
 			boxNewSlots.EndTime[i] = endTime
+			boxNewSlots.Capacity[i] = max(t.Capacity, 1)
 		}
 	}
 
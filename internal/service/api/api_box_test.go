@@ -57,7 +57,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Name: &newName,
@@ -88,7 +88,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Name: &newName,
@@ -131,7 +131,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Name:  &newName,
@@ -169,7 +169,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -189,7 +189,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -208,7 +208,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -227,7 +227,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{Name: &newName}
 		dbErr := errors.New("db error")
@@ -253,7 +253,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -287,7 +287,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -325,7 +325,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{Name: &newName}
 
@@ -354,7 +354,7 @@ func TestUpdate(t *testing.T) {
 
 		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
 		mockTxRepo := mocks.NewMockTxRepository(ctrl)
-		svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+		svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 		req := &models.BoxUpdate{
 			Slots: []models.BoxAvailableSlot{
@@ -366,6 +366,7 @@ func TestUpdate(t *testing.T) {
 			Date:      []time.Time{time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
 			StartTime: []time.Time{time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)},
 			EndTime:   []time.Time{time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC)},
+			Capacity:  []int{1},
 		}
 
 		mockTxRepo.EXPECT().
@@ -396,6 +397,70 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+// fakePublisher records the events or fails to queue them
+type fakePublisher struct {
+	events []string
+	err    error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event string, _ any) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestUpdateStatus(t *testing.T) {
+	serviceID := int64(1)
+	status := models.ServiceStatus("inactive")
+
+	newService := func(t *testing.T, publisher *fakePublisher) (*APIBoxService, *mocks.MockBoxSolutionRepository) {
+		ctrl := gomock.NewController(t)
+		mockLister := mocks.NewMockBoxSolutionRepository(ctrl)
+		mockTxRepo := mocks.NewMockTxRepository(ctrl)
+		mockTxRepo.EXPECT().
+			RunToTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+		return NewAPIBoxService(mockLister, nil, mockTxRepo, publisher), mockLister
+	}
+
+	t.Run("success - event queued with the status", func(t *testing.T) {
+		publisher := &fakePublisher{}
+		svc, mockLister := newService(t, publisher)
+
+		mockLister.EXPECT().
+			UpdateServiceStatus(gomock.Any(), serviceID, status).
+			Return(&models.BoxUpdateStatusResult{ID: serviceID, Status: string(status)}, nil)
+		mockLister.EXPECT().
+			GetServiceByID(gomock.Any(), serviceID).
+			Return(&models.Service{ID: serviceID, Status: string(status)}, nil)
+
+		res, err := svc.UpdateStatus(context.Background(), serviceID, status)
+		require.NoError(t, err)
+		assert.Equal(t, string(status), res.Status)
+		assert.Len(t, publisher.events, 1)
+	})
+
+	t.Run("failed event fails the update", func(t *testing.T) {
+		queueErr := errors.New("queue error")
+		svc, mockLister := newService(t, &fakePublisher{err: queueErr})
+
+		mockLister.EXPECT().
+			UpdateServiceStatus(gomock.Any(), serviceID, status).
+			Return(&models.BoxUpdateStatusResult{ID: serviceID, Status: string(status)}, nil)
+		mockLister.EXPECT().
+			GetServiceByID(gomock.Any(), serviceID).
+			Return(&models.Service{ID: serviceID, Status: string(status)}, nil)
+
+		res, err := svc.UpdateStatus(context.Background(), serviceID, status)
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, queueErr, "the transaction must roll back with the event")
+	})
+}
+
 func TestExport_PDF(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -408,7 +473,7 @@ func TestExport_PDF(t *testing.T) {
 		Return(fakeServices, nil)
 
 	mockTxRepo := mocks.NewMockTxRepository(ctrl)
-	svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+	svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 	data, contentType, err := svc.Export(context.Background(), "active", "pdf")
 
@@ -438,7 +503,7 @@ func TestExport_CSV(t *testing.T) {
 		Return(fakeServices, nil)
 
 	mockTxRepo := mocks.NewMockTxRepository(ctrl)
-	svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+	svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 	data, contentType, err := svc.Export(context.Background(), "", "csv")
 
@@ -486,7 +551,7 @@ func TestExport_DefaultFormat(t *testing.T) {
 		Return(fakeServices, nil)
 
 	mockTxRepo := mocks.NewMockTxRepository(ctrl)
-	svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+	svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 	// Передаём невалидный формат — должен вернуть pdf
 	_, contentType, err := svc.Export(context.Background(), "", "xml")
@@ -510,7 +575,7 @@ func TestExport_ListerError(t *testing.T) {
 		Return(nil, errors.New("db error"))
 
 	mockTxRepo := mocks.NewMockTxRepository(ctrl)
-	svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+	svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 	_, _, err := svc.Export(context.Background(), "active", "pdf")
 	if err == nil {
@@ -529,7 +594,7 @@ func TestExport_EmptyServices(t *testing.T) {
 		Return([]models.Service{}, nil)
 
 	mockTxRepo := mocks.NewMockTxRepository(ctrl)
-	svc := NewAPIBoxService(mockLister, nil, mockTxRepo)
+	svc := NewAPIBoxService(mockLister, nil, mockTxRepo, nil)
 
 	data, contentType, err := svc.Export(context.Background(), "", "pdf")
 
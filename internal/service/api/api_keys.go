@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	// APIKeyPrefix tells an API key from a JWT in the Authorization header
+	APIKeyPrefix = "ak_"
+
+	apiKeySize        = 32
+	apiKeyShownPrefix = len(APIKeyPrefix) + 8
+	// apiKeyTouchPeriod limits how often last use is written for a busy key
+	apiKeyTouchPeriod = time.Minute
+)
+
+// APIKeyService issues the API keys of external systems and checks them on
+// requests. A key grants exactly its scopes, taken from models.MapPermissions.
+type APIKeyService struct {
+	repo repository.APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{repo: repo, now: time.Now}
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *APIKeyService) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Create issues a key and returns it along with the stored record. The key
+// is not kept anywhere, so it cannot be shown again.
+func (s *APIKeyService) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, string, error) {
+	scopes, err := normalizeScopes(key.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	key.Scopes = scopes
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()) {
+		return nil, "", fmt.Errorf("%w: expiry is in the past", models.ErrInvalidInput)
+	}
+
+	b := make([]byte, apiKeySize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("generate api key: %w", err)
+	}
+	plain := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	key.Prefix = plain[:apiKeyShownPrefix]
+	key.KeyHash = hashAPIKey(plain)
+
+	created, err := s.repo.Create(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, plain, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id int64) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Authenticate returns the active key matching plain. Unknown, revoked and
+// expired keys are all ErrUnauthorized, so the caller learns nothing about them.
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*models.APIKey, error) {
+	if !strings.HasPrefix(plain, APIKeyPrefix) {
+		return nil, models.ErrUnauthorized
+	}
+
+	key, err := s.repo.GetByHash(ctx, hashAPIKey(plain))
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			return nil, models.ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	if !key.Active(now) {
+		return nil, models.ErrUnauthorized
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchPeriod {
+		if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+			logger.Warn("failed to record api key use", zap.Int64("api_key_id", key.ID), zap.Error(err))
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
+
+// normalizeScopes checks the scopes against the known permissions and drops duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: no scopes", models.ErrUnknownPermission)
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !models.MapPermissions[scope] {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownPermission, scope)
+		}
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeAPIKeyRepo struct {
+	keys    map[int64]*models.APIKey
+	touches int
+}
+
+func (r *fakeAPIKeyRepo) Create(_ context.Context, key *models.APIKey) (*models.APIKey, error) {
+	stored := *key
+	stored.ID = int64(len(r.keys) + 1)
+	r.keys[stored.ID] = &stored
+	return &stored, nil
+}
+
+func (r *fakeAPIKeyRepo) GetByID(_ context.Context, id int64) (*models.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, models.ErrAPIKeyNotFound
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	for id, key := range r.keys {
+		if key.KeyHash == hash {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, models.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) List(_ context.Context) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(_ context.Context, id int64) error {
+	key, ok := r.keys[id]
+	if !ok {
+		return models.ErrAPIKeyNotFound
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) Touch(_ context.Context, id int64, usedAt time.Time) error {
+	r.touches++
+	r.keys[id].LastUsedAt = &usedAt
+	return nil
+}
+
+func newTestAPIKeyService() (*APIKeyService, *fakeAPIKeyRepo) {
+	repo := &fakeAPIKeyRepo{keys: map[int64]*models.APIKey{}}
+	return NewAPIKeyService(repo), repo
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	ctx := context.Background()
+
+	_, _, err := svc.Create(ctx, &models.APIKey{Name: "crm", Scopes: []string{"bookings:fly"}})
+	assert.ErrorIs(t, err, models.ErrUnknownPermission)
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err = svc.Create(ctx, &models.APIKey{Name: "crm", Scopes: []string{models.PermBookingsView}, ExpiresAt: &past})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+	key, plain, err := svc.Create(ctx, &models.APIKey{
+		Name:   "crm",
+		Scopes: []string{models.PermBookingsView, models.PermBookingsView},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(plain, key.Prefix))
+	assert.Equal(t, []string{models.PermBookingsView}, key.Scopes, "duplicate scopes are dropped")
+
+	stored := repo.keys[key.ID]
+	assert.NotEqual(t, plain, stored.KeyHash, "only the hash is stored")
+	assert.Equal(t, hashAPIKey(plain), stored.KeyHash)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	svc, repo := newTestAPIKeyService()
+	ctx := context.Background()
+
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	expires := now.Add(time.Hour)
+	key, plain, err := svc.Create(ctx, &models.APIKey{Name: "crm", Scopes: []string{models.PermBookingsView}, ExpiresAt: &expires})
+	require.NoError(t, err)
+
+	got, err := svc.Authenticate(ctx, plain)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, got.ID)
+	assert.True(t, got.HasScope(models.PermBookingsView))
+	assert.False(t, got.HasScope(models.PermBookingsEdit))
+
+	_, err = svc.Authenticate(ctx, plain)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.touches, "last use is written at most once per period")
+
+	_, err = svc.Authenticate(ctx, plain+"x")
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+	_, err = svc.Authenticate(ctx, "not-a-key")
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+
+	svc.now = func() time.Time { return expires }
+	_, err = svc.Authenticate(ctx, plain)
+	assert.ErrorIs(t, err, models.ErrUnauthorized, "an expired key is rejected")
+
+	svc.now = func() time.Time { return now }
+	require.NoError(t, svc.Revoke(ctx, key.ID))
+	_, err = svc.Authenticate(ctx, plain)
+	assert.ErrorIs(t, err, models.ErrUnauthorized, "a revoked key is rejected")
+}
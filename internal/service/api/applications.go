@@ -9,17 +9,20 @@ import (
 	"github.com/yandex-development-1-team/go/internal/ctxutil"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 )
 
 type ApplicationsService struct {
-	repo   repository.ApplicationRepository
-	txRepo repository.TxRepository
+	repo     repository.ApplicationRepository
+	txRepo   repository.TxRepository
+	webhooks webhook.Publisher
 }
 
-func NewApplicationsService(repo repository.ApplicationRepository, txRepo repository.TxRepository) *ApplicationsService {
+func NewApplicationsService(repo repository.ApplicationRepository, txRepo repository.TxRepository, webhooks webhook.Publisher) *ApplicationsService {
 	return &ApplicationsService{
-		repo:   repo,
-		txRepo: txRepo,
+		repo:     repo,
+		txRepo:   txRepo,
+		webhooks: webhooks,
 	}
 }
 
@@ -31,7 +34,21 @@ func (s *ApplicationsService) Create(ctx context.Context, req *models.Applicatio
 
 	req.ContactInfo = normalized_nickname
 
-	return s.repo.CreateApplication(ctx, req)
+	// The event is queued in the same transaction, so it is never lost
+	return s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		if err := s.repo.CreateApplication(txCtx, req); err != nil {
+			return err
+		}
+		if s.webhooks == nil {
+			return nil
+		}
+
+		app, err := s.repo.GetApplicationByID(txCtx, req.ID)
+		if err != nil {
+			return err
+		}
+		return s.webhooks.Publish(txCtx, webhook.EventApplicationCreated, webhook.NewApplication(app, ""))
+	})
 }
 
 func (s *ApplicationsService) GetApplicationByID(ctx context.Context, id int64) (*models.Application, error) {
@@ -51,15 +68,29 @@ func (s *ApplicationsService) UpdateApplicationStatus(ctx context.Context, id in
 
 	txCtx := ctxutil.WithTx(ctx, tx)
 
+	var prev *models.Application
+	prev, err = s.repo.GetApplicationByID(txCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = s.repo.UpdateApplicationStatus(txCtx, id, status); err != nil {
 		return nil, err
 	}
 
-	app, err := s.repo.GetApplicationByID(txCtx, id)
+	var app *models.Application
+	app, err = s.repo.GetApplicationByID(txCtx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.webhooks != nil && prev.Status != app.Status {
+		err = s.webhooks.Publish(txCtx, webhook.EventApplicationStatusChanged, webhook.NewApplication(app, prev.Status))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// AuditService reads the audit log of the admin API.
+type AuditService struct {
+	repo repository.AuditLogRepository
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(repo repository.AuditLogRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// List returns the entries matching the filter, newest first
+func (s *AuditService) List(ctx context.Context, filter models.AuditFilter) (*models.AuditList, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, models.ErrInvalidInput
+	}
+	return s.repo.List(ctx, filter)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -14,8 +15,10 @@ import (
 	"github.com/go-mail/mail/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
 )
@@ -28,8 +31,6 @@ const (
 	RoleUser     = "user"
 )
 
-var Roles = []string{RoleAdmin, RoleManager1, RoleManager2, RoleManager3}
-
 // HashPassword hashes a password for storage (e.g. when creating/updating users).
 func HashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -40,47 +41,104 @@ func HashPassword(password string) (string, error) {
 }
 
 type AuthService struct {
-	db         *sqlx.DB
-	rtRepo     repository.RefreshTokenRepository
-	prRepo     repository.PasswordResetRepository
-	staffRepo  repository.StaffRepository
-	emailSvc   *EmailService
-	JwtSecret  []byte
-	accessTTL  time.Duration
-	refreshTTL time.Duration
-	txRepo     repository.TxRepository
+	db           *sqlx.DB
+	rtRepo       repository.RefreshTokenRepository
+	prRepo       repository.PasswordResetRepository
+	staffRepo    repository.StaffRepository
+	emailSvc     *EmailService
+	JwtSecret    []byte
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+	txRepo       repository.TxRepository
+	perms        *PermissionService
+	tokens       repository.TokenRevocation
+	mfa          *MFAService
+	registration models.RegistrationPolicy
+	guard        *LoginGuard
+	keys         *JWTKeys
 }
 
+// AccessClaims carry the token id (jti) and the token version of the user, so
+// that a token can be revoked before it expires. MFAPending marks a user whose
+// role requires a second factor that is not enrolled yet.
 type AccessClaims struct {
+	UserID     int64  `json:"user_id"`
+	Role       string `json:"role"`
+	SessionID  int64  `json:"sid,omitempty"`
+	Version    int64  `json:"ver,omitempty"`
+	MFAPending bool   `json:"mfa_pending,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// MFAChallengeClaims identify a user who passed the password check and still
+// has to provide the second factor
+type MFAChallengeClaims struct {
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// MFAChallengeTTL is how long the user has to enter the code after the password
+const MFAChallengeTTL = 5 * time.Minute
+
+// EmailVerificationClaims confirm that the email belongs to the staff member;
+// a link sent to an address changed since then is not accepted
+type EmailVerificationClaims struct {
 	UserID int64  `json:"user_id"`
-	Role   string `json:"role"`
+	Email  string `json:"email"`
 	jwt.RegisteredClaims
 }
 
+// EmailVerificationTTL is how long the verification link works
+const EmailVerificationTTL = 24 * time.Hour
+
 type ResetPasswordClaims struct {
 	UserID int64 `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
 func NewAuthService(db *sqlx.DB, rtRepo repository.RefreshTokenRepository, prRepo repository.PasswordResetRepository, staffRepo repository.StaffRepository,
-	emailSvc *EmailService, txRepo repository.TxRepository, jwtSecret string, accessTTLMinutes, refreshTTlDays int) *AuthService {
+	emailSvc *EmailService, txRepo repository.TxRepository, jwtSecret string, accessTTLMinutes, refreshTTlDays int, perms *PermissionService, tokens repository.TokenRevocation, mfa *MFAService,
+	registration models.RegistrationPolicy, guard *LoginGuard, keys *JWTKeys) *AuthService {
+	if keys == nil {
+		keys = newHMACKeys([]byte(jwtSecret))
+	}
 	return &AuthService{
-		db:         db,
-		rtRepo:     rtRepo,
-		prRepo:     prRepo,
-		staffRepo:  staffRepo,
-		emailSvc:   emailSvc,
-		JwtSecret:  []byte(jwtSecret),
-		txRepo:     txRepo,
-		accessTTL:  time.Duration(accessTTLMinutes) * time.Minute,
-		refreshTTL: time.Duration(refreshTTlDays) * time.Hour * 24,
+		db:           db,
+		rtRepo:       rtRepo,
+		prRepo:       prRepo,
+		staffRepo:    staffRepo,
+		emailSvc:     emailSvc,
+		JwtSecret:    []byte(jwtSecret),
+		txRepo:       txRepo,
+		accessTTL:    time.Duration(accessTTLMinutes) * time.Minute,
+		refreshTTL:   time.Duration(refreshTTlDays) * time.Hour * 24,
+		perms:        perms,
+		tokens:       tokens,
+		mfa:          mfa,
+		registration: registration,
+		guard:        guard,
+		keys:         keys,
 	}
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (*models.AuthResult, error) {
+// Keys returns the keys access tokens are signed and verified with
+func (s *AuthService) Keys() *JWTKeys {
+	return s.keys
+}
+
+// Login checks the password. A locked out email or IP gets the same
+// *models.LockoutError whether the password is right or not.
+func (s *AuthService) Login(ctx context.Context, email, password string, client models.ClientInfo) (*models.AuthResult, error) {
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, models.GuardedActionLogin, email, client.IP); err != nil {
+			return nil, err
+		}
+	}
+
 	authInfo, err := s.staffRepo.GetUserByEmail(ctx, email)
 	if errors.Is(err, models.ErrUserNotFound) {
-		return nil, models.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, email, client)
 	}
 	if err != nil {
 		return nil, err
@@ -91,23 +149,119 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(authInfo.PassHash), []byte(password)); err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, bcrypt.ErrHashTooShort) {
-			return nil, models.ErrInvalidCredentials
+			return nil, s.loginFailed(ctx, email, client)
 		}
 		return nil, fmt.Errorf("compare password hash: %w", err)
 	}
-	token, err := s.generateAccessToken(user.ID, user.Role)
+	if s.guard != nil {
+		s.guard.Reset(ctx, models.GuardedActionLogin, email)
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, models.ErrEmailNotVerified
+	}
+
+	if s.mfa != nil {
+		mfa, err := s.mfa.State(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if mfa.Enabled() {
+			challenge, err := s.generateMFAChallenge(user.ID, user.Email)
+			if err != nil {
+				return nil, err
+			}
+			return &models.AuthResult{MFAToken: challenge}, nil
+		}
+	}
+
+	return s.issueTokens(ctx, user, client)
+}
+
+// loginFailed counts the failed attempt; the one that runs over the limit
+// already gets the lockout
+func (s *AuthService) loginFailed(ctx context.Context, email string, client models.ClientInfo) error {
+	if s.guard != nil {
+		if err := s.guard.Attempt(ctx, models.GuardedActionLogin, email, client.IP); err != nil {
+			return err
+		}
+	}
+	return models.ErrInvalidCredentials
+}
+
+// ClearLockout lets an admin lift the lockout of an email and/or an IP
+func (s *AuthService) ClearLockout(ctx context.Context, actorID int64, email, ip string) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.Clear(ctx, actorID, email, ip)
+}
+
+// VerifyMFA exchanges the challenge of Login and a TOTP or recovery code for
+// the token pair. A challenge takes one code: after a wrong one the user logs
+// in again, and wrong codes count towards the lockout of the user and the IP.
+func (s *AuthService) VerifyMFA(ctx context.Context, challenge, code string, client models.ClientInfo) (*models.AuthResult, error) {
+	if s.mfa == nil {
+		return nil, models.ErrUnauthorized
+	}
+	claims, err := s.parseMFAChallenge(challenge)
 	if err != nil {
 		return nil, err
 	}
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, models.GuardedActionMFA, claims.Email, client.IP); err != nil {
+			return nil, err
+		}
+	}
+	if s.tokens != nil {
+		fresh, err := s.tokens.Consume(ctx, claims.ID, claims.ExpiresAt.Time)
+		if err != nil {
+			return nil, err
+		}
+		if !fresh {
+			return nil, models.ErrUnauthorized
+		}
+	}
 
-	err = s.rtRepo.DeleteByStaffID(ctx, user.ID)
+	authInfo, err := s.staffRepo.GetUserByEmail(ctx, claims.Email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil, models.ErrUnauthorized
+	}
 	if err != nil {
 		return nil, err
 	}
+	user := authInfo.User
+	if user.ID != claims.UserID {
+		return nil, models.ErrUnauthorized
+	}
+	if user.Status == "blocked" {
+		return nil, models.ErrUserBlocked
+	}
 
-	refreshToken := generateRandomToken()
-	expiresAt := time.Now().Add(s.refreshTTL)
-	if err := s.rtRepo.CreateRefreshToken(ctx, user.ID, refreshToken, expiresAt); err != nil {
+	if err := s.mfa.Verify(ctx, user.ID, code); err != nil {
+		if errors.Is(err, models.ErrMFAInvalidCode) && s.guard != nil {
+			if lockErr := s.guard.Attempt(ctx, models.GuardedActionMFA, claims.Email, client.IP); lockErr != nil {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+	if s.guard != nil {
+		s.guard.Reset(ctx, models.GuardedActionMFA, claims.Email)
+	}
+
+	return s.issueTokens(ctx, user, client)
+}
+
+// issueTokens opens a session for the authenticated user
+func (s *AuthService) issueTokens(ctx context.Context, user *models.UserAPI, client models.ClientInfo) (*models.AuthResult, error) {
+	// Other devices of the user stay logged in
+	sessionID, refreshToken, err := s.startSession(ctx, user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateAccessToken(ctx, user.ID, user.Role, sessionID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -115,96 +269,280 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 		User:         user,
 		Token:        token,
 		RefreshToken: refreshToken,
+		Permissions:  s.effectivePermissions(ctx, user),
 	}, nil
 }
 
-func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.RefreshResponse, error) {
-	var accessToken string
-	var newRT *models.RefreshToken
+// effectivePermissions lists what the frontend may show to the user. Access is
+// still checked on every request, so a lookup failure only hides controls.
+func (s *AuthService) effectivePermissions(ctx context.Context, user *models.UserAPI) []string {
+	if s.perms == nil {
+		return []string{}
+	}
+	permissions, err := s.perms.Effective(ctx, user.ID, user.Role)
+	if err != nil {
+		logger.Warn("failed to resolve permissions on login", zap.Int64("user_id", user.ID), zap.Error(err))
+		return []string{}
+	}
+	return permissions
+}
+
+// Refresh rotates the refresh token within its session. A token that was
+// already rotated means it leaked: the whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client models.ClientInfo) (*models.RefreshResponse, error) {
+	var accessToken, newToken string
+	var reusedSessionID int64
 
 	err := s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
-		rt, err := s.rtRepo.GetForUpdate(txCtx, refreshToken)
+		rt, err := s.rtRepo.GetForUpdate(txCtx, hashRefreshToken(refreshToken))
 		if err != nil {
 			return err
 		}
+		if rt.RotatedAt != nil {
+			reusedSessionID = rt.SessionID
+			return models.ErrRefreshTokenReused
+		}
 
-		accessToken, err = s.generateAccessToken(rt.UserID, rt.Role)
+		accessToken, err = s.generateAccessToken(txCtx, rt.UserID, rt.Role, rt.SessionID)
 		if err != nil {
 			return err
 		}
 
-		newRT = &models.RefreshToken{
-			UserID:    rt.UserID,
-			Token:     generateRandomToken(),
-			ExpiresAt: time.Now().Add(s.refreshTTL),
-		}
-		if err := s.rtRepo.Create(txCtx, newRT); err != nil {
+		if err := s.rtRepo.MarkRotated(txCtx, rt.ID); err != nil {
 			return err
 		}
 
-		if err := s.rtRepo.DeleteByToken(txCtx, refreshToken); err != nil {
+		newToken = generateRandomToken()
+		expiresAt := time.Now().Add(s.refreshTTL)
+		if err := s.rtRepo.Create(txCtx, &models.RefreshToken{
+			UserID:    rt.UserID,
+			SessionID: rt.SessionID,
+			TokenHash: hashRefreshToken(newToken),
+			ExpiresAt: expiresAt,
+		}); err != nil {
 			return err
 		}
 
-		return nil
+		return s.rtRepo.TouchSession(txCtx, rt.SessionID, client, expiresAt)
 	})
+	if errors.Is(err, models.ErrRefreshTokenReused) {
+		logger.Warn("refresh token reuse detected, revoking session",
+			zap.Int64("session_id", reusedSessionID), zap.String("ip", client.IP))
+		if delErr := s.rtRepo.DeleteSession(ctx, reusedSessionID); delErr != nil {
+			logger.Error("failed to revoke session", zap.Int64("session_id", reusedSessionID), zap.Error(delErr))
+		}
+		if denyErr := s.denySessionTokens(ctx, reusedSessionID); denyErr != nil {
+			logger.Error("failed to deny session access tokens", zap.Int64("session_id", reusedSessionID), zap.Error(denyErr))
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.RefreshResponse{
 		Token:        accessToken,
-		RefreshToken: newRT.Token,
+		RefreshToken: newToken,
 	}, nil
 }
 
-func (s *AuthService) Register(ctx context.Context, user *models.UserAPI, password string) (*models.AuthResult, error) {
+// Register signs up a staff member according to the registration policy.
+// With an invite the member is logged in right away; a member registered by
+// the email domain gets no tokens until the email is verified.
+func (s *AuthService) Register(ctx context.Context, user *models.UserAPI, password string, client models.ClientInfo) (*models.AuthResult, error) {
+	switch s.registration.Mode {
+	case models.RegistrationInviteOnly:
+		if user.InviteToken == "" {
+			return nil, models.ErrInvalidInvite
+		}
+	case models.RegistrationDomains:
+		if !s.registration.AllowsEmail(user.Email) {
+			return nil, models.ErrEmailDomainNotAllowed
+		}
+	default:
+		return nil, models.ErrRegistrationDisabled
+	}
+
 	hashPassword, err := HashPassword(password)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken := generateRandomToken()
-	expiresAt := time.Now().Add(s.refreshTTL)
-
-	var accessToken string
-	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
-
-		user, err = s.staffRepo.CreateStaff(txCtx, user, hashPassword)
+	if s.registration.Mode == models.RegistrationInviteOnly {
+		invited, err := s.staffRepo.RegisterInvited(ctx, user.InviteToken, user, hashPassword)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return s.issueTokens(ctx, invited, client)
+	}
 
-		err = s.rtRepo.CreateRefreshToken(txCtx, user.ID, refreshToken, expiresAt)
-		if err != nil {
-			return err
-		}
+	created, err := s.staffRepo.CreateStaff(ctx, user, hashPassword)
+	if err != nil {
+		return nil, err
+	}
 
-		// accessToken
-		accessToken, err = s.generateAccessToken(user.ID, user.Role)
-		if err != nil {
-			return err
-		}
+	// The account exists anyway: the link can be requested again
+	if err := s.sendVerification(ctx, created); err != nil {
+		logger.Error("failed to send verification email", zap.Int64("user_id", created.ID), zap.Error(err))
+	}
+	return &models.AuthResult{User: created}, nil
+}
+
+// VerifyEmail confirms the email from the verification link and activates
+// the staff member
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) (*models.UserAPI, error) {
+	claims, err := s.parseEmailVerification(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.staffRepo.VerifyEmail(ctx, claims.UserID, claims.Email)
+}
 
+// ResendVerification sends a new verification link. Like ForgotPassword it
+// does not tell whether the email is registered.
+func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+	authInfo, err := s.staffRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, models.ErrUserNotFound) {
 		return nil
-	})
+	}
+	if err != nil {
+		return err
+	}
+	if authInfo.User.EmailVerifiedAt != nil || authInfo.User.Status == "blocked" {
+		return nil
+	}
+	return s.sendVerification(ctx, authInfo.User)
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, user *models.UserAPI) error {
+	if s.emailSvc == nil {
+		return nil
+	}
+	expiresAt := time.Now().Add(EmailVerificationTTL)
+	token, err := s.generateEmailVerification(user.ID, user.Email, expiresAt)
+	if err != nil {
+		return err
+	}
+	return s.emailSvc.SendVerificationEmail(ctx, user.Email, user.Name, token, expiresAt)
+}
+
+// AcceptInvite sets the password of a staff member invited by an admin,
+// activates them and logs them in
+func (s *AuthService) AcceptInvite(ctx context.Context, inviteToken, password string, client models.ClientInfo) (*models.AuthResult, error) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	user, err := s.staffRepo.AcceptInvite(ctx, inviteToken, hash)
 	if err != nil {
 		return nil, err
 	}
 
-	// в случае успеха коммит и возвращаем User
-	return &models.AuthResult{
-		User:         user,
-		Token:        accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return s.issueTokens(ctx, user, client)
+}
+
+// Logout ends the session the refresh token belongs to and, when given, denies
+// the access token of the request
+func (s *AuthService) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	if err := s.rtRepo.DeleteSessionByToken(ctx, hashRefreshToken(refreshToken)); err != nil {
+		return err
+	}
+
+	if s.tokens == nil || accessToken == "" {
+		return nil
+	}
+	claims, err := s.ParseAccessToken(accessToken)
+	if err != nil || claims.ExpiresAt == nil {
+		// An invalid or expired token grants nothing: there is nothing to deny
+		return nil
+	}
+	return s.tokens.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// RevokeAccessTokens invalidates every access token of the user at once
+func (s *AuthService) RevokeAccessTokens(ctx context.Context, userID int64) error {
+	if s.tokens == nil {
+		return nil
+	}
+	return s.tokens.RevokeAll(ctx, userID)
+}
+
+// ParseAccessToken checks the signature and expiry of an access token
+func (s *AuthService) ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, s.keys.Keyfunc)
+	if err != nil || !token.Valid {
+		return nil, models.ErrUnauthorized
+	}
+
+	claims, ok := token.Claims.(*AccessClaims)
+	if !ok {
+		return nil, models.ErrUnauthorized
+	}
+	return claims, nil
+}
+
+// Sessions returns the active sessions of the user
+func (s *AuthService) Sessions(ctx context.Context, userID int64) ([]models.AuthSession, error) {
+	return s.rtRepo.ListSessions(ctx, userID)
+}
+
+// RevokeSession ends a session of the user and denies the access tokens the
+// device still holds; sessions of others are not found
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID int64) error {
+	if err := s.rtRepo.DeleteUserSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	return s.denySessionTokens(ctx, sessionID)
 }
 
-func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	return s.rtRepo.DeleteByToken(ctx, refreshToken)
+// denySessionTokens denies the outstanding access tokens of a deleted session
+func (s *AuthService) denySessionTokens(ctx context.Context, sessionID int64) error {
+	if s.tokens == nil {
+		return nil
+	}
+	return s.tokens.RevokeSession(ctx, sessionID, s.accessTTL)
+}
+
+// startSession opens a session for the device and issues its first refresh token
+func (s *AuthService) startSession(ctx context.Context, userID int64, client models.ClientInfo) (int64, string, error) {
+	expiresAt := time.Now().Add(s.refreshTTL)
+	session, err := s.rtRepo.CreateSession(ctx, &models.AuthSession{
+		UserID:    userID,
+		Device:    client.Device,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	refreshToken := generateRandomToken()
+	if err := s.rtRepo.Create(ctx, &models.RefreshToken{
+		UserID:    userID,
+		SessionID: session.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return 0, "", err
+	}
+
+	return session.ID, refreshToken, nil
 }
 
-func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+// ForgotPassword sends a reset link. Every request counts as an attempt: it
+// sends an email, whether the address is registered or not.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string, client models.ClientInfo) error {
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, models.GuardedActionForgotPassword, email, client.IP); err != nil {
+			return err
+		}
+		if err := s.guard.Attempt(ctx, models.GuardedActionForgotPassword, email, client.IP); err != nil {
+			return err
+		}
+	}
+
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 || !domainHasMX(parts[1]) {
 		return models.ErrInvalidEmail
@@ -273,7 +611,8 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 		return fmt.Errorf("hash password: %w", err)
 	}
 
-	return s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+	var userID int64
+	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
 		prt, err := s.prRepo.GetToken(txCtx, token)
 		if err != nil {
 			if errors.Is(err, models.ErrTokenNotFound) {
@@ -294,24 +633,132 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 			return err
 		}
 
+		userID = prt.UserID
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// The password may have leaked: end every session and access token
+	if err := s.rtRepo.DeleteByStaffID(ctx, userID); err != nil {
+		return fmt.Errorf("end sessions: %w", err)
+	}
+	return s.RevokeAccessTokens(ctx, userID)
 }
 
-func (s *AuthService) generateAccessToken(userID int64, role string) (string, error) {
+func (s *AuthService) generateAccessToken(ctx context.Context, userID int64, role string, sessionID int64) (string, error) {
 	now := time.Now().UTC()
 
+	var version int64
+	if s.tokens != nil {
+		var err error
+		if version, err = s.tokens.Version(ctx, userID); err != nil {
+			return "", err
+		}
+	}
+
+	var mfaPending bool
+	if s.mfa != nil {
+		var err error
+		if mfaPending, err = s.mfa.Pending(ctx, userID); err != nil {
+			return "", err
+		}
+	}
+
 	claims := AccessClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:     userID,
+		Role:       role,
+		SessionID:  sessionID,
+		Version:    version,
+		MFAPending: mfaPending,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        generateRandomToken(),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
 		},
 	}
 
+	return s.keys.Sign(claims)
+}
+
+func (s *AuthService) generateMFAChallenge(userID int64, email string) (string, error) {
+	now := time.Now().UTC()
+	claims := MFAChallengeClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        generateRandomToken(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(MFAChallengeTTL)),
+		},
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.JwtSecret)
+	return token.SignedString(s.mfaChallengeKey())
+}
+
+func (s *AuthService) parseMFAChallenge(tokenString string) (*MFAChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return s.mfaChallengeKey(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, models.ErrUnauthorized
+	}
+
+	claims, ok := token.Claims.(*MFAChallengeClaims)
+	if !ok || claims.ExpiresAt == nil {
+		return nil, models.ErrUnauthorized
+	}
+	return claims, nil
+}
+
+func (s *AuthService) generateEmailVerification(userID int64, email string, expiresAt time.Time) (string, error) {
+	claims := EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.purposeKey("email-verification"))
+}
+
+func (s *AuthService) parseEmailVerification(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return s.purposeKey("email-verification"), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, models.ErrInvalidVerification
+	}
+
+	claims, ok := token.Claims.(*EmailVerificationClaims)
+	if !ok {
+		return nil, models.ErrInvalidVerification
+	}
+	return claims, nil
+}
+
+// mfaChallengeKey signs challenges with a key of their own, so that a
+// challenge is never accepted as an access token
+func (s *AuthService) mfaChallengeKey() []byte {
+	return s.purposeKey("mfa-challenge")
+}
+
+// purposeKey derives a signing key from the JWT secret for tokens that must
+// not be mistaken for each other or for access tokens
+func (s *AuthService) purposeKey(purpose string) []byte {
+	sum := sha256.Sum256(append([]byte(purpose+":"), s.JwtSecret...))
+	return sum[:]
 }
 
 func generateRandomToken() string {
@@ -322,6 +769,12 @@ func generateRandomToken() string {
 	return hex.EncodeToString(b)
 }
 
+// hashRefreshToken is what the database stores instead of the token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func domainHasMX(domain string) bool {
 	records, err := net.LookupMX(domain)
 	return err == nil && len(records) > 0
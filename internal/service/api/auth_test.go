@@ -21,6 +21,8 @@ import (
 
 	"github.com/yandex-development-1-team/go/internal/config"
 	"github.com/yandex-development-1-team/go/internal/database"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
 	pgrepo "github.com/yandex-development-1-team/go/internal/repository/postgres"
 )
 
@@ -69,6 +71,12 @@ func TestMain(m *testing.M) {
 		"test-service",
 		15,
 		7,
+		nil,
+		nil,
+		nil,
+		models.RegistrationPolicy{},
+		nil,
+		nil,
 	)
 
 	code := m.Run()
@@ -148,59 +156,101 @@ func insertTestUser(t *testing.T) int64 {
 
 func clearRefreshTokens(t *testing.T) {
 	t.Helper()
-	_, err := db.Exec("DELETE FROM refresh_tokens")
+	_, err := db.Exec("DELETE FROM auth_sessions")
 	assert.NoError(t, err)
 }
 
+// insertRefreshToken opens a session with the token the way a login does
+func insertRefreshToken(t *testing.T, userID int64, token string, expiresAt time.Time) int64 {
+	t.Helper()
+
+	var sessionID int64
+	err := db.QueryRow(`
+		INSERT INTO auth_sessions (user_id, expires_at)
+		VALUES ($1, $2)
+		RETURNING id`, userID, expiresAt).Scan(&sessionID)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`
+		INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)`, userID, sessionID, hashRefreshToken(token), expiresAt)
+	assert.NoError(t, err)
+	return sessionID
+}
+
+func countActiveRefreshTokens(t *testing.T, userID int64) int {
+	t.Helper()
+
+	var count int
+	err := db.Get(&count, `SELECT COUNT(*) FROM refresh_tokens WHERE user_id = $1 AND rotated_at IS NULL`, userID)
+	assert.NoError(t, err)
+	return count
+}
+
 func TestAuthService_Refresh_Success(t *testing.T) {
 	clearRefreshTokens(t)
 	userID := insertTestUser(t)
-
-	_, err := db.Exec(`
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)
-	`, userID, "valid-refresh-token", time.Now().Add(24*time.Hour))
-	assert.NoError(t, err)
+	insertRefreshToken(t, userID, "valid-refresh-token", time.Now().Add(24*time.Hour))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := svc.Refresh(ctx, "valid-refresh-token")
+	client := models.ClientInfo{IP: "10.0.0.1", UserAgent: "test-agent"}
+	resp, err := svc.Refresh(ctx, "valid-refresh-token", client)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, resp.Token, "access token should not be empty")
 	assert.NotEmpty(t, resp.RefreshToken, "new refresh token should not be empty")
 
-	// старый токен удалён
-	var oldCount int
-	err = db.Get(&oldCount, `SELECT COUNT(*) FROM refresh_tokens WHERE token = $1`, "valid-refresh-token")
+	// старый токен помечен использованным, открытых значений в базе нет
+	var rotated int
+	err = db.Get(&rotated, `SELECT COUNT(*) FROM refresh_tokens WHERE token_hash = $1 AND rotated_at IS NOT NULL`,
+		hashRefreshToken("valid-refresh-token"))
 	assert.NoError(t, err)
-	assert.Equal(t, 0, oldCount, "old refresh token should be deleted")
+	assert.Equal(t, 1, rotated, "old refresh token should be rotated")
 
-	// новый токен создан
-	var newCount int
-	err = db.Get(&newCount, `SELECT COUNT(*) FROM refresh_tokens WHERE user_id = $1`, userID)
+	assert.Equal(t, 1, countActiveRefreshTokens(t, userID), "should be exactly one active refresh token")
+
+	sessions, err := svc.Sessions(ctx, userID)
 	assert.NoError(t, err)
-	assert.Equal(t, 1, newCount, "should be exactly one active refresh token")
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, "10.0.0.1", sessions[0].IP)
+		assert.Equal(t, "test-agent", sessions[0].UserAgent)
+	}
 }
 
-func TestAuthService_Refresh_Expired(t *testing.T) {
+func TestAuthService_Refresh_ReuseRevokesSession(t *testing.T) {
 	clearRefreshTokens(t)
 	userID := insertTestUser(t)
+	insertRefreshToken(t, userID, "reused-refresh-token", time.Now().Add(24*time.Hour))
+	insertRefreshToken(t, userID, "other-device-token", time.Now().Add(24*time.Hour))
 
-	_, err := db.Exec(`
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)
-	`,
-		userID,
-		"expired-refresh-token",
-		time.Now().Add(-1*time.Hour),
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := svc.Refresh(ctx, "reused-refresh-token", models.ClientInfo{})
+	assert.NoError(t, err)
+
+	_, err = svc.Refresh(ctx, "reused-refresh-token", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrRefreshTokenReused)
+
+	// вся сессия отозвана, включая выданный после ротации токен
+	_, err = svc.Refresh(ctx, resp.RefreshToken, models.ClientInfo{})
+	assert.ErrorIs(t, err, pgrepo.ErrRefreshTokenNotFound)
+
+	// другое устройство не затронуто
+	_, err = svc.Refresh(ctx, "other-device-token", models.ClientInfo{})
 	assert.NoError(t, err)
+}
+
+func TestAuthService_Refresh_Expired(t *testing.T) {
+	clearRefreshTokens(t)
+	userID := insertTestUser(t)
+	insertRefreshToken(t, userID, "expired-refresh-token", time.Now().Add(-1*time.Hour))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err = svc.Refresh(ctx, "expired-refresh-token")
+	_, err := svc.Refresh(ctx, "expired-refresh-token", models.ClientInfo{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, pgrepo.ErrRefreshTokenExpired))
 }
@@ -211,19 +261,16 @@ func TestAuthService_Refresh_Revoked(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := svc.Refresh(ctx, "non-existent-token")
+	_, err := svc.Refresh(ctx, "non-existent-token", models.ClientInfo{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, pgrepo.ErrRefreshTokenNotFound))
 }
 
 func TestAuthService_Refresh_NotFound(t *testing.T) {
-	clearRefreshTokens(t)
-	_ = insertTestUser(t)
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := svc.Refresh(ctx, "unknown-token")
+	_, err := svc.Refresh(ctx, "unknown-token", models.ClientInfo{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, pgrepo.ErrRefreshTokenNotFound))
 }
@@ -231,16 +278,7 @@ func TestAuthService_Refresh_NotFound(t *testing.T) {
 func TestAuthService_Refresh_ConcurrentRotation(t *testing.T) {
 	clearRefreshTokens(t)
 	userID := insertTestUser(t)
-
-	_, err := db.Exec(`
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)
-	`,
-		userID,
-		"race-refresh-token",
-		time.Now().Add(24*time.Hour),
-	)
-	assert.NoError(t, err)
+	insertRefreshToken(t, userID, "race-refresh-token", time.Now().Add(24*time.Hour))
 
 	const goroutines = 10
 	results := make(chan error, goroutines)
@@ -251,7 +289,7 @@ func TestAuthService_Refresh_ConcurrentRotation(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			_, err := svc.Refresh(ctx, "race-refresh-token")
+			_, err := svc.Refresh(ctx, "race-refresh-token", models.ClientInfo{})
 			if err == nil {
 				atomic.AddInt32(&successCount, 1)
 			}
@@ -265,46 +303,185 @@ func TestAuthService_Refresh_ConcurrentRotation(t *testing.T) {
 
 	assert.Equal(t, int32(1), successCount, "only one refresh should succeed")
 
-	// старый токен удалён
-	var oldCount int
-	err = db.Get(&oldCount, `SELECT COUNT(*) FROM refresh_tokens WHERE token = $1`, "race-refresh-token")
+	// повторное предъявление токена считается утечкой: сессия отозвана
+	assert.Equal(t, 0, countActiveRefreshTokens(t, userID), "session should be revoked on reuse")
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	clearRefreshTokens(t)
+	userID := insertTestUser(t)
+	insertRefreshToken(t, userID, "logout-refresh-token", time.Now().Add(24*time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := svc.Logout(ctx, "logout-refresh-token", "")
 	assert.NoError(t, err)
-	assert.Equal(t, 0, oldCount, "old token should be deleted")
 
-	// только один активный токен
-	var activeCount int
-	err = db.Get(&activeCount, `SELECT COUNT(*) FROM refresh_tokens WHERE user_id = $1`, userID)
+	// сессия и её токены удалены
+	sessions, err := svc.Sessions(ctx, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions, "session should be deleted after logout")
+	assert.Equal(t, 0, countActiveRefreshTokens(t, userID))
+
+	// повторный logout — токена нет
+	err = svc.Logout(ctx, "logout-refresh-token", "")
 	assert.NoError(t, err)
-	assert.Equal(t, 1, activeCount, "should be exactly one active token")
 }
 
-func TestAuthService_Logout(t *testing.T) {
+func TestAuthService_RevokeSession(t *testing.T) {
 	clearRefreshTokens(t)
 	userID := insertTestUser(t)
+	sessionID := insertRefreshToken(t, userID, "revoke-refresh-token", time.Now().Add(24*time.Hour))
 
-	_, err := db.Exec(`
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)
-	`,
-		userID,
-		"logout-refresh-token",
-		time.Now().Add(24*time.Hour),
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := svc.RevokeSession(ctx, userID+1, sessionID)
+	assert.ErrorIs(t, err, models.ErrAuthSessionNotFound, "sessions of others are not visible")
+
+	tokens := &sessionDenylist{}
+	revoking := NewAuthService(db, rtRepo, nil, staffRepo, nil, pgrepo.NewTxRepo(db), "test-service", 15, 7, nil, tokens, nil,
+		models.RegistrationPolicy{}, nil, nil)
+
+	err = revoking.RevokeSession(ctx, userID, sessionID)
 	assert.NoError(t, err)
+	assert.Equal(t, map[int64]time.Duration{sessionID: 15 * time.Minute}, tokens.sessions,
+		"access tokens of the device are denied for their lifetime")
+
+	_, err = svc.Refresh(ctx, "revoke-refresh-token", models.ClientInfo{})
+	assert.ErrorIs(t, err, pgrepo.ErrRefreshTokenNotFound)
+}
+
+type sessionDenylist struct {
+	repository.TokenRevocation
+	sessions map[int64]time.Duration
+}
+
+func (d *sessionDenylist) RevokeSession(_ context.Context, sessionID int64, ttl time.Duration) error {
+	if d.sessions == nil {
+		d.sessions = map[int64]time.Duration{}
+	}
+	d.sessions[sessionID] = ttl
+	return nil
+}
+
+func TestAuthService_LoginWithMFA(t *testing.T) {
+	clearRefreshTokens(t)
+	userID := insertTestUser(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = svc.Logout(ctx, "logout-refresh-token")
+	hash, err := HashPassword("password123")
+	assert.NoError(t, err)
+	var email string
+	err = db.Get(&email, `UPDATE staff SET password_hash = $2 WHERE id = $1 RETURNING email`, userID, hash)
 	assert.NoError(t, err)
 
-	// токен удалён
-	var count int
-	err = db.Get(&count, `SELECT COUNT(*) FROM refresh_tokens WHERE token = $1`, "logout-refresh-token")
+	mfaSvc, err := NewMFAService(pgrepo.NewMFARepo(db), pgrepo.NewTxRepo(db), "test-mfa-key", "Test")
 	assert.NoError(t, err)
-	assert.Equal(t, 0, count, "token should be deleted after logout")
+	mfaAuth := NewAuthService(db, rtRepo, nil, staffRepo, nil, pgrepo.NewTxRepo(db), "test-service", 15, 7, nil, nil, mfaSvc,
+		models.RegistrationPolicy{}, nil, nil)
 
-	// повторный logout — токена нет
-	err = svc.Logout(ctx, "logout-refresh-token")
+	enrollment, err := mfaSvc.Enroll(ctx, userID)
+	assert.NoError(t, err)
+	secret, err := base32NoPadding.DecodeString(enrollment.Secret)
+	assert.NoError(t, err)
+	code := totpCode(secret, time.Now().Unix()/totpPeriod)
+	recoveryCodes, err := mfaSvc.Confirm(ctx, userID, code)
+	assert.NoError(t, err)
+
+	// пароль верный, но вместо токенов — challenge для второго фактора
+	result, err := mfaAuth.Login(ctx, email, "password123", models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.MFAToken)
+	assert.Empty(t, result.Token)
+	assert.Equal(t, 0, countActiveRefreshTokens(t, userID))
+
+	_, err = mfaAuth.ParseAccessToken(result.MFAToken)
+	assert.Error(t, err, "challenge is not an access token")
+
+	// код уже использован при подтверждении
+	_, err = mfaAuth.VerifyMFA(ctx, result.MFAToken, code, models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrMFAInvalidCode)
+
+	// после неверного кода нужен новый challenge
+	result, err = mfaAuth.Login(ctx, email, "password123", models.ClientInfo{})
+	assert.NoError(t, err)
+	result, err = mfaAuth.VerifyMFA(ctx, result.MFAToken, recoveryCodes[0], models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.Equal(t, 1, countActiveRefreshTokens(t, userID))
+
+	_, err = mfaAuth.VerifyMFA(ctx, "not-a-challenge", recoveryCodes[1], models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+}
+
+func TestAuthService_RegisterByDomain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	domainAuth := NewAuthService(db, rtRepo, nil, staffRepo, nil, pgrepo.NewTxRepo(db), "test-service", 15, 7, nil, nil, nil,
+		models.RegistrationPolicy{Mode: models.RegistrationDomains, AllowedDomains: []string{"staff.test"}}, nil, nil)
+
+	_, err := domainAuth.Register(ctx, &models.UserAPI{Name: "Outside", LastName: "User", Email: "outside@other.test"},
+		"password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrEmailDomainNotAllowed)
+
+	result, err := domainAuth.Register(ctx, &models.UserAPI{Name: "Self", LastName: "Registered", Email: "self@STAFF.test"},
+		"password123", models.ClientInfo{})
 	assert.NoError(t, err)
+	assert.Empty(t, result.Token, "no tokens before the email is verified")
+	assert.Equal(t, RoleUser, result.User.Role)
+	assert.Nil(t, result.User.EmailVerifiedAt)
+	assert.Nil(t, result.User.ApprovedAt)
+
+	_, err = domainAuth.Login(ctx, "self@STAFF.test", "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrEmailNotVerified)
+
+	_, err = domainAuth.VerifyEmail(ctx, "not-a-token")
+	assert.ErrorIs(t, err, models.ErrInvalidVerification)
+
+	token, err := domainAuth.generateEmailVerification(result.User.ID, result.User.Email, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	_, err = domainAuth.ParseAccessToken(token)
+	assert.Error(t, err, "verification link is not an access token")
+
+	user, err := domainAuth.VerifyEmail(ctx, token)
+	assert.NoError(t, err)
+	assert.NotNil(t, user.EmailVerifiedAt)
+	assert.Equal(t, "active", user.Status)
+
+	login, err := domainAuth.Login(ctx, "self@STAFF.test", "password123", models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, login.Token)
+
+	admin := RoleAdmin
+	_, err = staffRepo.UpdateStaff(ctx, user.ID, &models.StaffAdminUpdate{Role: &admin})
+	assert.ErrorIs(t, err, models.ErrStaffNotApproved)
+
+	_, err = staffRepo.Approve(ctx, user.ID)
+	assert.NoError(t, err)
+	updated, err := staffRepo.UpdateStaff(ctx, user.ID, &models.StaffAdminUpdate{Role: &admin})
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, updated.Role)
+}
+
+func TestAuthService_RegisterPolicy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newUser := func() *models.UserAPI {
+		return &models.UserAPI{Name: "New", LastName: "User", Email: "policy@example.com", InviteToken: "token"}
+	}
+
+	_, err := svc.Register(ctx, newUser(), "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrRegistrationDisabled, "an unset mode disables registration")
+
+	inviteAuth := NewAuthService(db, rtRepo, nil, staffRepo, nil, pgrepo.NewTxRepo(db), "test-service", 15, 7, nil, nil, nil,
+		models.RegistrationPolicy{Mode: models.RegistrationInviteOnly}, nil, nil)
+	_, err = inviteAuth.Register(ctx, newUser(), "password123", models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrInvalidInvite, "a token not issued by an admin is rejected")
 }
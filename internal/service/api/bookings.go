@@ -2,21 +2,44 @@ package service
 
 import (
 	"context"
+	"strings"
+
+	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/ctxutil"
+	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
 	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 )
 
+// messageSettings serves the message templates of the guest notifications
+type messageSettings interface {
+	GetSettings(ctx context.Context) (models.SettingsFormMessages, error)
+}
+
 type BookingsService struct {
-	repo   repository.BookingRepository
-	txRepo repository.TxRepository
+	repo     repository.BookingRepository
+	txRepo   repository.TxRepository
+	settings messageSettings
+	outbox   repository.NotificationOutboxRepository
+	webhooks webhook.Publisher
 }
 
-func NewBookingsService(repo repository.BookingRepository, txRepo repository.TxRepository) *BookingsService {
+func NewBookingsService(
+	repo repository.BookingRepository,
+	txRepo repository.TxRepository,
+	settings messageSettings,
+	outbox repository.NotificationOutboxRepository,
+	webhooks webhook.Publisher,
+) *BookingsService {
 	return &BookingsService{
-		repo:   repo,
-		txRepo: txRepo,
+		repo:     repo,
+		txRepo:   txRepo,
+		settings: settings,
+		outbox:   outbox,
+		webhooks: webhooks,
 	}
 }
 
@@ -41,6 +64,22 @@ func (s *BookingsService) UpdateBookingStatus(ctx context.Context, id int64, sta
 
 	txCtx := ctxutil.WithTx(ctx, tx)
 
+	// The lock makes concurrent updates take turns: only the first one sees
+	// the old status and notifies about the change
+	var prev *models.BookingAPI
+	prev, err = s.repo.LockBooking(txCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cancelled booking gave up its place: it comes back only if the slot
+	// still has a free one
+	if prev.Status == models.BookingStatusCancelled && status != models.BookingStatusCancelled {
+		if err = s.repo.ReserveBookingSlot(txCtx, id); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = s.repo.UpdateBookingStatus(txCtx, id, status); err != nil {
 		return nil, err
 	}
@@ -51,6 +90,18 @@ func (s *BookingsService) UpdateBookingStatus(ctx context.Context, id int64, sta
 		return nil, err
 	}
 
+	if prev.Status != status {
+		if err = s.notifyGuest(txCtx, app); err != nil {
+			return nil, err
+		}
+		if s.webhooks != nil {
+			err = s.webhooks.Publish(txCtx, webhook.EventBookingStatusChanged, webhook.NewBooking(app, prev.Status))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -61,3 +112,49 @@ func (s *BookingsService) UpdateBookingStatus(ctx context.Context, id int64, sta
 func (s *BookingsService) DeleteBooking(ctx context.Context, id int64) error {
 	return s.repo.DeleteBooking(ctx, id)
 }
+
+// notifyGuest puts the confirmation or cancellation message into the outbox
+// within the status update transaction; the bot process delivers it.
+func (s *BookingsService) notifyGuest(ctx context.Context, booking *models.BookingAPI) error {
+	if s.outbox == nil || s.settings == nil {
+		return nil
+	}
+
+	settings, err := s.settings.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	var text string
+	switch booking.Status {
+	case models.BookingStatusConfirmed:
+		text = settings.RecordConfirmation
+	case models.BookingStatusCancelled:
+		text = settings.CancellationMessage
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	// The template was validated on save, so an error here means it was
+	// changed behind the admin panel: the status change must not fail on it
+	message, err := bookingNotificationText(text, booking)
+	if err != nil {
+		logger.Error("failed to render guest notification", zap.Int64("booking_id", booking.ID), zap.Error(err))
+		return nil
+	}
+
+	return s.outbox.Enqueue(ctx, booking.UserID, message)
+}
+
+// bookingNotificationText renders the notification; a text without
+// placeholders is followed by the booking details as before the templates
+func bookingNotificationText(text string, booking *models.BookingAPI) (string, error) {
+	return msgtemplate.Render(msgtemplate.WithDetails(text), msgtemplate.Data{
+		GuestName:   booking.GuestName,
+		ServiceName: booking.ServiceName,
+		Date:        msgtemplate.FormatDate(booking.BookingDate),
+		Time:        booking.BookingTime,
+		BookingID:   booking.ID,
+	})
+}
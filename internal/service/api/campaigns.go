@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	maxCampaignText    = 4096 // Telegram message limit
+	maxCampaignCaption = 1024 // Telegram photo caption limit
+	maxCampaignButtons = 10
+)
+
+// CampaignService manages the broadcasts to the bot users. The messages are
+// delivered by the campaign worker of the bot process.
+type CampaignService struct {
+	repo repository.CampaignRepository
+}
+
+// NewCampaignService creates a new CampaignService.
+func NewCampaignService(repo repository.CampaignRepository) *CampaignService {
+	return &CampaignService{repo: repo}
+}
+
+func (s *CampaignService) List(ctx context.Context) ([]models.Campaign, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *CampaignService) GetByID(ctx context.Context, id int64) (*models.Campaign, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Create validates and saves the campaign as a draft
+func (s *CampaignService) Create(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	if err := normalizeCampaign(campaign); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, campaign)
+}
+
+// Update replaces the content and the segment of a draft
+func (s *CampaignService) Update(ctx context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	current, err := s.repo.Get(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != models.CampaignStatusDraft {
+		return nil, models.ErrCampaignStatus
+	}
+
+	if err := normalizeCampaign(campaign); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, campaign)
+}
+
+// Delete deletes a campaign that is not being sent
+func (s *CampaignService) Delete(ctx context.Context, id int64) error {
+	current, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.Status == models.CampaignStatusRunning {
+		return models.ErrCampaignStatus
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Audience returns the number of bot users the segment reaches now
+func (s *CampaignService) Audience(ctx context.Context, segment models.CampaignSegment) (int, error) {
+	if err := normalizeCampaignSegment(&segment); err != nil {
+		return 0, err
+	}
+	return s.repo.CountAudience(ctx, segment)
+}
+
+// Start resolves the segment of the draft into recipients and queues the
+// campaign for delivery
+func (s *CampaignService) Start(ctx context.Context, id int64) (*models.Campaign, error) {
+	current, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != models.CampaignStatusDraft {
+		return nil, models.ErrCampaignStatus
+	}
+
+	if _, err := s.repo.Start(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Cancel stops the delivery; the recipients already reached keep the message
+func (s *CampaignService) Cancel(ctx context.Context, id int64) (*models.Campaign, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// ListRecipients returns the delivery status of every recipient of the campaign
+func (s *CampaignService) ListRecipients(ctx context.Context, filter models.CampaignRecipientFilter) (*models.CampaignRecipientList, error) {
+	if _, err := s.repo.Get(ctx, filter.CampaignID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRecipients(ctx, filter)
+}
+
+// normalizeCampaign checks what Telegram would reject: the length of the text
+// or the caption, the image and button URLs, and the segment
+func normalizeCampaign(campaign *models.Campaign) error {
+	campaign.Title = strings.TrimSpace(campaign.Title)
+	campaign.Text = strings.TrimSpace(campaign.Text)
+	campaign.ImageURL = strings.TrimSpace(campaign.ImageURL)
+
+	if campaign.Title == "" {
+		return fmt.Errorf("%w: empty title", models.ErrInvalidCampaign)
+	}
+
+	limit := maxCampaignText
+	if campaign.ImageURL != "" {
+		if !isCampaignURL(campaign.ImageURL, "http", "https") {
+			return fmt.Errorf("%w: invalid image url", models.ErrInvalidCampaign)
+		}
+		limit = maxCampaignCaption
+	}
+	if campaign.Text == "" || utf8.RuneCountInString(campaign.Text) > limit {
+		return fmt.Errorf("%w: text must be 1 to %d characters", models.ErrInvalidCampaign, limit)
+	}
+
+	if len(campaign.Buttons) > maxCampaignButtons {
+		return fmt.Errorf("%w: more than %d buttons", models.ErrInvalidCampaign, maxCampaignButtons)
+	}
+	for i := range campaign.Buttons {
+		button := &campaign.Buttons[i]
+		button.Text = strings.TrimSpace(button.Text)
+		button.URL = strings.TrimSpace(button.URL)
+		if button.Text == "" {
+			return fmt.Errorf("%w: empty button text", models.ErrInvalidCampaign)
+		}
+		if !isCampaignURL(button.URL, "http", "https", "tg") {
+			return fmt.Errorf("%w: invalid button url %q", models.ErrInvalidCampaign, button.URL)
+		}
+	}
+
+	return normalizeCampaignSegment(&campaign.Segment)
+}
+
+// normalizeCampaignSegment checks the segment and drops the parameters
+// of the other segment types and duplicates
+func normalizeCampaignSegment(segment *models.CampaignSegment) error {
+	switch segment.Type {
+	case models.CampaignSegmentAll:
+		segment.ServiceIDs, segment.Grades = nil, nil
+	case models.CampaignSegmentService:
+		if len(segment.ServiceIDs) == 0 {
+			return fmt.Errorf("%w: no services", models.ErrInvalidCampaign)
+		}
+		slices.Sort(segment.ServiceIDs)
+		segment.ServiceIDs = slices.Compact(segment.ServiceIDs)
+		segment.Grades = nil
+	case models.CampaignSegmentGrade:
+		if len(segment.Grades) == 0 {
+			return fmt.Errorf("%w: no grades", models.ErrInvalidCampaign)
+		}
+		slices.Sort(segment.Grades)
+		segment.Grades = slices.Compact(segment.Grades)
+		segment.ServiceIDs = nil
+	default:
+		return fmt.Errorf("%w: unknown segment %q", models.ErrInvalidCampaign, segment.Type)
+	}
+	return nil
+}
+
+func isCampaignURL(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || !slices.Contains(schemes, u.Scheme) {
+		return false
+	}
+	return u.Host != ""
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeCampaignRepo struct {
+	campaigns map[int64]*models.Campaign
+	audience  int
+}
+
+func (r *fakeCampaignRepo) Create(_ context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	stored := *campaign
+	stored.ID = int64(len(r.campaigns) + 1)
+	stored.Status = models.CampaignStatusDraft
+	r.campaigns[stored.ID] = &stored
+	return &stored, nil
+}
+
+func (r *fakeCampaignRepo) Get(_ context.Context, id int64) (*models.Campaign, error) {
+	campaign, ok := r.campaigns[id]
+	if !ok {
+		return nil, models.ErrCampaignNotFound
+	}
+	copied := *campaign
+	return &copied, nil
+}
+
+func (r *fakeCampaignRepo) List(_ context.Context) ([]models.Campaign, error) {
+	campaigns := make([]models.Campaign, 0, len(r.campaigns))
+	for _, campaign := range r.campaigns {
+		campaigns = append(campaigns, *campaign)
+	}
+	return campaigns, nil
+}
+
+func (r *fakeCampaignRepo) Update(_ context.Context, campaign *models.Campaign) (*models.Campaign, error) {
+	stored := *campaign
+	stored.Status = r.campaigns[campaign.ID].Status
+	r.campaigns[campaign.ID] = &stored
+	return &stored, nil
+}
+
+func (r *fakeCampaignRepo) Delete(_ context.Context, id int64) error {
+	delete(r.campaigns, id)
+	return nil
+}
+
+func (r *fakeCampaignRepo) CountAudience(context.Context, models.CampaignSegment) (int, error) {
+	return r.audience, nil
+}
+
+func (r *fakeCampaignRepo) Start(_ context.Context, id int64) (int, error) {
+	if r.audience == 0 {
+		return 0, models.ErrCampaignNoRecipients
+	}
+	campaign := r.campaigns[id]
+	campaign.Status = models.CampaignStatusRunning
+	campaign.Progress = models.CampaignProgress{Total: r.audience, Pending: r.audience}
+	return r.audience, nil
+}
+
+func (r *fakeCampaignRepo) Cancel(_ context.Context, id int64) error {
+	campaign := r.campaigns[id]
+	if campaign.Status != models.CampaignStatusDraft && campaign.Status != models.CampaignStatusRunning {
+		return models.ErrCampaignStatus
+	}
+	campaign.Status = models.CampaignStatusCancelled
+	return nil
+}
+
+func (r *fakeCampaignRepo) ListRecipients(_ context.Context, filter models.CampaignRecipientFilter) (*models.CampaignRecipientList, error) {
+	return &models.CampaignRecipientList{Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+func newTestCampaignService(audience int) (*CampaignService, *fakeCampaignRepo) {
+	repo := &fakeCampaignRepo{campaigns: map[int64]*models.Campaign{}, audience: audience}
+	return NewCampaignService(repo), repo
+}
+
+func newTestCampaign() *models.Campaign {
+	return &models.Campaign{
+		Title:   " Новая коробка ",
+		Text:    "Встречайте экскурсию по дата-центру!",
+		Buttons: []models.CampaignButton{{Text: "Записаться", URL: "https://t.me/bot?start=box_7"}},
+		Segment: models.CampaignSegment{Type: models.CampaignSegmentService, ServiceIDs: []int64{3, 1, 3}, Grades: []int{2}},
+	}
+}
+
+func TestCampaignService_CreateNormalizes(t *testing.T) {
+	svc, _ := newTestCampaignService(10)
+
+	campaign, err := svc.Create(context.Background(), newTestCampaign())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Новая коробка", campaign.Title)
+	assert.Equal(t, models.CampaignStatusDraft, campaign.Status)
+	assert.Equal(t, []int64{1, 3}, campaign.Segment.ServiceIDs)
+	assert.Nil(t, campaign.Segment.Grades, "grades are not a part of the service segment")
+}
+
+func TestCampaignService_CreateValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *models.Campaign)
+	}{
+		{"empty text", func(c *models.Campaign) { c.Text = "  " }},
+		{"caption too long", func(c *models.Campaign) {
+			c.ImageURL = "https://cdn.example.com/box.png"
+			c.Text = strings.Repeat("а", maxCampaignCaption+1)
+		}},
+		{"image not http", func(c *models.Campaign) { c.ImageURL = "ftp://cdn.example.com/box.png" }},
+		{"button without url", func(c *models.Campaign) { c.Buttons[0].URL = "" }},
+		{"unknown segment", func(c *models.Campaign) { c.Segment.Type = "vip" }},
+		{"grade segment without grades", func(c *models.Campaign) { c.Segment = models.CampaignSegment{Type: models.CampaignSegmentGrade} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestCampaignService(10)
+			campaign := newTestCampaign()
+			tt.modify(campaign)
+
+			_, err := svc.Create(context.Background(), campaign)
+			assert.ErrorIs(t, err, models.ErrInvalidCampaign)
+		})
+	}
+}
+
+func TestCampaignService_CaptionAllowsLongTextWithoutImage(t *testing.T) {
+	svc, _ := newTestCampaignService(10)
+	campaign := newTestCampaign()
+	campaign.Text = strings.Repeat("а", maxCampaignCaption+1)
+
+	_, err := svc.Create(context.Background(), campaign)
+	assert.NoError(t, err)
+}
+
+func TestCampaignService_StartOnlyDraft(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestCampaignService(3)
+	created, err := svc.Create(ctx, newTestCampaign())
+	require.NoError(t, err)
+
+	started, err := svc.Start(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignStatusRunning, started.Status)
+	assert.Equal(t, 3, started.Progress.Pending)
+
+	_, err = svc.Start(ctx, created.ID)
+	assert.ErrorIs(t, err, models.ErrCampaignStatus)
+
+	created.Title = "Другой заголовок"
+	_, err = svc.Update(ctx, created)
+	assert.ErrorIs(t, err, models.ErrCampaignStatus, "a running campaign can't be edited")
+
+	assert.ErrorIs(t, svc.Delete(ctx, created.ID), models.ErrCampaignStatus, "a running campaign must be cancelled first")
+
+	cancelled, err := svc.Cancel(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignStatusCancelled, cancelled.Status)
+	assert.NoError(t, svc.Delete(ctx, created.ID))
+}
+
+func TestCampaignService_StartEmptyAudience(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestCampaignService(0)
+	created, err := svc.Create(ctx, newTestCampaign())
+	require.NoError(t, err)
+
+	_, err = svc.Start(ctx, created.ID)
+	assert.ErrorIs(t, err, models.ErrCampaignNoRecipients)
+	assert.Equal(t, models.CampaignStatusDraft, repo.campaigns[created.ID].Status)
+}
+
+func TestCampaignService_NotFound(t *testing.T) {
+	svc, _ := newTestCampaignService(1)
+
+	_, err := svc.Start(context.Background(), 42)
+	assert.ErrorIs(t, err, models.ErrCampaignNotFound)
+
+	_, err = svc.ListRecipients(context.Background(), models.CampaignRecipientFilter{CampaignID: 42})
+	assert.ErrorIs(t, err, models.ErrCampaignNotFound)
+}
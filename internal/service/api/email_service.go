@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-mail/mail/v2"
 
@@ -37,3 +38,41 @@ func (s *EmailService) SendPasswordResetEmail(ctx context.Context, toEmail, rese
 
 	return s.dialer.DialAndSend(m)
 }
+
+// SendInviteEmail sends the link where an invited staff member sets their password
+func (s *EmailService) SendInviteEmail(ctx context.Context, toEmail, name, inviteToken string, expiresAt time.Time) error {
+	inviteURL := fmt.Sprintf("%s/accept-invite?token=%s", s.baseURL, inviteToken)
+
+	m := mail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", "Приглашение в панель управления")
+	m.SetBody("text/plain", fmt.Sprintf("%s, вас пригласили в панель управления.\n\nЧтобы задать пароль и войти, перейдите по ссылке: %s\n\nСсылка действительна до %s.",
+		name, inviteURL, expiresAt.Format("02.01.2006 15:04 MST")))
+
+	return s.dialer.DialAndSend(m)
+}
+
+// SendVerificationEmail sends the link confirming the email of a self-registered staff member
+func (s *EmailService) SendVerificationEmail(ctx context.Context, toEmail, name, token string, expiresAt time.Time) error {
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", s.baseURL, token)
+
+	m := mail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", "Подтверждение email")
+	m.SetBody("text/plain", fmt.Sprintf("%s, чтобы завершить регистрацию, подтвердите email по ссылке: %s\n\nСсылка действительна до %s.",
+		name, verifyURL, expiresAt.Format("02.01.2006 15:04 MST")))
+
+	return s.dialer.DialAndSend(m)
+}
+
+func (s *EmailService) SendNotificationEmail(ctx context.Context, toEmail, subject, body string) error {
+	m := mail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/plain", body)
+
+	return s.dialer.DialAndSend(m)
+}
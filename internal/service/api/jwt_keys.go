@@ -0,0 +1,166 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/dto"
+)
+
+const minRSAKeyBits = 2048
+
+// JWTKeys signs and verifies access tokens. Without asymmetric keys tokens
+// are signed with the HMAC secret. With them a token is signed with the
+// active key and names it in the kid header; every known key verifies, so
+// switching the active key logs nobody out. HMAC tokens are then rejected,
+// and clients holding one get a new token with their refresh token.
+type JWTKeys struct {
+	secret []byte
+	active *jwtKey
+	keys   map[string]*jwtKey
+}
+
+type jwtKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+// NewJWTKeys loads the keys listed in cfg; with none it falls back to secret
+func NewJWTKeys(secret string, cfg config.SigningConfig) (*JWTKeys, error) {
+	keys := newHMACKeys([]byte(secret))
+	for _, keyCfg := range cfg.Keys {
+		key, err := loadJWTKey(keyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", keyCfg.ID, err)
+		}
+		keys.keys[key.id] = key
+	}
+
+	if len(keys.keys) == 0 {
+		return keys, nil
+	}
+	active, ok := keys.keys[cfg.ActiveKeyID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("active signing key %q has no private key", cfg.ActiveKeyID)
+	}
+	keys.active = active
+	return keys, nil
+}
+
+func newHMACKeys(secret []byte) *JWTKeys {
+	return &JWTKeys{secret: secret, keys: map[string]*jwtKey{}}
+}
+
+// Sign signs the claims with the active key
+func (k *JWTKeys) Sign(claims jwt.Claims) (string, error) {
+	if k.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.id
+	return token.SignedString(k.active.private)
+}
+
+// Keyfunc picks the key that verifies the token, see jwt.Keyfunc
+func (k *JWTKeys) Keyfunc(token *jwt.Token) (any, error) {
+	if k.active == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public keys for other services to verify tokens with.
+// It is empty while tokens are signed with the HMAC secret.
+func (k *JWTKeys) JWKS() dto.JWKSResponse {
+	resp := dto.JWKSResponse{Keys: make([]dto.JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk := dto.JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		resp.Keys = append(resp.Keys, jwk)
+	}
+	slices.SortFunc(resp.Keys, func(a, b dto.JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return resp
+}
+
+func loadJWTKey(cfg config.SigningKeyConfig) (*jwtKey, error) {
+	file := cfg.PrivateKeyFile
+	if file == "" {
+		file = cfg.PublicKeyFile
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+
+	key := &jwtKey{id: cfg.ID}
+	var parsed any
+	if cfg.PrivateKeyFile != "" {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		}
+	} else {
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse key: %w", err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, want RSA or Ed25519", parsed)
+	}
+
+	if public, ok := key.public.(*rsa.PublicKey); ok && public.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("rsa key is %d bits, want at least %d", public.N.BitLen(), minRSAKeyBits)
+	}
+	return key, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+)
+
+// writePEM stores the key in a temporary file and returns its path
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func writePrivateKey(t *testing.T, name string, key any) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, name, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, name string, key any) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return writePEM(t, name, "PUBLIC KEY", der)
+}
+
+func testClaims() *AccessClaims {
+	return &AccessClaims{
+		UserID: 1,
+		Role:   RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func parseWith(keys *JWTKeys, token string) error {
+	_, err := jwt.ParseWithClaims(token, &AccessClaims{}, keys.Keyfunc)
+	return err
+}
+
+func TestJWTKeys_HMAC(t *testing.T) {
+	keys, err := NewJWTKeys("secret", config.SigningConfig{})
+	require.NoError(t, err)
+
+	token, err := keys.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, parseWith(keys, token))
+	assert.Empty(t, keys.JWKS().Keys)
+
+	other, err := NewJWTKeys("other-secret", config.SigningConfig{})
+	require.NoError(t, err)
+	assert.Error(t, parseWith(other, token))
+}
+
+func TestJWTKeys_Rotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaFile := writePrivateKey(t, "rsa.pem", rsaKey)
+	edFile := writePrivateKey(t, "ed.pem", edKey)
+
+	old, err := NewJWTKeys("secret", config.SigningConfig{
+		ActiveKeyID: "2026-04",
+		Keys:        []config.SigningKeyConfig{{ID: "2026-04", PrivateKeyFile: rsaFile}},
+	})
+	require.NoError(t, err)
+	oldToken, err := old.Sign(testClaims())
+	require.NoError(t, err)
+
+	hmacKeys, err := NewJWTKeys("secret", config.SigningConfig{})
+	require.NoError(t, err)
+	hmacToken, err := hmacKeys.Sign(testClaims())
+	require.NoError(t, err)
+
+	// The new key signs, the old one is kept by its public part to verify
+	rotated, err := NewJWTKeys("secret", config.SigningConfig{
+		ActiveKeyID: "2026-10",
+		Keys: []config.SigningKeyConfig{
+			{ID: "2026-04", PublicKeyFile: writePublicKey(t, "rsa.pub", &rsaKey.PublicKey)},
+			{ID: "2026-10", PrivateKeyFile: edFile},
+		},
+	})
+	require.NoError(t, err)
+
+	newToken, err := rotated.Sign(testClaims())
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &AccessClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+
+	assert.NoError(t, parseWith(rotated, newToken))
+	assert.NoError(t, parseWith(rotated, oldToken), "tokens of the previous key stay valid")
+	assert.Error(t, parseWith(old, newToken), "a key that is not listed does not verify")
+	assert.Error(t, parseWith(rotated, hmacToken), "HMAC tokens are rejected once keys are configured")
+
+	jwks := rotated.JWKS().Keys
+	require.Len(t, jwks, 2)
+	assert.Equal(t, "2026-04", jwks[0].Kid)
+	assert.Equal(t, "RSA", jwks[0].Kty)
+	assert.Equal(t, "RS256", jwks[0].Alg)
+	assert.Equal(t, "AQAB", jwks[0].E)
+	assert.Equal(t, "OKP", jwks[1].Kty)
+	assert.Equal(t, "Ed25519", jwks[1].Crv)
+	assert.NotEmpty(t, jwks[1].X)
+}
+
+func TestJWTKeys_ActiveKeyMustSign(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = NewJWTKeys("secret", config.SigningConfig{
+		ActiveKeyID: "pub",
+		Keys:        []config.SigningKeyConfig{{ID: "pub", PublicKeyFile: writePublicKey(t, "ed.pub", edKey.Public())}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// lockoutMemory is how long a lockout counts towards doubling the next one
+const lockoutMemory = 24 * time.Hour
+
+// LoginLimit is how many attempts are allowed within the window
+type LoginLimit struct {
+	Attempts int
+	Window   time.Duration
+}
+
+type LoginGuardConfig struct {
+	PerEmail    LoginLimit
+	PerIP       LoginLimit
+	LockoutBase time.Duration
+	LockoutMax  time.Duration
+}
+
+// LoginGuard protects login, the second factor and password recovery against
+// brute force. Failed
+// attempts are counted per email and per client IP; a subject over its limit
+// is locked out, each next time twice as long. Counting is best effort: when
+// Redis is unavailable attempts are let through.
+type LoginGuard struct {
+	throttle repository.LoginThrottle
+	log      repository.SecurityLogRepository
+	cfg      LoginGuardConfig
+}
+
+func NewLoginGuard(throttle repository.LoginThrottle, log repository.SecurityLogRepository, cfg LoginGuardConfig) *LoginGuard {
+	return &LoginGuard{throttle: throttle, log: log, cfg: cfg}
+}
+
+type guardSubject struct {
+	key   string
+	limit LoginLimit
+	email string
+	ip    string
+}
+
+// Check returns a *models.LockoutError while the email or the IP is locked out
+func (g *LoginGuard) Check(ctx context.Context, action, email, ip string) error {
+	subjects := g.subjects(action, email, ip)
+	keys := make([]string, 0, len(subjects))
+	for _, s := range subjects {
+		keys = append(keys, s.key)
+	}
+
+	locked, err := g.throttle.LockedFor(ctx, keys...)
+	if err != nil {
+		logger.Warn("login throttle is unavailable", zap.String("action", action), zap.Error(err))
+		return nil
+	}
+	if locked > 0 {
+		return &models.LockoutError{RetryAfter: locked}
+	}
+	return nil
+}
+
+// Attempt counts an attempt that did not succeed. The email or the IP that
+// runs over its limit is locked out and a *models.LockoutError is returned.
+func (g *LoginGuard) Attempt(ctx context.Context, action, email, ip string) error {
+	var lockedFor time.Duration
+	for _, s := range g.subjects(action, email, ip) {
+		count, err := g.throttle.AddAttempt(ctx, s.key, s.limit.Window)
+		if err != nil {
+			logger.Warn("login throttle is unavailable", zap.String("action", action), zap.Error(err))
+			return nil
+		}
+		if count < int64(s.limit.Attempts) {
+			continue
+		}
+
+		duration, err := g.throttle.Lock(ctx, s.key, g.cfg.LockoutBase, g.cfg.LockoutMax, lockoutMemory)
+		if err != nil {
+			logger.Warn("login throttle is unavailable", zap.String("action", action), zap.Error(err))
+			return nil
+		}
+		lockedFor = max(lockedFor, duration)
+
+		lockedUntil := time.Now().Add(duration)
+		logger.Warn("login locked out",
+			zap.String("action", action), zap.String("email", s.email), zap.String("ip", s.ip),
+			zap.Duration("duration", duration))
+		g.record(ctx, &models.SecurityEvent{
+			Event:       models.SecurityEventLockout,
+			Action:      action,
+			Email:       s.email,
+			IP:          s.ip,
+			LockedUntil: &lockedUntil,
+		})
+	}
+
+	if lockedFor > 0 {
+		return &models.LockoutError{RetryAfter: lockedFor}
+	}
+	return nil
+}
+
+// Reset forgets the failed attempts of the email after it succeeded. The IP
+// keeps its count, so that one valid account does not cover guessing others.
+func (g *LoginGuard) Reset(ctx context.Context, action, email string) {
+	if err := g.throttle.ResetAttempts(ctx, emailKey(action, email)); err != nil {
+		logger.Warn("login throttle is unavailable", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// Clear lifts the lockouts of the email and/or the IP for every action
+func (g *LoginGuard) Clear(ctx context.Context, actorID int64, email, ip string) error {
+	if email == "" && ip == "" {
+		return models.ErrInvalidInput
+	}
+
+	for _, action := range []string{models.GuardedActionLogin, models.GuardedActionMFA, models.GuardedActionForgotPassword} {
+		for _, s := range g.subjects(action, email, ip) {
+			if err := g.throttle.Unlock(ctx, s.key); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.record(ctx, &models.SecurityEvent{
+		Event:   models.SecurityEventLockoutCleared,
+		Email:   normalizeEmail(email),
+		IP:      ip,
+		ActorID: &actorID,
+	})
+	return nil
+}
+
+func (g *LoginGuard) subjects(action, email, ip string) []guardSubject {
+	subjects := make([]guardSubject, 0, 2)
+	if email = normalizeEmail(email); email != "" {
+		subjects = append(subjects, guardSubject{key: emailKey(action, email), limit: g.cfg.PerEmail, email: email})
+	}
+	if ip != "" {
+		subjects = append(subjects, guardSubject{key: action + ":ip:" + ip, limit: g.cfg.PerIP, ip: ip})
+	}
+	return subjects
+}
+
+// record writes to the security log; the lockout holds even if it fails
+func (g *LoginGuard) record(ctx context.Context, event *models.SecurityEvent) {
+	if g.log == nil {
+		return
+	}
+	if err := g.log.Create(ctx, event); err != nil {
+		logger.Error("failed to write security log", zap.String("event", event.Event), zap.Error(err))
+	}
+}
+
+func emailKey(action, email string) string {
+	return action + ":email:" + normalizeEmail(email)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
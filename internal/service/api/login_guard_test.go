@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+// fakeThrottle keeps attempts and lockouts in memory; the window never slides
+type fakeThrottle struct {
+	attempts map[string]int64
+	locked   map[string]time.Duration
+	levels   map[string]int64
+	err      error
+}
+
+func newFakeThrottle() *fakeThrottle {
+	return &fakeThrottle{
+		attempts: map[string]int64{},
+		locked:   map[string]time.Duration{},
+		levels:   map[string]int64{},
+	}
+}
+
+func (f *fakeThrottle) AddAttempt(_ context.Context, key string, _ time.Duration) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.attempts[key]++
+	return f.attempts[key], nil
+}
+
+func (f *fakeThrottle) Lock(_ context.Context, key string, base, limit, _ time.Duration) (time.Duration, error) {
+	f.levels[key]++
+	duration := base << (f.levels[key] - 1)
+	duration = min(duration, limit)
+	f.locked[key] = duration
+	delete(f.attempts, key)
+	return duration, nil
+}
+
+func (f *fakeThrottle) LockedFor(_ context.Context, keys ...string) (time.Duration, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	var locked time.Duration
+	for _, key := range keys {
+		locked = max(locked, f.locked[key])
+	}
+	return locked, nil
+}
+
+func (f *fakeThrottle) ResetAttempts(_ context.Context, key string) error {
+	delete(f.attempts, key)
+	return nil
+}
+
+func (f *fakeThrottle) Unlock(_ context.Context, key string) error {
+	delete(f.attempts, key)
+	delete(f.locked, key)
+	delete(f.levels, key)
+	return nil
+}
+
+type fakeSecurityLog struct {
+	events []models.SecurityEvent
+}
+
+func (l *fakeSecurityLog) Create(_ context.Context, event *models.SecurityEvent) error {
+	l.events = append(l.events, *event)
+	return nil
+}
+
+func newTestLoginGuard() (*LoginGuard, *fakeThrottle, *fakeSecurityLog) {
+	throttle, log := newFakeThrottle(), &fakeSecurityLog{}
+	guard := NewLoginGuard(throttle, log, LoginGuardConfig{
+		PerEmail:    LoginLimit{Attempts: 3, Window: time.Minute},
+		PerIP:       LoginLimit{Attempts: 10, Window: time.Minute},
+		LockoutBase: time.Minute,
+		LockoutMax:  3 * time.Minute,
+	})
+	return guard, throttle, log
+}
+
+func TestLoginGuard_LocksOutEmail(t *testing.T) {
+	guard, _, log := newTestLoginGuard()
+	ctx := context.Background()
+
+	for range 2 {
+		require.NoError(t, guard.Attempt(ctx, models.GuardedActionLogin, "User@Test.local", "10.0.0.1"))
+	}
+	require.NoError(t, guard.Check(ctx, models.GuardedActionLogin, "user@test.local", "10.0.0.1"))
+
+	err := guard.Attempt(ctx, models.GuardedActionLogin, "user@test.local", "10.0.0.1")
+	var lockout *models.LockoutError
+	require.True(t, errors.As(err, &lockout), "the attempt over the limit is rejected")
+	assert.ErrorIs(t, err, models.ErrTooManyAttempts)
+	assert.Equal(t, time.Minute, lockout.RetryAfter)
+	assert.Equal(t, 60, lockout.RetryAfterSeconds())
+
+	assert.ErrorIs(t, guard.Check(ctx, models.GuardedActionLogin, "USER@test.local", "10.0.0.2"), models.ErrTooManyAttempts,
+		"the email stays locked out from another IP")
+	assert.NoError(t, guard.Check(ctx, models.GuardedActionLogin, "other@test.local", "10.0.0.1"),
+		"the IP is under its own limit")
+	assert.NoError(t, guard.Check(ctx, models.GuardedActionForgotPassword, "user@test.local", "10.0.0.1"),
+		"actions are counted separately")
+
+	require.Len(t, log.events, 1)
+	assert.Equal(t, models.SecurityEventLockout, log.events[0].Event)
+	assert.Equal(t, "user@test.local", log.events[0].Email)
+	assert.NotNil(t, log.events[0].LockedUntil)
+}
+
+func TestLoginGuard_BackoffGrows(t *testing.T) {
+	guard, _, _ := newTestLoginGuard()
+	ctx := context.Background()
+
+	var lockout *models.LockoutError
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		var err error
+		for range 3 {
+			err = guard.Attempt(ctx, models.GuardedActionLogin, "user@test.local", "")
+		}
+		require.True(t, errors.As(err, &lockout))
+		assert.Equal(t, want, lockout.RetryAfter)
+	}
+}
+
+func TestLoginGuard_Reset(t *testing.T) {
+	guard, throttle, _ := newTestLoginGuard()
+	ctx := context.Background()
+
+	for range 2 {
+		require.NoError(t, guard.Attempt(ctx, models.GuardedActionLogin, "user@test.local", "10.0.0.1"))
+	}
+	guard.Reset(ctx, models.GuardedActionLogin, "user@test.local")
+
+	assert.Zero(t, throttle.attempts[emailKey(models.GuardedActionLogin, "user@test.local")])
+	assert.EqualValues(t, 2, throttle.attempts[models.GuardedActionLogin+":ip:10.0.0.1"], "the IP keeps its count")
+}
+
+func TestLoginGuard_Clear(t *testing.T) {
+	guard, _, log := newTestLoginGuard()
+	ctx := context.Background()
+
+	for range 3 {
+		_ = guard.Attempt(ctx, models.GuardedActionForgotPassword, "user@test.local", "")
+	}
+	require.ErrorIs(t, guard.Check(ctx, models.GuardedActionForgotPassword, "user@test.local", ""), models.ErrTooManyAttempts)
+
+	assert.ErrorIs(t, guard.Clear(ctx, 1, "", ""), models.ErrInvalidInput)
+	require.NoError(t, guard.Clear(ctx, 1, "User@test.local", ""))
+	assert.NoError(t, guard.Check(ctx, models.GuardedActionForgotPassword, "user@test.local", ""))
+
+	last := log.events[len(log.events)-1]
+	assert.Equal(t, models.SecurityEventLockoutCleared, last.Event)
+	require.NotNil(t, last.ActorID)
+	assert.EqualValues(t, 1, *last.ActorID)
+}
+
+func TestLoginGuard_FailsOpen(t *testing.T) {
+	guard, throttle, _ := newTestLoginGuard()
+	throttle.err = errors.New("redis is down")
+	ctx := context.Background()
+
+	for range 5 {
+		assert.NoError(t, guard.Attempt(ctx, models.GuardedActionLogin, "user@test.local", "10.0.0.1"))
+	}
+	assert.NoError(t, guard.Check(ctx, models.GuardedActionLogin, "user@test.local", "10.0.0.1"))
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	mfaSecretSize     = 20
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAService manages the TOTP second factor of staff. Secrets are stored
+// encrypted with AES-GCM, recovery codes as SHA-256 hashes.
+type MFAService struct {
+	repo   repository.MFARepository
+	txRepo repository.TxRepository
+	aead   cipher.AEAD
+	issuer string
+	now    func() time.Time
+}
+
+// NewMFAService creates a new MFAService. The AES-256 key is derived from
+// encryptionKey; changing it makes the stored secrets unreadable.
+func NewMFAService(repo repository.MFARepository, txRepo repository.TxRepository, encryptionKey, issuer string) (*MFAService, error) {
+	if encryptionKey == "" {
+		return nil, errors.New("mfa encryption key is empty")
+	}
+
+	key := sha256.Sum256([]byte(encryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+
+	return &MFAService{
+		repo:   repo,
+		txRepo: txRepo,
+		aead:   aead,
+		issuer: issuer,
+		now:    time.Now,
+	}, nil
+}
+
+// State returns the second factor state of the staff member
+func (s *MFAService) State(ctx context.Context, staffID int64) (*models.StaffMFA, error) {
+	return s.repo.Get(ctx, staffID)
+}
+
+// Pending reports whether the role of the staff member requires a second
+// factor that is not enrolled yet
+func (s *MFAService) Pending(ctx context.Context, staffID int64) (bool, error) {
+	mfa, err := s.repo.Get(ctx, staffID)
+	if err != nil {
+		return false, err
+	}
+	return mfa.Pending(), nil
+}
+
+// Enroll generates a new secret. It takes effect only after Confirm, so an
+// abandoned enrollment does not lock the staff member out.
+func (s *MFAService) Enroll(ctx context.Context, staffID int64) (*models.MFAEnrollment, error) {
+	mfa, err := s.repo.Get(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	secret := make([]byte, mfaSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate secret: %w", err)
+	}
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetSecret(ctx, staffID, encrypted); err != nil {
+		return nil, err
+	}
+
+	encoded := base32NoPadding.EncodeToString(secret)
+	return &models.MFAEnrollment{
+		Secret: encoded,
+		URL:    s.otpauthURL(mfa.Email, encoded),
+	}, nil
+}
+
+// Confirm enables the enrolled secret once the staff member proves it works
+// and returns the recovery codes. The codes are shown only once.
+func (s *MFAService) Confirm(ctx context.Context, staffID int64, code string) ([]string, error) {
+	mfa, err := s.repo.Get(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+	if mfa.Secret == nil {
+		return nil, models.ErrMFANotEnrolled
+	}
+	if err := s.verify(ctx, mfa, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		if err := s.repo.Enable(txCtx, staffID); err != nil {
+			return err
+		}
+		return s.repo.ReplaceRecoveryCodes(txCtx, staffID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, staffID int64, code string) ([]string, error) {
+	mfa, err := s.enabled(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(ctx, mfa, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		return s.repo.ReplaceRecoveryCodes(txCtx, staffID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns the second factor off. A role requiring it keeps it on.
+func (s *MFAService) Disable(ctx context.Context, staffID int64, code string) error {
+	mfa, err := s.enabled(ctx, staffID)
+	if err != nil {
+		return err
+	}
+	if mfa.Required {
+		return models.ErrMFARequired
+	}
+	if err := s.verify(ctx, mfa, code, true); err != nil {
+		return err
+	}
+	return s.repo.Disable(ctx, staffID)
+}
+
+// Verify checks the second factor on login: a TOTP code or a recovery code
+func (s *MFAService) Verify(ctx context.Context, staffID int64, code string) error {
+	mfa, err := s.enabled(ctx, staffID)
+	if err != nil {
+		return err
+	}
+	return s.verify(ctx, mfa, code, true)
+}
+
+func (s *MFAService) enabled(ctx context.Context, staffID int64) (*models.StaffMFA, error) {
+	mfa, err := s.repo.Get(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled() {
+		return nil, models.ErrMFANotEnrolled
+	}
+	return mfa, nil
+}
+
+// verify accepts a TOTP code and, if allowed, spends a recovery code. A TOTP
+// code is accepted once: its time step must be after the last accepted one.
+func (s *MFAService) verify(ctx context.Context, mfa *models.StaffMFA, code string, allowRecovery bool) error {
+	code = normalizeMFACode(code)
+
+	if isTOTPCode(code) {
+		secret, err := s.decrypt(*mfa.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := totpStep(secret, code, s.now())
+		if !ok {
+			return models.ErrMFAInvalidCode
+		}
+		return s.repo.UseStep(ctx, mfa.StaffID, step)
+	}
+
+	if !allowRecovery || code == "" {
+		return models.ErrMFAInvalidCode
+	}
+	return s.repo.UseRecoveryCode(ctx, mfa.StaffID, hashRecoveryCode(code))
+}
+
+func (s *MFAService) otpauthURL(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + s.issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// encrypt seals the secret; the nonce is stored in front of the ciphertext
+func (s *MFAService) encrypt(secret []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, secret, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *MFAService) decrypt(encrypted string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decode mfa secret: %w", err)
+	}
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, errors.New("decrypt mfa secret: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	secret, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt mfa secret: %w", err)
+	}
+	return secret, nil
+}
+
+// newRecoveryCodes returns the codes to show and their hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		code := strings.ToLower(base32NoPadding.EncodeToString(b))
+		codes = append(codes, code[:len(code)/2]+"-"+code[len(code)/2:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeMFACode drops the separators users copy along with a code
+func normalizeMFACode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type fakeMFARepo struct {
+	repository.MFARepository
+	mfa   models.StaffMFA
+	codes map[string]bool
+}
+
+func (r *fakeMFARepo) Get(_ context.Context, _ int64) (*models.StaffMFA, error) {
+	mfa := r.mfa
+	return &mfa, nil
+}
+
+func (r *fakeMFARepo) SetSecret(_ context.Context, _ int64, secret string) error {
+	r.mfa.Secret = &secret
+	return nil
+}
+
+func (r *fakeMFARepo) Enable(_ context.Context, _ int64) error {
+	now := time.Now()
+	r.mfa.EnabledAt = &now
+	return nil
+}
+
+func (r *fakeMFARepo) Disable(_ context.Context, _ int64) error {
+	r.mfa.Secret, r.mfa.EnabledAt, r.mfa.LastStep = nil, nil, nil
+	r.codes = nil
+	return nil
+}
+
+func (r *fakeMFARepo) UseStep(_ context.Context, _ int64, step int64) error {
+	if r.mfa.LastStep != nil && *r.mfa.LastStep >= step {
+		return models.ErrMFAInvalidCode
+	}
+	r.mfa.LastStep = &step
+	return nil
+}
+
+func (r *fakeMFARepo) ReplaceRecoveryCodes(_ context.Context, _ int64, hashes []string) error {
+	r.codes = make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		r.codes[h] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCode(_ context.Context, _ int64, hash string) error {
+	used, ok := r.codes[hash]
+	if !ok || used {
+		return models.ErrMFAInvalidCode
+	}
+	r.codes[hash] = true
+	return nil
+}
+
+type fakeTxRepo struct {
+	repository.TxRepository
+}
+
+func (fakeTxRepo) RunToTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestMFAService(t *testing.T) (*MFAService, *fakeMFARepo) {
+	t.Helper()
+	repo := &fakeMFARepo{mfa: models.StaffMFA{StaffID: 1, Email: "admin@test.local"}}
+	svc, err := NewMFAService(repo, fakeTxRepo{}, "test-key", "Test")
+	require.NoError(t, err)
+	return svc, repo
+}
+
+// enrollMFA enrolls and confirms a secret, returning it with the recovery codes
+func enrollMFA(t *testing.T, svc *MFAService, now time.Time) ([]byte, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := svc.Enroll(ctx, 1)
+	require.NoError(t, err)
+	secret, err := base32NoPadding.DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now }
+	codes, err := svc.Confirm(ctx, 1, totpCode(secret, now.Unix()/totpPeriod))
+	require.NoError(t, err)
+	return secret, codes
+}
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	// RFC 6238 appendix B, SHA1, truncated to 6 digits
+	assert.Equal(t, "287082", totpCode(secret, 59/totpPeriod))
+	assert.Equal(t, "081804", totpCode(secret, 1111111109/totpPeriod))
+	assert.Equal(t, "050471", totpCode(secret, 1111111111/totpPeriod))
+	assert.Equal(t, "005924", totpCode(secret, 1234567890/totpPeriod))
+}
+
+func TestTOTPStep_Skew(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / totpPeriod
+
+	for _, step := range []int64{current - 1, current, current + 1} {
+		got, ok := totpStep(secret, totpCode(secret, step), now)
+		assert.True(t, ok)
+		assert.Equal(t, step, got)
+	}
+
+	_, ok := totpStep(secret, totpCode(secret, current-2), now)
+	assert.False(t, ok, "codes older than the allowed drift are rejected")
+}
+
+func TestMFAService_EncryptsSecret(t *testing.T) {
+	svc, repo := newTestMFAService(t)
+
+	enrollment, err := svc.Enroll(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.URL, "otpauth://totp/Test:admin@test.local?"))
+
+	require.NotNil(t, repo.mfa.Secret)
+	assert.NotContains(t, *repo.mfa.Secret, enrollment.Secret)
+
+	secret, err := svc.decrypt(*repo.mfa.Secret)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, base32NoPadding.EncodeToString(secret))
+
+	other, err := NewMFAService(repo, fakeTxRepo{}, "other-key", "Test")
+	require.NoError(t, err)
+	_, err = other.decrypt(*repo.mfa.Secret)
+	assert.Error(t, err, "a secret cannot be read with another key")
+}
+
+func TestMFAService_Confirm(t *testing.T) {
+	svc, repo := newTestMFAService(t)
+	ctx := context.Background()
+
+	_, err := svc.Confirm(ctx, 1, "123456")
+	assert.ErrorIs(t, err, models.ErrMFANotEnrolled)
+
+	_, codes := enrollMFA(t, svc, time.Now())
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Len(t, repo.codes, recoveryCodeCount, "only hashes are stored")
+	for _, code := range codes {
+		_, stored := repo.codes[code]
+		assert.False(t, stored)
+	}
+
+	_, err = svc.Enroll(ctx, 1)
+	assert.ErrorIs(t, err, models.ErrMFAAlreadyEnabled)
+}
+
+func TestMFAService_Verify(t *testing.T) {
+	svc, _ := newTestMFAService(t)
+	ctx := context.Background()
+	now := time.Now()
+	secret, codes := enrollMFA(t, svc, now)
+
+	err := svc.Verify(ctx, 1, totpCode(secret, now.Unix()/totpPeriod))
+	assert.ErrorIs(t, err, models.ErrMFAInvalidCode, "an accepted code cannot be replayed")
+
+	later := now.Add(totpPeriod * time.Second)
+	svc.now = func() time.Time { return later }
+	assert.NoError(t, svc.Verify(ctx, 1, totpCode(secret, later.Unix()/totpPeriod)))
+
+	assert.ErrorIs(t, svc.Verify(ctx, 1, "000000x"), models.ErrMFAInvalidCode)
+
+	assert.NoError(t, svc.Verify(ctx, 1, " "+strings.ToUpper(codes[0])+" "))
+	assert.ErrorIs(t, svc.Verify(ctx, 1, codes[0]), models.ErrMFAInvalidCode, "recovery codes are single use")
+}
+
+func TestMFAService_Disable(t *testing.T) {
+	svc, repo := newTestMFAService(t)
+	ctx := context.Background()
+	_, codes := enrollMFA(t, svc, time.Now())
+
+	repo.mfa.Required = true
+	assert.ErrorIs(t, svc.Disable(ctx, 1, codes[0]), models.ErrMFARequired)
+
+	repo.mfa.Required = false
+	assert.ErrorIs(t, svc.Disable(ctx, 1, "wrong-code"), models.ErrMFAInvalidCode)
+	assert.NoError(t, svc.Disable(ctx, 1, codes[0]))
+
+	state, err := svc.State(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, state.Enabled())
+}
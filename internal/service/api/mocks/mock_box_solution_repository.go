@@ -302,6 +302,49 @@ func (mr *MockBookingRepositoryMockRecorder) GetBookingsList(ctx, filter any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingsList", reflect.TypeOf((*MockBookingRepository)(nil).GetBookingsList), ctx, filter)
 }
 
+// LockBooking mocks base method.
+func (m *MockBookingRepository) LockBooking(ctx context.Context, id int64) (*models.BookingAPI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBooking", ctx, id)
+	ret0, _ := ret[0].(*models.BookingAPI)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBooking indicates an expected call of LockBooking.
+func (mr *MockBookingRepositoryMockRecorder) LockBooking(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBooking", reflect.TypeOf((*MockBookingRepository)(nil).LockBooking), ctx, id)
+}
+
+// RescheduleBooking mocks base method.
+func (m *MockBookingRepository) RescheduleBooking(ctx context.Context, id int64, date time.Time, bookingTime *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleBooking", ctx, id, date, bookingTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleBooking indicates an expected call of RescheduleBooking.
+func (mr *MockBookingRepositoryMockRecorder) RescheduleBooking(ctx, id, date, bookingTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleBooking", reflect.TypeOf((*MockBookingRepository)(nil).RescheduleBooking), ctx, id, date, bookingTime)
+}
+
+// ReserveBookingSlot mocks base method.
+func (m *MockBookingRepository) ReserveBookingSlot(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveBookingSlot", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveBookingSlot indicates an expected call of ReserveBookingSlot.
+func (mr *MockBookingRepositoryMockRecorder) ReserveBookingSlot(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveBookingSlot", reflect.TypeOf((*MockBookingRepository)(nil).ReserveBookingSlot), ctx, id)
+}
+
 // UpdateBookingStatus mocks base method.
 func (m *MockBookingRepository) UpdateBookingStatus(ctx context.Context, bookingID int64, status string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// PermissionService resolves the effective permissions of staff members:
+// the role matrix from role_permissions merged with personal overrides.
+type PermissionService struct {
+	cache     repository.PermissionCache
+	staffRepo repository.StaffRepository
+}
+
+// NewPermissionService creates a new PermissionService.
+func NewPermissionService(cache repository.PermissionCache, staffRepo repository.StaffRepository) *PermissionService {
+	return &PermissionService{cache: cache, staffRepo: staffRepo}
+}
+
+// Effective returns what the staff member may do. The admin has every
+// permission and the user role has none, regardless of overrides.
+func (s *PermissionService) Effective(ctx context.Context, staffID int64, role string) ([]string, error) {
+	switch role {
+	case RoleAdmin:
+		return slices.Sorted(maps.Keys(models.MapPermissions)), nil
+	case RoleUser:
+		return []string{}, nil
+	}
+
+	rolePermissions, err := s.cache.Permissions(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.cache.Overrides(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	return overrides.Apply(rolePermissions), nil
+}
+
+// Get returns the overrides of the staff member and the permissions they result in
+func (s *PermissionService) Get(ctx context.Context, staffID int64) (*models.StaffPermissions, []string, error) {
+	permissions, err := s.staffRepo.GetPermissions(ctx, staffID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	effective, err := s.Effective(ctx, staffID, permissions.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	return permissions, effective, nil
+}
+
+// Update replaces the overrides of the staff member. Every permission must be
+// known and none may be both granted and denied.
+func (s *PermissionService) Update(ctx context.Context, staffID int64, overrides models.PermissionOverrides) (*models.StaffPermissions, []string, error) {
+	granted, err := normalizePermissions(overrides.Granted)
+	if err != nil {
+		return nil, nil, err
+	}
+	denied, err := normalizePermissions(overrides.Denied)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, permission := range granted {
+		if slices.Contains(denied, permission) {
+			return nil, nil, models.ErrPermissionConflict
+		}
+	}
+
+	permissions, err := s.staffRepo.UpdatePermissionOverrides(ctx, staffID, models.PermissionOverrides{
+		Granted: granted,
+		Denied:  denied,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	effective, err := s.Effective(ctx, staffID, permissions.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+	return permissions, effective, nil
+}
+
+// normalizePermissions checks the permissions and drops duplicates
+func normalizePermissions(permissions []string) ([]string, error) {
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !models.MapPermissions[permission] {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownPermission, permission)
+		}
+		if !slices.Contains(result, permission) {
+			result = append(result, permission)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type fakePermissionCache struct {
+	repository.PermissionCache
+	roles     map[string][]string
+	overrides map[int64]models.PermissionOverrides
+}
+
+func (c *fakePermissionCache) Permissions(_ context.Context, role string) ([]string, error) {
+	return c.roles[role], nil
+}
+
+func (c *fakePermissionCache) Overrides(_ context.Context, staffID int64) (models.PermissionOverrides, error) {
+	return c.overrides[staffID], nil
+}
+
+type fakePermissionStaffRepo struct {
+	repository.StaffRepository
+	cache *fakePermissionCache
+	roles map[int64]string
+}
+
+func (r *fakePermissionStaffRepo) UpdatePermissionOverrides(_ context.Context, id int64, overrides models.PermissionOverrides) (*models.StaffPermissions, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	r.cache.overrides[id] = overrides
+	return &models.StaffPermissions{StaffID: id, Role: role, PermissionOverrides: overrides}, nil
+}
+
+func newTestPermissionService() *PermissionService {
+	cache := &fakePermissionCache{
+		roles: map[string][]string{
+			RoleManager1: {models.PermBookingsView, models.PermBookingsEdit},
+		},
+		overrides: map[int64]models.PermissionOverrides{},
+	}
+	staff := &fakePermissionStaffRepo{cache: cache, roles: map[int64]string{1: RoleManager1, 2: RoleAdmin}}
+	return NewPermissionService(cache, staff)
+}
+
+func TestPermissionService_Update(t *testing.T) {
+	svc := newTestPermissionService()
+	ctx := context.Background()
+
+	_, effective, err := svc.Update(ctx, 1, models.PermissionOverrides{
+		Granted: []string{models.PermAnalyticsView, models.PermAnalyticsView},
+		Denied:  []string{models.PermBookingsEdit},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.PermBookingsView, models.PermAnalyticsView}, effective)
+
+	_, _, err = svc.Update(ctx, 1, models.PermissionOverrides{Granted: []string{"boxes:fly"}})
+	assert.ErrorIs(t, err, models.ErrUnknownPermission)
+
+	_, _, err = svc.Update(ctx, 1, models.PermissionOverrides{
+		Granted: []string{models.PermBoxesEdit},
+		Denied:  []string{models.PermBoxesEdit},
+	})
+	assert.ErrorIs(t, err, models.ErrPermissionConflict)
+
+	_, _, err = svc.Update(ctx, 42, models.PermissionOverrides{})
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestPermissionService_EffectiveAdmin(t *testing.T) {
+	svc := newTestPermissionService()
+
+	effective, err := svc.Effective(context.Background(), 2, RoleAdmin)
+	require.NoError(t, err)
+	assert.Len(t, effective, len(models.MapPermissions))
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// roleNamePattern keeps role names usable in URLs and cache keys
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+// RoleService manages the staff roles defined by the admin.
+type RoleService struct {
+	repo repository.RoleRepository
+}
+
+// NewRoleService creates a new RoleService.
+func NewRoleService(repo repository.RoleRepository) *RoleService {
+	return &RoleService{repo: repo}
+}
+
+func (s *RoleService) List(ctx context.Context) ([]models.Role, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *RoleService) Get(ctx context.Context, name string) (*models.Role, error) {
+	return s.repo.Get(ctx, name)
+}
+
+func (s *RoleService) Create(ctx context.Context, role *models.Role) (*models.Role, error) {
+	if !roleNamePattern.MatchString(role.Name) {
+		return nil, fmt.Errorf("%w: role name %q", models.ErrInvalidInput, role.Name)
+	}
+	return s.repo.Create(ctx, role)
+}
+
+func (s *RoleService) Update(ctx context.Context, name string, upd *models.RoleUpdate) (*models.Role, error) {
+	return s.repo.Update(ctx, name, upd)
+}
+
+// Delete deletes a custom role together with its permissions. System roles
+// and roles still assigned to someone are kept.
+func (s *RoleService) Delete(ctx context.Context, name string) error {
+	role, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return models.ErrSystemRole
+	}
+	return s.repo.Delete(ctx, name)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type fakeRoleRepo struct {
+	repository.RoleRepository
+	roles map[string]models.Role
+}
+
+func (r *fakeRoleRepo) Get(_ context.Context, name string) (*models.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, models.ErrRoleNotFound
+	}
+	return &role, nil
+}
+
+func (r *fakeRoleRepo) Create(_ context.Context, role *models.Role) (*models.Role, error) {
+	if _, ok := r.roles[role.Name]; ok {
+		return nil, models.ErrRoleAlreadyExist
+	}
+	r.roles[role.Name] = *role
+	return role, nil
+}
+
+func (r *fakeRoleRepo) Delete(_ context.Context, name string) error {
+	delete(r.roles, name)
+	return nil
+}
+
+func newTestRoleService() (*RoleService, *fakeRoleRepo) {
+	repo := &fakeRoleRepo{roles: map[string]models.Role{
+		RoleAdmin:    {Name: RoleAdmin, IsSystem: true},
+		RoleManager1: {Name: RoleManager1},
+	}}
+	return NewRoleService(repo), repo
+}
+
+func TestRoleService_Create(t *testing.T) {
+	svc, _ := newTestRoleService()
+	ctx := context.Background()
+
+	role, err := svc.Create(ctx, &models.Role{Name: "content_editor", Title: "Редактор"})
+	require.NoError(t, err)
+	assert.Equal(t, "content_editor", role.Name)
+
+	for _, name := range []string{"", "Editor", "1st", "editor-1", "a"} {
+		_, err = svc.Create(ctx, &models.Role{Name: name, Title: "x"})
+		assert.ErrorIs(t, err, models.ErrInvalidInput, name)
+	}
+
+	_, err = svc.Create(ctx, &models.Role{Name: RoleManager1, Title: "x"})
+	assert.ErrorIs(t, err, models.ErrRoleAlreadyExist)
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	svc, repo := newTestRoleService()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.Delete(ctx, RoleAdmin), models.ErrSystemRole)
+	assert.ErrorIs(t, svc.Delete(ctx, "ghost"), models.ErrRoleNotFound)
+
+	require.NoError(t, svc.Delete(ctx, RoleManager1))
+	assert.NotContains(t, repo.roles, RoleManager1)
+	assert.Contains(t, repo.roles, RoleAdmin)
+}
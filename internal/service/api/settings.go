@@ -8,6 +8,7 @@ import (
 
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
 	"github.com/yandex-development-1-team/go/internal/repository"
 )
 
@@ -40,6 +41,10 @@ func (a SettingsService) GetSettingsPermissions(ctx context.Context, role string
 }
 
 func (a SettingsService) PutSettings(ctx context.Context, reqService models.SettingsFormMessages) error {
+	if err := validateMessageTemplates(reqService); err != nil {
+		return err
+	}
+
 	err := a.settingsRepo.PutSettings(ctx, reqService)
 	if err != nil {
 		logger.Error("failed to get settings messages from service", zap.Error(err))
@@ -49,6 +54,14 @@ func (a SettingsService) PutSettings(ctx context.Context, reqService models.Sett
 	return nil
 }
 
+// PreviewMessage renders the message template as the bot would send it
+func (a SettingsService) PreviewMessage(text string, data msgtemplate.Data) (string, error) {
+	if err := msgtemplate.Validate(text); err != nil {
+		return "", err
+	}
+	return msgtemplate.Render(text, data)
+}
+
 func (a SettingsService) PostSettings(ctx context.Context, reqService models.SettingsPermissions) error {
 	err := a.settingsRepo.PostSettings(ctx, reqService)
 	if err != nil {
@@ -59,6 +72,21 @@ func (a SettingsService) PostSettings(ctx context.Context, reqService models.Set
 	return nil
 }
 
+// validateMessageTemplates checks every message template so that a broken one
+// is rejected on save instead of failing in the bot
+func validateMessageTemplates(messages models.SettingsFormMessages) error {
+	fields := map[string]string{}
+	for key, text := range messages.Messages() {
+		if err := msgtemplate.Validate(text); err != nil {
+			fields[key] = err.Error()
+		}
+	}
+	if len(fields) > 0 {
+		return &models.MessageTemplateError{Fields: fields}
+	}
+	return nil
+}
+
 //func (a SettingsService) GetSettings(ctx context.Context) ([]models.Setting, error) {
 //	settingsDB, err := a.settingsRepo.GetSettings(ctx)
 //	if err != nil {
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/i18n"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type fakeSettingsRepo struct {
+	repository.SettingsRepository
+	saved *models.SettingsFormMessages
+}
+
+func (r *fakeSettingsRepo) PutSettings(_ context.Context, settings models.SettingsFormMessages) error {
+	r.saved = &settings
+	return nil
+}
+
+func TestSettingsService_PutSettingsValidatesTemplates(t *testing.T) {
+	repo := &fakeSettingsRepo{}
+	svc := NewSettingsService(repo)
+
+	err := svc.PutSettings(context.Background(), models.SettingsFormMessages{
+		WelcomeMessage:      "Привет, {{.GuestName}}",
+		ThanksMessage:       "{{.Phone}}",
+		CancellationMessage: "{{.Date",
+	})
+
+	var templateErr *models.MessageTemplateError
+	require.True(t, errors.As(err, &templateErr))
+	assert.ErrorIs(t, err, models.ErrValidation)
+	assert.Equal(t, []string{"cancellation_message", "thanks_message"}, keys(templateErr.Messages()))
+	assert.Nil(t, repo.saved, "invalid templates must not be saved")
+
+	err = svc.PutSettings(context.Background(), models.SettingsFormMessages{WelcomeMessage: "Привет, {{.GuestName}}"})
+	require.NoError(t, err)
+	require.NotNil(t, repo.saved)
+}
+
+func TestSettingsService_PreviewMessage(t *testing.T) {
+	svc := NewSettingsService(&fakeSettingsRepo{})
+
+	data := msgtemplate.Sample(i18n.EN)
+	data.GuestName = "Anna"
+
+	text, err := svc.PreviewMessage(`{{if eq .Lang "en"}}Hello{{else}}Привет{{end}}, {{.GuestName}}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Anna", text)
+
+	_, err = svc.PreviewMessage("{{.Unknown}}", data)
+	assert.ErrorIs(t, err, models.ErrValidation)
+}
+
+// keys takes the settings keys from "key: reason" messages
+func keys(messages []string) []string {
+	out := make([]string, 0, len(messages))
+	for _, message := range messages {
+		key, _, _ := strings.Cut(message, ":")
+		out = append(out, key)
+	}
+	return out
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	// telegramLinkAlphabet leaves out letters and digits that are easy to mix up
+	telegramLinkAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	telegramLinkCodeLength = 8
+	// staffDeskLimit is how many new items the bot lists at once
+	staffDeskLimit = 10
+)
+
+// StaffBookings is the part of BookingsService the staff desk works with
+type StaffBookings interface {
+	GetBookingsList(ctx context.Context, filter *models.ApplicationFilter) (*models.BookingList, error)
+	GetBookingById(ctx context.Context, id int64) (*models.BookingAPI, error)
+	UpdateBookingStatus(ctx context.Context, id int64, status string) (*models.BookingAPI, error)
+}
+
+// StaffApplications is the part of ApplicationsService the staff desk works with
+type StaffApplications interface {
+	ApplicationsList(ctx context.Context, filter *models.ApplicationFilter) (*models.ApplicationList, error)
+	GetApplicationByID(ctx context.Context, id int64) (*models.Application, error)
+	UpdateApplicationStatus(ctx context.Context, id int64, status string) (*models.Application, error)
+}
+
+// StaffTelegramService links staff accounts to Telegram and serves the staff
+// desk of the bot: the new bookings and applications assigned to a linked
+// staff member, which they confirm or cancel with the same permissions as in
+// the admin API.
+type StaffTelegramService struct {
+	repo         repository.StaffTelegramRepository
+	permissions  *PermissionService
+	bookings     StaffBookings
+	applications StaffApplications
+	codeTTL      time.Duration
+	now          func() time.Time
+}
+
+func NewStaffTelegramService(repo repository.StaffTelegramRepository, permissions *PermissionService,
+	bookings StaffBookings, applications StaffApplications, codeTTLMinutes int) *StaffTelegramService {
+	return &StaffTelegramService{
+		repo:         repo,
+		permissions:  permissions,
+		bookings:     bookings,
+		applications: applications,
+		codeTTL:      time.Duration(codeTTLMinutes) * time.Minute,
+		now:          time.Now,
+	}
+}
+
+// CreateLinkCode issues a one-time code for the staff member to send to the
+// bot. A new code replaces the previous one.
+func (s *StaffTelegramService) CreateLinkCode(ctx context.Context, staffID int64) (*models.TelegramLinkCode, error) {
+	code, err := generateTelegramLinkCode()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.now().Add(s.codeTTL)
+	if err := s.repo.SetLinkCode(ctx, staffID, hashTelegramLinkCode(code), expiresAt); err != nil {
+		return nil, err
+	}
+	return &models.TelegramLinkCode{Code: code, ExpiresAt: expiresAt}, nil
+}
+
+// Link links the Telegram account to the staff member the code was issued to
+func (s *StaffTelegramService) Link(ctx context.Context, code string, telegramID int64) (*models.LinkedStaff, error) {
+	code = normalizeTelegramLinkCode(code)
+	if len(code) != telegramLinkCodeLength {
+		return nil, models.ErrTelegramLinkInvalid
+	}
+	return s.repo.Link(ctx, hashTelegramLinkCode(code), telegramID)
+}
+
+func (s *StaffTelegramService) Unlink(ctx context.Context, staffID int64) error {
+	return s.repo.Unlink(ctx, staffID)
+}
+
+// Staff returns the staff member linked to the Telegram account with their
+// effective permissions, or ErrTelegramNotLinked for a guest
+func (s *StaffTelegramService) Staff(ctx context.Context, telegramID int64) (*models.LinkedStaff, error) {
+	staff, err := s.repo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.permissions != nil {
+		staff.Permissions, err = s.permissions.Effective(ctx, staff.ID, staff.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return staff, nil
+}
+
+// NewBookings returns the pending bookings assigned to the staff member
+func (s *StaffTelegramService) NewBookings(ctx context.Context, staff *models.LinkedStaff) ([]models.BookingAPI, error) {
+	if !staff.Can(models.PermBookingsView) {
+		return nil, models.ErrForbidden
+	}
+
+	list, err := s.bookings.GetBookingsList(ctx, &models.ApplicationFilter{
+		Status:    models.BookingStatusPending,
+		ManagerID: staff.ID,
+		Limit:     staffDeskLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// NewApplications returns the pending applications assigned to the staff member
+func (s *StaffTelegramService) NewApplications(ctx context.Context, staff *models.LinkedStaff) ([]models.Application, error) {
+	if !staff.Can(models.PermSpecProjectView) {
+		return nil, models.ErrForbidden
+	}
+
+	list, err := s.applications.ApplicationsList(ctx, &models.ApplicationFilter{
+		Status:    models.BookingStatusPending,
+		ManagerID: staff.ID,
+		Limit:     staffDeskLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// SetBookingStatus confirms or cancels a booking assigned to the staff member
+func (s *StaffTelegramService) SetBookingStatus(ctx context.Context, staff *models.LinkedStaff, id int64, status string) (*models.BookingAPI, error) {
+	if err := checkDeskStatus(status); err != nil {
+		return nil, err
+	}
+	if !staff.Can(models.PermBookingsEdit) {
+		return nil, models.ErrForbidden
+	}
+
+	booking, err := s.bookings.GetBookingById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.ManagerID != staff.ID {
+		return nil, models.ErrForbidden
+	}
+	return s.bookings.UpdateBookingStatus(ctx, id, status)
+}
+
+// SetApplicationStatus confirms or cancels an application assigned to the staff member
+func (s *StaffTelegramService) SetApplicationStatus(ctx context.Context, staff *models.LinkedStaff, id int64, status string) (*models.Application, error) {
+	if err := checkDeskStatus(status); err != nil {
+		return nil, err
+	}
+	if !staff.Can(models.PermSpecProjectEdit) {
+		return nil, models.ErrForbidden
+	}
+
+	app, err := s.applications.GetApplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if app.ManagerID != staff.ID {
+		return nil, models.ErrForbidden
+	}
+	return s.applications.UpdateApplicationStatus(ctx, id, status)
+}
+
+// checkDeskStatus allows the statuses a bot button may set
+func checkDeskStatus(status string) error {
+	if status != models.BookingStatusConfirmed && status != models.BookingStatusCancelled {
+		return fmt.Errorf("%w: status %q", models.ErrInvalidInput, status)
+	}
+	return nil
+}
+
+func generateTelegramLinkCode() (string, error) {
+	b := make([]byte, telegramLinkCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate telegram link code: %w", err)
+	}
+	// The alphabet has 32 letters, so the remainder keeps the bytes uniform
+	for i := range b {
+		b[i] = telegramLinkAlphabet[int(b[i])%len(telegramLinkAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeTelegramLinkCode accepts the code typed in lower case or split with spaces or dashes
+func normalizeTelegramLinkCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+func hashTelegramLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
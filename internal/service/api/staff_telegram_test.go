@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeStaffTelegramRepo struct {
+	codes   map[string]int64
+	expires map[string]time.Time
+	linked  map[int64]*models.LinkedStaff
+	roles   map[int64]string
+}
+
+func (r *fakeStaffTelegramRepo) SetLinkCode(_ context.Context, staffID int64, codeHash string, expiresAt time.Time) error {
+	if _, ok := r.roles[staffID]; !ok {
+		return models.ErrUserNotFound
+	}
+	r.codes[codeHash] = staffID
+	r.expires[codeHash] = expiresAt
+	return nil
+}
+
+func (r *fakeStaffTelegramRepo) Link(_ context.Context, codeHash string, telegramID int64) (*models.LinkedStaff, error) {
+	staffID, ok := r.codes[codeHash]
+	if !ok || !time.Now().Before(r.expires[codeHash]) {
+		return nil, models.ErrTelegramLinkInvalid
+	}
+	delete(r.codes, codeHash)
+	staff := &models.LinkedStaff{ID: staffID, TelegramID: telegramID, Role: r.roles[staffID]}
+	r.linked[telegramID] = staff
+	return staff, nil
+}
+
+func (r *fakeStaffTelegramRepo) Unlink(_ context.Context, staffID int64) error {
+	for telegramID, staff := range r.linked {
+		if staff.ID == staffID {
+			delete(r.linked, telegramID)
+		}
+	}
+	return nil
+}
+
+func (r *fakeStaffTelegramRepo) GetByTelegramID(_ context.Context, telegramID int64) (*models.LinkedStaff, error) {
+	staff, ok := r.linked[telegramID]
+	if !ok {
+		return nil, models.ErrTelegramNotLinked
+	}
+	copied := *staff
+	return &copied, nil
+}
+
+type fakeStaffBookings struct {
+	StaffBookings
+	bookings map[int64]*models.BookingAPI
+}
+
+func (b *fakeStaffBookings) GetBookingById(_ context.Context, id int64) (*models.BookingAPI, error) {
+	booking, ok := b.bookings[id]
+	if !ok {
+		return nil, models.ErrBookingNotFound
+	}
+	return booking, nil
+}
+
+func (b *fakeStaffBookings) UpdateBookingStatus(_ context.Context, id int64, status string) (*models.BookingAPI, error) {
+	b.bookings[id].Status = status
+	return b.bookings[id], nil
+}
+
+func newTestStaffTelegramService() (*StaffTelegramService, *fakeStaffTelegramRepo, *fakeStaffBookings) {
+	repo := &fakeStaffTelegramRepo{
+		codes:   map[string]int64{},
+		expires: map[string]time.Time{},
+		linked:  map[int64]*models.LinkedStaff{},
+		roles:   map[int64]string{1: RoleManager1},
+	}
+	bookings := &fakeStaffBookings{bookings: map[int64]*models.BookingAPI{
+		10: {ID: 10, ManagerID: 1, Status: models.BookingStatusPending},
+		11: {ID: 11, ManagerID: 2, Status: models.BookingStatusPending},
+	}}
+	return NewStaffTelegramService(repo, newTestPermissionService(), bookings, nil, 15), repo, bookings
+}
+
+func TestStaffTelegramService_Link(t *testing.T) {
+	svc, _, _ := newTestStaffTelegramService()
+	ctx := context.Background()
+
+	_, err := svc.CreateLinkCode(ctx, 99)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+
+	code, err := svc.CreateLinkCode(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, code.Code, telegramLinkCodeLength)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), code.ExpiresAt, time.Minute)
+
+	_, err = svc.Staff(ctx, 500)
+	assert.ErrorIs(t, err, models.ErrTelegramNotLinked)
+
+	typed := code.Code[:4] + "-" + code.Code[4:]
+	linked, err := svc.Link(ctx, typed, 500)
+	require.NoError(t, err, "the code is accepted with a separator")
+	assert.EqualValues(t, 1, linked.ID)
+
+	_, err = svc.Link(ctx, code.Code, 501)
+	assert.ErrorIs(t, err, models.ErrTelegramLinkInvalid, "the code works once")
+
+	staff, err := svc.Staff(ctx, 500)
+	require.NoError(t, err)
+	assert.True(t, staff.Can(models.PermBookingsEdit), "the role permissions are resolved")
+
+	require.NoError(t, svc.Unlink(ctx, 1))
+	_, err = svc.Staff(ctx, 500)
+	assert.ErrorIs(t, err, models.ErrTelegramNotLinked)
+}
+
+func TestStaffTelegramService_SetBookingStatus(t *testing.T) {
+	svc, _, bookings := newTestStaffTelegramService()
+	ctx := context.Background()
+	staff := &models.LinkedStaff{ID: 1, Role: RoleManager1, Permissions: []string{models.PermBookingsView, models.PermBookingsEdit}}
+
+	_, err := svc.SetBookingStatus(ctx, staff, 10, models.BookingStatusPending)
+	assert.ErrorIs(t, err, models.ErrInvalidInput, "buttons only confirm or cancel")
+
+	_, err = svc.SetBookingStatus(ctx, staff, 11, models.BookingStatusConfirmed)
+	assert.ErrorIs(t, err, models.ErrForbidden, "a booking of another manager")
+	assert.Equal(t, models.BookingStatusPending, bookings.bookings[11].Status)
+
+	viewer := &models.LinkedStaff{ID: 1, Role: RoleManager1, Permissions: []string{models.PermBookingsView}}
+	_, err = svc.SetBookingStatus(ctx, viewer, 10, models.BookingStatusConfirmed)
+	assert.ErrorIs(t, err, models.ErrForbidden, "no edit permission")
+
+	booking, err := svc.SetBookingStatus(ctx, staff, 10, models.BookingStatusConfirmed)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusConfirmed, booking.Status)
+
+	_, err = svc.NewApplications(ctx, staff)
+	assert.ErrorIs(t, err, models.ErrForbidden, "no special project permission")
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/yandex-development-1-team/go/internal/dto"
+	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
 )
@@ -40,10 +46,26 @@ func (s *UserService) GetByID(ctx context.Context, id int64) (*dto.UserWithDetai
 type UsersAdminService struct {
 	staffRepo repository.StaffRepository
 	rtRepo    repository.RefreshTokenRepository
+	roleRepo  repository.RoleRepository
+	tokens    repository.TokenRevocation
+	emailSvc  *EmailService
+	inviteTTL time.Duration
+}
+
+func NewUsersAdminService(staffRepo repository.StaffRepository, rtRepo repository.RefreshTokenRepository, roleRepo repository.RoleRepository,
+	tokens repository.TokenRevocation, emailSvc *EmailService, inviteTTLHours int) *UsersAdminService {
+	return &UsersAdminService{
+		staffRepo: staffRepo,
+		rtRepo:    rtRepo,
+		roleRepo:  roleRepo,
+		tokens:    tokens,
+		emailSvc:  emailSvc,
+		inviteTTL: time.Duration(inviteTTLHours) * time.Hour,
+	}
 }
 
-func NewUsersAdminService(staffRepo repository.StaffRepository, rtRepo repository.RefreshTokenRepository) *UsersAdminService {
-	return &UsersAdminService{staffRepo: staffRepo, rtRepo: rtRepo}
+func (s *UsersAdminService) GetByID(ctx context.Context, id int64) (*dto.UserWithDetails, error) {
+	return s.staffRepo.GetByID(ctx, id)
 }
 
 func (s *UsersAdminService) Create(ctx context.Context, req dto.UserCreateRequest) (*models.UserAPI, error) {
@@ -52,7 +74,7 @@ func (s *UsersAdminService) Create(ctx context.Context, req dto.UserCreateReques
 		status = "invited"
 	}
 
-	if err := validateStaffEnums(req.Role, status); err != nil {
+	if err := s.validateStaffEnums(ctx, req.Role, status); err != nil {
 		return nil, err
 	}
 	m := &models.StaffAdminCreate{
@@ -70,7 +92,53 @@ func (s *UsersAdminService) Create(ctx context.Context, req dto.UserCreateReques
 		Address:     req.Address,
 		InviteToken: generateInviteToken(),
 	}
-	return s.staffRepo.CreateStaffByAdmin(ctx, m)
+	if status == "invited" {
+		expiresAt := time.Now().Add(s.inviteTTL)
+		m.InviteExpiresAt = &expiresAt
+	}
+
+	user, err := s.staffRepo.CreateStaffByAdmin(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.InviteExpiresAt != nil {
+		// The staff member is created anyway: the admin can resend the invite
+		if err := s.sendInvite(ctx, user); err != nil {
+			logger.Error("failed to send invite email", zap.Int64("user_id", user.ID), zap.Error(err))
+		}
+	}
+	return user, nil
+}
+
+// ResendInvite issues a new invite link to a staff member who has not
+// accepted yet; the previous link stops working
+func (s *UsersAdminService) ResendInvite(ctx context.Context, id int64) (*models.UserAPI, error) {
+	user, err := s.staffRepo.RenewInvite(ctx, id, generateInviteToken(), time.Now().Add(s.inviteTTL))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sendInvite(ctx, user); err != nil {
+		return nil, fmt.Errorf("send invite email: %w", err)
+	}
+	return user, nil
+}
+
+// RevokeInvite makes the pending invite link unusable
+func (s *UsersAdminService) RevokeInvite(ctx context.Context, id int64) (*models.UserAPI, error) {
+	return s.staffRepo.RevokeInvite(ctx, id)
+}
+
+// Approve lets a self-registered staff member be given roles above user
+func (s *UsersAdminService) Approve(ctx context.Context, id int64) (*models.UserAPI, error) {
+	return s.staffRepo.Approve(ctx, id)
+}
+
+func (s *UsersAdminService) sendInvite(ctx context.Context, user *models.UserAPI) error {
+	if s.emailSvc == nil {
+		return nil
+	}
+	return s.emailSvc.SendInviteEmail(ctx, user.Email, user.Name, user.InviteToken, *user.InviteExpiresAt)
 }
 
 func (s *UsersAdminService) Update(ctx context.Context, id int64, req dto.UserUpdateRequest) (*models.UserAPI, error) {
@@ -88,17 +156,39 @@ func (s *UsersAdminService) Update(ctx context.Context, id int64, req dto.UserUp
 		Supervisor:  req.Supervisor,
 		Address:     req.Address,
 	}
+	var previousRole string
 	if u.Role != nil {
-		if err := validateRole(*u.Role); err != nil {
+		if err := s.validateRole(ctx, *u.Role); err != nil {
+			return nil, err
+		}
+		current, err := s.staffRepo.GetPermissions(ctx, id)
+		if err != nil {
 			return nil, err
 		}
+		previousRole = current.Role
 	}
 	if u.Status != nil {
 		if err := validateStatus(*u.Status); err != nil {
 			return nil, err
 		}
 	}
-	return s.staffRepo.UpdateStaff(ctx, id, u)
+
+	user, err := s.staffRepo.UpdateStaff(ctx, id, u)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case user.Status == "blocked":
+		err = s.endAccess(ctx, id)
+	case u.Role != nil && user.Role != previousRole:
+		// Tokens carry the role: the user has to get new ones
+		err = s.revokeAccessTokens(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (s *UsersAdminService) UpdateStatus(ctx context.Context, id int64, status string) (*models.UserAPI, error) {
@@ -106,29 +196,49 @@ func (s *UsersAdminService) UpdateStatus(ctx context.Context, id int64, status s
 	if err != nil {
 		return nil, err
 	}
-	// удаляем refresh tokens только при блокировке
+	// завершаем сессии и отзываем выданные токены только при блокировке
 	if user.Status == "blocked" {
-		if err := s.rtRepo.DeleteByStaffID(ctx, id); err != nil {
+		if err := s.endAccess(ctx, id); err != nil {
 			return nil, err
 		}
 	}
 	return user, nil
 }
 
-func validateStaffEnums(role, status string) error {
-	if err := validateRole(role); err != nil {
+// endAccess ends all sessions of the user and invalidates their access tokens
+func (s *UsersAdminService) endAccess(ctx context.Context, id int64) error {
+	if err := s.rtRepo.DeleteByStaffID(ctx, id); err != nil {
 		return err
 	}
-	return validateStatus(status)
+	return s.revokeAccessTokens(ctx, id)
 }
 
-func validateRole(role string) error {
-	switch role {
-	case "admin", "manager_1", "manager_2", "manager_3", "user":
+func (s *UsersAdminService) revokeAccessTokens(ctx context.Context, id int64) error {
+	if s.tokens == nil {
 		return nil
-	default:
+	}
+	return s.tokens.RevokeAll(ctx, id)
+}
+
+func (s *UsersAdminService) validateStaffEnums(ctx context.Context, role, status string) error {
+	if err := s.validateRole(ctx, role); err != nil {
+		return err
+	}
+	return validateStatus(status)
+}
+
+// validateRole checks that the role is defined in the roles table
+func (s *UsersAdminService) validateRole(ctx context.Context, role string) error {
+	if role == "" {
 		return models.ErrInvalidInput
 	}
+	if _, err := s.roleRepo.Get(ctx, role); err != nil {
+		if errors.Is(err, models.ErrRoleNotFound) {
+			return models.ErrInvalidInput
+		}
+		return err
+	}
+	return nil
 }
 
 func validateStatus(status string) error {
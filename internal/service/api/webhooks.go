@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
+)
+
+// WebhookService manages the webhook subscriptions of external systems.
+type WebhookService struct {
+	repo repository.WebhookRepository
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+// Create validates and saves the subscription. A secret is generated when
+// the admin did not provide one.
+func (s *WebhookService) Create(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	sub.URL = strings.TrimSpace(sub.URL)
+	if err := validateWebhookURL(sub.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(sub.Events)
+	if err != nil {
+		return nil, err
+	}
+	sub.Events = events
+
+	if sub.Secret == "" {
+		if sub.Secret, err = webhook.GenerateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *WebhookService) Update(ctx context.Context, id int64, upd *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	if upd.URL != nil {
+		trimmed := strings.TrimSpace(*upd.URL)
+		upd.URL = &trimmed
+		if err := validateWebhookURL(trimmed); err != nil {
+			return nil, err
+		}
+	}
+	if upd.Events != nil {
+		events, err := normalizeWebhookEvents(upd.Events)
+		if err != nil {
+			return nil, err
+		}
+		upd.Events = events
+	}
+
+	return s.repo.UpdateSubscription(ctx, id, upd)
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns the delivery log of the subscription
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) (*models.WebhookDeliveryList, error) {
+	if _, err := s.repo.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookEvents checks the events and drops duplicates
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: no events", models.ErrUnknownWebhookEvent)
+	}
+
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		if !webhook.IsKnownEvent(event) {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownWebhookEvent, event)
+		}
+		if !slices.Contains(result, event) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
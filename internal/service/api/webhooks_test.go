@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
+)
+
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	created *models.WebhookSubscription
+	updated *models.WebhookSubscriptionUpdate
+}
+
+func (r *fakeWebhookRepo) CreateSubscription(_ context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	r.created = sub
+	return sub, nil
+}
+
+func (r *fakeWebhookRepo) UpdateSubscription(_ context.Context, id int64, upd *models.WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	r.updated = upd
+	return &models.WebhookSubscription{ID: id, URL: *upd.URL}, nil
+}
+
+func TestWebhookService_TrimsURL(t *testing.T) {
+	repo := &fakeWebhookRepo{}
+	svc := NewWebhookService(repo)
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, &models.WebhookSubscription{URL: "  https://example.com/hook\n", Events: []string{webhook.EventBookingCreated}})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", repo.created.URL)
+
+	url := " https://example.com/other "
+	_, err = svc.Update(ctx, 1, &models.WebhookSubscriptionUpdate{URL: &url})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", *repo.updated.URL)
+
+	blank := "   "
+	_, err = svc.Update(ctx, 1, &models.WebhookSubscriptionUpdate{URL: &blank})
+	assert.ErrorIs(t, err, models.ErrInvalidWebhookURL)
+}
@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/webhook"
+)
+
+// ErrBookingClosed is returned when a cancelled or past booking is changed
+var ErrBookingClosed = errors.New("booking can not be changed")
+
+// IsBookingChangeable reports whether the guest can still cancel or reschedule the booking
+func IsBookingChangeable(status string, date time.Time) bool {
+	if status == models.BookingStatusCancelled {
+		return false
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+	return !date.Before(today)
+}
+
+// GetUserBooking returns the booking if it belongs to the user
+func (s *BookingService) GetUserBooking(ctx context.Context, userID, bookingID int64) (*models.BookingAPI, error) {
+	booking, err := s.repo.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != userID {
+		return nil, models.ErrBookingNotFound
+	}
+	return booking, nil
+}
+
+// CancelBooking cancels the user's booking and notifies the assigned manager.
+// The booking is checked on its locked row, so a concurrent change by a
+// manager can not slip in between; the status and the webhook event are
+// saved together.
+func (s *BookingService) CancelBooking(ctx context.Context, userID, bookingID int64) (*models.BookingAPI, error) {
+	var booking *models.BookingAPI
+	err := s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if booking, err = s.repo.LockBooking(txCtx, bookingID); err != nil {
+			return err
+		}
+		if err := checkChangeable(booking, userID); err != nil {
+			return err
+		}
+
+		if err := s.repo.UpdateBookingStatus(txCtx, bookingID, models.BookingStatusCancelled); err != nil {
+			return err
+		}
+		return s.publishBookingEvent(txCtx, webhook.EventBookingStatusChanged, bookingID, booking.Status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	booking.Status = models.BookingStatusCancelled
+
+	s.notifyManager(ctx, booking, "Бронирование отменено",
+		fmt.Sprintf("Гость отменил бронирование #%d.\n\n%s", booking.ID, managerBookingDetails(booking)))
+
+	return booking, nil
+}
+
+// StartReschedule creates a booking session for moving an existing booking.
+// The guest data is copied from the booking, so the form only asks for a new date.
+func (s *BookingService) StartReschedule(ctx context.Context, userID, bookingID int64) (*BookingState, error) {
+	booking, err := s.getChangeableBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &BookingState{
+		UserID:              userID,
+		ServiceID:           int64(booking.ServiceID),
+		ServiceName:         booking.ServiceName,
+		SelectedSlot:        models.BoxAvailableSlot{},
+		GuestName:           booking.GuestName,
+		GuestOrganization:   booking.GuestOrganization,
+		GuestPosition:       booking.GuestPosition,
+		RescheduleBookingID: booking.ID,
+		Step:                StepSelectDate,
+		CreatedAt:           time.Now(),
+	}
+
+	if err := s.SaveSession(ctx, userID, *state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// RescheduleBooking moves the booking from state to the selected slot and
+// notifies the assigned manager. The previous slot is released. As in
+// CancelBooking, the booking is checked on its locked row.
+func (s *BookingService) RescheduleBooking(ctx context.Context, state *BookingState) (*models.BookingAPI, error) {
+	date, err := time.Parse("2006-01-02", state.SelectedSlot.Date)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := time.Parse("15:04", state.SelectedSlot.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking *models.BookingAPI
+	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if booking, err = s.repo.LockBooking(txCtx, state.RescheduleBookingID); err != nil {
+			return err
+		}
+		if err := checkChangeable(booking, state.UserID); err != nil {
+			return err
+		}
+
+		if err := s.repo.RescheduleBooking(txCtx, booking.ID, date, &startTime); err != nil {
+			return err
+		}
+		if booking.Status == models.BookingStatusPending {
+			return nil
+		}
+		return s.publishBookingEvent(txCtx, webhook.EventBookingStatusChanged, booking.ID, booking.Status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oldDate, oldTime := booking.BookingDate, booking.BookingTime
+	booking.BookingDate = state.SelectedSlot.Date
+	booking.BookingTime = state.SelectedSlot.StartTime
+	booking.Status = models.BookingStatusPending
+
+	s.notifyManager(ctx, booking, "Бронирование перенесено",
+		fmt.Sprintf("Гость перенёс бронирование #%d с %s %s.\n\n%s",
+			booking.ID, oldDate, oldTime, managerBookingDetails(booking)))
+
+	return booking, nil
+}
+
+// getChangeableBooking returns the user's booking if it can still be changed
+func (s *BookingService) getChangeableBooking(ctx context.Context, userID, bookingID int64) (*models.BookingAPI, error) {
+	booking, err := s.repo.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkChangeable(booking, userID); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// checkChangeable hides the bookings of other users as not found and refuses
+// a cancelled or past booking
+func checkChangeable(booking *models.BookingAPI, userID int64) error {
+	if booking.UserID != userID {
+		return models.ErrBookingNotFound
+	}
+
+	date, err := time.Parse("2006-01-02", booking.BookingDate)
+	if err != nil {
+		return err
+	}
+	if !IsBookingChangeable(booking.Status, date) {
+		return ErrBookingClosed
+	}
+	return nil
+}
+
+// notifyManagerTimeout bounds the background delivery of one notification
+const notifyManagerTimeout = 30 * time.Second
+
+// notifyManager informs the assigned manager in the background, so a slow
+// mail server does not hold the bot update. Failures are logged only: the
+// guest's action is already saved and must not be rolled back.
+func (s *BookingService) notifyManager(ctx context.Context, booking *models.BookingAPI, subject, text string) {
+	if s.notifier == nil {
+		return
+	}
+	if booking.ManagerID == 0 {
+		logger.Warn("booking has no manager to notify", zap.Int64("booking_id", booking.ID))
+		return
+	}
+
+	bookingID, managerID := booking.ID, booking.ManagerID
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyManagerTimeout)
+		defer cancel()
+
+		if err := s.notifier.NotifyManager(ctx, managerID, subject, text); err != nil {
+			logger.Error("failed to notify manager",
+				zap.Int64("booking_id", bookingID),
+				zap.Int64("manager_id", managerID),
+				zap.Error(err))
+		}
+	}()
+}
+
+// publishBookingEvent queues the webhook event for the changed booking in the
+// transaction of ctx, so that it is sent only with the change
+func (s *BookingService) publishBookingEvent(ctx context.Context, event string, bookingID int64, previousStatus string) error {
+	if s.webhooks == nil {
+		return nil
+	}
+
+	booking, err := s.repo.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	return s.webhooks.Publish(ctx, event, webhook.NewBooking(booking, previousStatus))
+}
+
+// managerBookingDetails formats the booking for the manager notification
+func managerBookingDetails(b *models.BookingAPI) string {
+	return fmt.Sprintf("Услуга: %s\nДата: %s\nВремя: %s\nГость: %s\nОрганизация: %s\nДолжность: %s\nКонтакт: %s",
+		b.ServiceName, b.BookingDate, b.BookingTime, b.GuestName, b.GuestOrganization, b.GuestPosition, b.GuestContact)
+}
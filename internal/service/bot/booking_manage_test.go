@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type memoryBookings struct {
+	repository.BookingRepository
+	bookings    map[int64]*models.BookingAPI
+	rescheduled []int64
+}
+
+func (m *memoryBookings) GetBookingById(_ context.Context, id int64) (*models.BookingAPI, error) {
+	booking, ok := m.bookings[id]
+	if !ok {
+		return nil, models.ErrBookingNotFound
+	}
+	copied := *booking
+	return &copied, nil
+}
+
+func (m *memoryBookings) LockBooking(ctx context.Context, id int64) (*models.BookingAPI, error) {
+	return m.GetBookingById(ctx, id)
+}
+
+func (m *memoryBookings) UpdateBookingStatus(_ context.Context, id int64, status string) error {
+	m.bookings[id].Status = status
+	return nil
+}
+
+func (m *memoryBookings) RescheduleBooking(_ context.Context, id int64, date time.Time, _ *time.Time) error {
+	m.bookings[id].BookingDate = date.Format("2006-01-02")
+	m.bookings[id].Status = models.BookingStatusPending
+	m.rescheduled = append(m.rescheduled, id)
+	return nil
+}
+
+type passThroughTx struct {
+	repository.TxRepository
+}
+
+func (passThroughTx) RunToTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type chanNotifier struct {
+	sent chan int64
+}
+
+func (n *chanNotifier) NotifyManager(_ context.Context, managerID int64, _, _ string) error {
+	n.sent <- managerID
+	return nil
+}
+
+func newManageTestService(bookings map[int64]*models.BookingAPI) (*BookingService, *memoryBookings, *chanNotifier) {
+	repo := &memoryBookings{bookings: bookings}
+	notifier := &chanNotifier{sent: make(chan int64, 1)}
+	session := &memorySession{sessions: map[int64]*models.UserSession{}}
+	return NewBookingService(session, repo, nil, passThroughTx{}, notifier, nil), repo, notifier
+}
+
+func TestIsBookingChangeable(t *testing.T) {
+	today := time.Now().Truncate(24 * time.Hour)
+
+	assert.True(t, IsBookingChangeable(models.BookingStatusPending, today))
+	assert.True(t, IsBookingChangeable(models.BookingStatusConfirmed, today.AddDate(0, 0, 3)))
+	assert.False(t, IsBookingChangeable(models.BookingStatusCancelled, today.AddDate(0, 0, 3)))
+	assert.False(t, IsBookingChangeable(models.BookingStatusConfirmed, today.AddDate(0, 0, -1)))
+}
+
+func TestBookingService_CancelBooking(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	svc, repo, notifier := newManageTestService(map[int64]*models.BookingAPI{
+		1: {ID: 1, UserID: 7, ManagerID: 3, BookingDate: tomorrow, Status: models.BookingStatusConfirmed},
+		2: {ID: 2, UserID: 7, BookingDate: tomorrow, Status: models.BookingStatusCancelled},
+		3: {ID: 3, UserID: 7, BookingDate: yesterday, Status: models.BookingStatusConfirmed},
+	})
+
+	_, err := svc.CancelBooking(ctx, 8, 1)
+	assert.ErrorIs(t, err, models.ErrBookingNotFound, "another user's booking is hidden")
+	assert.Equal(t, models.BookingStatusConfirmed, repo.bookings[1].Status)
+
+	_, err = svc.CancelBooking(ctx, 7, 2)
+	assert.ErrorIs(t, err, ErrBookingClosed)
+
+	_, err = svc.CancelBooking(ctx, 7, 3)
+	assert.ErrorIs(t, err, ErrBookingClosed)
+	assert.Equal(t, models.BookingStatusConfirmed, repo.bookings[3].Status)
+
+	booking, err := svc.CancelBooking(ctx, 7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusCancelled, booking.Status)
+	assert.Equal(t, models.BookingStatusCancelled, repo.bookings[1].Status)
+
+	select {
+	case managerID := <-notifier.sent:
+		assert.Equal(t, int64(3), managerID)
+	case <-time.After(time.Second):
+		t.Fatal("the manager was not notified")
+	}
+}
+
+func TestBookingService_Reschedule(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	svc, repo, notifier := newManageTestService(map[int64]*models.BookingAPI{
+		1: {ID: 1, UserID: 7, ManagerID: 3, BookingDate: tomorrow, Status: models.BookingStatusConfirmed},
+		2: {ID: 2, UserID: 7, BookingDate: yesterday, Status: models.BookingStatusConfirmed},
+		3: {ID: 3, UserID: 7, BookingDate: tomorrow, Status: models.BookingStatusCancelled},
+	})
+
+	_, err := svc.StartReschedule(ctx, 8, 1)
+	assert.ErrorIs(t, err, models.ErrBookingNotFound)
+	_, err = svc.StartReschedule(ctx, 7, 2)
+	assert.ErrorIs(t, err, ErrBookingClosed)
+	_, err = svc.StartReschedule(ctx, 7, 3)
+	assert.ErrorIs(t, err, ErrBookingClosed)
+
+	// A forged session can not move a booking of another user either
+	slot := models.BoxAvailableSlot{Date: nextWeek, StartTime: "10:00"}
+	_, err = svc.RescheduleBooking(ctx, &BookingState{UserID: 8, RescheduleBookingID: 1, SelectedSlot: slot})
+	assert.ErrorIs(t, err, models.ErrBookingNotFound)
+	_, err = svc.RescheduleBooking(ctx, &BookingState{UserID: 7, RescheduleBookingID: 2, SelectedSlot: slot})
+	assert.ErrorIs(t, err, ErrBookingClosed)
+	assert.Empty(t, repo.rescheduled)
+
+	state, err := svc.StartReschedule(ctx, 7, 1)
+	require.NoError(t, err)
+	state.SelectedSlot = slot
+
+	booking, err := svc.RescheduleBooking(ctx, state)
+	require.NoError(t, err)
+	assert.Equal(t, nextWeek, booking.BookingDate)
+	assert.Equal(t, models.BookingStatusPending, booking.Status)
+	assert.Equal(t, []int64{1}, repo.rescheduled)
+
+	select {
+	case managerID := <-notifier.sent:
+		assert.Equal(t, int64(3), managerID)
+	case <-time.After(time.Second):
+		t.Fatal("the manager was not notified")
+	}
+}
+
+func TestBookingService_RescheduleAfterManagerCancelled(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	svc, repo, _ := newManageTestService(map[int64]*models.BookingAPI{
+		1: {ID: 1, UserID: 7, BookingDate: tomorrow, Status: models.BookingStatusConfirmed},
+	})
+
+	state, err := svc.StartReschedule(ctx, 7, 1)
+	require.NoError(t, err)
+
+	// A manager cancels the booking while the guest picks a new date
+	repo.bookings[1].Status = models.BookingStatusCancelled
+
+	state.SelectedSlot = models.BoxAvailableSlot{Date: nextWeek, StartTime: "10:00"}
+	_, err = svc.RescheduleBooking(ctx, state)
+	assert.ErrorIs(t, err, ErrBookingClosed)
+	assert.Empty(t, repo.rescheduled)
+	assert.Equal(t, models.BookingStatusCancelled, repo.bookings[1].Status)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/yandex-development-1-team/go/internal/logger"
 	"github.com/yandex-development-1-team/go/internal/models"
 	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 )
 
 // State constants of the booking process
@@ -40,16 +41,22 @@ type BookingState struct {
 	GuestName         string
 	GuestOrganization string
 	GuestPosition     string
-	Step              int
-	OldMessageID      *int
-	CreatedAt         time.Time
+	// RescheduleBookingID is set when the form moves an existing booking
+	// instead of creating a new one
+	RescheduleBookingID int64
+	Step                int
+	OldMessageID        *int
+	CreatedAt           time.Time
 }
 
 // BookingService implements a booking service
 type BookingService struct {
-	session repository.SessionRepository
-	repo    repository.BookingRepository
-	boxRepo repository.BoxSolutionRepository
+	session  repository.SessionRepository
+	repo     repository.BookingRepository
+	boxRepo  repository.BoxSolutionRepository
+	txRepo   repository.TxRepository
+	notifier ManagerNotifier
+	webhooks webhook.Publisher
 }
 
 // NewBookingService creates a new instance of the booking service
@@ -57,11 +64,17 @@ func NewBookingService(
 	session repository.SessionRepository,
 	repo repository.BookingRepository,
 	boxRepo repository.BoxSolutionRepository,
+	txRepo repository.TxRepository,
+	notifier ManagerNotifier,
+	webhooks webhook.Publisher,
 ) *BookingService {
 	return &BookingService{
-		session: session,
-		repo:    repo,
-		boxRepo: boxRepo,
+		session:  session,
+		repo:     repo,
+		boxRepo:  boxRepo,
+		txRepo:   txRepo,
+		notifier: notifier,
+		webhooks: webhooks,
 	}
 }
 
@@ -159,7 +172,18 @@ func (s *BookingService) CreateBooking(ctx context.Context, state *BookingState)
 	// if err := s.ClearSession(ctx, state.UserID); err != nil {
 	// 	return 0, fmt.Errorf("clear session: %w", err)
 	// }
-	return s.repo.CreateBooking(ctx, booking)
+	var id int64
+	err = s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if id, err = s.repo.CreateBooking(txCtx, booking); err != nil {
+			return err
+		}
+		return s.publishBookingEvent(txCtx, webhook.EventBookingCreated, id, "")
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
 // ClearSession clears the user session
@@ -228,9 +252,13 @@ func (s *BookingService) ProcessDateSelection(ctx context.Context, state *Bookin
 		return false, err
 	}
 
-	if !available {
+	switch {
+	case !available:
 		state.Step = StepStartBooking
-	} else {
+	case state.RescheduleBookingID != 0:
+		state.SelectedSlot = slot
+		state.Step = StepConfirmation
+	default:
 		state.SelectedSlot = slot
 		state.Step = StepEnterName
 	}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Sections a deep link t.me/<bot>?start=<payload> opens
+const (
+	LinkSectionBoxes          = "box"
+	LinkSectionSpecialProject = "sp"
+	LinkSectionGuide          = "guide"
+	LinkSectionExamples       = "examples"
+	LinkSectionAbout          = "about"
+	LinkSectionSupport        = "support"
+)
+
+const (
+	// startLinkSeparator joins the parts of a payload, e.g. box_city-tour__src_vk
+	startLinkSeparator = "__"
+	startLinkBoxPrefix = LinkSectionBoxes + "_"
+	startLinkSrcPrefix = "src_"
+)
+
+var (
+	startLinkSections = map[string]bool{
+		LinkSectionBoxes:          true,
+		LinkSectionSpecialProject: true,
+		LinkSectionGuide:          true,
+		LinkSectionExamples:       true,
+		LinkSectionAbout:          true,
+		LinkSectionSupport:        true,
+	}
+	// Telegram allows A-Z, a-z, 0-9, _ and - in a payload of up to 64 characters
+	startLinkTokenRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// StartLink is a parsed /start payload. Section is empty for a plain /start;
+// Slug is set for a link to a box card; Source is the src_ campaign tag.
+type StartLink struct {
+	Section string
+	Slug    string
+	Source  string
+}
+
+// ParseStartLink parses the payload of /start. The parts are joined with "__":
+// a section ("sp", "box" or "box_<slug>" for a card) and a "src_<campaign>"
+// tag, in any order. Unknown parts are ignored, so an outdated link still
+// opens the bot.
+func ParseStartLink(payload string) StartLink {
+	var link StartLink
+	for _, part := range strings.Split(strings.TrimSpace(payload), startLinkSeparator) {
+		if !startLinkTokenRe.MatchString(part) {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(part, startLinkSrcPrefix):
+			if link.Source == "" && len(part) > len(startLinkSrcPrefix) {
+				link.Source = strings.ToLower(strings.TrimPrefix(part, startLinkSrcPrefix))
+			}
+		case link.Section != "":
+		case strings.HasPrefix(part, startLinkBoxPrefix):
+			link.Section = LinkSectionBoxes
+			link.Slug = strings.TrimPrefix(part, startLinkBoxPrefix)
+		case startLinkSections[part]:
+			link.Section = part
+		}
+	}
+	return link
+}
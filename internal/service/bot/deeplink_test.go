@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStartLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    StartLink
+	}{
+		{name: "plain start", payload: "", want: StartLink{}},
+		{name: "section", payload: "sp", want: StartLink{Section: LinkSectionSpecialProject}},
+		{name: "box list", payload: "box", want: StartLink{Section: LinkSectionBoxes}},
+		{name: "box card", payload: "box_new-year-quest", want: StartLink{Section: LinkSectionBoxes, Slug: "new-year-quest"}},
+		{name: "source only", payload: "src_VK_spring", want: StartLink{Source: "vk_spring"}},
+		{
+			name:    "card with source",
+			payload: "box_city-tour__src_newsletter",
+			want:    StartLink{Section: LinkSectionBoxes, Slug: "city-tour", Source: "newsletter"},
+		},
+		{name: "source first", payload: "src_qr__guide", want: StartLink{Section: LinkSectionGuide, Source: "qr"}},
+		{name: "first section wins", payload: "about__support", want: StartLink{Section: LinkSectionAbout}},
+		{name: "unknown parts are ignored", payload: "promo__src_", want: StartLink{}},
+		{name: "invalid characters", payload: "src_a b", want: StartLink{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseStartLink(tt.payload))
+		})
+	}
+}
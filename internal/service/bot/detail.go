@@ -20,6 +20,7 @@ var (
 // ServiceRepo defines the data access layer interface for service operations
 type ServiceRepo interface {
 	GetServiceByID(ctx context.Context, serviceID int64) (*models.Service, error)
+	GetActiveServiceIDBySlug(ctx context.Context, slug string) (int64, error)
 }
 
 // DetailService provides logic for service detail
@@ -41,6 +42,15 @@ func (s *DetailService) GetByID(ctx context.Context, serviceID int64) (*models.S
 	return service, nil
 }
 
+// GetIDBySlug returns the ID of the active service with the slug, for deep links
+func (s *DetailService) GetIDBySlug(ctx context.Context, slug string) (int64, error) {
+	id, err := s.repo.GetActiveServiceIDBySlug(ctx, slug)
+	if errors.Is(err, models.ErrBoxSolutionNotFound) {
+		return 0, ErrServiceNotFound
+	}
+	return id, err
+}
+
 // ParseServiceID returns the service ID
 func (s *DetailService) ParseServiceID(callbackData string) (int64, error) {
 	parts := strings.Split(callbackData, ":")
@@ -59,11 +69,3 @@ func (s *DetailService) ParseServiceID(callbackData string) (int64, error) {
 	}
 	return id, nil
 }
-
-// GetDisplayName returns the service name for display
-func (s *DetailService) GetDisplayName(service *models.Service) string {
-	if service.Name == "" {
-		return "Прочее"
-	}
-	return service.Name
-}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// ManagerNotifier delivers messages to the manager assigned to a booking
+type ManagerNotifier interface {
+	NotifyManager(ctx context.Context, managerID int64, subject, text string) error
+}
+
+// notificationMailer sends plain text emails
+type notificationMailer interface {
+	SendNotificationEmail(ctx context.Context, toEmail, subject, body string) error
+}
+
+// EmailManagerNotifier notifies managers by email from their staff profile
+type EmailManagerNotifier struct {
+	staff  repository.StaffRepository
+	mailer notificationMailer
+}
+
+// NewEmailManagerNotifier creates a new instance of the email manager notifier
+func NewEmailManagerNotifier(staff repository.StaffRepository, mailer notificationMailer) *EmailManagerNotifier {
+	return &EmailManagerNotifier{
+		staff:  staff,
+		mailer: mailer,
+	}
+}
+
+// NotifyManager sends the message to the manager's email
+func (n *EmailManagerNotifier) NotifyManager(ctx context.Context, managerID int64, subject, text string) error {
+	manager, err := n.staff.GetByID(ctx, managerID)
+	if err != nil {
+		return fmt.Errorf("get manager: %w", err)
+	}
+	if manager.Email == "" {
+		return fmt.Errorf("manager %d has no email", managerID)
+	}
+
+	return n.mailer.SendNotificationEmail(ctx, manager.Email, subject, text)
+}
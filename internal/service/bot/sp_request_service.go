@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/handlers/validation"
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+// State constants of the special project request
+const (
+	SpRequestStepEnterName = iota
+	SpRequestStepEnterContact
+	SpRequestStepEnterDescription
+	SpRequestStepConfirmation
+)
+
+// CallbackSpRequestPrefix is the callback prefix and the session state of the
+// special project request
+const CallbackSpRequestPrefix = "sp_request"
+
+// SpRequestState is the special project request being filled in
+type SpRequestState struct {
+	UserID       int64
+	CustomerName string
+	ContactInfo  string
+	Description  string
+	Step         int
+	OldMessageID *int
+	CreatedAt    time.Time
+}
+
+// ApplicationCreator saves special project applications
+type ApplicationCreator interface {
+	Create(ctx context.Context, req *models.Application) error
+}
+
+// SpRequestService keeps the special project request in the user session,
+// the same way as BookingService keeps the booking form
+type SpRequestService struct {
+	session      repository.SessionRepository
+	applications ApplicationCreator
+}
+
+// NewSpRequestService creates a new instance of the special project request service
+func NewSpRequestService(session repository.SessionRepository, applications ApplicationCreator) *SpRequestService {
+	return &SpRequestService{
+		session:      session,
+		applications: applications,
+	}
+}
+
+// GetState returns the request being filled in or nil
+func (s *SpRequestService) GetState(ctx context.Context, userID int64) *SpRequestState {
+	session, err := s.session.GetSession(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get user session",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return nil
+	}
+
+	if session.CurrentState != CallbackSpRequestPrefix {
+		return nil
+	}
+
+	stateData, ok := session.StateData[KeyForBookingData]
+	if !ok {
+		logger.Debug("state data not found in session")
+		return nil
+	}
+
+	jsonData, err := json.Marshal(stateData)
+	if err != nil {
+		logger.Error("failed to marshal state data", zap.Error(err))
+		return nil
+	}
+
+	var state SpRequestState
+	if err := json.Unmarshal(jsonData, &state); err != nil {
+		logger.Error("failed to unmarshal state data", zap.Error(err))
+		return nil
+	}
+
+	return &state
+}
+
+// SaveSession saves the request state
+func (s *SpRequestService) SaveSession(ctx context.Context, state SpRequestState) error {
+	data := map[string]interface{}{
+		KeyForBookingData: state,
+	}
+
+	err := s.session.SaveSession(ctx, state.UserID, CallbackSpRequestPrefix, data)
+	if err != nil {
+		logger.Error("failed to save user session",
+			zap.Error(err),
+			zap.Int64("user_id", state.UserID),
+		)
+		return err
+	}
+	return nil
+}
+
+// Start begins a new request
+func (s *SpRequestService) Start(ctx context.Context, userID int64) (*SpRequestState, error) {
+	state := &SpRequestState{
+		UserID:    userID,
+		Step:      SpRequestStepEnterName,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.SaveSession(ctx, *state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// ClearSession drops the request and returns the user to the main menu state
+func (s *SpRequestService) ClearSession(ctx context.Context, userID int64) error {
+	if err := s.session.SaveSession(ctx, userID, "main_menu", map[string]interface{}{}); err != nil {
+		logger.Error("failed to clear user session",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return err
+	}
+	return nil
+}
+
+// ValidateAndSetName validates and sets the customer name
+func (s *SpRequestService) ValidateAndSetName(state *SpRequestState, name string) error {
+	if err := validation.Name(name); err != nil {
+		return err
+	}
+	state.CustomerName = strings.TrimSpace(name)
+	state.Step = SpRequestStepEnterContact
+	return nil
+}
+
+// ValidateAndSetContact validates and sets the Telegram username
+func (s *SpRequestService) ValidateAndSetContact(state *SpRequestState, contact string) error {
+	if err := validation.Contact(contact); err != nil {
+		return err
+	}
+	state.ContactInfo = strings.TrimSpace(contact)
+	state.Step = SpRequestStepEnterDescription
+	return nil
+}
+
+// ValidateAndSetDescription validates and sets the description
+func (s *SpRequestService) ValidateAndSetDescription(state *SpRequestState, description string) error {
+	if err := validation.Description(description); err != nil {
+		return err
+	}
+	state.Description = strings.TrimSpace(description)
+	state.Step = SpRequestStepConfirmation
+	return nil
+}
+
+// Submit creates the application and returns its ID
+func (s *SpRequestService) Submit(ctx context.Context, state *SpRequestState) (int64, error) {
+	app := &models.Application{
+		CustomerName: state.CustomerName,
+		ContactInfo:  state.ContactInfo,
+		Description:  state.Description,
+	}
+
+	if err := s.applications.Create(ctx, app); err != nil {
+		return 0, err
+	}
+
+	logger.Info("special project application created from the bot",
+		zap.Int64("application_id", app.ID),
+		zap.Int64("user_id", state.UserID))
+
+	return app.ID, nil
+}
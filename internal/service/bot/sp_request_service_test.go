@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+type memorySession struct {
+	repository.SessionRepository
+	sessions map[int64]*models.UserSession
+}
+
+func (m *memorySession) SaveSession(_ context.Context, userID int64, state string, data map[string]interface{}) error {
+	m.sessions[userID] = &models.UserSession{UserID: userID, CurrentState: state, StateData: data}
+	return nil
+}
+
+func (m *memorySession) GetSession(_ context.Context, userID int64) (*models.UserSession, error) {
+	session, ok := m.sessions[userID]
+	if !ok {
+		return nil, models.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+type fakeApplications struct {
+	created []models.Application
+}
+
+func (f *fakeApplications) Create(_ context.Context, app *models.Application) error {
+	app.ID = int64(len(f.created) + 1)
+	f.created = append(f.created, *app)
+	return nil
+}
+
+func TestSpRequestService_FillAndSubmit(t *testing.T) {
+	ctx := context.Background()
+	apps := &fakeApplications{}
+	svc := NewSpRequestService(&memorySession{sessions: map[int64]*models.UserSession{}}, apps)
+
+	state, err := svc.Start(ctx, 7)
+	require.NoError(t, err)
+
+	assert.Error(t, svc.ValidateAndSetName(state, "Иван"), "a full name is required")
+	assert.Equal(t, SpRequestStepEnterName, state.Step)
+	require.NoError(t, svc.ValidateAndSetName(state, " Иванов Иван "))
+
+	assert.Error(t, svc.ValidateAndSetContact(state, "+7 999 000-00-00"))
+	require.NoError(t, svc.ValidateAndSetContact(state, "@ivan_ivanov"))
+
+	assert.Error(t, svc.ValidateAndSetDescription(state, "выставка"))
+	require.NoError(t, svc.ValidateAndSetDescription(state, "Выставка на 300 гостей в мае"))
+	assert.Equal(t, SpRequestStepConfirmation, state.Step)
+
+	// The state survives between the updates in the session
+	require.NoError(t, svc.SaveSession(ctx, *state))
+	restored := svc.GetState(ctx, 7)
+	require.NotNil(t, restored)
+	assert.Equal(t, state.Description, restored.Description)
+
+	id, err := svc.Submit(ctx, restored)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	assert.Equal(t, models.Application{
+		ID:           1,
+		CustomerName: "Иванов Иван",
+		ContactInfo:  "@ivan_ivanov",
+		Description:  "Выставка на 300 гостей в мае",
+	}, apps.created[0])
+
+	require.NoError(t, svc.ClearSession(ctx, 7))
+	assert.Nil(t, svc.GetState(ctx, 7), "a cleared request is gone")
+}
+
+func TestSpRequestService_IgnoresOtherSessions(t *testing.T) {
+	session := &memorySession{sessions: map[int64]*models.UserSession{}}
+	svc := NewSpRequestService(session, &fakeApplications{})
+
+	require.NoError(t, session.SaveSession(context.Background(), 8, CallbackBookingPrefix,
+		map[string]interface{}{KeyForBookingData: BookingState{UserID: 8, Step: StepEnterName}}))
+
+	assert.Nil(t, svc.GetState(context.Background(), 8), "the booking form is not a request")
+}
@@ -6,14 +6,17 @@ import (
 
 	"github.com/yandex-development-1-team/go/internal/models"
 	repository "github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
 )
 
 type SpecialProjectService struct {
-	repo repository.SpecialProjectRepository
+	repo     repository.SpecialProjectRepository
+	txRepo   repository.TxRepository
+	webhooks webhook.Publisher
 }
 
-func NewSpecialProjectService(repo repository.SpecialProjectRepository) *SpecialProjectService {
-	return &SpecialProjectService{repo: repo}
+func NewSpecialProjectService(repo repository.SpecialProjectRepository, txRepo repository.TxRepository, webhooks webhook.Publisher) *SpecialProjectService {
+	return &SpecialProjectService{repo: repo, txRepo: txRepo, webhooks: webhooks}
 }
 
 func (s *SpecialProjectService) Create(ctx context.Context, proj *models.SpecialProject) (*models.SpecialProjectDB, error) {
@@ -21,7 +24,14 @@ func (s *SpecialProjectService) Create(ctx context.Context, proj *models.Special
 		return nil, errors.New("title is required")
 	}
 
-	dbModel, err := s.repo.Create(ctx, proj)
+	var dbModel *models.SpecialProjectDB
+	err := s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if dbModel, err = s.repo.Create(txCtx, proj); err != nil {
+			return err
+		}
+		return s.publish(txCtx, webhook.EventSpecialProjectCreated, dbModel)
+	})
 	if err != nil {
 		if errors.Is(err, models.ErrSpecialProjectAlreadyExists) {
 			return nil, models.ErrSpecialProjectAlreadyExists
@@ -56,7 +66,14 @@ func (s *SpecialProjectService) Update(ctx context.Context, id int64, proj *mode
 	if id <= 0 || proj == nil {
 		return nil, models.ErrInvalidInput
 	}
-	dbModel, err := s.repo.Update(ctx, id, proj)
+	var dbModel *models.SpecialProjectDB
+	err := s.txRepo.RunToTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if dbModel, err = s.repo.Update(txCtx, id, proj); err != nil {
+			return err
+		}
+		return s.publish(txCtx, webhook.EventSpecialProjectUpdated, dbModel)
+	})
 	if err != nil {
 		if errors.Is(err, models.ErrSpecialProjectNotFound) {
 			return nil, models.ErrSpecialProjectNotFound
@@ -79,3 +96,12 @@ func (s *SpecialProjectService) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+// publish queues the project event in the transaction of ctx, so that the
+// event is sent only with the saved project
+func (s *SpecialProjectService) publish(ctx context.Context, event string, proj *models.SpecialProjectDB) error {
+	if s.webhooks == nil || proj == nil {
+		return nil
+	}
+	return s.webhooks.Publish(ctx, event, webhook.NewSpecialProject(proj))
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -58,12 +59,25 @@ func (m *mockSpecialProjectRepo) Delete(ctx context.Context, id int64) error {
 
 var _ repository.SpecialProjectRepository = (*mockSpecialProjectRepo)(nil)
 
+// passThroughTx runs the function without a database transaction
+type passThroughTx struct{}
+
+func (passThroughTx) RunToTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (passThroughTx) BeginTx(context.Context) (*sqlx.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+var _ repository.TxRepository = passThroughTx{}
+
 func TestSpecialProjectService_Create(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("empty title returns error", func(t *testing.T) {
 		repo := &mockSpecialProjectRepo{}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		_, err := svc.Create(ctx, &models.SpecialProject{Title: ""})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "title is required")
@@ -75,7 +89,7 @@ func TestSpecialProjectService_Create(t *testing.T) {
 				return nil, models.ErrSpecialProjectAlreadyExists
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		_, err := svc.Create(ctx, &models.SpecialProject{Title: "Duplicate"})
 		require.Error(t, err)
 		assert.ErrorIs(t, err, models.ErrSpecialProjectAlreadyExists)
@@ -92,7 +106,7 @@ func TestSpecialProjectService_Create(t *testing.T) {
 				}, nil
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		got, err := svc.Create(ctx, &models.SpecialProject{
 			Title:  "Test",
 			Image:  &img,
@@ -113,7 +127,7 @@ func TestSpecialProjectService_GetByID(t *testing.T) {
 				return nil, models.ErrSpecialProjectNotFound
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		_, err := svc.GetByID(ctx, 999)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, models.ErrSpecialProjectNotFound)
@@ -131,7 +145,7 @@ func TestSpecialProjectService_GetByID(t *testing.T) {
 				}, nil
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		got, err := svc.GetByID(ctx, 1)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), got.ID)
@@ -145,7 +159,7 @@ func TestSpecialProjectService_Update(t *testing.T) {
 
 	t.Run("invalid id <= 0", func(t *testing.T) {
 		repo := &mockSpecialProjectRepo{}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		title := "T"
 		_, err := svc.Update(ctx, 0, &models.SpecialProjectUpdate{Title: &title})
 		require.Error(t, err)
@@ -154,7 +168,7 @@ func TestSpecialProjectService_Update(t *testing.T) {
 
 	t.Run("nil update", func(t *testing.T) {
 		repo := &mockSpecialProjectRepo{}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		_, err := svc.Update(ctx, 1, nil)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, models.ErrInvalidInput)
@@ -166,7 +180,7 @@ func TestSpecialProjectService_Update(t *testing.T) {
 				return nil, models.ErrSpecialProjectNotFound
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		title := "T"
 		_, err := svc.Update(ctx, 1, &models.SpecialProjectUpdate{Title: &title})
 		require.Error(t, err)
@@ -187,7 +201,7 @@ func TestSpecialProjectService_Update(t *testing.T) {
 				}, nil
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		got, err := svc.Update(ctx, 1, &models.SpecialProjectUpdate{
 			Title:       &title,
 			Description: &desc,
@@ -205,7 +219,7 @@ func TestSpecialProjectService_Delete(t *testing.T) {
 
 	t.Run("invalid id <= 0", func(t *testing.T) {
 		repo := &mockSpecialProjectRepo{}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		err := svc.Delete(ctx, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, models.ErrInvalidInput)
@@ -217,7 +231,7 @@ func TestSpecialProjectService_Delete(t *testing.T) {
 				return models.ErrSpecialProjectNotFound
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		err := svc.Delete(ctx, 999)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, models.ErrSpecialProjectNotFound)
@@ -232,7 +246,7 @@ func TestSpecialProjectService_Delete(t *testing.T) {
 				return nil
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		err := svc.Delete(ctx, 1)
 		require.NoError(t, err)
 		assert.True(t, called)
@@ -251,7 +265,7 @@ func TestSpecialProjectService_List(t *testing.T) {
 				}, 10, nil
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		got, total, err := svc.List(ctx, "active", "q", 10, 0)
 		require.NoError(t, err)
 		require.Len(t, got, 2)
@@ -267,7 +281,7 @@ func TestSpecialProjectService_List(t *testing.T) {
 				return nil, 0, repoErr
 			},
 		}
-		svc := NewSpecialProjectService(repo)
+		svc := NewSpecialProjectService(repo, passThroughTx{}, nil)
 		_, _, err := svc.List(ctx, "", "", 0, 0)
 		require.Error(t, err)
 		assert.ErrorIs(t, err, repoErr)
@@ -0,0 +1,195 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+)
+
+var (
+	// ErrConflict is returned when an issue with the same unique value already exists
+	ErrConflict = errors.New("tracker: issue already exists")
+	// ErrNotFound is returned when the issue does not exist
+	ErrNotFound = errors.New("tracker: issue not found")
+)
+
+// Client describes the Yandex Tracker operations used by the service.
+type Client interface {
+	CreateIssue(ctx context.Context, req IssueRequest) (*Issue, error)
+	GetIssue(ctx context.Context, key string) (*Issue, error)
+	FindIssueByUnique(ctx context.Context, unique string) (*Issue, error)
+	Transitions(ctx context.Context, key string) ([]Transition, error)
+	ExecuteTransition(ctx context.Context, key, transitionID string) error
+}
+
+// IssueRequest is the body of the issue creation request.
+// Unique protects from duplicates when the creation is retried.
+type IssueRequest struct {
+	Queue       string   `json:"queue"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	Unique      string   `json:"unique,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// Issue is a Tracker issue reduced to the fields the service needs.
+type Issue struct {
+	Key    string `json:"key"`
+	Status Status `json:"status"`
+}
+
+// Status of an issue or target of a transition
+type Status struct {
+	Key     string `json:"key"`
+	Display string `json:"display"`
+}
+
+// Transition moves an issue to another status
+type Transition struct {
+	ID string `json:"id"`
+	To Status `json:"to"`
+}
+
+// HTTPClient implements Client over the Tracker REST API v2.
+type HTTPClient struct {
+	baseURL    string
+	token      string
+	orgID      string
+	cloudOrgID string
+	queue      string
+	httpClient *http.Client
+}
+
+// New creates a new Tracker client.
+func New(cfg config.TrackerConfig, httpClient *http.Client) *HTTPClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &HTTPClient{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		token:      cfg.Token,
+		orgID:      cfg.OrgID,
+		cloudOrgID: cfg.CloudOrgID,
+		queue:      cfg.Queue,
+		httpClient: httpClient,
+	}
+}
+
+// CreateIssue creates an issue. ErrConflict means an issue with the same
+// Unique value was created before.
+func (c *HTTPClient) CreateIssue(ctx context.Context, req IssueRequest) (*Issue, error) {
+	if req.Queue == "" {
+		req.Queue = c.queue
+	}
+
+	var issue Issue
+	if err := c.do(ctx, http.MethodPost, "/v2/issues/", req, &issue); err != nil {
+		return nil, fmt.Errorf("create issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// GetIssue returns the issue by key
+func (c *HTTPClient) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodGet, "/v2/issues/"+url.PathEscape(key), nil, &issue); err != nil {
+		return nil, fmt.Errorf("get issue %s: %w", key, err)
+	}
+	return &issue, nil
+}
+
+// FindIssueByUnique searches the queue for the issue created with the unique value
+func (c *HTTPClient) FindIssueByUnique(ctx context.Context, unique string) (*Issue, error) {
+	body := map[string]any{
+		"filter": map[string]string{
+			"queue":  c.queue,
+			"unique": unique,
+		},
+	}
+
+	var issues []Issue
+	if err := c.do(ctx, http.MethodPost, "/v2/issues/_search", body, &issues); err != nil {
+		return nil, fmt.Errorf("search issue: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil, ErrNotFound
+	}
+	return &issues[0], nil
+}
+
+// Transitions returns the transitions available for the issue
+func (c *HTTPClient) Transitions(ctx context.Context, key string) ([]Transition, error) {
+	var transitions []Transition
+	path := "/v2/issues/" + url.PathEscape(key) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, &transitions); err != nil {
+		return nil, fmt.Errorf("get transitions of %s: %w", key, err)
+	}
+	return transitions, nil
+}
+
+// ExecuteTransition moves the issue along the transition
+func (c *HTTPClient) ExecuteTransition(ctx context.Context, key, transitionID string) error {
+	path := "/v2/issues/" + url.PathEscape(key) + "/transitions/" + url.PathEscape(transitionID) + "/_execute"
+	if err := c.do(ctx, http.MethodPost, path, struct{}{}, nil); err != nil {
+		return fmt.Errorf("execute transition %s of %s: %w", transitionID, key, err)
+	}
+	return nil
+}
+
+func (c *HTTPClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "OAuth "+c.token)
+	if c.cloudOrgID != "" {
+		req.Header.Set("X-Cloud-Org-ID", c.cloudOrgID)
+	} else {
+		req.Header.Set("X-Org-ID", c.orgID)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= http.StatusBadRequest:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("tracker responded %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
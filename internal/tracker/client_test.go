@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+)
+
+func TestHTTPClient_CreateIssue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/issues/", r.URL.Path)
+		assert.Equal(t, "OAuth secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "42", r.Header.Get("X-Org-ID"))
+
+		var req IssueRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "BOOK", req.Queue)
+		assert.Equal(t, "bot-booking-1", req.Unique)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key":"BOOK-1","status":{"key":"open","display":"Открыт"}}`))
+	}))
+	defer srv.Close()
+
+	client := New(config.TrackerConfig{BaseURL: srv.URL + "/", Token: "secret", OrgID: "42", Queue: "BOOK"}, srv.Client())
+
+	issue, err := client.CreateIssue(context.Background(), IssueRequest{Summary: "test", Unique: "bot-booking-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "BOOK-1", issue.Key)
+	assert.Equal(t, "open", issue.Status.Key)
+}
+
+func TestHTTPClient_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "cloud-org", r.Header.Get("X-Cloud-Org-ID"))
+		switch r.URL.Path {
+		case "/v2/issues/":
+			w.WriteHeader(http.StatusConflict)
+		case "/v2/issues/BOOK-404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errorMessages":["no access"]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := New(config.TrackerConfig{BaseURL: srv.URL, Token: "secret", CloudOrgID: "cloud-org", Queue: "BOOK"}, srv.Client())
+	ctx := context.Background()
+
+	_, err := client.CreateIssue(ctx, IssueRequest{Summary: "test"})
+	assert.ErrorIs(t, err, ErrConflict)
+
+	_, err = client.GetIssue(ctx, "BOOK-404")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = client.ExecuteTransition(ctx, "BOOK-1", "close")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "no access")
+}
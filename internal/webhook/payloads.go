@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+// Booking is the data of booking events
+type Booking struct {
+	ID                int64     `json:"id"`
+	UserID            int64     `json:"user_id"`
+	ServiceID         int16     `json:"service_id"`
+	ServiceName       string    `json:"service_name,omitempty"`
+	BookingDate       string    `json:"booking_date"`
+	BookingTime       string    `json:"booking_time"`
+	GuestName         string    `json:"guest_name"`
+	GuestOrganization string    `json:"guest_organization"`
+	GuestPosition     string    `json:"guest_position"`
+	Status            string    `json:"status"`
+	PreviousStatus    string    `json:"previous_status,omitempty"`
+	ManagerID         int64     `json:"manager_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Application is the data of application events
+type Application struct {
+	ID             int64     `json:"id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	CustomerName   string    `json:"customer_name"`
+	ContactInfo    string    `json:"contact_info"`
+	Description    string    `json:"description"`
+	ManagerID      int64     `json:"manager_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Box is the data of the box.updated event
+type Box struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	Price       int       `json:"price"`
+	Location    string    `json:"location"`
+	Organizer   string    `json:"organizer"`
+	Slots       []Slot    `json:"slots"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Slot is a box time slot
+type Slot struct {
+	Date     string `json:"date"`
+	TimeFrom string `json:"time_from"`
+	TimeTo   string `json:"time_to"`
+	Capacity int    `json:"capacity,omitempty"`
+}
+
+// SpecialProject is the data of special project events
+type SpecialProject struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewBooking(b *models.BookingAPI, previousStatus string) Booking {
+	return Booking{
+		ID:                b.ID,
+		UserID:            b.UserID,
+		ServiceID:         b.ServiceID,
+		ServiceName:       b.ServiceName,
+		BookingDate:       b.BookingDate,
+		BookingTime:       b.BookingTime,
+		GuestName:         b.GuestName,
+		GuestOrganization: b.GuestOrganization,
+		GuestPosition:     b.GuestPosition,
+		Status:            b.Status,
+		PreviousStatus:    previousStatus,
+		ManagerID:         b.ManagerID,
+		CreatedAt:         b.CreatedAt,
+		UpdatedAt:         b.UpdatedAt,
+	}
+}
+
+func NewApplication(a *models.Application, previousStatus string) Application {
+	return Application{
+		ID:             a.ID,
+		Status:         a.Status,
+		PreviousStatus: previousStatus,
+		CustomerName:   a.CustomerName,
+		ContactInfo:    a.ContactInfo,
+		Description:    a.Description,
+		ManagerID:      a.ManagerID,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+func NewBox(s *models.Service) Box {
+	slots := make([]Slot, 0, len(s.BoxAvailableSlots))
+	for _, slot := range s.BoxAvailableSlots {
+		slots = append(slots, Slot{
+			Date:     slot.Date,
+			TimeFrom: slot.StartTime,
+			TimeTo:   slot.EndTime,
+			Capacity: slot.Capacity,
+		})
+	}
+
+	return Box{
+		ID:          s.ID,
+		Name:        s.Name,
+		Slug:        s.Slug,
+		Description: s.Description,
+		Status:      s.Status,
+		Price:       s.Price,
+		Location:    s.Location,
+		Organizer:   s.Organizer,
+		Slots:       slots,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+func NewSpecialProject(p *models.SpecialProjectDB) SpecialProject {
+	return SpecialProject{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
@@ -0,0 +1,100 @@
+// Package webhook publishes domain events to the external endpoints
+// subscribed to them. Events are queued in the database together with the
+// change that caused them and delivered by worker.WebhookDeliveryWorker.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Domain events a subscription can listen to
+const (
+	EventBookingCreated           = "booking.created"
+	EventBookingStatusChanged     = "booking.status_changed"
+	EventApplicationCreated       = "application.created"
+	EventApplicationStatusChanged = "application.status_changed"
+	EventBoxUpdated               = "box.updated"
+	EventSpecialProjectCreated    = "special_project.created"
+	EventSpecialProjectUpdated    = "special_project.updated"
+)
+
+// Events lists all supported events
+var Events = []string{
+	EventBookingCreated,
+	EventBookingStatusChanged,
+	EventApplicationCreated,
+	EventApplicationStatusChanged,
+	EventBoxUpdated,
+	EventSpecialProjectCreated,
+	EventSpecialProjectUpdated,
+}
+
+// Headers of a delivery request
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// IsKnownEvent reports whether the event can be subscribed to
+func IsKnownEvent(event string) bool {
+	return slices.Contains(Events, event)
+}
+
+// Publisher queues a domain event for the subscribed endpoints
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any) error
+}
+
+type eventQueue interface {
+	EnqueueEvent(ctx context.Context, event string, payload []byte) error
+}
+
+// QueuePublisher implements Publisher over the delivery queue in the database.
+// The event is queued within the transaction stored in ctx, if any, so it is
+// sent only when the change is committed.
+type QueuePublisher struct {
+	queue eventQueue
+}
+
+// NewPublisher creates a new QueuePublisher
+func NewPublisher(queue eventQueue) *QueuePublisher {
+	return &QueuePublisher{queue: queue}
+}
+
+func (p *QueuePublisher) Publish(ctx context.Context, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", event, err)
+	}
+	return p.queue.EnqueueEvent(ctx, event, payload)
+}
+
+// Sign returns the signature of the request body sent at timestamp (unix seconds).
+// The receiver computes HMAC-SHA256 of "<timestamp>.<body>" with the
+// subscription secret and compares it with the X-Webhook-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueue struct {
+	event   string
+	payload []byte
+}
+
+func (q *fakeQueue) EnqueueEvent(_ context.Context, event string, payload []byte) error {
+	q.event, q.payload = event, payload
+	return nil
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"box.updated"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, want, Sign("secret", 1700000000, body))
+	assert.NotEqual(t, want, Sign("other", 1700000000, body))
+	assert.NotEqual(t, want, Sign("secret", 1700000001, body))
+}
+
+func TestQueuePublisher_Publish(t *testing.T) {
+	queue := &fakeQueue{}
+	p := NewPublisher(queue)
+
+	require.NoError(t, p.Publish(context.Background(), EventBoxUpdated, Box{ID: 3, Name: "Экскурсия", Slots: []Slot{}}))
+
+	assert.Equal(t, EventBoxUpdated, queue.event)
+	assert.JSONEq(t, `{"id":3,"name":"Экскурсия","slug":"","description":"","status":"","price":0,
+		"location":"","organizer":"","slots":[],"updated_at":"0001-01-01T00:00:00Z"}`, string(queue.payload))
+}
+
+func TestIsKnownEvent(t *testing.T) {
+	assert.True(t, IsKnownEvent(EventBookingCreated))
+	assert.False(t, IsKnownEvent("booking.deleted"))
+}
@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/msgtemplate"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	reminderWeekLead    = 7 * 24 * time.Hour
+	reminder24HoursLead = 24 * time.Hour
+)
+
+// MessageSender sends messages to Telegram chats.
+type MessageSender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+}
+
+// ChatRateLimiter limits the rate of messages sent to a single chat.
+type ChatRateLimiter interface {
+	Exec(ctx context.Context, chatID int64, f func() error) error
+}
+
+type settingsGetter interface {
+	GetSettings(ctx context.Context) (models.SettingsFormMessages, error)
+}
+
+// BookingReminderWorker periodically reminds guests about their confirmed bookings
+// a week and 24 hours before the visit.
+type BookingReminderWorker struct {
+	repo      repository.BookingReminderRepository
+	settings  settingsGetter
+	bot       MessageSender
+	msgRL     ChatRateLimiter
+	interval  time.Duration
+	batchSize int
+}
+
+// NewBookingReminderWorker creates a new BookingReminderWorker.
+func NewBookingReminderWorker(
+	repo repository.BookingReminderRepository,
+	settings settingsGetter,
+	bot MessageSender,
+	msgRL ChatRateLimiter,
+	interval time.Duration,
+	batchSize int,
+) *BookingReminderWorker {
+	return &BookingReminderWorker{
+		repo:      repo,
+		settings:  settings,
+		bot:       bot,
+		msgRL:     msgRL,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Start runs the reminder loop until the context is cancelled.
+func (w *BookingReminderWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		logger.Warn("booking reminder worker disabled: interval <= 0")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("booking reminder worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *BookingReminderWorker) runOnce(ctx context.Context) {
+	settings, err := w.settings.GetSettings(ctx)
+	if err != nil {
+		logger.Error("booking reminders: get settings failed", zap.Error(err))
+		return
+	}
+
+	// The week reminder is only due while the 24 hours one is not,
+	// so a late booking receives a single reminder.
+	w.sendReminders(ctx, models.ReminderKindWeek, settings.EventReminderForWeek, reminder24HoursLead, reminderWeekLead)
+	w.sendReminders(ctx, models.ReminderKind24Hours, settings.EventReminderFor24Hours, 0, reminder24HoursLead)
+}
+
+func (w *BookingReminderWorker) sendReminders(ctx context.Context, kind models.ReminderKind, text string, from, to time.Duration) {
+	if strings.TrimSpace(text) == "" {
+		logger.Debug("booking reminders: message text is empty", zap.String("kind", string(kind)))
+		return
+	}
+
+	reminders, err := w.repo.GetDueReminders(ctx, kind, from, to, w.batchSize)
+	if err != nil {
+		logger.Error("booking reminders: get due reminders failed", zap.String("kind", string(kind)), zap.Error(err))
+		return
+	}
+
+	var sent int
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			return
+		}
+		if w.sendReminder(ctx, reminder, text) {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		logger.Info("booking reminders sent", zap.String("kind", string(kind)), zap.Int("count", sent))
+	}
+}
+
+func (w *BookingReminderWorker) sendReminder(ctx context.Context, reminder models.DueReminder, text string) bool {
+	kind := string(reminder.Kind)
+
+	// Claim the reminder before sending so a restart or a second instance
+	// never delivers it twice.
+	claimed, err := w.repo.ClaimReminder(ctx, reminder.BookingID, reminder.Kind)
+	if err != nil {
+		logger.Error("booking reminders: claim failed",
+			zap.Int64("booking_id", reminder.BookingID), zap.String("kind", kind), zap.Error(err))
+		metrics.IncRemindersFailed(kind)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	message, err := formatReminder(text, reminder)
+	if err != nil {
+		// The template was validated on save; keep the claim, the next run
+		// would fail the same way
+		logger.Error("booking reminders: render failed",
+			zap.Int64("booking_id", reminder.BookingID), zap.String("kind", kind), zap.Error(err))
+		metrics.IncRemindersFailed(kind)
+		return false
+	}
+
+	msg := tgbotapi.NewMessage(reminder.ChatID, message)
+	err = w.msgRL.Exec(ctx, reminder.ChatID, func() error {
+		_, err := w.bot.Send(msg)
+		return err
+	})
+	if err != nil {
+		logger.Error("booking reminders: send failed",
+			zap.Int64("booking_id", reminder.BookingID), zap.String("kind", kind), zap.Error(err))
+		metrics.IncRemindersFailed(kind)
+
+		if err := w.repo.ReleaseReminder(context.WithoutCancel(ctx), reminder.BookingID, reminder.Kind); err != nil {
+			logger.Error("booking reminders: release failed",
+				zap.Int64("booking_id", reminder.BookingID), zap.String("kind", kind), zap.Error(err))
+		}
+		return false
+	}
+
+	metrics.IncRemindersSent(kind)
+	return true
+}
+
+// formatReminder renders the reminder; a text without placeholders is
+// followed by the booking details as before the templates
+func formatReminder(text string, reminder models.DueReminder) (string, error) {
+	return msgtemplate.Render(msgtemplate.WithDetails(text), msgtemplate.Data{
+		GuestName:   reminder.GuestName,
+		ServiceName: reminder.ServiceName,
+		Date:        reminder.BookingDate.Format("02.01.2006"),
+		Time:        msgtemplate.FormatTime(reminder.BookingTime),
+		BookingID:   reminder.BookingID,
+		Lang:        reminder.Language,
+	})
+}
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeReminderRepo struct {
+	due      map[models.ReminderKind][]models.DueReminder
+	claimed  map[int64]bool
+	released []int64
+}
+
+func (r *fakeReminderRepo) GetDueReminders(_ context.Context, kind models.ReminderKind, _, _ time.Duration, _ int) ([]models.DueReminder, error) {
+	var out []models.DueReminder
+	for _, rem := range r.due[kind] {
+		if !r.claimed[rem.BookingID] {
+			rem.Kind = kind
+			out = append(out, rem)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeReminderRepo) ClaimReminder(_ context.Context, bookingID int64, _ models.ReminderKind) (bool, error) {
+	if r.claimed[bookingID] {
+		return false, nil
+	}
+	r.claimed[bookingID] = true
+	return true, nil
+}
+
+func (r *fakeReminderRepo) ReleaseReminder(_ context.Context, bookingID int64, _ models.ReminderKind) error {
+	delete(r.claimed, bookingID)
+	r.released = append(r.released, bookingID)
+	return nil
+}
+
+type fakeSettings struct{ s models.SettingsFormMessages }
+
+func (f fakeSettings) GetSettings(context.Context) (models.SettingsFormMessages, error) {
+	return f.s, nil
+}
+
+type fakeSender struct {
+	sent []tgbotapi.MessageConfig
+	err  error
+}
+
+func (f *fakeSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	if f.err != nil {
+		return tgbotapi.Message{}, f.err
+	}
+	f.sent = append(f.sent, c.(tgbotapi.MessageConfig))
+	return tgbotapi.Message{}, nil
+}
+
+type noopLimiter struct{}
+
+func (noopLimiter) Exec(_ context.Context, _ int64, f func() error) error { return f() }
+
+func newTestReminderWorker(repo *fakeReminderRepo, sender *fakeSender) *BookingReminderWorker {
+	metrics.Initialize(config.Config{Environment: "test", HostName: "test"})
+	settings := fakeSettings{s: models.SettingsFormMessages{
+		EventReminderForWeek:    "Через неделю",
+		EventReminderFor24Hours: "Завтра",
+	}}
+	return NewBookingReminderWorker(repo, settings, sender, noopLimiter{}, time.Minute, 10)
+}
+
+func TestBookingReminderWorker_SendsOnce(t *testing.T) {
+	visit := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	at := time.Date(0, 1, 1, 14, 30, 0, 0, time.UTC)
+	repo := &fakeReminderRepo{
+		due: map[models.ReminderKind][]models.DueReminder{
+			models.ReminderKind24Hours: {{BookingID: 1, ChatID: 100, ServiceName: "Бокс А", BookingDate: visit, BookingTime: &at}},
+		},
+		claimed: map[int64]bool{},
+	}
+	sender := &fakeSender{}
+	w := newTestReminderWorker(repo, sender)
+
+	w.runOnce(context.Background())
+	w.runOnce(context.Background())
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, int64(100), sender.sent[0].ChatID)
+	assert.Equal(t, "Завтра\n\nБокс А, 10.05.2026 14:30", sender.sent[0].Text)
+}
+
+func TestBookingReminderWorker_ReleasesOnSendError(t *testing.T) {
+	repo := &fakeReminderRepo{
+		due: map[models.ReminderKind][]models.DueReminder{
+			models.ReminderKindWeek: {{BookingID: 2, ChatID: 200, BookingDate: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)}},
+		},
+		claimed: map[int64]bool{},
+	}
+	sender := &fakeSender{err: errors.New("telegram unavailable")}
+	w := newTestReminderWorker(repo, sender)
+
+	w.runOnce(context.Background())
+
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, []int64{2}, repo.released)
+	assert.False(t, repo.claimed[2])
+}
+
+func TestBookingReminderWorker_RendersTemplate(t *testing.T) {
+	at := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	repo := &fakeReminderRepo{
+		due: map[models.ReminderKind][]models.DueReminder{
+			models.ReminderKind24Hours: {{BookingID: 3, ChatID: 300, GuestName: "Анна", ServiceName: "Бокс Б", Language: "en",
+				BookingDate: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC), BookingTime: &at}},
+		},
+		claimed: map[int64]bool{},
+	}
+	sender := &fakeSender{}
+	settings := fakeSettings{s: models.SettingsFormMessages{
+		EventReminderFor24Hours: `{{if eq .Lang "en"}}See you tomorrow{{else}}До завтра{{end}}, {{.GuestName}}: {{.ServiceName}} at {{.Time}}`,
+	}}
+	metrics.Initialize(config.Config{Environment: "test", HostName: "test"})
+	w := NewBookingReminderWorker(repo, settings, sender, noopLimiter{}, time.Minute, 10)
+
+	w.runOnce(context.Background())
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "See you tomorrow, Анна: Бокс Б at 09:00", sender.sent[0].Text)
+}
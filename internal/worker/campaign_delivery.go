@@ -0,0 +1,200 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	// campaignLease covers a batch sent at the global rate limit
+	campaignLease        = 5 * time.Minute
+	campaignRetryBase    = 30 * time.Second
+	campaignRetryMaxWait = 30 * time.Minute
+)
+
+// GlobalRateLimiter limits the rate of all requests of the bot to the
+// Telegram API, whatever chat they go to.
+type GlobalRateLimiter interface {
+	Exec(ctx context.Context, f func()) error
+}
+
+// CampaignDeliveryWorker sends the campaigns to their recipients within the
+// global and per-chat limits of Telegram and records the result of every
+// delivery.
+type CampaignDeliveryWorker struct {
+	repo        repository.CampaignDeliveryRepository
+	bot         MessageSender
+	apiRL       GlobalRateLimiter
+	msgRL       ChatRateLimiter
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewCampaignDeliveryWorker creates a new CampaignDeliveryWorker.
+func NewCampaignDeliveryWorker(
+	repo repository.CampaignDeliveryRepository,
+	bot MessageSender,
+	apiRL GlobalRateLimiter,
+	msgRL ChatRateLimiter,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts int,
+) *CampaignDeliveryWorker {
+	return &CampaignDeliveryWorker{
+		repo:        repo,
+		bot:         bot,
+		apiRL:       apiRL,
+		msgRL:       msgRL,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start runs the delivery loop until the context is cancelled.
+func (w *CampaignDeliveryWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		logger.Warn("campaign delivery worker disabled: interval <= 0")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("campaign delivery worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *CampaignDeliveryWorker) runOnce(ctx context.Context) {
+	claim := func(ctx context.Context) ([]models.CampaignDelivery, error) {
+		return w.repo.ClaimDeliveries(ctx, w.batchSize, campaignLease)
+	}
+	if err := deliverClaimed(ctx, claim, w.deliver); err != nil {
+		logger.Error("campaign delivery: claim failed", zap.Error(err))
+		return
+	}
+
+	// A campaign is finished once none of its recipients is pending
+	done, err := w.repo.CompleteFinished(ctx)
+	if err != nil {
+		logger.Error("campaign delivery: complete failed", zap.Error(err))
+		return
+	}
+	if done > 0 {
+		logger.Info("campaign delivery: campaigns finished", zap.Int("count", done))
+	}
+}
+
+func (w *CampaignDeliveryWorker) deliver(ctx context.Context, d models.CampaignDelivery) {
+	err := w.msgRL.Exec(ctx, d.TelegramID, func() error {
+		var sendErr error
+		if err := w.apiRL.Exec(ctx, func() {
+			_, sendErr = w.bot.Send(campaignMessage(d))
+		}); err != nil {
+			return err
+		}
+		return sendErr
+	})
+	if err != nil && ctx.Err() != nil {
+		// Shutdown interrupted the wait for the rate limiters before the
+		// send: the recipient is picked up again after the lease
+		return
+	}
+
+	if err == nil {
+		recordOutcome(ctx, "campaign delivery: mark sent", d.ID, func(ctx context.Context) error {
+			return w.repo.MarkDelivered(ctx, d.ID, models.CampaignRecipientSent, "")
+		})
+		return
+	}
+
+	status, retryAt := w.failure(err, d.Attempts)
+
+	logger.Warn("campaign delivery: delivery failed",
+		zap.Int64("campaign_id", d.CampaignID),
+		zap.Int64("chat_id", d.TelegramID),
+		zap.Int("attempts", d.Attempts),
+		zap.String("status", status),
+		zap.Error(err),
+	)
+
+	// A recipient left pending keeps the campaign running; any other status is final
+	recordOutcome(ctx, "campaign delivery: mark failed", d.ID, func(ctx context.Context) error {
+		if retryAt != nil {
+			return w.repo.MarkRetry(ctx, d.ID, err.Error(), *retryAt)
+		}
+		return w.repo.MarkDelivered(ctx, d.ID, status, err.Error())
+	})
+}
+
+// failure decides what the send error means for the recipient: the user has
+// blocked the bot, Telegram rejected the message, or it is worth retrying.
+// A non-nil retryAt keeps the recipient pending.
+func (w *CampaignDeliveryWorker) failure(err error, attempts int) (string, *time.Time) {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) {
+		switch {
+		case tgErr.Code == http.StatusForbidden:
+			return models.CampaignRecipientBlocked, nil
+		case tgErr.Code == http.StatusBadRequest:
+			return models.CampaignRecipientFailed, nil
+		case tgErr.RetryAfter > 0 && attempts < w.maxAttempts:
+			// Flood control: wait as long as Telegram asks to
+			next := time.Now().Add(time.Duration(tgErr.RetryAfter) * time.Second)
+			return models.CampaignRecipientPending, &next
+		}
+	}
+
+	if retryAt := nextRetry(attempts, w.maxAttempts, campaignRetryBase, campaignRetryMaxWait); retryAt != nil {
+		return models.CampaignRecipientPending, retryAt
+	}
+	return models.CampaignRecipientFailed, nil
+}
+
+// campaignMessage is a photo with the text as the caption or a text message,
+// with a row per button
+func campaignMessage(d models.CampaignDelivery) tgbotapi.Chattable {
+	var keyboard *tgbotapi.InlineKeyboardMarkup
+	if len(d.Buttons) > 0 {
+		rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(d.Buttons))
+		for _, b := range d.Buttons {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL(b.Text, b.URL)))
+		}
+		markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		keyboard = &markup
+	}
+
+	if d.ImageURL != "" {
+		photo := tgbotapi.NewPhoto(d.TelegramID, tgbotapi.FileURL(d.ImageURL))
+		photo.Caption = d.Text
+		if keyboard != nil {
+			photo.ReplyMarkup = keyboard
+		}
+		return photo
+	}
+
+	msg := tgbotapi.NewMessage(d.TelegramID, d.Text)
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+	return msg
+}
@@ -0,0 +1,158 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeCampaignRepo struct {
+	pending   []models.CampaignDelivery
+	statuses  map[int64]string
+	retries   map[int64]time.Time
+	completed int
+}
+
+func (r *fakeCampaignRepo) ClaimDeliveries(context.Context, int, time.Duration) ([]models.CampaignDelivery, error) {
+	out := r.pending
+	r.pending = nil
+	return out, nil
+}
+
+func (r *fakeCampaignRepo) MarkDelivered(_ context.Context, id int64, status, _ string) error {
+	r.statuses[id] = status
+	return nil
+}
+
+func (r *fakeCampaignRepo) MarkRetry(_ context.Context, id int64, _ string, retryAt time.Time) error {
+	r.retries[id] = retryAt
+	return nil
+}
+
+func (r *fakeCampaignRepo) CompleteFinished(context.Context) (int, error) {
+	r.completed++
+	return 0, nil
+}
+
+// fakeChattableSender records any message kind, unlike fakeSender
+type fakeChattableSender struct {
+	sent []tgbotapi.Chattable
+	errs map[int64]error
+}
+
+func (f *fakeChattableSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var chatID int64
+	switch m := c.(type) {
+	case tgbotapi.MessageConfig:
+		chatID = m.ChatID
+	case tgbotapi.PhotoConfig:
+		chatID = m.ChatID
+	}
+	if err := f.errs[chatID]; err != nil {
+		return tgbotapi.Message{}, err
+	}
+	f.sent = append(f.sent, c)
+	return tgbotapi.Message{}, nil
+}
+
+type countingGlobalLimiter struct {
+	calls int
+}
+
+func (l *countingGlobalLimiter) Exec(ctx context.Context, f func()) error {
+	// The real limiter fails to wait once the context is done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.calls++
+	f()
+	return nil
+}
+
+func newTestCampaignRepo(deliveries ...models.CampaignDelivery) *fakeCampaignRepo {
+	return &fakeCampaignRepo{
+		pending:  deliveries,
+		statuses: map[int64]string{},
+		retries:  map[int64]time.Time{},
+	}
+}
+
+func TestCampaignDeliveryWorker_Deliver(t *testing.T) {
+	repo := newTestCampaignRepo(
+		models.CampaignDelivery{ID: 1, CampaignID: 7, TelegramID: 100, Attempts: 1, Text: "Новая коробка!",
+			Buttons: []models.CampaignButton{{Text: "Записаться", URL: "https://t.me/bot?start=box_3"}}},
+		models.CampaignDelivery{ID: 2, CampaignID: 7, TelegramID: 200, Attempts: 1, Text: "Новая коробка!",
+			ImageURL: "https://cdn.example.com/box.png"},
+	)
+	sender := &fakeChattableSender{}
+	apiRL := &countingGlobalLimiter{}
+	w := NewCampaignDeliveryWorker(repo, sender, apiRL, noopLimiter{}, time.Second, 10, 5)
+
+	w.runOnce(context.Background())
+
+	require.Len(t, sender.sent, 2)
+	assert.Equal(t, 2, apiRL.calls, "every message must pass the global limiter")
+
+	msg, ok := sender.sent[0].(tgbotapi.MessageConfig)
+	require.True(t, ok)
+	assert.Equal(t, "Новая коробка!", msg.Text)
+	keyboard, ok := msg.ReplyMarkup.(*tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	require.NotNil(t, keyboard.InlineKeyboard[0][0].URL)
+	assert.Equal(t, "https://t.me/bot?start=box_3", *keyboard.InlineKeyboard[0][0].URL)
+
+	photo, ok := sender.sent[1].(tgbotapi.PhotoConfig)
+	require.True(t, ok)
+	assert.Equal(t, "Новая коробка!", photo.Caption)
+	assert.Nil(t, photo.ReplyMarkup)
+
+	assert.Equal(t, map[int64]string{1: models.CampaignRecipientSent, 2: models.CampaignRecipientSent}, repo.statuses)
+	assert.Equal(t, 1, repo.completed)
+}
+
+func TestCampaignDeliveryWorker_Failures(t *testing.T) {
+	repo := newTestCampaignRepo(
+		models.CampaignDelivery{ID: 1, TelegramID: 100, Attempts: 1, Text: "a"},
+		models.CampaignDelivery{ID: 2, TelegramID: 200, Attempts: 1, Text: "a"},
+		models.CampaignDelivery{ID: 3, TelegramID: 300, Attempts: 1, Text: "a"},
+		models.CampaignDelivery{ID: 4, TelegramID: 400, Attempts: 5, Text: "a"},
+		models.CampaignDelivery{ID: 5, TelegramID: 500, Attempts: 1, Text: "a"},
+	)
+	sender := &fakeChattableSender{errs: map[int64]error{
+		100: &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"},
+		200: &tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"},
+		300: errors.New("timeout"),
+		400: errors.New("timeout"),
+		500: &tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 30}},
+	}}
+	w := NewCampaignDeliveryWorker(repo, sender, &countingGlobalLimiter{}, noopLimiter{}, time.Second, 10, 5)
+
+	w.runOnce(context.Background())
+
+	assert.Equal(t, models.CampaignRecipientBlocked, repo.statuses[1])
+	assert.Equal(t, models.CampaignRecipientFailed, repo.statuses[2])
+	assert.Contains(t, repo.retries, int64(3), "transient error must be retried")
+	assert.Equal(t, models.CampaignRecipientFailed, repo.statuses[4], "recipient must be given up after max attempts")
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), repo.retries[5], 5*time.Second)
+}
+
+func TestCampaignDeliveryWorker_ShutdownKeepsPending(t *testing.T) {
+	repo := newTestCampaignRepo(models.CampaignDelivery{ID: 1, TelegramID: 100, Attempts: 1, Text: "a"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sender := &fakeChattableSender{}
+	w := NewCampaignDeliveryWorker(repo, sender, &countingGlobalLimiter{}, noopLimiter{}, time.Second, 10, 5)
+
+	w.deliver(ctx, repo.pending[0])
+
+	assert.Empty(t, sender.sent)
+	assert.Empty(t, repo.statuses)
+	assert.Empty(t, repo.retries)
+}
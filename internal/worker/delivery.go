@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+)
+
+// The delivery workers (notification outbox, webhooks, campaigns) share one
+// shape: claim a batch under a lease, attempt every item, then record the
+// outcome — sent, retry at a later time, or given up.
+
+// deliverClaimed claims a batch and attempts every item until ctx is
+// cancelled; the items left are claimed again once their lease expires
+func deliverClaimed[T any](
+	ctx context.Context,
+	claim func(ctx context.Context) ([]T, error),
+	deliver func(ctx context.Context, item T),
+) error {
+	items, err := claim(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return nil
+		}
+		deliver(ctx, item)
+	}
+	return nil
+}
+
+// recordOutcome stores the outcome of an attempt that was already made. A
+// shutdown must not cancel it: the item would stay claimed and be sent again
+// after its lease, so the store runs without the cancellation of ctx.
+func recordOutcome(ctx context.Context, operation string, id int64, store func(ctx context.Context) error) {
+	if err := store(context.WithoutCancel(ctx)); err != nil {
+		logger.Error(operation+" failed", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// nextRetry returns when a failed item is attempted again, nil once it has
+// used up maxAttempts
+func nextRetry(attempts, maxAttempts int, base, maxWait time.Duration) *time.Time {
+	if attempts >= maxAttempts {
+		return nil
+	}
+	next := time.Now().Add(retryBackoff(attempts, base, maxWait))
+	return &next
+}
+
+// retryBackoff doubles base with every attempt made so far, up to maxWait
+func retryBackoff(attempts int, base, maxWait time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxWait {
+			return maxWait
+		}
+	}
+	return delay
+}
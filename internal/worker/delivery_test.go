@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryBackoff(1, outboxRetryBase, outboxRetryMaxWait))
+	assert.Equal(t, time.Minute, retryBackoff(2, outboxRetryBase, outboxRetryMaxWait))
+	assert.Equal(t, 2*time.Minute, retryBackoff(3, outboxRetryBase, outboxRetryMaxWait))
+	assert.Equal(t, outboxRetryMaxWait, retryBackoff(20, outboxRetryBase, outboxRetryMaxWait))
+
+	assert.Equal(t, 4*time.Minute, retryBackoff(4, webhookRetryBase, webhookRetryMaxWait))
+	assert.Equal(t, webhookRetryMaxWait, retryBackoff(30, webhookRetryBase, webhookRetryMaxWait))
+}
+
+func TestNextRetry(t *testing.T) {
+	retryAt := nextRetry(1, 3, time.Minute, time.Hour)
+	if assert.NotNil(t, retryAt) {
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *retryAt, time.Second)
+	}
+	assert.Nil(t, nextRetry(3, 3, time.Minute, time.Hour), "no retry once the attempts are used up")
+}
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+)
+
+const (
+	outboxLease        = time.Minute
+	outboxRetryBase    = 30 * time.Second
+	outboxRetryMaxWait = 30 * time.Minute
+)
+
+// NotificationOutboxWorker delivers messages queued in the notification outbox
+// to Telegram, retrying failed deliveries with exponential backoff.
+type NotificationOutboxWorker struct {
+	repo        repository.NotificationOutboxRepository
+	bot         MessageSender
+	msgRL       ChatRateLimiter
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewNotificationOutboxWorker creates a new NotificationOutboxWorker.
+func NewNotificationOutboxWorker(
+	repo repository.NotificationOutboxRepository,
+	bot MessageSender,
+	msgRL ChatRateLimiter,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts int,
+) *NotificationOutboxWorker {
+	return &NotificationOutboxWorker{
+		repo:        repo,
+		bot:         bot,
+		msgRL:       msgRL,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start runs the delivery loop until the context is cancelled.
+func (w *NotificationOutboxWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		logger.Warn("notification outbox worker disabled: interval <= 0")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("notification outbox worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *NotificationOutboxWorker) runOnce(ctx context.Context) {
+	claim := func(ctx context.Context) ([]models.OutboxMessage, error) {
+		return w.repo.ClaimPending(ctx, w.batchSize, outboxLease)
+	}
+	if err := deliverClaimed(ctx, claim, w.deliver); err != nil {
+		logger.Error("notification outbox: claim failed", zap.Error(err))
+	}
+}
+
+func (w *NotificationOutboxWorker) deliver(ctx context.Context, msg models.OutboxMessage) {
+	err := w.msgRL.Exec(ctx, msg.ChatID, func() error {
+		_, err := w.bot.Send(tgbotapi.NewMessage(msg.ChatID, msg.Text))
+		return err
+	})
+
+	if err == nil {
+		recordOutcome(ctx, "notification outbox: mark sent", msg.ID, func(ctx context.Context) error {
+			return w.repo.MarkSent(ctx, msg.ID)
+		})
+		return
+	}
+
+	// A blocked bot or a missing chat does not get better with retries
+	var retryAt *time.Time
+	if !isPermanentSendError(err) {
+		retryAt = nextRetry(msg.Attempts, w.maxAttempts, outboxRetryBase, outboxRetryMaxWait)
+	}
+
+	logger.Warn("notification outbox: delivery failed",
+		zap.Int64("id", msg.ID),
+		zap.Int64("chat_id", msg.ChatID),
+		zap.Int("attempts", msg.Attempts),
+		zap.Bool("retry", retryAt != nil),
+		zap.Error(err),
+	)
+
+	recordOutcome(ctx, "notification outbox: mark failed", msg.ID, func(ctx context.Context) error {
+		return w.repo.MarkFailed(ctx, msg.ID, err.Error(), retryAt)
+	})
+}
+
+// isPermanentSendError reports whether retrying is pointless,
+// e.g. the user has blocked the bot or the chat does not exist.
+func isPermanentSendError(err error) bool {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) {
+		return tgErr.Code == http.StatusForbidden || tgErr.Code == http.StatusBadRequest
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/models"
+)
+
+type fakeOutboxRepo struct {
+	pending []models.OutboxMessage
+	sent    []int64
+	retries map[int64]*time.Time
+}
+
+func (r *fakeOutboxRepo) Enqueue(context.Context, int64, string) error { return nil }
+
+func (r *fakeOutboxRepo) ClaimPending(context.Context, int, time.Duration) ([]models.OutboxMessage, error) {
+	out := r.pending
+	r.pending = nil
+	return out, nil
+}
+
+func (r *fakeOutboxRepo) MarkSent(_ context.Context, id int64) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkFailed(_ context.Context, id int64, _ string, retryAt *time.Time) error {
+	r.retries[id] = retryAt
+	return nil
+}
+
+func TestNotificationOutboxWorker_Deliver(t *testing.T) {
+	repo := &fakeOutboxRepo{
+		pending: []models.OutboxMessage{{ID: 1, ChatID: 100, Text: "Ваша запись подтверждена", Attempts: 1}},
+		retries: map[int64]*time.Time{},
+	}
+	sender := &fakeSender{}
+	w := NewNotificationOutboxWorker(repo, sender, noopLimiter{}, time.Second, 10, 5)
+
+	w.runOnce(context.Background())
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Ваша запись подтверждена", sender.sent[0].Text)
+	assert.Equal(t, []int64{1}, repo.sent)
+}
+
+func TestNotificationOutboxWorker_Retry(t *testing.T) {
+	repo := &fakeOutboxRepo{
+		pending: []models.OutboxMessage{
+			{ID: 1, ChatID: 100, Text: "a", Attempts: 1},
+			{ID: 2, ChatID: 200, Text: "b", Attempts: 5},
+		},
+		retries: map[int64]*time.Time{},
+	}
+	w := NewNotificationOutboxWorker(repo, &fakeSender{err: errors.New("timeout")}, noopLimiter{}, time.Second, 10, 5)
+
+	w.runOnce(context.Background())
+
+	assert.NotNil(t, repo.retries[1], "transient error must be retried")
+	assert.Nil(t, repo.retries[2], "message must be given up after max attempts")
+	assert.Empty(t, repo.sent)
+}
+
+func TestNotificationOutboxWorker_BlockedChatIsPermanent(t *testing.T) {
+	repo := &fakeOutboxRepo{
+		pending: []models.OutboxMessage{{ID: 1, ChatID: 100, Text: "a", Attempts: 1}},
+		retries: map[int64]*time.Time{},
+	}
+	sender := &fakeSender{err: &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}}
+	w := NewNotificationOutboxWorker(repo, sender, noopLimiter{}, time.Second, 10, 5)
+
+	w.runOnce(context.Background())
+
+	_, recorded := repo.retries[1]
+	assert.True(t, recorded)
+	assert.Nil(t, repo.retries[1])
+}
@@ -0,0 +1,303 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/tracker"
+)
+
+var trackerEntities = []models.TrackerEntity{
+	models.TrackerEntityBooking,
+	models.TrackerEntityApplication,
+}
+
+type bookingStatusUpdater interface {
+	UpdateBookingStatus(ctx context.Context, id int64, status string) (*models.BookingAPI, error)
+}
+
+type applicationStatusUpdater interface {
+	UpdateApplicationStatus(ctx context.Context, id int64, status string) (*models.Application, error)
+}
+
+// TrackerSyncWorker creates Yandex Tracker issues for new bookings and
+// applications and keeps their statuses in sync in both directions.
+// A status changed locally is pushed to Tracker with a transition; a status
+// changed in Tracker is applied through the services, so guests get the
+// usual notifications.
+type TrackerSyncWorker struct {
+	repo          repository.TrackerSyncRepository
+	client        tracker.Client
+	bookings      bookingStatusUpdater
+	applications  applicationStatusUpdater
+	statuses      map[string]string
+	localStatuses map[string]string
+	interval      time.Duration
+	batchSize     int
+}
+
+// NewTrackerSyncWorker creates a new TrackerSyncWorker. statuses maps local
+// statuses to Tracker status keys; unmapped statuses are not synced.
+func NewTrackerSyncWorker(
+	repo repository.TrackerSyncRepository,
+	client tracker.Client,
+	bookings bookingStatusUpdater,
+	applications applicationStatusUpdater,
+	statuses map[string]string,
+	interval time.Duration,
+	batchSize int,
+) *TrackerSyncWorker {
+	local := make([]string, 0, len(statuses))
+	for status := range statuses {
+		local = append(local, status)
+	}
+	sort.Strings(local)
+
+	localStatuses := make(map[string]string, len(statuses))
+	for _, status := range local {
+		if _, ok := localStatuses[statuses[status]]; !ok {
+			localStatuses[statuses[status]] = status
+		}
+	}
+
+	return &TrackerSyncWorker{
+		repo:          repo,
+		client:        client,
+		bookings:      bookings,
+		applications:  applications,
+		statuses:      statuses,
+		localStatuses: localStatuses,
+		interval:      interval,
+		batchSize:     batchSize,
+	}
+}
+
+// Start runs the sync loop until the context is cancelled.
+func (w *TrackerSyncWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		logger.Warn("tracker sync worker disabled: interval <= 0")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("tracker sync worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *TrackerSyncWorker) runOnce(ctx context.Context) {
+	for _, entity := range trackerEntities {
+		if ctx.Err() != nil {
+			return
+		}
+		w.createIssues(ctx, entity)
+		w.syncStatuses(ctx, entity)
+	}
+}
+
+func (w *TrackerSyncWorker) createIssues(ctx context.Context, entity models.TrackerEntity) {
+	items, err := w.repo.GetWithoutTicket(ctx, entity, w.batchSize)
+	if err != nil {
+		logger.Error("tracker sync: get records without ticket failed",
+			zap.String("entity", string(entity)), zap.Error(err))
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.createIssue(ctx, item); err != nil {
+			metrics.IncTrackerOperations(string(entity), "create", "error")
+			logger.Error("tracker sync: create issue failed",
+				zap.String("entity", string(entity)),
+				zap.Int64("id", item.ID),
+				zap.Error(err))
+			continue
+		}
+		metrics.IncTrackerOperations(string(entity), "create", "ok")
+	}
+}
+
+func (w *TrackerSyncWorker) createIssue(ctx context.Context, item models.TrackerItem) error {
+	unique := trackerUnique(item)
+
+	issue, err := w.client.CreateIssue(ctx, trackerIssueRequest(item, unique))
+	if errors.Is(err, tracker.ErrConflict) {
+		issue, err = w.client.FindIssueByUnique(ctx, unique)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The agreed status is the one the new issue is in. When the record
+	// status differs, the next sync pushes it to Tracker.
+	return w.repo.SetTicket(ctx, item.Entity, item.ID, issue.Key, w.localStatuses[issue.Status.Key])
+}
+
+func (w *TrackerSyncWorker) syncStatuses(ctx context.Context, entity models.TrackerEntity) {
+	items, err := w.repo.GetForSync(ctx, entity, w.batchSize)
+	if err != nil {
+		logger.Error("tracker sync: get records for sync failed",
+			zap.String("entity", string(entity)), zap.Error(err))
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+
+		operation, syncFn := "pull", w.pullStatus
+		if item.Status != item.TrackerStatus {
+			operation, syncFn = "push", w.pushStatus
+		}
+
+		if err := syncFn(ctx, item); err != nil {
+			metrics.IncTrackerOperations(string(entity), operation, "error")
+			logger.Error("tracker sync: status sync failed",
+				zap.String("entity", string(entity)),
+				zap.Int64("id", item.ID),
+				zap.String("ticket", item.TicketKey),
+				zap.String("operation", operation),
+				zap.Error(err))
+			continue
+		}
+		metrics.IncTrackerOperations(string(entity), operation, "ok")
+	}
+}
+
+// pushStatus moves the issue to the status the record has locally
+func (w *TrackerSyncWorker) pushStatus(ctx context.Context, item models.TrackerItem) error {
+	if target, ok := w.statuses[item.Status]; ok {
+		issue, err := w.client.GetIssue(ctx, item.TicketKey)
+		if err != nil {
+			return err
+		}
+
+		if issue.Status.Key != target {
+			transitions, err := w.client.Transitions(ctx, item.TicketKey)
+			if err != nil {
+				return err
+			}
+
+			transitionID := ""
+			for _, t := range transitions {
+				if t.To.Key == target {
+					transitionID = t.ID
+					break
+				}
+			}
+			if transitionID == "" {
+				return fmt.Errorf("no transition from %q to %q", issue.Status.Key, target)
+			}
+
+			if err := w.client.ExecuteTransition(ctx, item.TicketKey, transitionID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.repo.MarkSynced(ctx, item.Entity, item.ID, item.Status)
+}
+
+// pullStatus applies the issue status to the record when it was changed in Tracker
+func (w *TrackerSyncWorker) pullStatus(ctx context.Context, item models.TrackerItem) error {
+	issue, err := w.client.GetIssue(ctx, item.TicketKey)
+	if err != nil {
+		return err
+	}
+
+	status, ok := w.localStatuses[issue.Status.Key]
+	if !ok || status == item.Status {
+		return w.repo.MarkSynced(ctx, item.Entity, item.ID, item.Status)
+	}
+
+	switch item.Entity {
+	case models.TrackerEntityBooking:
+		_, err = w.bookings.UpdateBookingStatus(ctx, item.ID, status)
+	case models.TrackerEntityApplication:
+		_, err = w.applications.UpdateApplicationStatus(ctx, item.ID, status)
+	default:
+		err = fmt.Errorf("unknown tracker entity %q", item.Entity)
+	}
+	if err != nil {
+		return fmt.Errorf("apply status %q: %w", status, err)
+	}
+
+	logger.Info("tracker sync: status changed in tracker",
+		zap.String("entity", string(item.Entity)),
+		zap.Int64("id", item.ID),
+		zap.String("ticket", item.TicketKey),
+		zap.String("status", status))
+
+	return w.repo.MarkSynced(ctx, item.Entity, item.ID, status)
+}
+
+func trackerUnique(item models.TrackerItem) string {
+	return fmt.Sprintf("bot-%s-%d", item.Entity, item.ID)
+}
+
+func trackerIssueRequest(item models.TrackerItem, unique string) tracker.IssueRequest {
+	var (
+		summary string
+		desc    strings.Builder
+	)
+
+	switch item.Entity {
+	case models.TrackerEntityBooking:
+		summary = fmt.Sprintf("Бронирование #%d: %s — %s", item.ID, item.ServiceName, item.GuestName)
+		fmt.Fprintf(&desc, "Услуга: %s\n", item.ServiceName)
+		if item.BookingDate != nil {
+			fmt.Fprintf(&desc, "Дата: %s\n", item.BookingDate.Format("02.01.2006"))
+		}
+		if item.BookingTime != nil {
+			fmt.Fprintf(&desc, "Время: %s\n", item.BookingTime.Format("15:04"))
+		}
+		fmt.Fprintf(&desc, "Гость: %s\n", item.GuestName)
+		fmt.Fprintf(&desc, "Организация: %s\n", item.GuestOrganization)
+		fmt.Fprintf(&desc, "Должность: %s\n", item.GuestPosition)
+	default:
+		summary = fmt.Sprintf("Заявка #%d: %s", item.ID, item.GuestName)
+		fmt.Fprintf(&desc, "Клиент: %s\n", item.GuestName)
+		fmt.Fprintf(&desc, "Описание: %s\n", item.Description)
+	}
+	if item.GuestContact != "" {
+		fmt.Fprintf(&desc, "Контакт: %s\n", item.GuestContact)
+	}
+	if item.ManagerName != "" {
+		fmt.Fprintf(&desc, "Менеджер: %s", item.ManagerName)
+		if item.ManagerEmail != "" {
+			fmt.Fprintf(&desc, " (%s)", item.ManagerEmail)
+		}
+		desc.WriteString("\n")
+	}
+	fmt.Fprintf(&desc, "Статус: %s\n", item.Status)
+
+	return tracker.IssueRequest{
+		Summary:     summary,
+		Description: desc.String(),
+		Unique:      unique,
+		Tags:        []string{string(item.Entity)},
+	}
+}
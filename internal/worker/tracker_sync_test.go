@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/tracker"
+)
+
+// trackerStub is a minimal in-memory Tracker API: every status can be reached
+// from any other one by the transition "to_<status>".
+type trackerStub struct {
+	mu       sync.Mutex
+	issues   map[string]*tracker.Issue
+	byUnique map[string]string
+	executed []string
+}
+
+func newTrackerStub(t *testing.T) (*trackerStub, *tracker.HTTPClient) {
+	stub := &trackerStub{issues: map[string]*tracker.Issue{}, byUnique: map[string]string{}}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	client := tracker.New(config.TrackerConfig{BaseURL: srv.URL, Token: "token", OrgID: "1", Queue: "BOOK"}, srv.Client())
+	return stub, client
+}
+
+func (s *trackerStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/issues/")
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		var req tracker.IssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if _, ok := s.byUnique[req.Unique]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		key := fmt.Sprintf("%s-%d", req.Queue, len(s.issues)+1)
+		s.issues[key] = &tracker.Issue{Key: key, Status: tracker.Status{Key: "open"}}
+		s.byUnique[req.Unique] = key
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(s.issues[key])
+
+	case r.Method == http.MethodPost && path == "_search":
+		var req struct {
+			Filter map[string]string `json:"filter"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		found := []tracker.Issue{}
+		if key, ok := s.byUnique[req.Filter["unique"]]; ok {
+			found = append(found, *s.issues[key])
+		}
+		_ = json.NewEncoder(w).Encode(found)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/transitions"):
+		_ = json.NewEncoder(w).Encode([]tracker.Transition{
+			{ID: "to_open", To: tracker.Status{Key: "open"}},
+			{ID: "to_inProgress", To: tracker.Status{Key: "inProgress"}},
+			{ID: "to_closed", To: tracker.Status{Key: "closed"}},
+		})
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/_execute"):
+		parts := strings.Split(path, "/")
+		issue, ok := s.issues[parts[0]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		issue.Status.Key = strings.TrimPrefix(parts[2], "to_")
+		s.executed = append(s.executed, parts[0]+":"+parts[2])
+		_ = json.NewEncoder(w).Encode([]tracker.Transition{})
+
+	case r.Method == http.MethodGet:
+		issue, ok := s.issues[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(issue)
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *trackerStub) setStatus(key, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issues[key].Status.Key = status
+}
+
+type fakeTrackerRepo struct {
+	withoutTicket []models.TrackerItem
+	forSync       []models.TrackerItem
+	tickets       map[int64]string
+	synced        map[int64]string
+}
+
+func (r *fakeTrackerRepo) GetWithoutTicket(_ context.Context, entity models.TrackerEntity, _ int) ([]models.TrackerItem, error) {
+	return filterTrackerItems(r.withoutTicket, entity), nil
+}
+
+func (r *fakeTrackerRepo) GetForSync(_ context.Context, entity models.TrackerEntity, _ int) ([]models.TrackerItem, error) {
+	return filterTrackerItems(r.forSync, entity), nil
+}
+
+func (r *fakeTrackerRepo) SetTicket(_ context.Context, _ models.TrackerEntity, id int64, key, trackerStatus string) error {
+	r.tickets[id] = key
+	r.synced[id] = trackerStatus
+	return nil
+}
+
+func (r *fakeTrackerRepo) MarkSynced(_ context.Context, _ models.TrackerEntity, id int64, status string) error {
+	r.synced[id] = status
+	return nil
+}
+
+func filterTrackerItems(items []models.TrackerItem, entity models.TrackerEntity) []models.TrackerItem {
+	var out []models.TrackerItem
+	for _, item := range items {
+		if item.Entity == entity {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+type fakeStatusUpdater struct {
+	bookings     map[int64]string
+	applications map[int64]string
+}
+
+func (f *fakeStatusUpdater) UpdateBookingStatus(_ context.Context, id int64, status string) (*models.BookingAPI, error) {
+	f.bookings[id] = status
+	return &models.BookingAPI{ID: id, Status: status}, nil
+}
+
+func (f *fakeStatusUpdater) UpdateApplicationStatus(_ context.Context, id int64, status string) (*models.Application, error) {
+	f.applications[id] = status
+	return &models.Application{ID: id, Status: status}, nil
+}
+
+var testTrackerStatuses = map[string]string{
+	models.BookingStatusPending:   "open",
+	models.BookingStatusConfirmed: "inProgress",
+	models.BookingStatusCancelled: "closed",
+}
+
+func newTestTrackerWorker(t *testing.T, repo *fakeTrackerRepo) (*TrackerSyncWorker, *trackerStub, *fakeStatusUpdater) {
+	metrics.Initialize(config.Config{Environment: "test", HostName: "test"})
+	stub, client := newTrackerStub(t)
+	updater := &fakeStatusUpdater{bookings: map[int64]string{}, applications: map[int64]string{}}
+	return NewTrackerSyncWorker(repo, client, updater, updater, testTrackerStatuses, time.Minute, 10), stub, updater
+}
+
+func newFakeTrackerRepo() *fakeTrackerRepo {
+	return &fakeTrackerRepo{tickets: map[int64]string{}, synced: map[int64]string{}}
+}
+
+func TestTrackerSyncWorker_CreatesIssues(t *testing.T) {
+	repo := newFakeTrackerRepo()
+	repo.withoutTicket = []models.TrackerItem{
+		{Entity: models.TrackerEntityBooking, ID: 1, Status: models.BookingStatusConfirmed, GuestName: "Иванов", ServiceName: "Музей"},
+		{Entity: models.TrackerEntityApplication, ID: 2, Status: models.BookingStatusPending, GuestName: "Петров", Description: "Корпоратив"},
+	}
+	w, stub, _ := newTestTrackerWorker(t, repo)
+
+	w.runOnce(context.Background())
+
+	assert.Equal(t, "BOOK-1", repo.tickets[1])
+	assert.Equal(t, "BOOK-2", repo.tickets[2])
+	// the new issue is open, so the agreed status is pending and the
+	// confirmed booking is pushed on the next sync
+	assert.Equal(t, models.BookingStatusPending, repo.synced[1])
+	assert.Len(t, stub.issues, 2)
+}
+
+func TestTrackerSyncWorker_CreateRetryFindsExistingIssue(t *testing.T) {
+	repo := newFakeTrackerRepo()
+	item := models.TrackerItem{Entity: models.TrackerEntityBooking, ID: 7, Status: models.BookingStatusPending}
+	repo.withoutTicket = []models.TrackerItem{item}
+	w, stub, _ := newTestTrackerWorker(t, repo)
+
+	w.runOnce(context.Background())
+	delete(repo.tickets, 7)
+	w.runOnce(context.Background())
+
+	assert.Equal(t, "BOOK-1", repo.tickets[7])
+	assert.Len(t, stub.issues, 1)
+}
+
+func TestTrackerSyncWorker_PushesLocalStatus(t *testing.T) {
+	repo := newFakeTrackerRepo()
+	w, stub, updater := newTestTrackerWorker(t, repo)
+	stub.issues["BOOK-1"] = &tracker.Issue{Key: "BOOK-1", Status: tracker.Status{Key: "open"}}
+	repo.forSync = []models.TrackerItem{{
+		Entity:        models.TrackerEntityBooking,
+		ID:            1,
+		TicketKey:     "BOOK-1",
+		Status:        models.BookingStatusConfirmed,
+		TrackerStatus: models.BookingStatusPending,
+	}}
+
+	w.runOnce(context.Background())
+
+	assert.Equal(t, []string{"BOOK-1:to_inProgress"}, stub.executed)
+	assert.Equal(t, models.BookingStatusConfirmed, repo.synced[1])
+	assert.Empty(t, updater.bookings)
+}
+
+func TestTrackerSyncWorker_PullsTrackerStatus(t *testing.T) {
+	repo := newFakeTrackerRepo()
+	w, stub, updater := newTestTrackerWorker(t, repo)
+	stub.issues["BOOK-1"] = &tracker.Issue{Key: "BOOK-1", Status: tracker.Status{Key: "open"}}
+	stub.issues["BOOK-2"] = &tracker.Issue{Key: "BOOK-2", Status: tracker.Status{Key: "open"}}
+	stub.setStatus("BOOK-1", "closed")
+	stub.setStatus("BOOK-2", "inProgress")
+	repo.forSync = []models.TrackerItem{
+		{Entity: models.TrackerEntityBooking, ID: 1, TicketKey: "BOOK-1", Status: "pending", TrackerStatus: "pending"},
+		{Entity: models.TrackerEntityApplication, ID: 2, TicketKey: "BOOK-2", Status: "pending", TrackerStatus: "pending"},
+	}
+
+	w.runOnce(context.Background())
+
+	assert.Equal(t, models.BookingStatusCancelled, updater.bookings[1])
+	assert.Equal(t, models.BookingStatusConfirmed, updater.applications[2])
+	assert.Equal(t, models.BookingStatusCancelled, repo.synced[1])
+	assert.Equal(t, models.BookingStatusConfirmed, repo.synced[2])
+	assert.Empty(t, stub.executed)
+}
+
+func TestTrackerSyncWorker_UnchangedStatusOnlyMarksSynced(t *testing.T) {
+	repo := newFakeTrackerRepo()
+	w, stub, updater := newTestTrackerWorker(t, repo)
+	stub.issues["BOOK-1"] = &tracker.Issue{Key: "BOOK-1", Status: tracker.Status{Key: "inProgress"}}
+	repo.forSync = []models.TrackerItem{
+		{Entity: models.TrackerEntityBooking, ID: 1, TicketKey: "BOOK-1", Status: "confirmed", TrackerStatus: "confirmed"},
+	}
+
+	w.runOnce(context.Background())
+
+	require.Equal(t, "confirmed", repo.synced[1])
+	assert.Empty(t, updater.bookings)
+	assert.Empty(t, stub.executed)
+}
@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yandex-development-1-team/go/internal/logger"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
+)
+
+const (
+	webhookLease        = 2 * time.Minute
+	webhookRetryBase    = 30 * time.Second
+	webhookRetryMaxWait = 6 * time.Hour
+	webhookErrorBodyMax = 512
+)
+
+// webhookEnvelope is the body of a delivery request
+type webhookEnvelope struct {
+	ID         int64           `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDeliveryWorker sends queued domain events to the subscribed
+// endpoints. Each request is signed with the subscription secret; a non-2xx
+// answer is retried with exponential backoff until maxAttempts.
+type WebhookDeliveryWorker struct {
+	repo        repository.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewWebhookDeliveryWorker creates a new WebhookDeliveryWorker.
+func NewWebhookDeliveryWorker(
+	repo repository.WebhookRepository,
+	client *http.Client,
+	interval time.Duration,
+	batchSize int,
+	maxAttempts int,
+) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		repo:        repo,
+		client:      client,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Start runs the delivery loop until the context is cancelled.
+func (w *WebhookDeliveryWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		logger.Warn("webhook delivery worker disabled: interval <= 0")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *WebhookDeliveryWorker) runOnce(ctx context.Context) {
+	claim := func(ctx context.Context) ([]models.WebhookDeliveryTask, error) {
+		return w.repo.ClaimDeliveries(ctx, w.batchSize, webhookLease)
+	}
+	if err := deliverClaimed(ctx, claim, w.deliver); err != nil {
+		logger.Error("webhook delivery: claim failed", zap.Error(err))
+	}
+}
+
+func (w *WebhookDeliveryWorker) deliver(ctx context.Context, task models.WebhookDeliveryTask) {
+	status, err := w.send(ctx, task)
+
+	if err == nil {
+		metrics.IncWebhookDeliveries(task.Event, "delivered")
+		recordOutcome(ctx, "webhook delivery: mark delivered", task.ID, func(ctx context.Context) error {
+			return w.repo.MarkDelivered(ctx, task.ID, status)
+		})
+		return
+	}
+
+	// Any failure of the endpoint is retried: it may be down for a while
+	retryAt := nextRetry(task.Attempts, w.maxAttempts, webhookRetryBase, webhookRetryMaxWait)
+	result := "failed"
+	if retryAt != nil {
+		result = "retry"
+	}
+	metrics.IncWebhookDeliveries(task.Event, result)
+
+	logger.Warn("webhook delivery failed",
+		zap.Int64("id", task.ID),
+		zap.Int64("subscription_id", task.SubscriptionID),
+		zap.String("event", task.Event),
+		zap.Int("attempts", task.Attempts),
+		zap.Int("response_status", status),
+		zap.Bool("retry", retryAt != nil),
+		zap.Error(err),
+	)
+
+	recordOutcome(ctx, "webhook delivery: mark failed", task.ID, func(ctx context.Context) error {
+		return w.repo.MarkDeliveryFailed(ctx, task.ID, status, err.Error(), retryAt)
+	})
+}
+
+// send posts the event and returns the response status, 0 if there was no response
+func (w *WebhookDeliveryWorker) send(ctx context.Context, task models.WebhookDeliveryTask) (int, error) {
+	body, err := json.Marshal(webhookEnvelope{
+		ID:         task.ID,
+		Event:      task.Event,
+		OccurredAt: task.CreatedAt,
+		Data:       task.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal envelope: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderEvent, task.Event)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(task.ID, 10))
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(task.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyMax))
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-development-1-team/go/internal/config"
+	"github.com/yandex-development-1-team/go/internal/metrics"
+	"github.com/yandex-development-1-team/go/internal/models"
+	"github.com/yandex-development-1-team/go/internal/repository"
+	"github.com/yandex-development-1-team/go/internal/webhook"
+)
+
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+
+	pending   []models.WebhookDeliveryTask
+	delivered map[int64]int
+	retries   map[int64]*time.Time
+	statuses  map[int64]int
+}
+
+func newFakeWebhookRepo(tasks ...models.WebhookDeliveryTask) *fakeWebhookRepo {
+	return &fakeWebhookRepo{
+		pending:   tasks,
+		delivered: map[int64]int{},
+		retries:   map[int64]*time.Time{},
+		statuses:  map[int64]int{},
+	}
+}
+
+func (r *fakeWebhookRepo) ClaimDeliveries(context.Context, int, time.Duration) ([]models.WebhookDeliveryTask, error) {
+	out := r.pending
+	r.pending = nil
+	return out, nil
+}
+
+func (r *fakeWebhookRepo) MarkDelivered(_ context.Context, id int64, status int) error {
+	r.delivered[id] = status
+	return nil
+}
+
+func (r *fakeWebhookRepo) MarkDeliveryFailed(_ context.Context, id int64, status int, _ string, retryAt *time.Time) error {
+	r.statuses[id] = status
+	r.retries[id] = retryAt
+	return nil
+}
+
+func webhookTask(id int64, url string, attempts int) models.WebhookDeliveryTask {
+	return models.WebhookDeliveryTask{
+		WebhookDelivery: models.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: 1,
+			Event:          webhook.EventBookingStatusChanged,
+			Payload:        []byte(`{"id":42,"status":"confirmed"}`),
+			Attempts:       attempts,
+			CreatedAt:      time.Date(2026, 5, 5, 10, 0, 0, 0, time.UTC),
+		},
+		URL:    url,
+		Secret: "test-secret",
+	}
+}
+
+func newTestWebhookWorker(repo *fakeWebhookRepo) *WebhookDeliveryWorker {
+	metrics.Initialize(config.Config{Environment: "test", HostName: "test"})
+	return NewWebhookDeliveryWorker(repo, &http.Client{Timeout: time.Second}, time.Second, 10, 3)
+}
+
+func TestWebhookDeliveryWorker_SignedDelivery(t *testing.T) {
+	var (
+		headers http.Header
+		body    []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := newFakeWebhookRepo(webhookTask(7, srv.URL, 1))
+	newTestWebhookWorker(repo).runOnce(context.Background())
+
+	assert.Equal(t, map[int64]int{7: http.StatusNoContent}, repo.delivered)
+	assert.Equal(t, webhook.EventBookingStatusChanged, headers.Get(webhook.HeaderEvent))
+	assert.Equal(t, "7", headers.Get(webhook.HeaderDelivery))
+
+	ts, err := strconv.ParseInt(headers.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Sign("test-secret", ts, body), headers.Get(webhook.HeaderSignature))
+
+	var envelope struct {
+		ID    int64           `json:"id"`
+		Event string          `json:"event"`
+		Data  json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, int64(7), envelope.ID)
+	assert.JSONEq(t, `{"id":42,"status":"confirmed"}`, string(envelope.Data))
+}
+
+func TestWebhookDeliveryWorker_RetryAndGiveUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	repo := newFakeWebhookRepo(webhookTask(1, srv.URL, 1), webhookTask(2, srv.URL, 3))
+	newTestWebhookWorker(repo).runOnce(context.Background())
+
+	assert.Empty(t, repo.delivered)
+	assert.NotNil(t, repo.retries[1], "failed delivery must be retried")
+	assert.Nil(t, repo.retries[2], "delivery must be given up after max attempts")
+	assert.Equal(t, http.StatusServiceUnavailable, repo.statuses[1])
+}
+
+func TestWebhookDeliveryWorker_UnreachableEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	repo := newFakeWebhookRepo(webhookTask(1, url, 1))
+	newTestWebhookWorker(repo).runOnce(context.Background())
+
+	assert.NotNil(t, repo.retries[1])
+	assert.Equal(t, 0, repo.statuses[1])
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS booking_reminders (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_booking_reminders_booking
+        FOREIGN KEY (booking_id)
+            REFERENCES bookings(id)
+            ON DELETE CASCADE,
+
+    CONSTRAINT uq_booking_reminders_booking_kind
+        UNIQUE (booking_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_reminders_booking_id ON booking_reminders(booking_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_booking_reminders_booking_id;
+DROP TABLE IF EXISTS booking_reminders;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+ALTER TABLE service_available_slots
+    ADD COLUMN IF NOT EXISTS capacity INTEGER NOT NULL DEFAULT 1;
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE service_available_slots
+        ADD CONSTRAINT chk_available_slots_capacity CHECK (capacity > 0);
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_bookings_service_slot
+    ON bookings (service_id, booking_date, booking_time)
+    WHERE deleted_at IS NULL AND status != 'cancelled';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_bookings_service_slot;
+ALTER TABLE service_available_slots DROP CONSTRAINT IF EXISTS chk_available_slots_capacity;
+ALTER TABLE service_available_slots DROP COLUMN IF EXISTS capacity;
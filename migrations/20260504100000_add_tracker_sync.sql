@@ -0,0 +1,29 @@
+-- +goose Up
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS tracker_status VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS tracker_synced_at TIMESTAMPTZ;
+
+ALTER TABLE applications
+    ADD COLUMN IF NOT EXISTS tracker_ticket_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS tracker_status VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS tracker_synced_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_tracker_sync
+    ON bookings (tracker_synced_at NULLS FIRST)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_applications_tracker_sync
+    ON applications (tracker_synced_at NULLS FIRST)
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_applications_tracker_sync;
+DROP INDEX IF EXISTS idx_bookings_tracker_sync;
+ALTER TABLE applications
+    DROP COLUMN IF EXISTS tracker_synced_at,
+    DROP COLUMN IF EXISTS tracker_status,
+    DROP COLUMN IF EXISTS tracker_ticket_id;
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS tracker_synced_at,
+    DROP COLUMN IF EXISTS tracker_status;
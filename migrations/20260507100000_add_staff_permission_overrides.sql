@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Персональные права сотрудника поверх матрицы role_permissions:
+-- granted добавляет права к роли, denied отзывает их (запрет сильнее).
+ALTER TABLE staff
+    ADD COLUMN IF NOT EXISTS granted_permissions TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS denied_permissions TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE staff
+    DROP COLUMN IF EXISTS denied_permissions,
+    DROP COLUMN IF EXISTS granted_permissions;
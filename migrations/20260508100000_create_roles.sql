@@ -0,0 +1,67 @@
+-- +goose Up
+
+-- Роли задаются администратором вместо перечисления user_role_type.
+-- Системные роли (admin, user) нельзя удалить: на них завязана логика доступа.
+CREATE TABLE IF NOT EXISTS roles (
+    name        VARCHAR(64) PRIMARY KEY,
+    title       VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    is_system   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO roles (name, title, is_system) VALUES
+    ('admin', 'Администратор', TRUE),
+    ('manager_1', 'Менеджер 1', FALSE),
+    ('manager_2', 'Менеджер 2', FALSE),
+    ('manager_3', 'Менеджер 3', FALSE),
+    ('user', 'Пользователь', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE staff ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE staff ALTER COLUMN role TYPE VARCHAR(64) USING role::text;
+ALTER TABLE staff ALTER COLUMN role SET DEFAULT 'user';
+ALTER TABLE staff ADD CONSTRAINT fk_staff_role FOREIGN KEY (role) REFERENCES roles (name);
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(64) USING role::text;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles (name);
+
+ALTER TABLE role_permissions ALTER COLUMN role TYPE VARCHAR(64) USING role::text;
+ALTER TABLE role_permissions ADD CONSTRAINT fk_role_permissions_role
+    FOREIGN KEY (role) REFERENCES roles (name) ON DELETE CASCADE;
+
+DROP TYPE IF EXISTS user_role_type;
+
+-- +goose Down
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE user_role_type AS ENUM ('admin', 'manager_1', 'manager_2', 'manager_3', 'user');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+-- +goose StatementEnd
+
+ALTER TABLE role_permissions DROP CONSTRAINT IF EXISTS fk_role_permissions_role;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE staff DROP CONSTRAINT IF EXISTS fk_staff_role;
+
+-- Пользовательские роли в перечислении не выразить: сотрудники получают роль user
+DELETE FROM role_permissions WHERE role NOT IN ('admin', 'manager_1', 'manager_2', 'manager_3', 'user');
+UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'manager_1', 'manager_2', 'manager_3', 'user');
+UPDATE staff SET role = 'user' WHERE role NOT IN ('admin', 'manager_1', 'manager_2', 'manager_3', 'user');
+
+ALTER TABLE role_permissions ALTER COLUMN role TYPE user_role_type USING role::user_role_type;
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE user_role_type USING role::user_role_type;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+
+ALTER TABLE staff ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE staff ALTER COLUMN role TYPE user_role_type USING role::user_role_type;
+ALTER TABLE staff ALTER COLUMN role SET DEFAULT 'user';
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,60 @@
+-- +goose Up
+
+-- Сессия — семейство refresh-токенов одного устройства. Использованные токены
+-- остаются с rotated_at, чтобы повторное предъявление отозвало всю сессию.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES staff(id) ON DELETE CASCADE,
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_id ON auth_sessions(user_id);
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS session_id BIGINT REFERENCES auth_sessions(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS token_hash TEXT,
+    ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- Действующие токены становятся отдельными сессиями, открытые значения заменяются хешами
+INSERT INTO auth_sessions (id, user_id, expires_at, last_used_at, created_at)
+SELECT id, user_id, expires_at, COALESCE(created_at, NOW()), COALESCE(created_at, NOW())
+FROM refresh_tokens;
+
+SELECT setval(pg_get_serial_sequence('auth_sessions', 'id'), COALESCE((SELECT MAX(id) FROM auth_sessions), 0) + 1, false);
+
+UPDATE refresh_tokens
+SET session_id = id,
+    token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN session_id SET NOT NULL,
+    ALTER COLUMN token_hash SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+
+-- +goose Down
+
+-- Хеши не обратить в токены: все сессии завершаются
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS token_hash,
+    DROP COLUMN IF EXISTS session_id,
+    ADD COLUMN IF NOT EXISTS token TEXT UNIQUE NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
+
+DROP TABLE IF EXISTS auth_sessions;
@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- Роль может требовать двухфакторную аутентификацию от всех своих сотрудников
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS mfa_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Секрет TOTP хранится зашифрованным (AES-GCM), ключ — в конфигурации сервиса.
+-- mfa_enabled_at заполняется после подтверждения первым кодом, mfa_last_step
+-- не даёт повторно использовать уже принятый код.
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS mfa_secret TEXT;
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS mfa_enabled_at TIMESTAMPTZ;
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS mfa_last_step BIGINT;
+
+-- Резервные коды одноразовые, хранятся только их SHA-256 хеши
+CREATE TABLE IF NOT EXISTS staff_recovery_codes (
+    id         BIGSERIAL PRIMARY KEY,
+    staff_id   BIGINT NOT NULL REFERENCES staff(id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (staff_id, code_hash)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS staff_recovery_codes;
+
+ALTER TABLE staff DROP COLUMN IF EXISTS mfa_last_step;
+ALTER TABLE staff DROP COLUMN IF EXISTS mfa_enabled_at;
+ALTER TABLE staff DROP COLUMN IF EXISTS mfa_secret;
+
+ALTER TABLE roles DROP COLUMN IF EXISTS mfa_required;
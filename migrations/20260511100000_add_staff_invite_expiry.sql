@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Приглашение действует ограниченное время; повторная отправка выдаёт новый токен.
+-- Приглашения, выданные раньше, срока не имеют и не принимаются: их нужно отправить заново.
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS invite_expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_staff_invite_token ON staff (invite_token) WHERE invite_expires_at IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_staff_invite_token;
+
+ALTER TABLE staff DROP COLUMN IF EXISTS invite_expires_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Сотрудник, зарегистрировавшийся сам, подтверждает email по ссылке из письма
+-- и получает роль выше user только после одобрения администратором.
+-- Регистрация записывает NULL явно; остальные учётные записи, в том числе
+-- существующие, считаются подтверждёнными и одобренными.
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ DEFAULT NOW();
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS approved_at TIMESTAMPTZ DEFAULT NOW();
+
+-- +goose Down
+
+ALTER TABLE staff DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE staff DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- Журнал событий безопасности: блокировки входа и восстановления пароля
+-- после серии неудачных попыток и их снятие администратором.
+CREATE TABLE IF NOT EXISTS security_log (
+    id BIGSERIAL PRIMARY KEY,
+    event TEXT NOT NULL,
+    action TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    actor_id BIGINT,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_log_created_at ON security_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_security_log_email ON security_log (email, created_at DESC) WHERE email <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_security_log_email;
+DROP INDEX IF EXISTS idx_security_log_created_at;
+DROP TABLE IF EXISTS security_log;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- API-ключи для интеграций между системами. Сам ключ показывается один раз
+-- при выпуске, хранится только его SHA-256 хеш; prefix — начало ключа, по
+-- которому администратор узнаёт его в списке. Отозванный ключ остаётся в
+-- таблице для истории.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by BIGINT REFERENCES staff(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Сотрудник привязывает Telegram одноразовым кодом: администратор выпускает
+-- код в админке, сотрудник отправляет его боту командой /link. Хранится только
+-- SHA-256 хеш кода, после привязки код сбрасывается. Один аккаунт Telegram
+-- привязан не больше чем к одному сотруднику.
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS telegram_id BIGINT UNIQUE;
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS telegram_link_code_hash TEXT UNIQUE;
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS telegram_link_expires_at TIMESTAMPTZ;
+
+-- +goose Down
+
+ALTER TABLE staff DROP COLUMN IF EXISTS telegram_link_expires_at;
+ALTER TABLE staff DROP COLUMN IF EXISTS telegram_link_code_hash;
+ALTER TABLE staff DROP COLUMN IF EXISTS telegram_id;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- Источник привлечения гостя: метка src_<кампания> из диплинка
+-- t.me/<бот>?start=... Гость хранит последнюю метку, с которой пришёл, а
+-- бронирование получает метку гостя на момент создания, чтобы выгрузка
+-- аналитики разбивала бронирования по источникам.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS source VARCHAR(64);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS source VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_source ON bookings(source) WHERE source IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_bookings_source;
+ALTER TABLE bookings DROP COLUMN IF EXISTS source;
+ALTER TABLE users DROP COLUMN IF EXISTS source;
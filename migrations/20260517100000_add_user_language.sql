@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Язык, выбранный гостем в меню бота (ru, en). Пока гость язык не выбрал,
+-- значение пустое и бот берёт язык из language_code клиента Telegram.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+-- +goose Down
+
+ALTER TABLE users DROP COLUMN IF EXISTS language;
@@ -0,0 +1,64 @@
+-- +goose Up
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE campaign_status AS ENUM ('draft', 'running', 'done', 'cancelled');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE campaign_recipient_status AS ENUM ('pending', 'sent', 'blocked', 'failed');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+-- +goose StatementEnd
+
+-- Рассылка пользователям бота. Сегмент хранится как JSON
+-- ({"type": "all"}, {"type": "service", "service_ids": [...]},
+-- {"type": "grade", "grades": [...]}) и раскрывается в список получателей
+-- при запуске; редактировать можно только черновик.
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    image_url TEXT NOT NULL DEFAULT '',
+    buttons JSONB NOT NULL DEFAULT '[]',
+    segment JSONB NOT NULL DEFAULT '{"type": "all"}',
+    status campaign_status NOT NULL DEFAULT 'draft',
+    created_by BIGINT REFERENCES staff(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ NULL DEFAULT NULL,
+    finished_at TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Получатели рассылки и результат доставки каждому: blocked — пользователь
+-- заблокировал бота, failed — Telegram отклонил сообщение или кончились попытки.
+CREATE TABLE IF NOT EXISTS campaign_recipients (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL,
+    status campaign_recipient_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_campaign_recipients UNIQUE (campaign_id, telegram_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_recipients_pending
+    ON campaign_recipients (next_attempt_at)
+    WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_campaign_recipients_pending;
+DROP TABLE IF EXISTS campaign_recipients;
+DROP TABLE IF EXISTS campaigns;
+DROP TYPE IF EXISTS campaign_recipient_status;
+DROP TYPE IF EXISTS campaign_status;
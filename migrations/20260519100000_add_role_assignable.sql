@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- Сотрудникам роли с assignable автоматически назначаются новые бронирования
+-- и заявки. Раньше это были фиксированные admin и manager_1..3.
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS assignable BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE roles SET assignable = TRUE WHERE name IN ('admin', 'manager_1', 'manager_2', 'manager_3');
+
+-- +goose Down
+
+ALTER TABLE roles DROP COLUMN IF EXISTS assignable;